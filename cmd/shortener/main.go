@@ -42,12 +42,26 @@ func NewShortenerApp(
 	connection repository.Connection,
 	storage store.Store,
 	deleteURLsService service.DeleteURLsService,
+	expiredURLsService service.ExpiredURLsService,
+	purgeURLsService service.PurgeURLsService,
+	clickService service.ClickService,
 	auditService service.AuditService,
 	log logger.Logger,
 	parameters parameters.AppParameters,
 	configuration *config.Configuration,
 ) app.App {
-	shortener := app.NewShortenerApp(connection, storage, deleteURLsService, auditService, log, parameters, configuration)
+	shortener := app.NewShortenerApp(
+		connection,
+		storage,
+		deleteURLsService,
+		expiredURLsService,
+		purgeURLsService,
+		clickService,
+		auditService,
+		log,
+		parameters,
+		configuration,
+	)
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			log.Infof("Start application")
@@ -188,6 +202,9 @@ func main() {
 			parameters.NewAppParameters,
 			service.NewAuthorizationService,
 			service.NewDeleteURLsService,
+			service.NewExpiredURLsService,
+			service.NewPurgeURLsService,
+			service.NewClickService,
 			fx.Annotate(service.NewAuditService, fx.ParamTags(`group:"receivers"`)),
 			NewShortenerApp,
 			app.NewRouter,
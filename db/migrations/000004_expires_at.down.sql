@@ -0,0 +1,2 @@
+drop index urls_expires_at_idx;
+alter table urls drop column expires_at;
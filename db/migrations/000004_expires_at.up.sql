@@ -0,0 +1,2 @@
+alter table urls add column expires_at timestamptz;
+create index urls_expires_at_idx on urls(expires_at) where expires_at is not null;
@@ -0,0 +1,9 @@
+create table clicks(
+    id bigserial primary key,
+    url_key text not null references urls(url_key) on delete cascade,
+    clicked_at timestamptz not null,
+    referrer text not null default '',
+    user_agent text not null default '',
+    ip_hash text not null default ''
+);
+create index clicks_url_key_clicked_at_idx on clicks(url_key, clicked_at);
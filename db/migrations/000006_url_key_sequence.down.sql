@@ -0,0 +1 @@
+drop sequence url_key_seq;
@@ -0,0 +1 @@
+create sequence url_key_seq;
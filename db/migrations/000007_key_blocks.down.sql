@@ -0,0 +1,3 @@
+create sequence url_key_seq;
+select setval('url_key_seq', next_value, false) from key_blocks;
+drop table key_blocks;
@@ -0,0 +1,6 @@
+create table key_blocks(
+    id int primary key check (id = 1),
+    next_value bigint not null
+);
+insert into key_blocks(id, next_value) select 1, nextval('url_key_seq');
+drop sequence url_key_seq;
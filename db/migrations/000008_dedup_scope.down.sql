@@ -0,0 +1,9 @@
+do $$
+begin
+    if exists (select 1 from urls group by original_url having count(*) > 1) then
+        raise exception 'cannot restore unique original_url: URLs shortened with user or none dedup scope are duplicated, remove the duplicates first';
+    end if;
+end $$;
+drop index urls_original_url_idx;
+alter table urls add constraint urls_original_url_unique unique (original_url);
+alter table urls drop column dedup_scope;
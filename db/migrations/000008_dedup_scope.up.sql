@@ -0,0 +1,5 @@
+alter table urls add column dedup_scope text not null default 'global';
+create unique index urls_original_url_global_unique on urls(original_url) where dedup_scope = 'global';
+create unique index urls_user_original_url_unique on urls(user_id, original_url) where dedup_scope = 'user';
+alter table urls drop constraint urls_original_url_unique;
+create index urls_original_url_idx on urls(original_url);
@@ -0,0 +1,7 @@
+drop index urls_user_canonical_url_unique;
+drop index urls_canonical_url_global_unique;
+create unique index urls_user_original_url_unique on urls(user_id, original_url) where dedup_scope = 'user';
+create unique index urls_original_url_global_unique on urls(original_url) where dedup_scope = 'global';
+drop index urls_canonical_pending_idx;
+alter table urls drop column canonical_pending;
+alter table urls drop column canonical_url;
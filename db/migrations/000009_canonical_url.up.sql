@@ -0,0 +1,9 @@
+alter table urls add column canonical_url text;
+alter table urls add column canonical_pending boolean not null default false;
+update urls set canonical_url = original_url, canonical_pending = true;
+alter table urls alter column canonical_url set not null;
+drop index urls_original_url_global_unique;
+drop index urls_user_original_url_unique;
+create unique index urls_canonical_url_global_unique on urls(canonical_url) where dedup_scope = 'global';
+create unique index urls_user_canonical_url_unique on urls(user_id, canonical_url) where dedup_scope = 'user';
+create index urls_canonical_pending_idx on urls(url_key) where canonical_pending;
@@ -0,0 +1,2 @@
+drop index urls_deleted_at_idx;
+alter table urls drop column deleted_at;
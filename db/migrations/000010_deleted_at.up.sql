@@ -0,0 +1,3 @@
+alter table urls add column deleted_at timestamptz;
+update urls set deleted_at = now() where is_deleted;
+create index urls_deleted_at_idx on urls(deleted_at) where is_deleted;
@@ -0,0 +1,3 @@
+drop index urls_user_url_key_idx;
+drop index urls_user_created_at_idx;
+alter table urls drop column created_at;
@@ -0,0 +1,3 @@
+alter table urls add column created_at timestamptz not null default now();
+create index urls_user_created_at_idx on urls(user_id, created_at, url_key);
+create index urls_user_url_key_idx on urls(user_id, url_key);
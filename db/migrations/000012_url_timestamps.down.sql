@@ -0,0 +1,2 @@
+alter table urls drop column last_accessed_at;
+alter table urls drop column updated_at;
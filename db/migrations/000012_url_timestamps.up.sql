@@ -0,0 +1,8 @@
+alter table urls add column updated_at timestamptz;
+update urls set updated_at = coalesce(deleted_at, created_at);
+alter table urls alter column updated_at set not null, alter column updated_at set default now();
+
+alter table urls add column last_accessed_at timestamptz;
+update urls u set last_accessed_at = c.clicked_at
+from (select url_key, max(clicked_at) as clicked_at from clicks group by url_key) c
+where u.url_key = c.url_key;
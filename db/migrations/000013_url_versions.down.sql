@@ -0,0 +1 @@
+drop table url_versions;
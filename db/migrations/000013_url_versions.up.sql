@@ -0,0 +1,7 @@
+create table url_versions(
+    url_key text not null references urls(url_key) on delete cascade,
+    version integer not null,
+    original_url text not null,
+    replaced_at timestamptz not null,
+    primary key (url_key, version)
+);
@@ -0,0 +1,5 @@
+drop index urls_search_vector_idx;
+alter table urls drop column search_vector;
+drop function url_search_vector;
+drop table url_tags;
+drop table url_metadata;
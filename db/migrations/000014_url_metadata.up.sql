@@ -0,0 +1,17 @@
+create table url_metadata(
+    url_key text primary key references urls(url_key) on delete cascade,
+    title text not null default '',
+    notes text not null default ''
+);
+create table url_tags(
+    url_key text not null references urls(url_key) on delete cascade,
+    tag text not null,
+    primary key (url_key, tag)
+);
+create index url_tags_tag_idx on url_tags(tag);
+create function url_search_vector(original_url text, title text, notes text) returns tsvector
+    language sql immutable
+    as $$ select to_tsvector('simple', regexp_replace(concat_ws(' ', original_url, title, notes), '[^[:alnum:]]+', ' ', 'g')) $$;
+alter table urls add column search_vector tsvector;
+update urls set search_vector = url_search_vector(original_url, '', '');
+create index urls_search_vector_idx on urls using gin(search_vector);
@@ -0,0 +1,2 @@
+drop table collection_urls;
+drop table collections;
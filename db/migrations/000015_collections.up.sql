@@ -0,0 +1,13 @@
+create table collections(
+    id uuid primary key,
+    user_id text not null,
+    name text not null,
+    created_at timestamptz not null,
+    constraint collections_user_name_unique unique (user_id, name)
+);
+create table collection_urls(
+    collection_id uuid not null references collections(id) on delete cascade,
+    url_key text not null references urls(url_key) on delete cascade,
+    primary key (collection_id, url_key)
+);
+create index collection_urls_url_key_idx on collection_urls(url_key);
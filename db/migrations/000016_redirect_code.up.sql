@@ -0,0 +1 @@
+alter table urls add column redirect_code smallint not null default 0;
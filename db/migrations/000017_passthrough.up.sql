@@ -0,0 +1 @@
+alter table urls add column passthrough boolean not null default false;
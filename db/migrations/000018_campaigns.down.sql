@@ -0,0 +1,2 @@
+alter table urls drop column campaign_id;
+drop table campaigns;
@@ -0,0 +1,10 @@
+create table campaigns(
+    id uuid primary key,
+    user_id text not null,
+    name text not null,
+    parameters jsonb not null default '{}',
+    created_at timestamptz not null,
+    constraint campaigns_user_name_unique unique (user_id, name)
+);
+alter table urls add column campaign_id uuid references campaigns(id) on delete set null;
+create index urls_campaign_id_idx on urls(campaign_id);
@@ -0,0 +1 @@
+alter table urls add column redirect_rules jsonb;
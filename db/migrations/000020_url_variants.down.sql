@@ -0,0 +1,2 @@
+alter table clicks drop column variant;
+alter table urls drop column url_variants;
@@ -0,0 +1,2 @@
+alter table urls add column url_variants jsonb;
+alter table clicks add column variant text not null default '';
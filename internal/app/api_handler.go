@@ -2,12 +2,15 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/middleware"
 	"github.com/aleffnull/shortener/internal/pkg/logger"
+	"github.com/aleffnull/shortener/internal/pkg/store"
 	"github.com/aleffnull/shortener/internal/pkg/utils"
 	"github.com/aleffnull/shortener/internal/service"
 	"github.com/aleffnull/shortener/models"
@@ -51,7 +54,7 @@ func (h *APIHandler) HandleAPIRequest(response http.ResponseWriter, request *htt
 	userID := middleware.GetUserIDFromContext(ctx)
 	shortenerResponse, err := h.shortener.ShortenURL(ctx, &shortenRequest, userID)
 	if err != nil {
-		utils.HandleServerError(response, err, h.logger)
+		handleShortenError(response, err, h.logger)
 		return
 	}
 
@@ -74,6 +77,7 @@ func (h *APIHandler) HandleAPIRequest(response http.ResponseWriter, request *htt
 }
 
 // HandleAPIBatchRequest обработчик запроса пакетного сокращения URL.
+// Результат возвращается для каждого элемента: ошибка в одном элементе не мешает сохранению остальных.
 func (h *APIHandler) HandleAPIBatchRequest(response http.ResponseWriter, request *http.Request) {
 	var requestItems []*models.ShortenBatchRequestItem
 	if err := json.NewDecoder(request.Body).Decode(&requestItems); err != nil {
@@ -81,25 +85,84 @@ func (h *APIHandler) HandleAPIBatchRequest(response http.ResponseWriter, request
 		return
 	}
 
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	if err := validate.Var(requestItems, "omitempty,dive"); err != nil {
+	// Без уникального correlation_id результат элемента нельзя сопоставить с запросом.
+	if err := validateCorrelationIDs(requestItems); err != nil {
 		utils.HandleRequestError(response, err, h.logger)
 		return
 	}
 
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validItems := make([]*models.ShortenBatchRequestItem, 0, len(requestItems))
+	responseItems := make(map[string]*models.ShortenBatchResponseItem, len(requestItems))
+	for _, requestItem := range requestItems {
+		if err := validate.Struct(requestItem); err != nil {
+			responseItems[requestItem.CorrelationID] = &models.ShortenBatchResponseItem{
+				CorrelationID: requestItem.CorrelationID,
+				Status:        models.ShortenBatchStatusInvalid,
+				Reason:        err.Error(),
+			}
+			continue
+		}
+
+		validItems = append(validItems, requestItem)
+	}
+
 	ctx := request.Context()
 	userID := middleware.GetUserIDFromContext(ctx)
-	shortenerBatchResponse, err := h.shortener.ShortenURLBatch(ctx, requestItems, userID)
+	shortenerBatchResponse, err := h.shortener.ShortenURLBatch(ctx, validItems, userID)
 	if err != nil {
-		utils.HandleServerError(response, err, h.logger)
+		handleShortenError(response, err, h.logger)
 		return
 	}
 
+	for _, responseItem := range shortenerBatchResponse {
+		responseItems[responseItem.CorrelationID] = responseItem
+	}
+
+	// Порядок ответа совпадает с порядком запроса.
+	orderedItems := make([]*models.ShortenBatchResponseItem, 0, len(requestItems))
+	for _, requestItem := range requestItems {
+		orderedItems = append(orderedItems, responseItems[requestItem.CorrelationID])
+	}
+
+	allCreated := lo.EveryBy(orderedItems, func(item *models.ShortenBatchResponseItem) bool {
+		return item.Status == models.ShortenBatchStatusCreated
+	})
+
 	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
-	response.WriteHeader(http.StatusCreated)
+	response.WriteHeader(lo.Ternary(allCreated, http.StatusCreated, http.StatusMultiStatus))
 
-	if err = json.NewEncoder(response).Encode(shortenerBatchResponse); err != nil {
+	if err = json.NewEncoder(response).Encode(orderedItems); err != nil {
 		utils.HandleServerError(response, err, h.logger)
 		return
 	}
 }
+
+func handleShortenError(response http.ResponseWriter, err error, logger logger.Logger) {
+	var aliasConflictError *store.AliasConflictError
+	switch {
+	case errors.Is(err, store.ErrInvalidAlias), errors.Is(err, ErrCampaignNotFound), errors.Is(err, ErrInvalidRedirectRule),
+		errors.Is(err, ErrInvalidURLVariant):
+		utils.HandleRequestError(response, err, logger)
+	case errors.As(err, &aliasConflictError):
+		utils.HandleConflict(response, aliasConflictError, logger)
+	default:
+		utils.HandleServerError(response, err, logger)
+	}
+}
+
+func validateCorrelationIDs(requestItems []*models.ShortenBatchRequestItem) error {
+	correlationIDs := make(map[string]struct{}, len(requestItems))
+	for i, requestItem := range requestItems {
+		if requestItem == nil || len(requestItem.CorrelationID) == 0 {
+			return fmt.Errorf("item %v: correlation_id is required", i)
+		}
+
+		if _, exists := correlationIDs[requestItem.CorrelationID]; exists {
+			return fmt.Errorf("item %v: correlation_id %v is repeated", i, requestItem.CorrelationID)
+		}
+		correlationIDs[requestItem.CorrelationID] = struct{}{}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/store"
 	"github.com/aleffnull/shortener/internal/pkg/utils"
 	"github.com/aleffnull/shortener/models"
 	"github.com/google/uuid"
@@ -86,6 +88,63 @@ func TestAPIHandler_HandleAPIRequest(t *testing.T) {
 				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
 			},
 		},
+		{
+			name: "WHEN invalid alias THEN bad request",
+			args: args{
+				shortenRequest: &models.ShortenRequest{
+					URL:   fullURL,
+					Alias: "api",
+				},
+				responseWriter: httptest.NewRecorder(),
+			},
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(args args, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), args.shortenRequest, gomock.Any()).
+					Return(nil, fmt.Errorf("%w: reserved", store.ErrInvalidAlias))
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN campaign not found THEN bad request",
+			args: args{
+				shortenRequest: &models.ShortenRequest{
+					URL:      fullURL,
+					Campaign: uuid.New(),
+				},
+				responseWriter: httptest.NewRecorder(),
+			},
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(args args, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), args.shortenRequest, gomock.Any()).
+					Return(nil, ErrCampaignNotFound)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN alias is taken THEN conflict",
+			args: args{
+				shortenRequest: &models.ShortenRequest{
+					URL:   fullURL,
+					Alias: "spring-sale",
+				},
+				responseWriter: httptest.NewRecorder(),
+			},
+			want: want{
+				statusCode: http.StatusConflict,
+			},
+			hookBefore: func(args args, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), args.shortenRequest, gomock.Any()).
+					Return(nil, store.NewAliasConflictError(args.shortenRequest.Alias))
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
 		{
 			name: "WHEN response write error THEN internal error",
 			args: args{
@@ -251,6 +310,73 @@ func TestAPIHandler_HandleAPIBatchRequest(t *testing.T) {
 				return bytes.NewReader(jsonRequest), httptest.NewRecorder()
 			},
 		},
+		{
+			name: "WHEN repeated correlation ID THEN bad request",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(mock *mocks.Mock) (io.Reader, http.ResponseWriter) {
+				items := []*models.ShortenBatchRequestItem{
+					{
+						CorrelationID: correlationID,
+						OriginalURL:   fullURL,
+					},
+					{
+						CorrelationID: correlationID,
+						OriginalURL:   "http://bar.buz",
+					},
+				}
+				jsonRequest, err := json.Marshal(items)
+				require.NoError(t, err)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+				return bytes.NewReader(jsonRequest), httptest.NewRecorder()
+			},
+		},
+		{
+			name: "WHEN some items not created THEN multi-status in request order",
+			want: want{
+				statusCode: http.StatusMultiStatus,
+			},
+			hookBefore: func(mock *mocks.Mock) (io.Reader, http.ResponseWriter) {
+				items := []*models.ShortenBatchRequestItem{
+					{
+						CorrelationID: "1",
+						OriginalURL:   "not an URL",
+					},
+					{
+						CorrelationID: "2",
+						OriginalURL:   fullURL,
+					},
+				}
+				jsonRequest, err := json.Marshal(items)
+				require.NoError(t, err)
+				mock.App.EXPECT().
+					ShortenURLBatch(gomock.Any(), items[1:], uuid.UUID{}).
+					Return([]*models.ShortenBatchResponseItem{
+						{
+							CorrelationID: "2",
+							Status:        models.ShortenBatchStatusExisting,
+							ShortURL:      shortURL,
+						},
+					}, nil)
+				return bytes.NewReader(jsonRequest), httptest.NewRecorder()
+			},
+			hookAfter: func(result *http.Response) {
+				var responseItems []*models.ShortenBatchResponseItem
+				err := json.NewDecoder(result.Body).Decode(&responseItems)
+				require.NoError(t, err)
+				require.Len(t, responseItems, 2)
+				require.Equal(t, "1", responseItems[0].CorrelationID)
+				require.Equal(t, models.ShortenBatchStatusInvalid, responseItems[0].Status)
+				require.NotEmpty(t, responseItems[0].Reason)
+				require.Empty(t, responseItems[0].ShortURL)
+				require.Equal(t, &models.ShortenBatchResponseItem{
+					CorrelationID: "2",
+					Status:        models.ShortenBatchStatusExisting,
+					ShortURL:      shortURL,
+				}, responseItems[1])
+			},
+		},
 		{
 			name: "WHEN app error THEN internal error",
 			want: want{
@@ -289,6 +415,7 @@ func TestAPIHandler_HandleAPIBatchRequest(t *testing.T) {
 					Return([]*models.ShortenBatchResponseItem{
 						{
 							CorrelationID: correlationID,
+							Status:        models.ShortenBatchStatusCreated,
 							ShortURL:      shortURL,
 						},
 					}, nil)
@@ -315,6 +442,7 @@ func TestAPIHandler_HandleAPIBatchRequest(t *testing.T) {
 					Return([]*models.ShortenBatchResponseItem{
 						{
 							CorrelationID: correlationID,
+							Status:        models.ShortenBatchStatusCreated,
 							ShortURL:      shortURL,
 						},
 					}, nil)
@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"io"
+	"net/url"
 
 	"github.com/google/uuid"
 
@@ -12,10 +14,32 @@ type App interface {
 	Init(ctx context.Context) error
 	Shutdown()
 	GetURL(context.Context, string) (*models.GetURLResponseItem, error)
-	GetUserURLs(context.Context, uuid.UUID) ([]*models.UserURLsResponseItem, error)
+	GetPassthroughURL(*models.GetURLResponseItem, string, url.Values) (string, error)
+	GetUserURLs(context.Context, *models.UserURLsRequest, uuid.UUID) (*models.UserURLsResponse, error)
 	ShortenURL(context.Context, *models.ShortenRequest, uuid.UUID) (*models.ShortenResponse, error)
 	ShortenURLBatch(context.Context, []*models.ShortenBatchRequestItem, uuid.UUID) ([]*models.ShortenBatchResponseItem, error)
+	UpdateURL(context.Context, string, *models.UpdateURLRequest, uuid.UUID) (*models.UpdateURLResponse, error)
+	GetURLVersions(context.Context, string, uuid.UUID) ([]*models.URLVersionItem, error)
+	UpdateURLVariants(context.Context, string, *models.UpdateURLVariantsRequest, uuid.UUID) ([]*models.URLVariant, error)
 	DeleteURLs([]string, uuid.UUID)
+	RestoreURLs(context.Context, []string, uuid.UUID) ([]string, error)
+	CreateCollection(context.Context, *models.CollectionRequest, uuid.UUID) (*models.CollectionItem, error)
+	GetCollections(context.Context, uuid.UUID) ([]*models.CollectionItem, error)
+	GetCollection(context.Context, uuid.UUID, uuid.UUID) (*models.CollectionItem, error)
+	RenameCollection(context.Context, uuid.UUID, *models.CollectionRequest, uuid.UUID) (*models.CollectionItem, error)
+	DeleteCollection(context.Context, uuid.UUID, uuid.UUID) error
+	AddCollectionURLs(context.Context, uuid.UUID, []string, uuid.UUID) (*models.CollectionItem, error)
+	RemoveCollectionURLs(context.Context, uuid.UUID, []string, uuid.UUID) (*models.CollectionItem, error)
+	DeleteCollectionURLs(context.Context, uuid.UUID, uuid.UUID) ([]string, error)
+	ExportCollection(context.Context, uuid.UUID, uuid.UUID) ([]*models.UserURLsResponseItem, error)
+	CreateCampaign(context.Context, *models.CampaignRequest, uuid.UUID) (*models.CampaignItem, error)
+	GetCampaigns(context.Context, uuid.UUID) ([]*models.CampaignItem, error)
+	GetCampaign(context.Context, uuid.UUID, uuid.UUID) (*models.CampaignItem, error)
+	UpdateCampaign(context.Context, uuid.UUID, *models.CampaignRequest, uuid.UUID) (*models.CampaignItem, error)
+	DeleteCampaign(context.Context, uuid.UUID, uuid.UUID) error
 	CheckStore(context.Context) error
+	CompactStore(context.Context) error
+	ExportStore(context.Context, io.Writer) error
 	GetStatistics(context.Context) (*models.Statistics, error)
+	GetClickStatistics(context.Context, string, *models.ClickStatisticsRequest, uuid.UUID) (*models.ClickStatisticsResponse, error)
 }
@@ -0,0 +1,19 @@
+package app
+
+import "errors"
+
+var (
+	ErrURLNotFound               = errors.New("URL not found")
+	ErrURLAccessDenied           = errors.New("URL belongs to another user")
+	ErrInvalidStatisticsInterval = errors.New("invalid statistics interval")
+	ErrInvalidCursor             = errors.New("invalid cursor")
+	ErrDuplicateURL              = errors.New("URL is already shortened")
+	ErrCollectionNotFound        = errors.New("collection not found")
+	ErrDuplicateCollection       = errors.New("collection name is already taken")
+	ErrInvalidPassthroughPath    = errors.New("invalid passthrough path")
+	ErrCampaignNotFound          = errors.New("campaign not found")
+	ErrDuplicateCampaign         = errors.New("campaign name is already taken")
+	ErrInvalidCampaign           = errors.New("invalid campaign parameters")
+	ErrInvalidRedirectRule       = errors.New("invalid redirect rule")
+	ErrInvalidURLVariant         = errors.New("invalid URL variant")
+)
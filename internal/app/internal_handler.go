@@ -10,6 +10,9 @@ import (
 	"github.com/ldez/mimetype"
 )
 
+// mimetype не содержит типа для JSON Lines.
+const contentTypeJSONLines = "application/jsonl"
+
 type InternalHandler struct {
 	shortener App
 	logger    logger.Logger
@@ -37,3 +40,22 @@ func (h *InternalHandler) HandleStatsRequest(response http.ResponseWriter, reque
 		return
 	}
 }
+
+func (h *InternalHandler) HandleCompactRequest(response http.ResponseWriter, request *http.Request) {
+	if err := h.shortener.CompactStore(request.Context()); err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InternalHandler) HandleExportRequest(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set(headers.ContentType, contentTypeJSONLines)
+	response.WriteHeader(http.StatusOK)
+
+	// Заголовки уже отправлены, поэтому ошибку выгрузки можно только залогировать.
+	if err := h.shortener.ExportStore(request.Context(), response); err != nil {
+		h.logger.Errorf("Export error: %v", err)
+	}
+}
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +11,7 @@ import (
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
 	"github.com/aleffnull/shortener/internal/pkg/utils"
 	"github.com/aleffnull/shortener/models"
+	"github.com/go-http-utils/headers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -117,3 +120,110 @@ func TestInternalHandler_HandleStatsRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestInternalHandler_HandleCompactRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantStatus int
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN app error THEN internal error",
+			wantStatus: http.StatusInternalServerError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().CompactStore(gomock.Any()).Return(assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN no content",
+			wantStatus: http.StatusNoContent,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().CompactStore(gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewInternalHandler(mock.App, mock.Logger)
+			request := httptest.NewRequest(http.MethodPost, "/api/internal/compact", nil)
+
+			// Act.
+			handler.HandleCompactRequest(recorder, request)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+		})
+	}
+}
+
+func TestInternalHandler_HandleExportRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantBody   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN app error THEN logged",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportStore(gomock.Any(), gomock.Any()).Return(assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:     "WHEN no errors THEN exported",
+			wantBody: `{"type":"put","key":"foo","value":"http://foo.bar"}` + "\n",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportStore(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, writer io.Writer) error {
+						_, err := io.WriteString(writer, `{"type":"put","key":"foo","value":"http://foo.bar"}`+"\n")
+						return err
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewInternalHandler(mock.App, mock.Logger)
+			request := httptest.NewRequest(http.MethodGet, "/api/internal/export", nil)
+
+			// Act.
+			handler.HandleExportRequest(recorder, request)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, http.StatusOK, result.StatusCode)
+			require.Equal(t, contentTypeJSONLines, result.Header.Get(headers.ContentType))
+			body, err := io.ReadAll(result.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
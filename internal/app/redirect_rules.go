@@ -0,0 +1,222 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/samber/lo"
+
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/utils"
+	"github.com/aleffnull/shortener/models"
+)
+
+// RedirectClient свойства запроса перехода, по которым выбирается адрес. Заполняется и HTTP-, и gRPC-обработчиком.
+type RedirectClient struct {
+	UserAgent      string
+	AcceptLanguage string
+	IP             string
+	Now            time.Time
+}
+
+// redirectClient разобранные свойства клиента, по которым выбирается правило.
+type redirectClient struct {
+	platform string
+	// Языки из заголовка Accept-Language в порядке предпочтения.
+	languages []string
+	// Невалидный адрес не попадает ни в одну сеть.
+	ip  netip.Addr
+	now time.Time
+}
+
+func newRedirectClient(client *RedirectClient) *redirectClient {
+	ip, _ := netip.ParseAddr(client.IP)
+	return &redirectClient{
+		platform:  getPlatform(client.UserAgent),
+		languages: getAcceptedLanguages(client.AcceptLanguage),
+		ip:        ip.Unmap(),
+		now:       client.Now,
+	}
+}
+
+// getRedirectClient собирает свойства HTTP-запроса перехода.
+func getRedirectClient(request *http.Request, now time.Time) *RedirectClient {
+	return &RedirectClient{
+		UserAgent:      request.UserAgent(),
+		AcceptLanguage: request.Header.Get(headers.AcceptLanguage),
+		IP:             utils.GetClientIP(request),
+		Now:            now,
+	}
+}
+
+// SelectRedirectURL подставляет в item адрес первого правила, которому соответствует клиент.
+// Возвращает false, если ни одно правило не подошло и адрес не изменился.
+func SelectRedirectURL(item *models.GetURLResponseItem, client *RedirectClient) bool {
+	rule := matchRedirectRule(item.Rules, newRedirectClient(client))
+	if rule == nil {
+		return false
+	}
+
+	item.URL = rule.URL
+	return true
+}
+
+// matchRedirectRule возвращает первое правило, которому соответствует клиент, или nil.
+// Языки клиента перебираются в порядке предпочтения: правило для менее предпочтительного языка
+// срабатывает, только если ни одно правило не подошло для более предпочтительных.
+func matchRedirectRule(rules []*models.RedirectRule, client *redirectClient) *models.RedirectRule {
+	// Без языков подходят только правила без условия на язык.
+	languages := lo.Ternary(len(client.languages) == 0, []string{""}, client.languages)
+	for _, language := range languages {
+		for _, rule := range rules {
+			if matchesRedirectRule(rule, client, language) {
+				return rule
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchesRedirectRule(rule *models.RedirectRule, client *redirectClient, language string) bool {
+	if len(rule.Platform) > 0 && rule.Platform != client.platform {
+		return false
+	}
+
+	if len(rule.Language) > 0 && !matchesLanguage(rule.Language, language) {
+		return false
+	}
+
+	if len(rule.CIDR) > 0 {
+		prefix, err := netip.ParsePrefix(rule.CIDR)
+		if err != nil || !client.ip.IsValid() || !prefix.Contains(client.ip) {
+			return false
+		}
+	}
+
+	if rule.From != nil && client.now.Before(*rule.From) {
+		return false
+	}
+
+	return rule.To == nil || client.now.Before(*rule.To)
+}
+
+// matchesLanguage проверяет, что язык клиента совпадает с языком правила или уточняет его.
+func matchesLanguage(ruleLanguage, language string) bool {
+	return strings.EqualFold(ruleLanguage, language) ||
+		(len(language) > len(ruleLanguage) &&
+			language[len(ruleLanguage)] == '-' &&
+			strings.EqualFold(ruleLanguage, language[:len(ruleLanguage)]))
+}
+
+// getPlatform определяет платформу клиента по заголовку User-Agent, все, кроме iOS и Android, считается desktop.
+func getPlatform(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "iPod"):
+		return domain.PlatformIOS
+	case strings.Contains(userAgent, "Android"):
+		return domain.PlatformAndroid
+	default:
+		return domain.PlatformDesktop
+	}
+}
+
+// getAcceptedLanguages возвращает языки заголовка Accept-Language по убыванию веса, при равных весах - в порядке заголовка.
+// Языки с нулевым весом не принимаются клиентом и пропускаются.
+func getAcceptedLanguages(acceptLanguage string) []string {
+	type weightedLanguage struct {
+		language string
+		weight   float64
+	}
+
+	var accepted []weightedLanguage
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		language, parameters, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if len(language) == 0 || language == "*" {
+			continue
+		}
+
+		weight := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(parameters), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+
+			weight = parsed
+		}
+
+		if weight > 0 {
+			accepted = append(accepted, weightedLanguage{language: language, weight: weight})
+		}
+	}
+
+	slices.SortStableFunc(accepted, func(a, b weightedLanguage) int {
+		return cmp.Compare(b.weight, a.weight)
+	})
+
+	return lo.Map(accepted, func(item weightedLanguage, _ int) string {
+		return item.language
+	})
+}
+
+// validateRedirectRules проверяет то, что не проверяется тегами модели: интервал времени не должен быть пустым.
+func validateRedirectRules(rules []*models.RedirectRule) error {
+	for i, rule := range rules {
+		if rule.From != nil && rule.To != nil && !rule.To.After(*rule.From) {
+			return fmt.Errorf("%w: rule %v ends before it starts", ErrInvalidRedirectRule, i)
+		}
+	}
+
+	return nil
+}
+
+func toDomainRedirectRules(rules []*models.RedirectRule) []*domain.RedirectRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	return lo.Map(rules, func(rule *models.RedirectRule, _ int) *domain.RedirectRule {
+		return &domain.RedirectRule{
+			Platform: rule.Platform,
+			Language: rule.Language,
+			CIDR:     rule.CIDR,
+			From:     rule.From,
+			To:       rule.To,
+			URL:      rule.URL,
+		}
+	})
+}
+
+// toRedirectRules добавляет к адресам правил параметры кампании так же, как к URL ссылки.
+func toRedirectRules(rules []*domain.RedirectRule, campaignParameters map[string]string) ([]*models.RedirectRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	result := make([]*models.RedirectRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleURL, err := appendCampaignParameters(rule.URL, campaignParameters)
+		if err != nil {
+			return nil, fmt.Errorf("toRedirectRules, appendCampaignParameters failed: %w", err)
+		}
+
+		result = append(result, &models.RedirectRule{
+			Platform: rule.Platform,
+			Language: rule.Language,
+			CIDR:     rule.CIDR,
+			From:     rule.From,
+			To:       rule.To,
+			URL:      ruleURL,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aleffnull/shortener/models"
+)
+
+func TestRedirectRules_matchRedirectRule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.March, 5, 12, 0, 0, 0, time.UTC)
+	rules := []*models.RedirectRule{
+		{Platform: "ios", URL: "https://apps.apple.com/app/foo"},
+		{Platform: "android", URL: "https://play.google.com/store/apps/details?id=foo"},
+		{Language: "de", CIDR: "10.0.0.0/8", URL: "http://foo.bar/de-office"},
+		{Language: "de", URL: "http://foo.bar/de"},
+		{CIDR: "2001:db8::/32", URL: "http://foo.bar/v6"},
+		{From: lo.ToPtr(now.Add(-time.Hour)), To: lo.ToPtr(now), URL: "http://foo.bar/sale"},
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		now     time.Time
+		want    string
+	}{
+		{
+			name:    "WHEN iPhone THEN iOS rule",
+			headers: map[string]string{headers.UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"},
+			now:     now,
+			want:    "https://apps.apple.com/app/foo",
+		},
+		{
+			name:    "WHEN Android THEN Android rule",
+			headers: map[string]string{headers.UserAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8)"},
+			now:     now,
+			want:    "https://play.google.com/store/apps/details?id=foo",
+		},
+		{
+			name: "WHEN all conditions of rule match THEN rule",
+			headers: map[string]string{
+				headers.AcceptLanguage: "de-AT,de;q=0.9,en;q=0.8",
+				headers.XRealIP:        "10.1.2.3",
+			},
+			now:  now,
+			want: "http://foo.bar/de-office",
+		},
+		{
+			name:    "WHEN one condition of rule does not match THEN next rule",
+			headers: map[string]string{headers.AcceptLanguage: "en;q=0.5,de-DE"},
+			now:     now,
+			want:    "http://foo.bar/de",
+		},
+		{
+			name:    "WHEN no rule for preferred language THEN rule for next accepted language",
+			headers: map[string]string{headers.AcceptLanguage: "fr;q=1, it;q=0, de;q=0.8"},
+			now:     now,
+			want:    "http://foo.bar/de",
+		},
+		{
+			name:    "WHEN language not accepted THEN no match",
+			headers: map[string]string{headers.AcceptLanguage: "fr, de;q=0"},
+			now:     now,
+		},
+		{
+			name:    "WHEN language only partially equal THEN no match",
+			headers: map[string]string{headers.AcceptLanguage: "dev"},
+			now:     now,
+		},
+		{
+			name:    "WHEN IPv6 client in network THEN rule",
+			headers: map[string]string{headers.XRealIP: "2001:db8::1"},
+			now:     now,
+			want:    "http://foo.bar/v6",
+		},
+		{
+			name: "WHEN time window start THEN rule",
+			now:  now.Add(-time.Hour),
+			want: "http://foo.bar/sale",
+		},
+		{
+			name: "WHEN time window end THEN no match",
+			now:  now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			request := httptest.NewRequest(http.MethodGet, "/foo", nil)
+			request.RemoteAddr = "192.0.2.1:1234"
+			for name, value := range tt.headers {
+				request.Header.Set(name, value)
+			}
+
+			// Act.
+			rule := matchRedirectRule(rules, newRedirectClient(getRedirectClient(request, tt.now)))
+
+			// Assert.
+			if len(tt.want) == 0 {
+				require.Nil(t, rule)
+			} else {
+				require.NotNil(t, rule)
+				require.Equal(t, tt.want, rule.URL)
+			}
+		})
+	}
+}
+
+func TestRedirectRules_getAcceptedLanguages(t *testing.T) {
+	t.Parallel()
+
+	// Act-assert.
+	require.Empty(t, getAcceptedLanguages(""))
+	require.Empty(t, getAcceptedLanguages("*, fr;q=0"))
+	require.Equal(t, []string{"fr-CH", "fr", "en"}, getAcceptedLanguages("fr-CH, fr;q=0.9, en;q=0.8"))
+	require.Equal(t, []string{"en", "fr", "de"}, getAcceptedLanguages("de;q=0.7, en;q=0.8, fr;q=0.8"))
+}
+
+func TestRedirectRules_validateRedirectRules(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	// Act-assert.
+	require.NoError(t, validateRedirectRules([]*models.RedirectRule{{To: &now, URL: "http://foo.bar"}}))
+	require.ErrorIs(
+		t,
+		validateRedirectRules([]*models.RedirectRule{{From: &now, To: &now, URL: "http://foo.bar"}}),
+		ErrInvalidRedirectRule,
+	)
+}
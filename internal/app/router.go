@@ -3,6 +3,7 @@ package app
 import (
 	"net/http"
 	"net/http/pprof"
+	"net/url"
 
 	"github.com/go-chi/chi/v5"
 	cm "github.com/go-chi/chi/v5/middleware"
@@ -11,6 +12,7 @@ import (
 	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/middleware"
 	"github.com/aleffnull/shortener/internal/pkg/logger"
+	"github.com/aleffnull/shortener/internal/pkg/utils"
 	"github.com/aleffnull/shortener/internal/service"
 )
 
@@ -58,17 +60,24 @@ func (r *Router) NewMuxHandler() http.Handler {
 	mux.Get("/ping", r.maintenanceHandler.HandlePingRequest)
 
 	mux.Route("/", func(t chi.Router) {
-		t.Get("/{key}",
-			middleware.UserIDHandler(
-				setContentType(
-					func(writer http.ResponseWriter, request *http.Request) {
-						key := chi.URLParam(request, "key")
-						r.simpleAPIHandler.HandleGetRequest(writer, request, key)
-					},
-					mimetype.TextPlain),
-				r.authorizationService,
-				r.logger,
-				middleware.UserIDOptionsRequireValidToken))
+		redirectHandler := middleware.UserIDHandler(
+			setContentType(
+				func(writer http.ResponseWriter, request *http.Request) {
+					key := chi.URLParam(request, "key")
+					path, err := getPassthroughPath(request)
+					if err != nil {
+						utils.HandleRequestError(writer, err, r.logger)
+						return
+					}
+
+					r.simpleAPIHandler.HandleGetRequest(writer, request, key, path)
+				},
+				mimetype.TextPlain),
+			r.authorizationService,
+			r.logger,
+			middleware.UserIDOptionsRequireValidToken)
+		t.Get("/{key}", redirectHandler)
+		t.Get("/{key}/*", redirectHandler)
 		t.Post("/",
 			middleware.UserIDHandler(
 				setContentType(
@@ -106,6 +115,93 @@ func (r *Router) NewMuxHandler() http.Handler {
 
 		t.Get("/", r.userHandler.HandleGetUserURLsRequest)
 		t.Delete("/", r.userHandler.HandleBatchDeleteRequest)
+		t.Post("/restore", r.userHandler.HandleBatchRestoreRequest)
+		t.Patch("/{key}", func(writer http.ResponseWriter, request *http.Request) {
+			key := chi.URLParam(request, "key")
+			r.userHandler.HandleUpdateURLRequest(writer, request, key)
+		})
+		t.Get("/{key}/versions", func(writer http.ResponseWriter, request *http.Request) {
+			key := chi.URLParam(request, "key")
+			r.userHandler.HandleGetURLVersionsRequest(writer, request, key)
+		})
+		t.Put("/{key}/variants", func(writer http.ResponseWriter, request *http.Request) {
+			key := chi.URLParam(request, "key")
+			r.userHandler.HandleUpdateURLVariantsRequest(writer, request, key)
+		})
+		t.Get("/{key}/stats", func(writer http.ResponseWriter, request *http.Request) {
+			key := chi.URLParam(request, "key")
+			r.userHandler.HandleGetURLStatisticsRequest(writer, request, key)
+		})
+	})
+
+	mux.Route("/api/user/collections", func(t chi.Router) {
+		t.Use(func(next http.Handler) http.Handler {
+			return middleware.UserIDHandler(
+				setContentType(
+					middleware.GzipHandler(next.ServeHTTP),
+					mimetype.ApplicationJSON, mimetypeApplicationGZIP),
+				r.authorizationService,
+				r.logger,
+				middleware.UserIDOptionsRequireValidToken)
+		})
+
+		t.Get("/", r.userHandler.HandleGetCollectionsRequest)
+		t.Post("/", r.userHandler.HandleCreateCollectionRequest)
+		t.Get("/{id}", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleGetCollectionRequest(writer, request, id)
+		})
+		t.Patch("/{id}", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleRenameCollectionRequest(writer, request, id)
+		})
+		t.Delete("/{id}", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleDeleteCollectionRequest(writer, request, id)
+		})
+		t.Post("/{id}/urls", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleAddCollectionURLsRequest(writer, request, id)
+		})
+		t.Delete("/{id}/urls", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleRemoveCollectionURLsRequest(writer, request, id)
+		})
+		t.Post("/{id}/delete-urls", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleDeleteCollectionURLsRequest(writer, request, id)
+		})
+		t.Get("/{id}/export", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleExportCollectionRequest(writer, request, id)
+		})
+	})
+
+	mux.Route("/api/user/campaigns", func(t chi.Router) {
+		t.Use(func(next http.Handler) http.Handler {
+			return middleware.UserIDHandler(
+				setContentType(
+					middleware.GzipHandler(next.ServeHTTP),
+					mimetype.ApplicationJSON, mimetypeApplicationGZIP),
+				r.authorizationService,
+				r.logger,
+				middleware.UserIDOptionsRequireValidToken)
+		})
+
+		t.Get("/", r.userHandler.HandleGetCampaignsRequest)
+		t.Post("/", r.userHandler.HandleCreateCampaignRequest)
+		t.Get("/{id}", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleGetCampaignRequest(writer, request, id)
+		})
+		t.Put("/{id}", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleUpdateCampaignRequest(writer, request, id)
+		})
+		t.Delete("/{id}", func(writer http.ResponseWriter, request *http.Request) {
+			id := chi.URLParam(request, "id")
+			r.userHandler.HandleDeleteCampaignRequest(writer, request, id)
+		})
 	})
 
 	mux.Route("/api/internal", func(t chi.Router) {
@@ -117,6 +213,8 @@ func (r *Router) NewMuxHandler() http.Handler {
 		})
 
 		t.Get("/stats", r.internalHandler.HandleStatsRequest)
+		t.Post("/compact", r.internalHandler.HandleCompactRequest)
+		t.Get("/export", r.internalHandler.HandleExportRequest)
 	})
 
 	mux.Route("/debug/pprof", func(r chi.Router) {
@@ -130,6 +228,17 @@ func (r *Router) NewMuxHandler() http.Handler {
 	return mux
 }
 
+// getPassthroughPath возвращает остаток пути после ключа.
+// Если путь запроса содержит экранированные символы, chi сопоставляет маршрут по экранированному пути.
+func getPassthroughPath(request *http.Request) (string, error) {
+	path := chi.URLParam(request, "*")
+	if request.URL.RawPath == "" {
+		return path, nil
+	}
+
+	return url.PathUnescape(path)
+}
+
 func setContentType(next http.HandlerFunc, contentType ...string) http.HandlerFunc {
 	restrictor := cm.AllowContentType(contentType...)
 	restricted := restrictor(next)
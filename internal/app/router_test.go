@@ -3,6 +3,7 @@ package app
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
 	"github.com/aleffnull/shortener/models"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-http-utils/headers"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -24,9 +26,9 @@ func TestRouter_NewMuxHandler(t *testing.T) {
 	mock := mocks.NewMock(ctrl)
 
 	maintenanceHandler := NewMaintenanceHandler(mock.App, mock.Logger)
-	simpleAPIHandler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.Logger)
+	simpleAPIHandler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.ClickService, mock.Logger)
 	apiHandler := NewAPIHandler(mock.App, mock.AuditService, mock.Logger)
-	userHandler := NewUserHandler(mock.App, mock.Logger)
+	userHandler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
 	internalHandler := NewInternalHandler(mock.App, mock.Logger)
 	configuration := &config.Configuration{}
 	router := NewRouter(
@@ -66,8 +68,9 @@ func TestRouter_ServeHTTP(t *testing.T) {
 	mock.App.EXPECT().
 		GetURL(gomock.Any(), key).
 		Return(&models.GetURLResponseItem{
-			URL:    fullURL,
-			UserID: userID,
+			URL:          fullURL,
+			UserID:       userID,
+			RedirectCode: http.StatusTemporaryRedirect,
 		}, nil)
 	mock.AuditService.EXPECT().AuditEvent(gomock.Any()).DoAndReturn(func(event *domain.AuditEvent) {
 		require.LessOrEqual(t, event.Timestamp, time.Now())
@@ -75,12 +78,13 @@ func TestRouter_ServeHTTP(t *testing.T) {
 		require.Equal(t, userID, event.UserID)
 		require.Equal(t, fullURL, event.URL)
 	})
+	mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
 	mock.Logger.EXPECT().Infof(gomock.Any())
 
 	maintenanceHandler := NewMaintenanceHandler(mock.App, mock.Logger)
-	simpleAPIHandler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.Logger)
+	simpleAPIHandler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.ClickService, mock.Logger)
 	apiHandler := NewAPIHandler(mock.App, mock.AuditService, mock.Logger)
-	userHandler := NewUserHandler(mock.App, mock.Logger)
+	userHandler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
 	internalHandler := NewInternalHandler(mock.App, mock.Logger)
 	configuration := &config.Configuration{}
 	router := NewRouter(
@@ -101,3 +105,57 @@ func TestRouter_ServeHTTP(t *testing.T) {
 	// Act.
 	handler.ServeHTTP(recorder, request)
 }
+
+func TestRouter_ServeHTTP_Passthrough(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key     = "foo"
+		fullURL = "http://bar.buz"
+	)
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	item := &models.GetURLResponseItem{
+		URL:          fullURL,
+		RedirectCode: http.StatusTemporaryRedirect,
+		Passthrough:  true,
+	}
+	mock.AuthorizationService.EXPECT().CreateToken(gomock.Any()).Return("token", nil)
+	mock.App.EXPECT().GetURL(gomock.Any(), key).Return(item, nil)
+	mock.App.EXPECT().
+		GetPassthroughURL(item, "v2/a/b", url.Values{"x": {"1"}}).
+		Return(fullURL+"/v2/a/b?x=1", nil)
+	mock.AuditService.EXPECT().AuditEvent(gomock.Any())
+	mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
+	mock.Logger.EXPECT().Infof(gomock.Any())
+
+	maintenanceHandler := NewMaintenanceHandler(mock.App, mock.Logger)
+	simpleAPIHandler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.ClickService, mock.Logger)
+	apiHandler := NewAPIHandler(mock.App, mock.AuditService, mock.Logger)
+	userHandler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+	internalHandler := NewInternalHandler(mock.App, mock.Logger)
+	configuration := &config.Configuration{}
+	router := NewRouter(
+		maintenanceHandler,
+		simpleAPIHandler,
+		apiHandler,
+		userHandler,
+		internalHandler,
+		mock.AuthorizationService,
+		mock.Logger,
+		configuration,
+	)
+
+	handler := router.NewMuxHandler()
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/"+key+"/v2/a%2Fb?x=1", nil)
+
+	// Act.
+	handler.ServeHTTP(recorder, request)
+
+	// Assert.
+	require.Equal(t, http.StatusTemporaryRedirect, recorder.Code)
+	require.Equal(t, fullURL+"/v2/a/b?x=1", recorder.Header().Get(headers.Location))
+}
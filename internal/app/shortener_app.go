@@ -2,15 +2,23 @@ package app
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
 
 	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/canonical"
 	"github.com/aleffnull/shortener/internal/pkg/logger"
 	"github.com/aleffnull/shortener/internal/pkg/parameters"
 	"github.com/aleffnull/shortener/internal/pkg/store"
@@ -19,14 +27,31 @@ import (
 	"github.com/aleffnull/shortener/models"
 )
 
+const (
+	defaultClickStatisticsInterval = 7 * 24 * time.Hour
+	defaultClickStatisticsTopCount = 10
+	maxClickStatisticsBuckets      = 1000
+	defaultUserURLsLimit           = 100
+)
+
+const (
+	utmSource   = "utm_source"
+	utmMedium   = "utm_medium"
+	utmCampaign = "utm_campaign"
+)
+
 type ShortenerApp struct {
-	connection        repository.Connection
-	storage           store.Store
-	deleteURLsService service.DeleteURLsService
-	auditService      service.AuditService
-	logger            logger.Logger
-	parameters        parameters.AppParameters
-	configuration     *config.Configuration
+	connection         repository.Connection
+	storage            store.Store
+	deleteURLsService  service.DeleteURLsService
+	expiredURLsService service.ExpiredURLsService
+	purgeURLsService   service.PurgeURLsService
+	clickService       service.ClickService
+	auditService       service.AuditService
+	logger             logger.Logger
+	parameters         parameters.AppParameters
+	configuration      *config.Configuration
+	canonicalizer      *canonical.Canonicalizer
 }
 
 var _ App = (*ShortenerApp)(nil)
@@ -35,19 +60,31 @@ func NewShortenerApp(
 	connection repository.Connection,
 	storage store.Store,
 	deleteURLsService service.DeleteURLsService,
+	expiredURLsService service.ExpiredURLsService,
+	purgeURLsService service.PurgeURLsService,
+	clickService service.ClickService,
 	auditService service.AuditService,
 	logger logger.Logger,
 	parameters parameters.AppParameters,
 	configuration *config.Configuration,
 ) App {
+	var canonicalizationConfiguration *config.URLCanonicalizationConfiguration
+	if configuration != nil {
+		canonicalizationConfiguration = configuration.URLCanonicalization
+	}
+
 	return &ShortenerApp{
-		connection:        connection,
-		storage:           storage,
-		deleteURLsService: deleteURLsService,
-		auditService:      auditService,
-		logger:            logger,
-		parameters:        parameters,
-		configuration:     configuration,
+		connection:         connection,
+		storage:            storage,
+		deleteURLsService:  deleteURLsService,
+		expiredURLsService: expiredURLsService,
+		purgeURLsService:   purgeURLsService,
+		clickService:       clickService,
+		auditService:       auditService,
+		logger:             logger,
+		parameters:         parameters,
+		configuration:      configuration,
+		canonicalizer:      canonical.NewCanonicalizer(canonicalizationConfiguration),
 	}
 }
 
@@ -64,15 +101,33 @@ func (s *ShortenerApp) Init(ctx context.Context) error {
 		return fmt.Errorf("ShortenerApp.Init, parameters.Init failed: %w", err)
 	}
 
+	// URL, сохраненные до канонизации, канонизируются один раз, после миграции хранилища.
+	count, err := s.storage.BackfillCanonicalURLs(ctx, s.canonicalizer.Canonicalize)
+	if err != nil {
+		return fmt.Errorf("ShortenerApp.Init, storage.BackfillCanonicalURLs failed: %w", err)
+	}
+
+	if count > 0 {
+		s.logger.Infof("Канонизировано URL, сохраненных до канонизации: %v", count)
+	}
+
 	s.auditService.Init()
 	s.deleteURLsService.Init()
+	s.expiredURLsService.Init()
+	s.purgeURLsService.Init()
+	s.clickService.Init()
 
 	return nil
 }
 
 func (s *ShortenerApp) Shutdown() {
+	s.clickService.Shutdown()
+	s.purgeURLsService.Shutdown()
+	s.expiredURLsService.Shutdown()
 	s.deleteURLsService.Shutdown()
 	s.auditService.Shutdown()
+	// Сервисы выше пишут в хранилище, поэтому оно закрывается после них.
+	s.storage.Shutdown()
 	s.connection.Shutdown()
 }
 
@@ -86,38 +141,134 @@ func (s *ShortenerApp) GetURL(ctx context.Context, key string) (*models.GetURLRe
 		return nil, nil
 	}
 
+	targetURL, err := appendCampaignParameters(item.URL, item.CampaignParameters)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetURL, appendCampaignParameters failed: %w", err)
+	}
+
+	// Параметры кампании добавляются и к адресам правил.
+	rules, err := toRedirectRules(item.Rules, item.CampaignParameters)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetURL, toRedirectRules failed: %w", err)
+	}
+
+	variants, err := toURLVariants(item.Variants, item.CampaignParameters)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetURL, toURLVariants failed: %w", err)
+	}
+
+	now := time.Now()
+	redirectCode := lo.CoalesceOrEmpty(item.RedirectCode, int(s.configuration.RedirectCode))
 	return &models.GetURLResponseItem{
-		URL:       item.URL,
-		UserID:    item.UserID,
-		IsDeleted: item.IsDeleted,
+		URL:          targetURL,
+		UserID:       item.UserID,
+		IsDeleted:    item.IsDeleted,
+		IsExpired:    item.IsExpired(now),
+		RedirectCode: redirectCode,
+		CacheMaxAge:  s.getRedirectCacheMaxAge(item, redirectCode, now),
+		Passthrough:  item.Passthrough,
+		Rules:        rules,
+		Variants:     variants,
 	}, nil
 }
 
-func (s *ShortenerApp) GetUserURLs(ctx context.Context, userID uuid.UUID) ([]*models.UserURLsResponseItem, error) {
-	items, err := s.storage.LoadAllByUserID(ctx, userID)
+// GetPassthroughURL добавляет к URL ссылки остаток пути и параметры запроса перехода.
+// Одноименные параметры объединяются согласно настройке PassthroughQueryMerge.
+func (s *ShortenerApp) GetPassthroughURL(item *models.GetURLResponseItem, path string, query url.Values) (string, error) {
+	// Переход выше по пути ссылки не допускается.
+	segments := strings.Split(path, "/")
+	if slices.Contains(segments, ".") || slices.Contains(segments, "..") {
+		return "", fmt.Errorf("ShortenerApp.GetPassthroughURL, path '%v': %w", path, ErrInvalidPassthroughPath)
+	}
+
+	target, err := url.Parse(item.URL)
+	if err != nil {
+		return "", fmt.Errorf("ShortenerApp.GetPassthroughURL, url.Parse failed: %w", err)
+	}
+
+	if path != "" {
+		target = target.JoinPath(path)
+	}
+
+	if len(query) > 0 {
+		target.RawQuery = mergeQuery(target.Query(), query, s.configuration.PassthroughQueryMerge).Encode()
+	}
+
+	return target.String(), nil
+}
+
+func (s *ShortenerApp) GetUserURLs(
+	ctx context.Context,
+	request *models.UserURLsRequest,
+	userID uuid.UUID,
+) (*models.UserURLsResponse, error) {
+	query, err := newUserURLsQuery(request)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetUserURLs, newUserURLsQuery failed: %w", err)
+	}
+
+	// Лишний элемент показывает, что есть следующая страница.
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit++
+	}
+
+	items, err := s.storage.LoadAllByUserID(ctx, userID, query)
 	if err != nil {
 		return nil, fmt.Errorf("ShortenerApp.GetUserURLs, storage.LoadAllByUserID failed: %w", err)
 	}
 
-	responseItems := []*models.UserURLsResponseItem{}
+	response := &models.UserURLsResponse{
+		Items: []*models.UserURLsResponseItem{},
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+		response.NextCursor = encodeUserURLsCursor(items[len(items)-1])
+	}
+
 	for _, item := range items {
-		shortURL, err := url.JoinPath(s.configuration.BaseURL, item.URLKey)
+		responseItem, err := s.newUserURLsResponseItem(item)
 		if err != nil {
-			return nil, fmt.Errorf("ShortenerApp.GetUserURLs, url.JoinPath: %w", err)
+			return nil, fmt.Errorf("ShortenerApp.GetUserURLs, newUserURLsResponseItem failed: %w", err)
 		}
 
-		responseItems = append(responseItems, &models.UserURLsResponseItem{
-			ShortURL:    shortURL,
-			OriginalURL: item.OriginalURL,
-		})
+		response.Items = append(response.Items, responseItem)
 	}
 
-	return responseItems, nil
+	return response, nil
 }
 
 func (s *ShortenerApp) ShortenURL(ctx context.Context, request *models.ShortenRequest, userID uuid.UUID) (*models.ShortenResponse, error) {
-	longURL := request.URL
-	key, err := s.storage.Save(ctx, longURL, userID)
+	if err := validateRedirectRules(request.Rules); err != nil {
+		return nil, fmt.Errorf("ShortenURL, validateRedirectRules failed: %w", err)
+	}
+
+	if err := validateURLVariants(request.Variants); err != nil {
+		return nil, fmt.Errorf("ShortenURL, validateURLVariants failed: %w", err)
+	}
+
+	if request.Campaign != uuid.Nil {
+		if _, err := s.loadCampaign(ctx, request.Campaign, userID); err != nil {
+			return nil, fmt.Errorf("ShortenURL, loadCampaign failed: %w", err)
+		}
+	}
+
+	// Дубликаты ищутся по каноническому URL, а переход идет на исходный.
+	saveRequest := &domain.SaveRequest{
+		OriginalURL:  request.URL,
+		CanonicalURL: s.canonicalizer.Canonicalize(request.URL),
+		Alias:        request.Alias,
+		ExpiresAt:    getExpiresAt(request.ExpiresAt, request.TTL),
+		Title:        request.Title,
+		Tags:         normalizeTags(request.Tags),
+		Notes:        request.Notes,
+		RedirectCode: request.RedirectCode,
+		Passthrough:  request.Passthrough,
+		CampaignID:   request.Campaign,
+		Rules:        toDomainRedirectRules(request.Rules),
+		Variants:     toDomainURLVariants(request.Variants),
+	}
+	key, err := s.storage.Save(ctx, saveRequest, userID)
 
 	isDuplicate := false
 	if err != nil {
@@ -143,14 +294,33 @@ func (s *ShortenerApp) ShortenURL(ctx context.Context, request *models.ShortenRe
 }
 
 func (s *ShortenerApp) ShortenURLBatch(ctx context.Context, requestItems []*models.ShortenBatchRequestItem, userID uuid.UUID) ([]*models.ShortenBatchResponseItem, error) {
+	// Элементы с неверными правилами или вариантами, с чужой либо несуществующей кампанией не сохраняются.
+	requestItems, responseItems, err := s.filterBatchItems(ctx, requestItems, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenURLBatch, filterBatchItems failed: %w", err)
+	}
+
 	if len(requestItems) == 0 {
-		return []*models.ShortenBatchResponseItem{}, nil
+		return responseItems, nil
 	}
 
 	requestModels := lo.Map(requestItems, func(item *models.ShortenBatchRequestItem, _ int) *domain.BatchRequestItem {
 		return &domain.BatchRequestItem{
+			SaveRequest: domain.SaveRequest{
+				OriginalURL:  item.OriginalURL,
+				CanonicalURL: s.canonicalizer.Canonicalize(item.OriginalURL),
+				Alias:        item.Alias,
+				ExpiresAt:    getExpiresAt(item.ExpiresAt, item.TTL),
+				Title:        item.Title,
+				Tags:         normalizeTags(item.Tags),
+				Notes:        item.Notes,
+				RedirectCode: item.RedirectCode,
+				Passthrough:  item.Passthrough,
+				CampaignID:   item.Campaign,
+				Rules:        toDomainRedirectRules(item.Rules),
+				Variants:     toDomainURLVariants(item.Variants),
+			},
 			CorrelationID: item.CorrelationID,
-			OriginalURL:   item.OriginalURL,
 		}
 	})
 	responseModels, err := s.storage.SaveBatch(ctx, requestModels, userID)
@@ -158,22 +328,133 @@ func (s *ShortenerApp) ShortenURLBatch(ctx context.Context, requestItems []*mode
 		return nil, fmt.Errorf("ShortenURLBatch, storage.SaveBatch: %w", err)
 	}
 
-	responseItems := make([]*models.ShortenBatchResponseItem, 0, len(requestItems))
 	for _, responseModel := range responseModels {
-		shortURL, err := url.JoinPath(s.configuration.BaseURL, responseModel.Key)
-		if err != nil {
-			return nil, fmt.Errorf("ShortenURL, url.JoinPath: %w", err)
+		responseItem := &models.ShortenBatchResponseItem{
+			CorrelationID: responseModel.CorrelationID,
+			Status:        string(responseModel.Status),
+			Reason:        responseModel.Reason,
 		}
 
-		responseItems = append(responseItems, &models.ShortenBatchResponseItem{
-			CorrelationID: responseModel.CorrelationID,
-			ShortURL:      shortURL,
-		})
+		if responseModel.Status != domain.BatchItemStatusInvalid {
+			responseItem.ShortURL, err = url.JoinPath(s.configuration.BaseURL, responseModel.Key)
+			if err != nil {
+				return nil, fmt.Errorf("ShortenURL, url.JoinPath: %w", err)
+			}
+		}
+
+		responseItems = append(responseItems, responseItem)
 	}
 
 	return responseItems, nil
 }
 
+func (s *ShortenerApp) UpdateURL(
+	ctx context.Context,
+	key string,
+	request *models.UpdateURLRequest,
+	userID uuid.UUID,
+) (*models.UpdateURLResponse, error) {
+	item, err := s.loadOwnURL(ctx, key, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURL, loadOwnURL failed: %w", err)
+	}
+
+	// Менять можно только живую ссылку.
+	if item.IsDeleted || item.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURL: %w: '%v'", ErrURLNotFound, key)
+	}
+
+	// Новый адрес проверяется на дубликаты так же, как при сокращении.
+	updateRequest := &domain.UpdateRequest{
+		Key:          key,
+		OriginalURL:  request.URL,
+		CanonicalURL: s.canonicalizer.Canonicalize(request.URL),
+	}
+	version, err := s.storage.UpdateURL(ctx, updateRequest, userID)
+	if err != nil {
+		var duplicateURLError *store.DuplicateURLError
+		if errors.As(err, &duplicateURLError) {
+			return nil, fmt.Errorf("ShortenerApp.UpdateURL: %w: '%v'", ErrDuplicateURL, duplicateURLError.Key)
+		}
+
+		return nil, fmt.Errorf("ShortenerApp.UpdateURL, storage.UpdateURL failed: %w", err)
+	}
+
+	// Ссылка могла быть удалена или истечь после проверки.
+	if version == 0 {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURL: %w: '%v'", ErrURLNotFound, key)
+	}
+
+	shortURL, err := url.JoinPath(s.configuration.BaseURL, key)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURL, url.JoinPath: %w", err)
+	}
+
+	return &models.UpdateURLResponse{
+		ShortURL:    shortURL,
+		OriginalURL: request.URL,
+		Version:     version,
+	}, nil
+}
+
+func (s *ShortenerApp) GetURLVersions(ctx context.Context, key string, userID uuid.UUID) ([]*models.URLVersionItem, error) {
+	// История доступна владельцу и для удаленной ссылки, как и статистика.
+	if _, err := s.loadOwnURL(ctx, key, userID); err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetURLVersions, loadOwnURL failed: %w", err)
+	}
+
+	versions, err := s.storage.LoadVersions(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetURLVersions, storage.LoadVersions failed: %w", err)
+	}
+
+	items := make([]*models.URLVersionItem, 0, len(versions))
+	for _, version := range versions {
+		items = append(items, &models.URLVersionItem{
+			Version:     version.Version,
+			OriginalURL: version.OriginalURL,
+			ReplacedAt:  version.ReplacedAt,
+		})
+	}
+
+	return items, nil
+}
+
+// UpdateURLVariants меняет веса вариантов живой ссылки пользователя, возвращает все варианты ссылки.
+func (s *ShortenerApp) UpdateURLVariants(
+	ctx context.Context,
+	key string,
+	request *models.UpdateURLVariantsRequest,
+	userID uuid.UUID,
+) ([]*models.URLVariant, error) {
+	item, err := s.loadOwnURL(ctx, key, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURLVariants, loadOwnURL failed: %w", err)
+	}
+
+	if item.IsDeleted || item.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURLVariants: %w: '%v'", ErrURLNotFound, key)
+	}
+
+	variants, err := applyURLVariantWeights(item.Variants, request.Weights)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURLVariants, applyURLVariantWeights failed: %w", err)
+	}
+
+	updated, err := s.storage.UpdateVariants(ctx, key, variants, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURLVariants, storage.UpdateVariants failed: %w", err)
+	}
+
+	// Ссылка могла быть удалена или истечь после проверки.
+	if !updated {
+		return nil, fmt.Errorf("ShortenerApp.UpdateURLVariants: %w: '%v'", ErrURLNotFound, key)
+	}
+
+	// Владельцу показываются адреса вариантов без параметров кампании.
+	return toURLVariants(variants, nil)
+}
+
 func (s *ShortenerApp) DeleteURLs(keys []string, userID uuid.UUID) {
 	s.deleteURLsService.Delete(&domain.DeleteURLsRequest{
 		Keys:   keys,
@@ -181,6 +462,265 @@ func (s *ShortenerApp) DeleteURLs(keys []string, userID uuid.UUID) {
 	})
 }
 
+func (s *ShortenerApp) RestoreURLs(ctx context.Context, keys []string, userID uuid.UUID) ([]string, error) {
+	// Восстановить можно только ссылки, удаленные не раньше периода восстановления.
+	deletedAfter := time.Now().Add(-s.configuration.DeletedURLsGracePeriod)
+	restored, err := s.storage.RestoreBatch(ctx, keys, userID, deletedAfter)
+	if err != nil {
+		return nil, fmt.Errorf("RestoreURLs, storage.RestoreBatch failed: %w", err)
+	}
+
+	return restored, nil
+}
+
+func (s *ShortenerApp) CreateCollection(
+	ctx context.Context,
+	request *models.CollectionRequest,
+	userID uuid.UUID,
+) (*models.CollectionItem, error) {
+	collection := &domain.Collection{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      request.Name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.storage.SaveCollection(ctx, collection); err != nil {
+		if errors.Is(err, store.ErrCollectionExists) {
+			return nil, fmt.Errorf("ShortenerApp.CreateCollection: %w: '%v'", ErrDuplicateCollection, request.Name)
+		}
+
+		return nil, fmt.Errorf("ShortenerApp.CreateCollection, storage.SaveCollection failed: %w", err)
+	}
+
+	return toCollectionItem(collection), nil
+}
+
+func (s *ShortenerApp) GetCollections(ctx context.Context, userID uuid.UUID) ([]*models.CollectionItem, error) {
+	collections, err := s.storage.LoadCollections(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetCollections, storage.LoadCollections failed: %w", err)
+	}
+
+	return lo.Map(collections, func(collection *domain.Collection, _ int) *models.CollectionItem {
+		return toCollectionItem(collection)
+	}), nil
+}
+
+func (s *ShortenerApp) GetCollection(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.CollectionItem, error) {
+	collection, err := s.loadCollection(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetCollection, loadCollection failed: %w", err)
+	}
+
+	return toCollectionItem(collection), nil
+}
+
+func (s *ShortenerApp) RenameCollection(
+	ctx context.Context,
+	id uuid.UUID,
+	request *models.CollectionRequest,
+	userID uuid.UUID,
+) (*models.CollectionItem, error) {
+	found, err := s.storage.RenameCollection(ctx, id, request.Name, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrCollectionExists) {
+			return nil, fmt.Errorf("ShortenerApp.RenameCollection: %w: '%v'", ErrDuplicateCollection, request.Name)
+		}
+
+		return nil, fmt.Errorf("ShortenerApp.RenameCollection, storage.RenameCollection failed: %w", err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("ShortenerApp.RenameCollection: %w: '%v'", ErrCollectionNotFound, id)
+	}
+
+	return s.GetCollection(ctx, id, userID)
+}
+
+// DeleteCollection удаляет только коллекцию, ссылки коллекции остаются.
+func (s *ShortenerApp) DeleteCollection(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	found, err := s.storage.DeleteCollection(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("ShortenerApp.DeleteCollection, storage.DeleteCollection failed: %w", err)
+	}
+
+	if !found {
+		return fmt.Errorf("ShortenerApp.DeleteCollection: %w: '%v'", ErrCollectionNotFound, id)
+	}
+
+	return nil
+}
+
+// AddCollectionURLs добавляет в коллекцию ссылки пользователя, чужие и несуществующие ключи пропускаются.
+func (s *ShortenerApp) AddCollectionURLs(
+	ctx context.Context,
+	id uuid.UUID,
+	keys []string,
+	userID uuid.UUID,
+) (*models.CollectionItem, error) {
+	found, err := s.storage.AddCollectionURLs(ctx, id, keys, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.AddCollectionURLs, storage.AddCollectionURLs failed: %w", err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("ShortenerApp.AddCollectionURLs: %w: '%v'", ErrCollectionNotFound, id)
+	}
+
+	return s.GetCollection(ctx, id, userID)
+}
+
+func (s *ShortenerApp) RemoveCollectionURLs(
+	ctx context.Context,
+	id uuid.UUID,
+	keys []string,
+	userID uuid.UUID,
+) (*models.CollectionItem, error) {
+	found, err := s.storage.RemoveCollectionURLs(ctx, id, keys, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.RemoveCollectionURLs, storage.RemoveCollectionURLs failed: %w", err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("ShortenerApp.RemoveCollectionURLs: %w: '%v'", ErrCollectionNotFound, id)
+	}
+
+	return s.GetCollection(ctx, id, userID)
+}
+
+// DeleteCollectionURLs удаляет все живые ссылки коллекции так же, как пакетное удаление, и возвращает их ключи.
+// Коллекция остается, удаленные ссылки можно восстановить в течение периода восстановления.
+func (s *ShortenerApp) DeleteCollectionURLs(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]string, error) {
+	items, err := s.loadCollectionURLs(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.DeleteCollectionURLs, loadCollectionURLs failed: %w", err)
+	}
+
+	keys := lo.Map(items, func(item *domain.KeyOriginalURLItem, _ int) string {
+		return item.URLKey
+	})
+	if len(keys) > 0 {
+		s.DeleteURLs(keys, userID)
+	}
+
+	return keys, nil
+}
+
+// ExportCollection возвращает все живые ссылки коллекции без разбиения на страницы.
+func (s *ShortenerApp) ExportCollection(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]*models.UserURLsResponseItem, error) {
+	items, err := s.loadCollectionURLs(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.ExportCollection, loadCollectionURLs failed: %w", err)
+	}
+
+	responseItems := make([]*models.UserURLsResponseItem, 0, len(items))
+	for _, item := range items {
+		responseItem, err := s.newUserURLsResponseItem(item)
+		if err != nil {
+			return nil, fmt.Errorf("ShortenerApp.ExportCollection, newUserURLsResponseItem failed: %w", err)
+		}
+
+		responseItems = append(responseItems, responseItem)
+	}
+
+	return responseItems, nil
+}
+
+func (s *ShortenerApp) CreateCampaign(
+	ctx context.Context,
+	request *models.CampaignRequest,
+	userID uuid.UUID,
+) (*models.CampaignItem, error) {
+	parameters, err := newCampaignParameters(request)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.CreateCampaign, newCampaignParameters failed: %w", err)
+	}
+
+	campaign := &domain.Campaign{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       request.Name,
+		CreatedAt:  time.Now(),
+		Parameters: parameters,
+	}
+	if err := s.storage.SaveCampaign(ctx, campaign); err != nil {
+		if errors.Is(err, store.ErrCampaignExists) {
+			return nil, fmt.Errorf("ShortenerApp.CreateCampaign: %w: '%v'", ErrDuplicateCampaign, request.Name)
+		}
+
+		return nil, fmt.Errorf("ShortenerApp.CreateCampaign, storage.SaveCampaign failed: %w", err)
+	}
+
+	return toCampaignItem(campaign), nil
+}
+
+func (s *ShortenerApp) GetCampaigns(ctx context.Context, userID uuid.UUID) ([]*models.CampaignItem, error) {
+	campaigns, err := s.storage.LoadCampaigns(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetCampaigns, storage.LoadCampaigns failed: %w", err)
+	}
+
+	return lo.Map(campaigns, func(campaign *domain.Campaign, _ int) *models.CampaignItem {
+		return toCampaignItem(campaign)
+	}), nil
+}
+
+func (s *ShortenerApp) GetCampaign(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.CampaignItem, error) {
+	campaign, err := s.loadCampaign(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetCampaign, loadCampaign failed: %w", err)
+	}
+
+	return toCampaignItem(campaign), nil
+}
+
+// UpdateCampaign заменяет имя и параметры кампании, новые параметры действуют на все ее ссылки.
+func (s *ShortenerApp) UpdateCampaign(
+	ctx context.Context,
+	id uuid.UUID,
+	request *models.CampaignRequest,
+	userID uuid.UUID,
+) (*models.CampaignItem, error) {
+	parameters, err := newCampaignParameters(request)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.UpdateCampaign, newCampaignParameters failed: %w", err)
+	}
+
+	found, err := s.storage.UpdateCampaign(ctx, &domain.Campaign{
+		ID:         id,
+		UserID:     userID,
+		Name:       request.Name,
+		Parameters: parameters,
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrCampaignExists) {
+			return nil, fmt.Errorf("ShortenerApp.UpdateCampaign: %w: '%v'", ErrDuplicateCampaign, request.Name)
+		}
+
+		return nil, fmt.Errorf("ShortenerApp.UpdateCampaign, storage.UpdateCampaign failed: %w", err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("ShortenerApp.UpdateCampaign: %w: '%v'", ErrCampaignNotFound, id)
+	}
+
+	return s.GetCampaign(ctx, id, userID)
+}
+
+// DeleteCampaign удаляет кампанию, ее ссылки остаются без параметров кампании.
+func (s *ShortenerApp) DeleteCampaign(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	found, err := s.storage.DeleteCampaign(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("ShortenerApp.DeleteCampaign, storage.DeleteCampaign failed: %w", err)
+	}
+
+	if !found {
+		return fmt.Errorf("ShortenerApp.DeleteCampaign: %w: '%v'", ErrCampaignNotFound, id)
+	}
+
+	return nil
+}
+
 func (s *ShortenerApp) CheckStore(ctx context.Context) error {
 	err := s.storage.CheckAvailability(ctx)
 	if err != nil {
@@ -190,6 +730,22 @@ func (s *ShortenerApp) CheckStore(ctx context.Context) error {
 	return nil
 }
 
+func (s *ShortenerApp) CompactStore(ctx context.Context) error {
+	if err := s.storage.Compact(ctx); err != nil {
+		return fmt.Errorf("CompactStore, storage.Compact failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ShortenerApp) ExportStore(ctx context.Context, writer io.Writer) error {
+	if err := s.storage.Export(ctx, writer); err != nil {
+		return fmt.Errorf("ExportStore, storage.Export failed: %w", err)
+	}
+
+	return nil
+}
+
 func (s *ShortenerApp) GetStatistics(ctx context.Context) (*models.Statistics, error) {
 	urlsCount, usersCount, err := s.storage.GetStatistics(ctx)
 	if err != nil {
@@ -201,3 +757,450 @@ func (s *ShortenerApp) GetStatistics(ctx context.Context) (*models.Statistics, e
 		UsersCount: usersCount,
 	}, nil
 }
+
+func (s *ShortenerApp) GetClickStatistics(
+	ctx context.Context,
+	key string,
+	request *models.ClickStatisticsRequest,
+	userID uuid.UUID,
+) (*models.ClickStatisticsResponse, error) {
+	item, err := s.storage.Load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetClickStatistics, storage.Load failed: %w", err)
+	}
+
+	if item == nil {
+		return nil, fmt.Errorf("ShortenerApp.GetClickStatistics: %w: '%v'", ErrURLNotFound, key)
+	}
+
+	// Статистику может смотреть только владелец ссылки.
+	if item.UserID != userID {
+		return nil, fmt.Errorf("ShortenerApp.GetClickStatistics: %w: '%v'", ErrURLAccessDenied, key)
+	}
+
+	statisticsRequest, err := newClickStatisticsRequest(key, request)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetClickStatistics, newClickStatisticsRequest failed: %w", err)
+	}
+
+	statistics, err := s.storage.GetClickStatistics(ctx, statisticsRequest)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.GetClickStatistics, storage.GetClickStatistics failed: %w", err)
+	}
+
+	return &models.ClickStatisticsResponse{
+		TotalClicks:    statistics.TotalClicks,
+		UniqueVisitors: statistics.UniqueVisitors,
+		From:           statisticsRequest.From,
+		To:             statisticsRequest.To,
+		Bucket:         string(statisticsRequest.Bucket),
+		Buckets:        fillClickBuckets(statisticsRequest, statistics.Buckets),
+		TopReferrers:   toClickValueItems(statistics.TopReferrers),
+		TopUserAgents:  toClickValueItems(statistics.TopUserAgents),
+		Variants:       toClickValueItems(statistics.Variants),
+	}, nil
+}
+
+// loadOwnURL загружает ссылку и проверяет, что она принадлежит пользователю.
+func (s *ShortenerApp) loadOwnURL(ctx context.Context, key string, userID uuid.UUID) (*domain.URLItem, error) {
+	item, err := s.storage.Load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.loadOwnURL, storage.Load failed: %w", err)
+	}
+
+	if item == nil {
+		return nil, fmt.Errorf("ShortenerApp.loadOwnURL: %w: '%v'", ErrURLNotFound, key)
+	}
+
+	if item.UserID != userID {
+		return nil, fmt.Errorf("ShortenerApp.loadOwnURL: %w: '%v'", ErrURLAccessDenied, key)
+	}
+
+	return item, nil
+}
+
+// loadCollection загружает коллекцию пользователя, коллекция другого пользователя считается ненайденной.
+func (s *ShortenerApp) loadCollection(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Collection, error) {
+	collection, err := s.storage.LoadCollection(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.loadCollection, storage.LoadCollection failed: %w", err)
+	}
+
+	if collection == nil {
+		return nil, fmt.Errorf("ShortenerApp.loadCollection: %w: '%v'", ErrCollectionNotFound, id)
+	}
+
+	return collection, nil
+}
+
+// loadCampaign загружает кампанию пользователя, кампания другого пользователя считается ненайденной.
+func (s *ShortenerApp) loadCampaign(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Campaign, error) {
+	campaign, err := s.storage.LoadCampaign(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.loadCampaign, storage.LoadCampaign failed: %w", err)
+	}
+
+	if campaign == nil {
+		return nil, fmt.Errorf("ShortenerApp.loadCampaign: %w: '%v'", ErrCampaignNotFound, id)
+	}
+
+	return campaign, nil
+}
+
+// filterBatchItems отделяет элементы пакета с неверными правилами или вариантами либо ненайденной кампанией,
+// для них сразу формируется ответ.
+func (s *ShortenerApp) filterBatchItems(
+	ctx context.Context,
+	requestItems []*models.ShortenBatchRequestItem,
+	userID uuid.UUID,
+) ([]*models.ShortenBatchRequestItem, []*models.ShortenBatchResponseItem, error) {
+	responseItems := make([]*models.ShortenBatchResponseItem, 0, len(requestItems))
+	found := make(map[uuid.UUID]bool)
+	validItems := make([]*models.ShortenBatchRequestItem, 0, len(requestItems))
+	for _, item := range requestItems {
+		err := validateRedirectRules(item.Rules)
+		if err == nil {
+			err = validateURLVariants(item.Variants)
+		}
+
+		if err != nil {
+			responseItems = append(responseItems, &models.ShortenBatchResponseItem{
+				CorrelationID: item.CorrelationID,
+				Status:        models.ShortenBatchStatusInvalid,
+				Reason:        err.Error(),
+			})
+			continue
+		}
+
+		if item.Campaign == uuid.Nil {
+			validItems = append(validItems, item)
+			continue
+		}
+
+		exists, checked := found[item.Campaign]
+		if !checked {
+			campaign, err := s.storage.LoadCampaign(ctx, item.Campaign, userID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("ShortenerApp.filterBatchItems, storage.LoadCampaign failed: %w", err)
+			}
+
+			exists = campaign != nil
+			found[item.Campaign] = exists
+		}
+
+		if !exists {
+			responseItems = append(responseItems, &models.ShortenBatchResponseItem{
+				CorrelationID: item.CorrelationID,
+				Status:        models.ShortenBatchStatusInvalid,
+				Reason:        fmt.Sprintf("%v: '%v'", ErrCampaignNotFound, item.Campaign),
+			})
+			continue
+		}
+
+		validItems = append(validItems, item)
+	}
+
+	return validItems, responseItems, nil
+}
+
+// loadCollectionURLs загружает все живые ссылки коллекции пользователя в порядке создания.
+func (s *ShortenerApp) loadCollectionURLs(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]*domain.KeyOriginalURLItem, error) {
+	if _, err := s.loadCollection(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("ShortenerApp.loadCollectionURLs, loadCollection failed: %w", err)
+	}
+
+	items, err := s.storage.LoadAllByUserID(ctx, userID, &domain.UserURLsQuery{
+		Sort:       domain.UserURLsSortCreated,
+		Status:     domain.UserURLsStatusActive,
+		Collection: id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.loadCollectionURLs, storage.LoadAllByUserID failed: %w", err)
+	}
+
+	return items, nil
+}
+
+func (s *ShortenerApp) newUserURLsResponseItem(item *domain.KeyOriginalURLItem) (*models.UserURLsResponseItem, error) {
+	shortURL, err := url.JoinPath(s.configuration.BaseURL, item.URLKey)
+	if err != nil {
+		return nil, fmt.Errorf("ShortenerApp.newUserURLsResponseItem, url.JoinPath: %w", err)
+	}
+
+	return &models.UserURLsResponseItem{
+		ShortURL:       shortURL,
+		OriginalURL:    item.OriginalURL,
+		CreatedAt:      item.CreatedAt,
+		UpdatedAt:      item.UpdatedAt,
+		LastAccessedAt: item.LastAccessedAt,
+		Title:          item.Title,
+		Tags:           item.Tags,
+		Notes:          item.Notes,
+	}, nil
+}
+
+func toCollectionItem(collection *domain.Collection) *models.CollectionItem {
+	return &models.CollectionItem{
+		ID:        collection.ID,
+		Name:      collection.Name,
+		CreatedAt: collection.CreatedAt,
+		URLCount:  collection.URLCount,
+	}
+}
+
+func toCampaignItem(campaign *domain.Campaign) *models.CampaignItem {
+	parameters := maps.Clone(campaign.Parameters)
+	item := &models.CampaignItem{
+		ID:          campaign.ID,
+		Name:        campaign.Name,
+		UTMSource:   parameters[utmSource],
+		UTMMedium:   parameters[utmMedium],
+		UTMCampaign: parameters[utmCampaign],
+		CreatedAt:   campaign.CreatedAt,
+	}
+
+	delete(parameters, utmSource)
+	delete(parameters, utmMedium)
+	delete(parameters, utmCampaign)
+	if len(parameters) > 0 {
+		item.Parameters = parameters
+	}
+
+	return item
+}
+
+// newCampaignParameters собирает UTM-параметры и дополнительные параметры кампании в один набор.
+// UTM-параметры задаются только отдельными полями запроса.
+func newCampaignParameters(request *models.CampaignRequest) (map[string]string, error) {
+	parameters := make(map[string]string, len(request.Parameters)+3)
+	for name, value := range request.Parameters {
+		if name == utmSource || name == utmMedium || name == utmCampaign {
+			return nil, fmt.Errorf("%w: parameter '%v' must be set by its own field", ErrInvalidCampaign, name)
+		}
+
+		parameters[name] = value
+	}
+
+	for name, value := range map[string]string{
+		utmSource:   request.UTMSource,
+		utmMedium:   request.UTMMedium,
+		utmCampaign: request.UTMCampaign,
+	} {
+		if len(value) > 0 {
+			parameters[name] = value
+		}
+	}
+
+	return parameters, nil
+}
+
+// appendCampaignParameters добавляет к URL параметры кампании, которых в нем еще нет.
+// Параметры, заданные в самом URL, имеют приоритет.
+func appendCampaignParameters(rawURL string, parameters map[string]string) (string, error) {
+	if len(parameters) == 0 {
+		return rawURL, nil
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("appendCampaignParameters, url.Parse failed: %w", err)
+	}
+
+	query := target.Query()
+	missing := make(url.Values, len(parameters))
+	for name, value := range parameters {
+		if !query.Has(name) {
+			missing.Set(name, value)
+		}
+	}
+
+	if len(missing) == 0 {
+		return rawURL, nil
+	}
+
+	// Существующая часть запроса не перекодируется.
+	target.RawQuery = strings.Join(lo.Compact([]string{target.RawQuery, missing.Encode()}), "&")
+	return target.String(), nil
+}
+
+// normalizeTags приводит метки к нижнему регистру, сортирует и убирает пустые и повторяющиеся.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); len(tag) > 0 {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// getRedirectCacheMaxAge возвращает время, в течение которого перенаправление можно кэшировать.
+// Временные перенаправления не кэшируются, постоянные кэшируются не дольше времени жизни ссылки.
+func (s *ShortenerApp) getRedirectCacheMaxAge(item *domain.URLItem, redirectCode int, now time.Time) time.Duration {
+	// Переход по ссылке с правилами зависит от клиента и времени, а с вариантами - от посетителя.
+	if !domain.IsPermanentRedirect(redirectCode) || len(item.Rules) > 0 || len(item.Variants) > 0 {
+		return 0
+	}
+
+	maxAge := s.configuration.RedirectCacheMaxAge
+	if item.ExpiresAt != nil {
+		maxAge = min(maxAge, item.ExpiresAt.Sub(now))
+	}
+
+	return max(maxAge, 0)
+}
+
+// mergeQuery объединяет параметры URL ссылки с параметрами запроса перехода.
+func mergeQuery(target, query url.Values, mode string) url.Values {
+	for name, values := range query {
+		switch {
+		case !target.Has(name) || mode == config.QueryMergeOverride:
+			target[name] = values
+		case mode == config.QueryMergeAppend:
+			target[name] = append(target[name], values...)
+		}
+	}
+
+	return target
+}
+
+func getExpiresAt(expiresAt *time.Time, ttl int64) *time.Time {
+	if expiresAt != nil {
+		return expiresAt
+	}
+
+	if ttl > 0 {
+		return lo.ToPtr(time.Now().Add(time.Duration(ttl) * time.Second))
+	}
+
+	return nil
+}
+
+func newClickStatisticsRequest(key string, request *models.ClickStatisticsRequest) (*domain.ClickStatisticsRequest, error) {
+	statisticsRequest := &domain.ClickStatisticsRequest{
+		Key:      key,
+		To:       lo.FromPtrOr(request.To, time.Now()).UTC(),
+		Bucket:   domain.ClickBucket(lo.CoalesceOrEmpty(request.Bucket, string(domain.ClickBucketDay))),
+		TopCount: lo.CoalesceOrEmpty(request.Top, defaultClickStatisticsTopCount),
+	}
+	statisticsRequest.From = lo.FromPtrOr(request.From, statisticsRequest.To.Add(-defaultClickStatisticsInterval)).UTC()
+
+	if !statisticsRequest.From.Before(statisticsRequest.To) {
+		return nil, fmt.Errorf("%w: 'from' must be before 'to'", ErrInvalidStatisticsInterval)
+	}
+
+	// Ограничиваем размер ответа.
+	bucketsCount := 0
+	start := statisticsRequest.Bucket.Truncate(statisticsRequest.From)
+	for ; start.Before(statisticsRequest.To); start = statisticsRequest.Bucket.Next(start) {
+		bucketsCount++
+		if bucketsCount > maxClickStatisticsBuckets {
+			return nil, fmt.Errorf("%w: too many buckets, use a larger bucket", ErrInvalidStatisticsInterval)
+		}
+	}
+
+	return statisticsRequest, nil
+}
+
+func newUserURLsQuery(request *models.UserURLsRequest) (*domain.UserURLsQuery, error) {
+	query := &domain.UserURLsQuery{
+		Sort:       domain.UserURLsSort(lo.CoalesceOrEmpty(request.Sort, string(domain.UserURLsSortCreated))),
+		Descending: request.Order == "desc",
+		Status:     domain.UserURLsStatus(lo.CoalesceOrEmpty(request.Status, string(domain.UserURLsStatusAll))),
+		Search:     request.Search,
+		Tag:        normalizeTag(request.Tag),
+		Limit:      request.Limit,
+	}
+
+	// Клиенты, которые не знают о страницах, получают весь список, как до появления курсора.
+	if query.Limit == 0 && len(request.Cursor) > 0 {
+		query.Limit = defaultUserURLsLimit
+	}
+
+	if len(request.Collection) > 0 {
+		collection, err := uuid.Parse(request.Collection)
+		if err != nil {
+			return nil, fmt.Errorf("invalid collection: %w", err)
+		}
+
+		query.Collection = collection
+	}
+
+	if len(request.Cursor) > 0 {
+		cursor, err := decodeUserURLsCursor(request.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		query.After = cursor
+	}
+
+	return query, nil
+}
+
+// Курсор содержит и время создания, и ключ последнего элемента страницы,
+// поэтому подходит для любой сортировки. Для клиента курсор непрозрачный.
+func encodeUserURLsCursor(item *domain.KeyOriginalURLItem) string {
+	value := strconv.FormatInt(item.CreatedAt.UnixNano(), 10) + ":" + item.URLKey
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func decodeUserURLsCursor(cursor string) (*domain.UserURLsCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	nanoseconds, key, found := strings.Cut(string(value), ":")
+	if !found || len(key) == 0 {
+		return nil, fmt.Errorf("%w: '%v'", ErrInvalidCursor, cursor)
+	}
+
+	createdAt, err := strconv.ParseInt(nanoseconds, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return &domain.UserURLsCursor{
+		CreatedAt: time.Unix(0, createdAt).UTC(),
+		URLKey:    key,
+	}, nil
+}
+
+func fillClickBuckets(request *domain.ClickStatisticsRequest, buckets []*domain.ClickBucketCount) []*models.ClickBucketItem {
+	counts := make(map[time.Time]int, len(buckets))
+	for _, bucket := range buckets {
+		counts[bucket.Start] = bucket.Count
+	}
+
+	// Интервалы без переходов тоже возвращаем, с нулевым количеством.
+	items := []*models.ClickBucketItem{}
+	start := request.Bucket.Truncate(request.From)
+	for ; start.Before(request.To); start = request.Bucket.Next(start) {
+		items = append(items, &models.ClickBucketItem{
+			Start: start,
+			Count: counts[start],
+		})
+	}
+
+	return items
+}
+
+func toClickValueItems(values []*domain.ClickValueCount) []*models.ClickValueItem {
+	return lo.Map(values, func(value *domain.ClickValueCount, _ int) *models.ClickValueItem {
+		return &models.ClickValueItem{
+			Value: value.Value,
+			Count: value.Count,
+		}
+	})
+}
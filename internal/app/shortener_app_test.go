@@ -1,10 +1,16 @@
 package app
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -48,14 +54,34 @@ func TestShortenerApp_Init(t *testing.T) {
 				mock.AppParameters.EXPECT().Init(gomock.Any()).Return(assert.AnError)
 			},
 		},
+		{
+			name:      "WHEN canonical URLs backfill error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Init().Return(nil)
+				mock.Connection.EXPECT().Init(gomock.Any()).Return(nil)
+				mock.AppParameters.EXPECT().Init(gomock.Any()).Return(nil)
+				mock.Store.EXPECT().BackfillCanonicalURLs(gomock.Any(), gomock.Any()).Return(0, assert.AnError)
+			},
+		},
 		{
 			name: "WHEN no errors THEN ok",
 			hookBefore: func(mock *mocks.Mock) {
 				mock.Store.EXPECT().Init().Return(nil)
 				mock.Connection.EXPECT().Init(gomock.Any()).Return(nil)
 				mock.AppParameters.EXPECT().Init(gomock.Any()).Return(nil)
+				mock.Store.EXPECT().
+					BackfillCanonicalURLs(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, canonicalize func(string) string) (int, error) {
+						require.Equal(t, "http://example.com/a", canonicalize("http://Example.com:80/a"))
+						return 2, nil
+					})
+				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 				mock.AuditService.EXPECT().Init()
 				mock.DeleteURLsService.EXPECT().Init()
+				mock.ExpiredURLsService.EXPECT().Init()
+				mock.PurgeURLsService.EXPECT().Init()
+				mock.ClickService.EXPECT().Init()
 			},
 		},
 	}
@@ -71,6 +97,9 @@ func TestShortenerApp_Init(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
@@ -90,14 +119,21 @@ func TestShortenerApp_Init(t *testing.T) {
 func TestShortenerApp_Shutdown(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mock := mocks.NewMock(ctrl)
+	mock.ClickService.EXPECT().Shutdown()
+	mock.PurgeURLsService.EXPECT().Shutdown()
+	mock.ExpiredURLsService.EXPECT().Shutdown()
 	mock.DeleteURLsService.EXPECT().Shutdown()
 	mock.AuditService.EXPECT().Shutdown()
+	mock.Store.EXPECT().Shutdown()
 	mock.Connection.EXPECT().Shutdown()
 
 	shortener := NewShortenerApp(
 		mock.Connection,
 		mock.Store,
 		mock.DeleteURLsService,
+		mock.ExpiredURLsService,
+		mock.PurgeURLsService,
+		mock.ClickService,
 		mock.AuditService,
 		mock.Logger,
 		mock.AppParameters,
@@ -152,9 +188,134 @@ func TestShortenerApp_GetURL(t *testing.T) {
 					IsDeleted: false,
 				}
 				responseItem := &models.GetURLResponseItem{
-					URL:       urlItem.URL,
-					UserID:    urlItem.UserID,
-					IsDeleted: urlItem.IsDeleted,
+					URL:          urlItem.URL,
+					UserID:       urlItem.UserID,
+					IsDeleted:    urlItem.IsDeleted,
+					RedirectCode: http.StatusTemporaryRedirect,
+				}
+				mocks.Store.EXPECT().Load(gomock.Any(), args.key).Return(urlItem, nil)
+				return responseItem
+			},
+		},
+		{
+			name: "WHEN expired item loaded THEN expired item returned",
+			args: &args{
+				key: "foo",
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) *models.GetURLResponseItem {
+				urlItem := &domain.URLItem{
+					URL:       "http://localhost/bar",
+					UserID:    uuid.New(),
+					ExpiresAt: lo.ToPtr(time.Now().Add(-time.Minute)),
+				}
+				responseItem := &models.GetURLResponseItem{
+					URL:          urlItem.URL,
+					UserID:       urlItem.UserID,
+					IsExpired:    true,
+					RedirectCode: http.StatusTemporaryRedirect,
+				}
+				mocks.Store.EXPECT().Load(gomock.Any(), args.key).Return(urlItem, nil)
+				return responseItem
+			},
+		},
+		{
+			name: "WHEN item with permanent redirect loaded THEN cacheable item returned",
+			args: &args{
+				key: "foo",
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) *models.GetURLResponseItem {
+				urlItem := &domain.URLItem{
+					URL:          "http://localhost/bar",
+					UserID:       uuid.New(),
+					RedirectCode: http.StatusMovedPermanently,
+					Passthrough:  true,
+				}
+				responseItem := &models.GetURLResponseItem{
+					URL:          urlItem.URL,
+					UserID:       urlItem.UserID,
+					RedirectCode: http.StatusMovedPermanently,
+					CacheMaxAge:  24 * time.Hour,
+					Passthrough:  true,
+				}
+				mocks.Store.EXPECT().Load(gomock.Any(), args.key).Return(urlItem, nil)
+				return responseItem
+			},
+		},
+		{
+			name: "WHEN item of campaign loaded THEN missing campaign parameters appended",
+			args: &args{
+				key: "foo",
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) *models.GetURLResponseItem {
+				urlItem := &domain.URLItem{
+					URL:    "http://localhost/bar?utm_source=site&q=a%20b",
+					UserID: uuid.New(),
+					CampaignParameters: map[string]string{
+						"utm_source":   "mail",
+						"utm_medium":   "email",
+						"utm_campaign": "spring sale",
+					},
+				}
+				responseItem := &models.GetURLResponseItem{
+					URL:          "http://localhost/bar?utm_source=site&q=a%20b&utm_campaign=spring+sale&utm_medium=email",
+					UserID:       urlItem.UserID,
+					RedirectCode: http.StatusTemporaryRedirect,
+				}
+				mocks.Store.EXPECT().Load(gomock.Any(), args.key).Return(urlItem, nil)
+				return responseItem
+			},
+		},
+		{
+			name: "WHEN item with rules loaded THEN rules with campaign parameters returned",
+			args: &args{
+				key: "foo",
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) *models.GetURLResponseItem {
+				urlItem := &domain.URLItem{
+					URL:                "http://localhost/bar",
+					UserID:             uuid.New(),
+					RedirectCode:       http.StatusMovedPermanently,
+					CampaignParameters: map[string]string{"utm_source": "qr"},
+					Rules: []*domain.RedirectRule{
+						{Platform: domain.PlatformIOS, URL: "https://apps.apple.com/app/bar"},
+					},
+				}
+				responseItem := &models.GetURLResponseItem{
+					URL:          "http://localhost/bar?utm_source=qr",
+					UserID:       urlItem.UserID,
+					RedirectCode: http.StatusMovedPermanently,
+					Rules: []*models.RedirectRule{
+						{Platform: "ios", URL: "https://apps.apple.com/app/bar?utm_source=qr"},
+					},
+				}
+				mocks.Store.EXPECT().Load(gomock.Any(), args.key).Return(urlItem, nil)
+				return responseItem
+			},
+		},
+		{
+			name: "WHEN item with variants loaded THEN variants with campaign parameters returned",
+			args: &args{
+				key: "foo",
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) *models.GetURLResponseItem {
+				urlItem := &domain.URLItem{
+					URL:                "http://localhost/bar",
+					UserID:             uuid.New(),
+					RedirectCode:       http.StatusFound,
+					CampaignParameters: map[string]string{"utm_source": "qr"},
+					Variants: []*domain.URLVariant{
+						{Name: "A", URL: "http://localhost/a", Weight: 1},
+						{Name: "B", URL: "http://localhost/b", Weight: 3},
+					},
+				}
+				responseItem := &models.GetURLResponseItem{
+					URL:          "http://localhost/bar?utm_source=qr",
+					UserID:       urlItem.UserID,
+					RedirectCode: http.StatusFound,
+					Variants: []*models.URLVariant{
+						{Name: "A", URL: "http://localhost/a?utm_source=qr", Weight: 1},
+						{Name: "B", URL: "http://localhost/b?utm_source=qr", Weight: 3},
+					},
 				}
 				mocks.Store.EXPECT().Load(gomock.Any(), args.key).Return(urlItem, nil)
 				return responseItem
@@ -175,10 +336,16 @@ func TestShortenerApp_GetURL(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
-				&config.Configuration{},
+				&config.Configuration{
+					RedirectCode:        http.StatusTemporaryRedirect,
+					RedirectCacheMaxAge: 24 * time.Hour,
+				},
 			)
 
 			// Act.
@@ -198,35 +365,40 @@ func TestShortenerApp_GetURL(t *testing.T) {
 func TestShortenerApp_GetUserURLs(t *testing.T) {
 	t.Parallel()
 
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+
 	type args struct {
-		userID uuid.UUID
+		request *models.UserURLsRequest
+		userID  uuid.UUID
 	}
 
 	tests := []struct {
 		name       string
 		args       *args
 		wantError  bool
-		hookBefore func(mocks *mocks.Mock, args *args) (*config.Configuration, []*models.UserURLsResponseItem)
+		hookBefore func(mocks *mocks.Mock, args *args) (*config.Configuration, *models.UserURLsResponse)
 	}{
 		{
 			name: "WHEN storage error THEN error",
 			args: &args{
-				userID: uuid.New(),
+				request: &models.UserURLsRequest{},
+				userID:  uuid.New(),
 			},
 			wantError: true,
-			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, []*models.UserURLsResponseItem) {
-				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID).Return(nil, assert.AnError)
+			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, *models.UserURLsResponse) {
+				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID, gomock.Any()).Return(nil, assert.AnError)
 				return &config.Configuration{}, nil
 			},
 		},
 		{
 			name: "WHEN join path error THEN error",
 			args: &args{
-				userID: uuid.New(),
+				request: &models.UserURLsRequest{},
+				userID:  uuid.New(),
 			},
 			wantError: true,
-			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, []*models.UserURLsResponseItem) {
-				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID).Return([]*domain.KeyOriginalURLItem{
+			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, *models.UserURLsResponse) {
+				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID, gomock.Any()).Return([]*domain.KeyOriginalURLItem{
 					{
 						URLKey:      "foo",
 						OriginalURL: "http://foo.bar",
@@ -238,12 +410,16 @@ func TestShortenerApp_GetUserURLs(t *testing.T) {
 			},
 		},
 		{
-			name: "WHEN no errors THEN ok",
+			name: "WHEN no options THEN all items with defaults",
 			args: &args{
-				userID: uuid.New(),
+				request: &models.UserURLsRequest{},
+				userID:  uuid.New(),
 			},
-			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, []*models.UserURLsResponseItem) {
-				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID).Return([]*domain.KeyOriginalURLItem{
+			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, *models.UserURLsResponse) {
+				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID, &domain.UserURLsQuery{
+					Sort:   domain.UserURLsSortCreated,
+					Status: domain.UserURLsStatusAll,
+				}).Return([]*domain.KeyOriginalURLItem{
 					{
 						URLKey:      "foo",
 						OriginalURL: "http://foo.bar",
@@ -252,11 +428,94 @@ func TestShortenerApp_GetUserURLs(t *testing.T) {
 				configuration := &config.Configuration{
 					BaseURL: "http://localhost",
 				}
-				want := []*models.UserURLsResponseItem{
+				want := &models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{
+						{
+							ShortURL:    "http://localhost/foo",
+							OriginalURL: "http://foo.bar",
+						},
+					},
+				}
+				return configuration, want
+			},
+		},
+		{
+			name: "WHEN cursor without limit THEN page of default size",
+			args: &args{
+				request: &models.UserURLsRequest{
+					Cursor: encodeUserURLsCursor(&domain.KeyOriginalURLItem{URLKey: "bar", CreatedAt: createdAt}),
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, *models.UserURLsResponse) {
+				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID, &domain.UserURLsQuery{
+					Sort:   domain.UserURLsSortCreated,
+					Status: domain.UserURLsStatusAll,
+					After:  &domain.UserURLsCursor{CreatedAt: createdAt, URLKey: "bar"},
+					Limit:  defaultUserURLsLimit + 1,
+				}).Return([]*domain.KeyOriginalURLItem{}, nil)
+				return &config.Configuration{BaseURL: "http://localhost"}, &models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{},
+				}
+			},
+		},
+		{
+			name: "WHEN more items than limit THEN next cursor",
+			args: &args{
+				request: &models.UserURLsRequest{
+					Limit:  1,
+					Cursor: encodeUserURLsCursor(&domain.KeyOriginalURLItem{URLKey: "bar", CreatedAt: createdAt}),
+					Sort:   "key",
+					Order:  "desc",
+					Status: "active",
+					Search: "foo",
+					Tag:    " Work ",
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mocks *mocks.Mock, args *args) (*config.Configuration, *models.UserURLsResponse) {
+				mocks.Store.EXPECT().LoadAllByUserID(gomock.Any(), args.userID, &domain.UserURLsQuery{
+					Sort:       domain.UserURLsSortKey,
+					Descending: true,
+					Status:     domain.UserURLsStatusActive,
+					Search:     "foo",
+					Tag:        "work",
+					After:      &domain.UserURLsCursor{CreatedAt: createdAt, URLKey: "bar"},
+					Limit:      2,
+				}).Return([]*domain.KeyOriginalURLItem{
 					{
-						ShortURL:    "http://localhost/foo",
-						OriginalURL: "http://foo.bar",
+						URLKey:         "foo",
+						OriginalURL:    "http://foo.bar",
+						CreatedAt:      createdAt,
+						UpdatedAt:      createdAt.Add(time.Hour),
+						LastAccessedAt: lo.ToPtr(createdAt.Add(2 * time.Hour)),
+						Title:          "Foo",
+						Tags:           []string{"work"},
+						Notes:          "Bar",
+					},
+					{
+						URLKey:      "afoo",
+						OriginalURL: "http://afoo.bar",
+						CreatedAt:   createdAt,
+					},
+				}, nil)
+				configuration := &config.Configuration{
+					BaseURL: "http://localhost",
+				}
+				want := &models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{
+						{
+							ShortURL:       "http://localhost/foo",
+							OriginalURL:    "http://foo.bar",
+							CreatedAt:      createdAt,
+							UpdatedAt:      createdAt.Add(time.Hour),
+							LastAccessedAt: lo.ToPtr(createdAt.Add(2 * time.Hour)),
+							Title:          "Foo",
+							Tags:           []string{"work"},
+							Notes:          "Bar",
+						},
 					},
+					NextCursor: encodeUserURLsCursor(&domain.KeyOriginalURLItem{URLKey: "foo", CreatedAt: createdAt}),
 				}
 				return configuration, want
 			},
@@ -276,6 +535,9 @@ func TestShortenerApp_GetUserURLs(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
@@ -283,7 +545,7 @@ func TestShortenerApp_GetUserURLs(t *testing.T) {
 			)
 
 			// Act.
-			got, err := shortener.GetUserURLs(context.Background(), tt.args.userID)
+			got, err := shortener.GetUserURLs(context.Background(), tt.args.request, tt.args.userID)
 
 			// Assert.
 			require.Equal(t, want, got)
@@ -296,6 +558,40 @@ func TestShortenerApp_GetUserURLs(t *testing.T) {
 	}
 }
 
+func TestShortenerApp_GetUserURLs_InvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	shortener := NewShortenerApp(
+		mock.Connection,
+		mock.Store,
+		mock.DeleteURLsService,
+		mock.ExpiredURLsService,
+		mock.PurgeURLsService,
+		mock.ClickService,
+		mock.AuditService,
+		mock.Logger,
+		mock.AppParameters,
+		&config.Configuration{},
+	)
+
+	cursors := []string{
+		"not a cursor",
+		base64.RawURLEncoding.EncodeToString([]byte("foo")),
+		base64.RawURLEncoding.EncodeToString([]byte("x:foo")),
+	}
+	for _, cursor := range cursors {
+		// Act.
+		got, err := shortener.GetUserURLs(context.Background(), &models.UserURLsRequest{Cursor: cursor}, uuid.New())
+
+		// Assert.
+		require.Nil(t, got)
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	}
+}
+
 func TestShortenerApp_ShortenURL(t *testing.T) {
 	t.Parallel()
 
@@ -320,10 +616,77 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 			},
 			wantError: true,
 			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
-				mock.Store.EXPECT().Save(gomock.Any(), args.request.URL, args.userID).Return("", assert.AnError)
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{OriginalURL: args.request.URL, CanonicalURL: "http://foo.bar/"}, args.userID).Return("", assert.AnError)
+				return nil, nil
+			},
+		},
+		{
+			name: "WHEN rule time window is empty THEN error",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL: "http://foo.bar",
+					Rules: []*models.RedirectRule{
+						{From: lo.ToPtr(time.Now()), To: lo.ToPtr(time.Now().Add(-time.Hour)), URL: "http://foo.bar/sale"},
+					},
+				},
+				userID: uuid.New(),
+			},
+			wantError: true,
+			hookBefore: func(_ *mocks.Mock, _ *args) (*config.Configuration, *models.ShortenResponse) {
+				return nil, nil
+			},
+		},
+		{
+			name: "WHEN variant names repeat THEN error",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL: "http://foo.bar",
+					Variants: []*models.URLVariant{
+						{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+						{Name: "A", URL: "http://foo.bar/b", Weight: 1},
+					},
+				},
+				userID: uuid.New(),
+			},
+			wantError: true,
+			hookBefore: func(_ *mocks.Mock, _ *args) (*config.Configuration, *models.ShortenResponse) {
+				return nil, nil
+			},
+		},
+		{
+			name: "WHEN campaign not found THEN error",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL:      "http://foo.bar",
+					Campaign: uuid.New(),
+				},
+				userID: uuid.New(),
+			},
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
+				mock.Store.EXPECT().LoadCampaign(gomock.Any(), args.request.Campaign, args.userID).Return(nil, nil)
 				return nil, nil
 			},
 		},
+		{
+			name: "GIVEN campaign WHEN no errors THEN campaign saved with URL",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL:      "http://foo.bar",
+					Campaign: uuid.New(),
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
+				mock.Store.EXPECT().LoadCampaign(gomock.Any(), args.request.Campaign, args.userID).Return(&domain.Campaign{ID: args.request.Campaign}, nil)
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{
+					OriginalURL:  args.request.URL,
+					CanonicalURL: "http://foo.bar/",
+					CampaignID:   args.request.Campaign,
+				}, args.userID).Return("foo", nil)
+				return &config.Configuration{BaseURL: "http://localhost"}, &models.ShortenResponse{Result: "http://localhost/foo"}
+			},
+		},
 		{
 			name: "WHEN join path error THEN error",
 			args: &args{
@@ -334,7 +697,7 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 			},
 			wantError: true,
 			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
-				mock.Store.EXPECT().Save(gomock.Any(), args.request.URL, args.userID).Return("foo", nil)
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{OriginalURL: args.request.URL, CanonicalURL: "http://foo.bar/"}, args.userID).Return("foo", nil)
 				return &config.Configuration{
 					BaseURL: ":::\\::",
 				}, nil
@@ -349,7 +712,7 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 				userID: uuid.New(),
 			},
 			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
-				mock.Store.EXPECT().Save(gomock.Any(), args.request.URL, args.userID).Return("foo", nil)
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{OriginalURL: args.request.URL, CanonicalURL: "http://foo.bar/"}, args.userID).Return("foo", nil)
 				configuration := &config.Configuration{
 					BaseURL: "http://localhost",
 				}
@@ -360,6 +723,87 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 				return configuration, want
 			},
 		},
+		{
+			name: "GIVEN metadata WHEN no errors THEN metadata saved with normalized tags",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL:   "http://foo.bar",
+					Title: "Foo",
+					Tags:  []string{"Work", "docs", "work"},
+					Notes: "Bar",
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{
+					OriginalURL:  args.request.URL,
+					CanonicalURL: "http://foo.bar/",
+					Title:        "Foo",
+					Tags:         []string{"docs", "work"},
+					Notes:        "Bar",
+				}, args.userID).Return("foo", nil)
+				configuration := &config.Configuration{
+					BaseURL: "http://localhost",
+				}
+				want := &models.ShortenResponse{
+					Result: "http://localhost/foo",
+				}
+				return configuration, want
+			},
+		},
+		{
+			name: "GIVEN redirect code and passthrough WHEN no errors THEN both saved",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL:          "http://foo.bar",
+					RedirectCode: http.StatusPermanentRedirect,
+					Passthrough:  true,
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{
+					OriginalURL:  args.request.URL,
+					CanonicalURL: "http://foo.bar/",
+					RedirectCode: http.StatusPermanentRedirect,
+					Passthrough:  true,
+				}, args.userID).Return("foo", nil)
+				configuration := &config.Configuration{
+					BaseURL: "http://localhost",
+				}
+				want := &models.ShortenResponse{
+					Result: "http://localhost/foo",
+				}
+				return configuration, want
+			},
+		},
+		{
+			name: "GIVEN tracking parameters WHEN no errors THEN canonical URL without them",
+			args: &args{
+				request: &models.ShortenRequest{
+					URL: "HTTP://Foo.bar:80/a/?utm_source=x&b=2&a=1",
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, *models.ShortenResponse) {
+				saveRequest := &domain.SaveRequest{
+					OriginalURL:  args.request.URL,
+					CanonicalURL: "http://foo.bar/a?a=1&b=2",
+				}
+				mock.Store.EXPECT().Save(gomock.Any(), saveRequest, args.userID).Return("foo", nil)
+				configuration := &config.Configuration{
+					BaseURL: "http://localhost",
+					URLCanonicalization: &config.URLCanonicalizationConfiguration{
+						QueryMode:          config.URLQueryStrip,
+						TrackingParameters: "utm_*",
+					},
+				}
+				want := &models.ShortenResponse{
+					Result: "http://localhost/foo",
+				}
+				return configuration, want
+			},
+		},
 		{
 			name: "GIVEN duplicate WHEN no errors THEN ok",
 			args: &args{
@@ -372,7 +816,7 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 				err := &store.DuplicateURLError{
 					Key: "bar",
 				}
-				mock.Store.EXPECT().Save(gomock.Any(), args.request.URL, args.userID).Return("", err)
+				mock.Store.EXPECT().Save(gomock.Any(), &domain.SaveRequest{OriginalURL: args.request.URL, CanonicalURL: "http://foo.bar/"}, args.userID).Return("", err)
 				mock.Logger.EXPECT().Infof(gomock.Any(), err)
 				configuration := &config.Configuration{
 					BaseURL: "http://localhost",
@@ -398,6 +842,9 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
@@ -415,28 +862,155 @@ func TestShortenerApp_ShortenURL(t *testing.T) {
 	}
 }
 
-func TestShortenerApp_ShortenURLBatch(t *testing.T) {
+func TestShortenerApp_normalizeTags(t *testing.T) {
 	t.Parallel()
 
-	type args struct {
-		requestItems []*models.ShortenBatchRequestItem
-		userID       uuid.UUID
+	// Act-assert.
+	require.Nil(t, normalizeTags(nil))
+	require.Nil(t, normalizeTags([]string{" ", ""}))
+	require.Equal(t, []string{"bar", "foo"}, normalizeTags([]string{"Foo", " bar ", "foo"}))
+	require.Equal(t, "foo", normalizeTag(" FOO "))
+}
+
+func TestShortenerApp_getRedirectCacheMaxAge(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	now := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	shortener := &ShortenerApp{
+		configuration: &config.Configuration{RedirectCacheMaxAge: 24 * time.Hour},
 	}
 
+	// Act-assert.
+	require.Zero(t, shortener.getRedirectCacheMaxAge(&domain.URLItem{}, http.StatusFound, now))
+	require.Zero(t, shortener.getRedirectCacheMaxAge(&domain.URLItem{}, http.StatusTemporaryRedirect, now))
+	require.Equal(t, 24*time.Hour, shortener.getRedirectCacheMaxAge(&domain.URLItem{}, http.StatusMovedPermanently, now))
+	require.Equal(t, time.Hour, shortener.getRedirectCacheMaxAge(
+		&domain.URLItem{ExpiresAt: lo.ToPtr(now.Add(time.Hour))}, http.StatusPermanentRedirect, now))
+	require.Zero(t, shortener.getRedirectCacheMaxAge(
+		&domain.URLItem{ExpiresAt: lo.ToPtr(now.Add(-time.Hour))}, http.StatusPermanentRedirect, now))
+	require.Zero(t, shortener.getRedirectCacheMaxAge(
+		&domain.URLItem{Rules: []*domain.RedirectRule{{Platform: domain.PlatformIOS}}}, http.StatusMovedPermanently, now))
+	require.Zero(t, shortener.getRedirectCacheMaxAge(
+		&domain.URLItem{Variants: []*domain.URLVariant{{Name: "A", Weight: 1}}}, http.StatusMovedPermanently, now))
+}
+
+func TestShortenerApp_GetPassthroughURL(t *testing.T) {
+	t.Parallel()
+
+	const fullURL = "https://docs.example/api?lang=en"
+
 	tests := []struct {
-		name       string
-		args       *args
-		wantError  bool
-		hookBefore func(mock *mocks.Mock, args *args) (*config.Configuration, []*models.ShortenBatchResponseItem)
+		name      string
+		path      string
+		query     url.Values
+		mode      string
+		want      string
+		wantError error
 	}{
 		{
-			name: "WHEN no request items THEN do nothing",
-			args: &args{
-				requestItems: []*models.ShortenBatchRequestItem{},
-			},
-			hookBefore: func(_ *mocks.Mock, _ *args) (*config.Configuration, []*models.ShortenBatchResponseItem) {
-				return nil, []*models.ShortenBatchResponseItem{}
-			},
+			name: "WHEN no path and query THEN stored URL",
+			mode: config.QueryMergeOverride,
+			want: fullURL,
+		},
+		{
+			name: "WHEN path THEN path appended",
+			path: "v2/auth",
+			mode: config.QueryMergeOverride,
+			want: "https://docs.example/api/v2/auth?lang=en",
+		},
+		{
+			name: "WHEN path with spaces THEN path escaped",
+			path: "v2/a b",
+			mode: config.QueryMergeOverride,
+			want: "https://docs.example/api/v2/a%20b?lang=en",
+		},
+		{
+			name:      "WHEN dot segment THEN error",
+			path:      "v2/../../admin",
+			mode:      config.QueryMergeOverride,
+			wantError: ErrInvalidPassthroughPath,
+		},
+		{
+			name:  "WHEN override mode THEN request values replace stored",
+			query: url.Values{"lang": {"de"}, "x": {"1"}},
+			mode:  config.QueryMergeOverride,
+			want:  "https://docs.example/api?lang=de&x=1",
+		},
+		{
+			name:  "WHEN keep mode THEN stored values win",
+			query: url.Values{"lang": {"de"}, "x": {"1"}},
+			mode:  config.QueryMergeKeep,
+			want:  "https://docs.example/api?lang=en&x=1",
+		},
+		{
+			name:  "WHEN append mode THEN both values kept",
+			path:  "v2",
+			query: url.Values{"lang": {"de"}},
+			mode:  config.QueryMergeAppend,
+			want:  "https://docs.example/api/v2?lang=en&lang=de",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			shortener := &ShortenerApp{
+				configuration: &config.Configuration{PassthroughQueryMerge: tt.mode},
+			}
+			item := &models.GetURLResponseItem{URL: fullURL, Passthrough: true}
+
+			// Act.
+			got, err := shortener.GetPassthroughURL(item, tt.path, tt.query)
+
+			// Assert.
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_getExpiresAt(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	// Act-assert.
+	require.Nil(t, getExpiresAt(nil, 0))
+	require.Equal(t, &expiresAt, getExpiresAt(&expiresAt, 0))
+	got := getExpiresAt(nil, 60)
+	require.NotNil(t, got)
+	require.WithinDuration(t, time.Now().Add(time.Minute), *got, time.Second)
+}
+
+func TestShortenerApp_ShortenURLBatch(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		requestItems []*models.ShortenBatchRequestItem
+		userID       uuid.UUID
+	}
+
+	tests := []struct {
+		name       string
+		args       *args
+		wantError  bool
+		hookBefore func(mock *mocks.Mock, args *args) (*config.Configuration, []*models.ShortenBatchResponseItem)
+	}{
+		{
+			name: "WHEN no request items THEN do nothing",
+			args: &args{
+				requestItems: []*models.ShortenBatchRequestItem{},
+			},
+			hookBefore: func(_ *mocks.Mock, _ *args) (*config.Configuration, []*models.ShortenBatchResponseItem) {
+				return nil, []*models.ShortenBatchResponseItem{}
+			},
 		},
 		{
 			name: "WHEN storage error THEN error",
@@ -455,7 +1029,10 @@ func TestShortenerApp_ShortenURLBatch(t *testing.T) {
 					SaveBatch(gomock.Any(), []*domain.BatchRequestItem{
 						{
 							CorrelationID: args.requestItems[0].CorrelationID,
-							OriginalURL:   args.requestItems[0].OriginalURL,
+							SaveRequest: domain.SaveRequest{
+								OriginalURL:  args.requestItems[0].OriginalURL,
+								CanonicalURL: "http://foo.bar/",
+							},
 						},
 					}, args.userID).
 					Return(nil, assert.AnError)
@@ -479,13 +1056,17 @@ func TestShortenerApp_ShortenURLBatch(t *testing.T) {
 					SaveBatch(gomock.Any(), []*domain.BatchRequestItem{
 						{
 							CorrelationID: args.requestItems[0].CorrelationID,
-							OriginalURL:   args.requestItems[0].OriginalURL,
+							SaveRequest: domain.SaveRequest{
+								OriginalURL:  args.requestItems[0].OriginalURL,
+								CanonicalURL: "http://foo.bar/",
+							},
 						},
 					}, args.userID).
 					Return([]*domain.BatchResponseItem{
 						{
 							CorrelationID: args.requestItems[0].CorrelationID,
 							Key:           "foo",
+							Status:        domain.BatchItemStatusCreated,
 						},
 					}, nil)
 				return &config.Configuration{
@@ -501,21 +1082,104 @@ func TestShortenerApp_ShortenURLBatch(t *testing.T) {
 						CorrelationID: "42",
 						OriginalURL:   "http://foo.bar",
 					},
+					{
+						CorrelationID: "43",
+						OriginalURL:   "http://bar.buz",
+					},
+					{
+						CorrelationID: "44",
+						OriginalURL:   "http://buz.foo",
+						Alias:         "api",
+					},
+				},
+				userID: uuid.New(),
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, []*models.ShortenBatchResponseItem) {
+				mock.Store.EXPECT().
+					SaveBatch(gomock.Any(), gomock.Len(3), args.userID).
+					Return([]*domain.BatchResponseItem{
+						{
+							CorrelationID: "42",
+							Key:           "foo",
+							Status:        domain.BatchItemStatusCreated,
+						},
+						{
+							CorrelationID: "43",
+							Key:           "bar",
+							Status:        domain.BatchItemStatusExisting,
+						},
+						{
+							CorrelationID: "44",
+							Status:        domain.BatchItemStatusInvalid,
+							Reason:        "invalid alias",
+						},
+					}, nil)
+				configuration := &config.Configuration{
+					BaseURL: "http://localhost",
+				}
+				responseItems := []*models.ShortenBatchResponseItem{
+					{
+						CorrelationID: "42",
+						Status:        models.ShortenBatchStatusCreated,
+						ShortURL:      "http://localhost/foo",
+					},
+					{
+						CorrelationID: "43",
+						Status:        models.ShortenBatchStatusExisting,
+						ShortURL:      "http://localhost/bar",
+					},
+					{
+						CorrelationID: "44",
+						Status:        models.ShortenBatchStatusInvalid,
+						Reason:        "invalid alias",
+					},
+				}
+				return configuration, responseItems
+			},
+		},
+		{
+			name: "GIVEN unknown campaign WHEN no errors THEN its items invalid",
+			args: &args{
+				requestItems: []*models.ShortenBatchRequestItem{
+					{
+						CorrelationID: "42",
+						OriginalURL:   "http://foo.bar",
+						Campaign:      uuid.MustParse("9f1c3f4e-8f0e-4a53-9d55-2b0f4c7c1a01"),
+					},
+					{
+						CorrelationID: "43",
+						OriginalURL:   "http://bar.buz",
+						Campaign:      uuid.MustParse("9f1c3f4e-8f0e-4a53-9d55-2b0f4c7c1a02"),
+					},
+					{
+						CorrelationID: "44",
+						OriginalURL:   "http://buz.foo",
+						Campaign:      uuid.MustParse("9f1c3f4e-8f0e-4a53-9d55-2b0f4c7c1a02"),
+					},
 				},
 				userID: uuid.New(),
 			},
 			hookBefore: func(mock *mocks.Mock, args *args) (*config.Configuration, []*models.ShortenBatchResponseItem) {
+				knownID := args.requestItems[0].Campaign
+				unknownID := args.requestItems[1].Campaign
+				mock.Store.EXPECT().LoadCampaign(gomock.Any(), knownID, args.userID).Return(&domain.Campaign{ID: knownID}, nil)
+				mock.Store.EXPECT().LoadCampaign(gomock.Any(), unknownID, args.userID).Return(nil, nil)
 				mock.Store.EXPECT().
 					SaveBatch(gomock.Any(), []*domain.BatchRequestItem{
 						{
-							CorrelationID: args.requestItems[0].CorrelationID,
-							OriginalURL:   args.requestItems[0].OriginalURL,
+							CorrelationID: "42",
+							SaveRequest: domain.SaveRequest{
+								OriginalURL:  "http://foo.bar",
+								CanonicalURL: "http://foo.bar/",
+								CampaignID:   knownID,
+							},
 						},
 					}, args.userID).
 					Return([]*domain.BatchResponseItem{
 						{
-							CorrelationID: args.requestItems[0].CorrelationID,
+							CorrelationID: "42",
 							Key:           "foo",
+							Status:        domain.BatchItemStatusCreated,
 						},
 					}, nil)
 				configuration := &config.Configuration{
@@ -523,7 +1187,18 @@ func TestShortenerApp_ShortenURLBatch(t *testing.T) {
 				}
 				responseItems := []*models.ShortenBatchResponseItem{
 					{
-						CorrelationID: args.requestItems[0].CorrelationID,
+						CorrelationID: "43",
+						Status:        models.ShortenBatchStatusInvalid,
+						Reason:        "campaign not found: '9f1c3f4e-8f0e-4a53-9d55-2b0f4c7c1a02'",
+					},
+					{
+						CorrelationID: "44",
+						Status:        models.ShortenBatchStatusInvalid,
+						Reason:        "campaign not found: '9f1c3f4e-8f0e-4a53-9d55-2b0f4c7c1a02'",
+					},
+					{
+						CorrelationID: "42",
+						Status:        models.ShortenBatchStatusCreated,
 						ShortURL:      "http://localhost/foo",
 					},
 				}
@@ -544,6 +1219,9 @@ func TestShortenerApp_ShortenURLBatch(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
@@ -561,59 +1239,82 @@ func TestShortenerApp_ShortenURLBatch(t *testing.T) {
 	}
 }
 
-func TestShortenerApp_DeleteURLs(t *testing.T) {
+func TestShortenerApp_UpdateURL(t *testing.T) {
 	t.Parallel()
 
-	type argsT struct {
-		keys   []string
-		userID uuid.UUID
-	}
-
-	args := &argsT{
-		keys:   []string{"foo", "bar"},
-		userID: uuid.New(),
-	}
-
-	// Arrange.
-	ctrl := gomock.NewController(t)
-	mock := mocks.NewMock(ctrl)
-	mock.DeleteURLsService.EXPECT().Delete(&domain.DeleteURLsRequest{
-		Keys:   args.keys,
-		UserID: args.userID,
-	})
-	shortener := NewShortenerApp(
-		mock.Connection,
-		mock.Store,
-		mock.DeleteURLsService,
-		mock.AuditService,
-		mock.Logger,
-		mock.AppParameters,
-		nil,
-	)
-
-	// Act.
-	shortener.DeleteURLs(args.keys, args.userID)
-}
-
-func TestShortenerApp_CheckStore(t *testing.T) {
-	t.Parallel()
+	const key = "abc"
+	userID := uuid.New()
+	request := &models.UpdateURLRequest{URL: "http://Foo.bar"}
+	updateRequest := &domain.UpdateRequest{Key: key, OriginalURL: "http://Foo.bar", CanonicalURL: "http://foo.bar/"}
 
 	tests := []struct {
 		name       string
-		wantError  bool
-		hookBefore func(mocks *mocks.Mock)
+		want       *models.UpdateURLResponse
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
 	}{
 		{
-			name:      "WHEN store error THEN error",
-			wantError: true,
-			hookBefore: func(mocks *mocks.Mock) {
-				mocks.Store.EXPECT().CheckAvailability(gomock.Any()).Return(assert.AnError)
+			name:      "WHEN load error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(nil, assert.AnError)
 			},
 		},
 		{
-			name: "WHEN no errors THEN ok",
-			hookBefore: func(mocks *mocks.Mock) {
-				mocks.Store.EXPECT().CheckAvailability(gomock.Any()).Return(nil)
+			name:      "WHEN key not found THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(nil, nil)
+			},
+		},
+		{
+			name:      "WHEN key deleted THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID, IsDeleted: true}, nil)
+			},
+		},
+		{
+			name:      "WHEN key of another user THEN error",
+			wantError: ErrURLAccessDenied,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: uuid.New()}, nil)
+			},
+		},
+		{
+			name:      "WHEN URL already shortened THEN error",
+			wantError: ErrDuplicateURL,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().UpdateURL(gomock.Any(), updateRequest, userID).Return(0, store.NewDuplicateURLError("foo", request.URL))
+			},
+		},
+		{
+			name:      "WHEN storage error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().UpdateURL(gomock.Any(), updateRequest, userID).Return(0, assert.AnError)
+			},
+		},
+		{
+			name:      "WHEN key deleted concurrently THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().UpdateURL(gomock.Any(), updateRequest, userID).Return(0, nil)
+			},
+		},
+		{
+			name: "WHEN no errors THEN new version",
+			want: &models.UpdateURLResponse{
+				ShortURL:    "http://localhost/abc",
+				OriginalURL: "http://Foo.bar",
+				Version:     3,
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().UpdateURL(gomock.Any(), updateRequest, userID).Return(3, nil)
 			},
 		},
 	}
@@ -622,6 +1323,7 @@ func TestShortenerApp_CheckStore(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
+			// Arrange.
 			ctrl := gomock.NewController(t)
 			mock := mocks.NewMock(ctrl)
 			tt.hookBefore(mock)
@@ -629,15 +1331,22 @@ func TestShortenerApp_CheckStore(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
-				nil,
+				&config.Configuration{BaseURL: "http://localhost"},
 			)
 
-			err := shortener.CheckStore(context.Background())
-			if tt.wantError {
-				require.Error(t, err)
+			// Act.
+			response, err := shortener.UpdateURL(context.Background(), key, request, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, response)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
 			} else {
 				require.NoError(t, err)
 			}
@@ -645,30 +1354,53 @@ func TestShortenerApp_CheckStore(t *testing.T) {
 	}
 }
 
-func TestShortenerApp_GetStatistics(t *testing.T) {
+func TestShortenerApp_GetURLVersions(t *testing.T) {
 	t.Parallel()
 
+	const key = "abc"
+	userID := uuid.New()
+	replacedAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name       string
-		want       *models.Statistics
-		wantError  bool
-		hookBefore func(mocks *mocks.Mock)
+		want       []*models.URLVersionItem
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
 	}{
+		{
+			name:      "WHEN key not found THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(nil, nil)
+			},
+		},
+		{
+			name:      "WHEN key of another user THEN error",
+			wantError: ErrURLAccessDenied,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: uuid.New()}, nil)
+			},
+		},
 		{
 			name:      "WHEN storage error THEN error",
-			wantError: true,
-			hookBefore: func(mocks *mocks.Mock) {
-				mocks.Store.EXPECT().GetStatistics(gomock.Any()).Return(0, 0, assert.AnError)
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().LoadVersions(gomock.Any(), key).Return(nil, assert.AnError)
 			},
 		},
 		{
-			name: "WHEN no errors THEN ok",
-			want: &models.Statistics{
-				UrlsCount:  1,
-				UsersCount: 2,
+			name: "WHEN deleted key THEN versions",
+			want: []*models.URLVersionItem{
+				{Version: 2, OriginalURL: "http://bar.buz", ReplacedAt: replacedAt.Add(time.Hour)},
+				{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: replacedAt},
 			},
-			hookBefore: func(mocks *mocks.Mock) {
-				mocks.Store.EXPECT().GetStatistics(gomock.Any()).Return(1, 2, nil)
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID, IsDeleted: true}, nil)
+				mock.Store.EXPECT().LoadVersions(gomock.Any(), key).Return([]*domain.URLVersion{
+					{Version: 2, OriginalURL: "http://bar.buz", ReplacedAt: replacedAt.Add(time.Hour)},
+					{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: replacedAt},
+				}, nil)
 			},
 		},
 	}
@@ -685,6 +1417,9 @@ func TestShortenerApp_GetStatistics(t *testing.T) {
 				mock.Connection,
 				mock.Store,
 				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
 				mock.AuditService,
 				mock.Logger,
 				mock.AppParameters,
@@ -692,10 +1427,1097 @@ func TestShortenerApp_GetStatistics(t *testing.T) {
 			)
 
 			// Act.
-			statistics, err := shortener.GetStatistics(context.Background())
-			require.Equal(t, tt.want, statistics)
-			if tt.wantError {
-				require.Error(t, err)
+			versions, err := shortener.GetURLVersions(context.Background(), key, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, versions)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_UpdateURLVariants(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+	userID := uuid.New()
+	request := &models.UpdateURLVariantsRequest{Weights: map[string]int{"B": 0}}
+	variants := []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 1},
+	}
+	updated := []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+	}
+
+	tests := []struct {
+		name       string
+		want       []*models.URLVariant
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN key not found THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(nil, nil)
+			},
+		},
+		{
+			name:      "WHEN key deleted THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).
+					Return(&domain.URLItem{UserID: userID, IsDeleted: true, Variants: variants}, nil)
+			},
+		},
+		{
+			name:      "WHEN key of another user THEN error",
+			wantError: ErrURLAccessDenied,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: uuid.New(), Variants: variants}, nil)
+			},
+		},
+		{
+			name:      "WHEN key has no variants THEN error",
+			wantError: ErrInvalidURLVariant,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+			},
+		},
+		{
+			name:      "WHEN storage error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID, Variants: variants}, nil)
+				mock.Store.EXPECT().UpdateVariants(gomock.Any(), key, updated, userID).Return(false, assert.AnError)
+			},
+		},
+		{
+			name:      "WHEN key deleted concurrently THEN error",
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID, Variants: variants}, nil)
+				mock.Store.EXPECT().UpdateVariants(gomock.Any(), key, updated, userID).Return(false, nil)
+			},
+		},
+		{
+			name: "WHEN no errors THEN variants without campaign parameters",
+			want: []*models.URLVariant{
+				{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+				{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{
+					UserID:             userID,
+					Variants:           variants,
+					CampaignParameters: map[string]string{"utm_source": "news"},
+				}, nil)
+				mock.Store.EXPECT().UpdateVariants(gomock.Any(), key, updated, userID).Return(true, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				&config.Configuration{BaseURL: "http://localhost"},
+			)
+
+			// Act.
+			response, err := shortener.UpdateURLVariants(context.Background(), key, request, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, response)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_DeleteURLs(t *testing.T) {
+	t.Parallel()
+
+	type argsT struct {
+		keys   []string
+		userID uuid.UUID
+	}
+
+	args := &argsT{
+		keys:   []string{"foo", "bar"},
+		userID: uuid.New(),
+	}
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.DeleteURLsService.EXPECT().Delete(&domain.DeleteURLsRequest{
+		Keys:   args.keys,
+		UserID: args.userID,
+	})
+	shortener := NewShortenerApp(
+		mock.Connection,
+		mock.Store,
+		mock.DeleteURLsService,
+		mock.ExpiredURLsService,
+		mock.PurgeURLsService,
+		mock.ClickService,
+		mock.AuditService,
+		mock.Logger,
+		mock.AppParameters,
+		nil,
+	)
+
+	// Act.
+	shortener.DeleteURLs(args.keys, args.userID)
+}
+
+func TestShortenerApp_RestoreURLs(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"foo", "bar"}
+	userID := uuid.New()
+	// Restored are the URLs deleted within the grace period.
+	deletedAfter := gomock.Cond(func(deletedAfter time.Time) bool {
+		return time.Since(deletedAfter) >= time.Hour && time.Since(deletedAfter) < time.Hour+time.Minute
+	})
+
+	tests := []struct {
+		name       string
+		want       []string
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN storage error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().RestoreBatch(gomock.Any(), keys, userID, deletedAfter).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN restored keys",
+			want: []string{"foo"},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().RestoreBatch(gomock.Any(), keys, userID, deletedAfter).Return([]string{"foo"}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				&config.Configuration{DeletedURLsGracePeriod: time.Hour},
+			)
+
+			// Act.
+			restored, err := shortener.RestoreURLs(context.Background(), keys, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, restored)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_CreateCollection(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	request := &models.CollectionRequest{Name: "work"}
+
+	tests := []struct {
+		name       string
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN name is taken THEN error",
+			wantError: ErrDuplicateCollection,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveCollection(gomock.Any(), gomock.Any()).Return(store.ErrCollectionExists)
+			},
+		},
+		{
+			name:      "WHEN storage error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveCollection(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN collection",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveCollection(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, collection *domain.Collection) error {
+						require.NotEqual(t, uuid.Nil, collection.ID)
+						require.Equal(t, userID, collection.UserID)
+						require.Equal(t, "work", collection.Name)
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			collection, err := shortener.CreateCollection(context.Background(), request, userID)
+
+			// Assert.
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+				require.Nil(t, collection)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "work", collection.Name)
+				require.Zero(t, collection.URLCount)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_RenameCollection(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	userID := uuid.New()
+	request := &models.CollectionRequest{Name: "study"}
+	createdAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		want       *models.CollectionItem
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN collection not found THEN error",
+			wantError: ErrCollectionNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().RenameCollection(gomock.Any(), id, "study", userID).Return(false, nil)
+			},
+		},
+		{
+			name:      "WHEN name is taken THEN error",
+			wantError: ErrDuplicateCollection,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().RenameCollection(gomock.Any(), id, "study", userID).Return(false, store.ErrCollectionExists)
+			},
+		},
+		{
+			name: "WHEN no errors THEN renamed collection",
+			want: &models.CollectionItem{ID: id, Name: "study", CreatedAt: createdAt, URLCount: 3},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().RenameCollection(gomock.Any(), id, "study", userID).Return(true, nil)
+				mock.Store.EXPECT().LoadCollection(gomock.Any(), id, userID).Return(&domain.Collection{
+					ID:        id,
+					UserID:    userID,
+					Name:      "study",
+					CreatedAt: createdAt,
+					URLCount:  3,
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			collection, err := shortener.RenameCollection(context.Background(), id, request, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, collection)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_DeleteCollectionURLs(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	userID := uuid.New()
+	query := &domain.UserURLsQuery{
+		Sort:       domain.UserURLsSortCreated,
+		Status:     domain.UserURLsStatusActive,
+		Collection: id,
+	}
+
+	tests := []struct {
+		name       string
+		want       []string
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN collection not found THEN error",
+			wantError: ErrCollectionNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().LoadCollection(gomock.Any(), id, userID).Return(nil, nil)
+			},
+		},
+		{
+			name:      "WHEN storage error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().LoadCollection(gomock.Any(), id, userID).Return(&domain.Collection{ID: id}, nil)
+				mock.Store.EXPECT().LoadAllByUserID(gomock.Any(), userID, query).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name: "WHEN empty collection THEN nothing deleted",
+			want: []string{},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().LoadCollection(gomock.Any(), id, userID).Return(&domain.Collection{ID: id}, nil)
+				mock.Store.EXPECT().LoadAllByUserID(gomock.Any(), userID, query).Return(nil, nil)
+			},
+		},
+		{
+			name: "WHEN collection has URLs THEN URLs deleted",
+			want: []string{"foo", "bar"},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().LoadCollection(gomock.Any(), id, userID).Return(&domain.Collection{ID: id}, nil)
+				mock.Store.EXPECT().LoadAllByUserID(gomock.Any(), userID, query).Return([]*domain.KeyOriginalURLItem{
+					{URLKey: "foo"},
+					{URLKey: "bar"},
+				}, nil)
+				mock.DeleteURLsService.EXPECT().Delete(&domain.DeleteURLsRequest{
+					Keys:   []string{"foo", "bar"},
+					UserID: userID,
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			keys, err := shortener.DeleteCollectionURLs(context.Background(), id, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, keys)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_ExportCollection(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	id := uuid.New()
+	userID := uuid.New()
+	createdAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Store.EXPECT().LoadCollection(gomock.Any(), id, userID).Return(&domain.Collection{ID: id}, nil)
+	mock.Store.EXPECT().LoadAllByUserID(gomock.Any(), userID, &domain.UserURLsQuery{
+		Sort:       domain.UserURLsSortCreated,
+		Status:     domain.UserURLsStatusActive,
+		Collection: id,
+	}).Return([]*domain.KeyOriginalURLItem{
+		{URLKey: "foo", OriginalURL: "http://foo.bar", CreatedAt: createdAt, UpdatedAt: createdAt, Tags: []string{"work"}},
+	}, nil)
+	shortener := NewShortenerApp(
+		mock.Connection,
+		mock.Store,
+		mock.DeleteURLsService,
+		mock.ExpiredURLsService,
+		mock.PurgeURLsService,
+		mock.ClickService,
+		mock.AuditService,
+		mock.Logger,
+		mock.AppParameters,
+		&config.Configuration{BaseURL: "http://localhost"},
+	)
+
+	// Act.
+	items, err := shortener.ExportCollection(context.Background(), id, userID)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, []*models.UserURLsResponseItem{
+		{
+			ShortURL:    "http://localhost/foo",
+			OriginalURL: "http://foo.bar",
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			Tags:        []string{"work"},
+		},
+	}, items)
+}
+
+func TestShortenerApp_CreateCampaign(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		request    *models.CampaignRequest
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN UTM parameter among extra parameters THEN error",
+			request: &models.CampaignRequest{
+				Name:       "spring",
+				Parameters: map[string]string{"utm_source": "mail"},
+			},
+			wantError:  ErrInvalidCampaign,
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:      "WHEN name is taken THEN error",
+			request:   &models.CampaignRequest{Name: "spring"},
+			wantError: ErrDuplicateCampaign,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveCampaign(gomock.Any(), gomock.Any()).Return(store.ErrCampaignExists)
+			},
+		},
+		{
+			name:      "WHEN storage error THEN error",
+			request:   &models.CampaignRequest{Name: "spring"},
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveCampaign(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN campaign",
+			request: &models.CampaignRequest{
+				Name:        "spring",
+				UTMSource:   "mail",
+				UTMCampaign: "spring_sale",
+				Parameters:  map[string]string{"ref": "growth"},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveCampaign(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, campaign *domain.Campaign) error {
+						require.NotEqual(t, uuid.Nil, campaign.ID)
+						require.Equal(t, userID, campaign.UserID)
+						require.Equal(t, "spring", campaign.Name)
+						require.Equal(t, map[string]string{
+							"utm_source":   "mail",
+							"utm_campaign": "spring_sale",
+							"ref":          "growth",
+						}, campaign.Parameters)
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			campaign, err := shortener.CreateCampaign(context.Background(), tt.request, userID)
+
+			// Assert.
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+				require.Nil(t, campaign)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, &models.CampaignItem{
+					ID:          campaign.ID,
+					Name:        "spring",
+					UTMSource:   "mail",
+					UTMCampaign: "spring_sale",
+					Parameters:  map[string]string{"ref": "growth"},
+					CreatedAt:   campaign.CreatedAt,
+				}, campaign)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_UpdateCampaign(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	userID := uuid.New()
+	request := &models.CampaignRequest{Name: "autumn", UTMMedium: "email"}
+	updated := &domain.Campaign{
+		ID:         id,
+		UserID:     userID,
+		Name:       "autumn",
+		Parameters: map[string]string{"utm_medium": "email"},
+	}
+
+	tests := []struct {
+		name       string
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN name is taken THEN error",
+			wantError: ErrDuplicateCampaign,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().UpdateCampaign(gomock.Any(), updated).Return(false, store.ErrCampaignExists)
+			},
+		},
+		{
+			name:      "WHEN campaign not found THEN error",
+			wantError: ErrCampaignNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().UpdateCampaign(gomock.Any(), updated).Return(false, nil)
+			},
+		},
+		{
+			name: "WHEN no errors THEN updated campaign",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().UpdateCampaign(gomock.Any(), updated).Return(true, nil)
+				mock.Store.EXPECT().LoadCampaign(gomock.Any(), id, userID).Return(updated, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			campaign, err := shortener.UpdateCampaign(context.Background(), id, request, userID)
+
+			// Assert.
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+				require.Nil(t, campaign)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, &models.CampaignItem{ID: id, Name: "autumn", UTMMedium: "email"}, campaign)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_CheckStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantError  bool
+		hookBefore func(mocks *mocks.Mock)
+	}{
+		{
+			name:      "WHEN store error THEN error",
+			wantError: true,
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().CheckAvailability(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().CheckAvailability(gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			err := shortener.CheckStore(context.Background())
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_CompactStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantError  bool
+		hookBefore func(mocks *mocks.Mock)
+	}{
+		{
+			name:      "WHEN store error THEN error",
+			wantError: true,
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().Compact(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().Compact(gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			err := shortener.CompactStore(context.Background())
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_ExportStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantError  bool
+		hookBefore func(mocks *mocks.Mock)
+	}{
+		{
+			name:      "WHEN store error THEN error",
+			wantError: true,
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().Export(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().Export(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			err := shortener.ExportStore(context.Background(), &bytes.Buffer{})
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_GetStatistics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		want       *models.Statistics
+		wantError  bool
+		hookBefore func(mocks *mocks.Mock)
+	}{
+		{
+			name:      "WHEN storage error THEN error",
+			wantError: true,
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().GetStatistics(gomock.Any()).Return(0, 0, assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			want: &models.Statistics{
+				UrlsCount:  1,
+				UsersCount: 2,
+			},
+			hookBefore: func(mocks *mocks.Mock) {
+				mocks.Store.EXPECT().GetStatistics(gomock.Any()).Return(1, 2, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			statistics, err := shortener.GetStatistics(context.Background())
+			require.Equal(t, tt.want, statistics)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenerApp_GetClickStatistics(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+	userID := uuid.New()
+	from := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	tests := []struct {
+		name       string
+		request    *models.ClickStatisticsRequest
+		want       *models.ClickStatisticsResponse
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN load error THEN error",
+			request:   &models.ClickStatisticsRequest{},
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:      "WHEN key not found THEN error",
+			request:   &models.ClickStatisticsRequest{},
+			wantError: ErrURLNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(nil, nil)
+			},
+		},
+		{
+			name:      "WHEN key of another user THEN error",
+			request:   &models.ClickStatisticsRequest{},
+			wantError: ErrURLAccessDenied,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: uuid.New()}, nil)
+			},
+		},
+		{
+			name:      "WHEN from after to THEN error",
+			request:   &models.ClickStatisticsRequest{From: &to, To: &from},
+			wantError: ErrInvalidStatisticsInterval,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+			},
+		},
+		{
+			name: "WHEN too many buckets THEN error",
+			request: &models.ClickStatisticsRequest{
+				From:   lo.ToPtr(from.AddDate(-1, 0, 0)),
+				To:     &to,
+				Bucket: "hour",
+			},
+			wantError: ErrInvalidStatisticsInterval,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+			},
+		},
+		{
+			name:      "WHEN storage error THEN error",
+			request:   &models.ClickStatisticsRequest{},
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().GetClickStatistics(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, request *domain.ClickStatisticsRequest) (*domain.ClickStatistics, error) {
+						// Значения по умолчанию.
+						require.Equal(t, domain.ClickBucketDay, request.Bucket)
+						require.Equal(t, 10, request.TopCount)
+						require.Equal(t, 7*24*time.Hour, request.To.Sub(request.From))
+						return nil, assert.AnError
+					})
+			},
+		},
+		{
+			name: "WHEN no errors THEN empty buckets filled",
+			request: &models.ClickStatisticsRequest{
+				From:   &from,
+				To:     &to,
+				Bucket: "hour",
+				Top:    1,
+			},
+			want: &models.ClickStatisticsResponse{
+				TotalClicks:    3,
+				UniqueVisitors: 2,
+				From:           from,
+				To:             to,
+				Bucket:         "hour",
+				Buckets: []*models.ClickBucketItem{
+					{Start: from, Count: 2},
+					{Start: from.Add(time.Hour), Count: 0},
+					{Start: from.Add(2 * time.Hour), Count: 1},
+				},
+				TopReferrers: []*models.ClickValueItem{
+					{Value: "r", Count: 2},
+				},
+				TopUserAgents: []*models.ClickValueItem{},
+				Variants: []*models.ClickValueItem{
+					{Value: "B", Count: 2},
+					{Value: "A", Count: 1},
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().Load(gomock.Any(), key).Return(&domain.URLItem{UserID: userID}, nil)
+				mock.Store.EXPECT().
+					GetClickStatistics(gomock.Any(), &domain.ClickStatisticsRequest{
+						Key:      key,
+						From:     from,
+						To:       to,
+						Bucket:   domain.ClickBucketHour,
+						TopCount: 1,
+					}).
+					Return(&domain.ClickStatistics{
+						TotalClicks:    3,
+						UniqueVisitors: 2,
+						Buckets: []*domain.ClickBucketCount{
+							{Start: from, Count: 2},
+							{Start: from.Add(2 * time.Hour), Count: 1},
+						},
+						TopReferrers: []*domain.ClickValueCount{
+							{Value: "r", Count: 2},
+						},
+						TopUserAgents: []*domain.ClickValueCount{},
+						Variants: []*domain.ClickValueCount{
+							{Value: "B", Count: 2},
+							{Value: "A", Count: 1},
+						},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			shortener := NewShortenerApp(
+				mock.Connection,
+				mock.Store,
+				mock.DeleteURLsService,
+				mock.ExpiredURLsService,
+				mock.PurgeURLsService,
+				mock.ClickService,
+				mock.AuditService,
+				mock.Logger,
+				mock.AppParameters,
+				nil,
+			)
+
+			// Act.
+			statistics, err := shortener.GetClickStatistics(context.Background(), key, tt.request, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, statistics)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
 			} else {
 				require.NoError(t, err)
 			}
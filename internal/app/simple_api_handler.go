@@ -22,20 +22,29 @@ import (
 type SimpleAPIHandler struct {
 	shortener    App
 	auditService service.AuditService
+	clickService service.ClickService
 	logger       logger.Logger
 }
 
 // NewSimpleAPIHandler Конструктор.
-func NewSimpleAPIHandler(shortener App, auditService service.AuditService, logger logger.Logger) *SimpleAPIHandler {
+func NewSimpleAPIHandler(
+	shortener App,
+	auditService service.AuditService,
+	clickService service.ClickService,
+	logger logger.Logger,
+) *SimpleAPIHandler {
 	return &SimpleAPIHandler{
 		shortener:    shortener,
 		auditService: auditService,
+		clickService: clickService,
 		logger:       logger,
 	}
 }
 
 // HandleGetRequest обработчик GET-запроса.
-func (h *SimpleAPIHandler) HandleGetRequest(response http.ResponseWriter, request *http.Request, key string) {
+// Подходящее правило ссылки заменяет ее URL, остаток пути после ключа и параметры запроса
+// добавляются к URL только у ссылок с Passthrough.
+func (h *SimpleAPIHandler) HandleGetRequest(response http.ResponseWriter, request *http.Request, key, path string) {
 	if key == "favicon.ico" {
 		response.WriteHeader(http.StatusNotFound)
 		return
@@ -51,20 +60,52 @@ func (h *SimpleAPIHandler) HandleGetRequest(response http.ResponseWriter, reques
 		return
 	}
 
-	if item.IsDeleted {
+	if item.IsDeleted || item.IsExpired {
 		response.WriteHeader(http.StatusGone)
 		return
 	}
 
-	response.Header().Set(headers.Location, item.URL)
-	response.WriteHeader(http.StatusTemporaryRedirect)
+	// Правила важнее вариантов: посетитель, подошедший под правило, в A/B-тест не попадает.
+	now := time.Now()
+	variantName := ""
+	if !SelectRedirectURL(item, getRedirectClient(request, now)) && len(item.Variants) > 0 {
+		variantName = SelectURLVariant(item, key, getVisitorID(response, request))
+	}
+
+	location := item.URL
+	if item.Passthrough {
+		location, err = h.shortener.GetPassthroughURL(item, path, request.URL.Query())
+		if err != nil {
+			if errors.Is(err, ErrInvalidPassthroughPath) {
+				utils.HandleRequestError(response, err, h.logger)
+			} else {
+				utils.HandleServerError(response, err, h.logger)
+			}
+			return
+		}
+	} else if path != "" {
+		utils.HandleNotFound(response, fmt.Errorf("path is not allowed for key '%v'", key), h.logger)
+		return
+	}
+
+	response.Header().Set(headers.Location, location)
+	response.Header().Set(headers.CacheControl, getRedirectCacheControl(item.CacheMaxAge))
+	response.WriteHeader(item.RedirectCode)
 
 	h.auditService.AuditEvent(&domain.AuditEvent{
-		Timestamp: domain.AuditFormattedTime(time.Now()),
+		Timestamp: domain.AuditFormattedTime(now),
 		Action:    domain.AuditActionFollow,
 		UserID:    item.UserID,
-		URL:       item.URL,
+		URL:       location,
+		Variant:   variantName,
 	})
+	h.clickService.RegisterClick(&domain.Click{
+		Key:       key,
+		Timestamp: now,
+		Referrer:  request.Referer(),
+		UserAgent: request.UserAgent(),
+		Variant:   variantName,
+	}, utils.GetClientIP(request))
 }
 
 // HandlePostRequest Обработчик POST-запроса.
@@ -105,3 +146,14 @@ func (h *SimpleAPIHandler) HandlePostRequest(response http.ResponseWriter, reque
 		})
 	}
 }
+
+// getRedirectCacheControl возвращает значение заголовка Cache-Control перенаправления.
+// Перенаправления, которые нельзя кэшировать, не сохраняются ни браузером, ни промежуточными кэшами.
+func getRedirectCacheControl(maxAge time.Duration) string {
+	seconds := int64(maxAge / time.Second)
+	if seconds <= 0 {
+		return "no-store"
+	}
+
+	return fmt.Sprintf("public, max-age=%v", seconds)
+}
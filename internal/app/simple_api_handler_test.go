@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -9,12 +10,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/store"
 	"github.com/aleffnull/shortener/internal/pkg/utils"
 	"github.com/aleffnull/shortener/models"
 	"github.com/go-http-utils/headers"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -34,6 +38,8 @@ func TestSimpleAPIHandler_HandleGetRequest(t *testing.T) {
 	tests := []struct {
 		name       string
 		key        string
+		path       string
+		query      string
 		want       want
 		hookBefore func(key string, mock *mocks.Mock)
 	}{
@@ -80,26 +86,240 @@ func TestSimpleAPIHandler_HandleGetRequest(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name: "WHEN key expired THEN gone",
+			key:  "foo",
+			want: want{
+				statusCode: http.StatusGone,
+				emptyBody:  true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(&models.GetURLResponseItem{
+					IsExpired: true,
+				}, nil)
+			},
+		},
 		{
 			name: "WHEN existing key THEN redirect",
 			key:  "foo",
 			want: want{
 				statusCode: http.StatusTemporaryRedirect,
 				headers: map[string]string{
-					headers.Location: fullURL,
+					headers.Location:     fullURL,
+					headers.CacheControl: "no-store",
 				},
 				emptyBody: true,
 			},
 			hookBefore: func(key string, mock *mocks.Mock) {
 				mock.App.EXPECT().
 					GetURL(gomock.Any(), key).
-					Return(&models.GetURLResponseItem{URL: fullURL}, nil)
+					Return(&models.GetURLResponseItem{URL: fullURL, RedirectCode: http.StatusTemporaryRedirect}, nil)
 				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).DoAndReturn(func(event *domain.AuditEvent) {
 					require.LessOrEqual(t, event.Timestamp, time.Now())
 					require.Equal(t, domain.AuditActionFollow, event.Action)
 					require.Equal(t, uuid.UUID{}, event.UserID)
 					require.Equal(t, fullURL, event.URL)
 				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), "192.0.2.1").Do(func(click *domain.Click, _ string) {
+					require.Equal(t, key, click.Key)
+					require.LessOrEqual(t, click.Timestamp, time.Now())
+				})
+			},
+		},
+		{
+			name: "WHEN permanent redirect THEN cacheable redirect",
+			key:  "foo",
+			want: want{
+				statusCode: http.StatusPermanentRedirect,
+				headers: map[string]string{
+					headers.Location:     fullURL,
+					headers.CacheControl: "public, max-age=86400",
+				},
+				emptyBody: true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetURL(gomock.Any(), key).
+					Return(&models.GetURLResponseItem{
+						URL:          fullURL,
+						RedirectCode: http.StatusPermanentRedirect,
+						CacheMaxAge:  24 * time.Hour,
+					}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any())
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN rule matches THEN redirect to rule URL",
+			key:  "foo",
+			want: want{
+				statusCode: http.StatusFound,
+				headers: map[string]string{
+					headers.Location: "http://bar.buz/desktop",
+				},
+				emptyBody: true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetURL(gomock.Any(), key).
+					Return(&models.GetURLResponseItem{
+						URL:          fullURL,
+						RedirectCode: http.StatusFound,
+						Rules: []*models.RedirectRule{
+							{Platform: "ios", URL: "https://apps.apple.com/app/buz"},
+							{Platform: "desktop", URL: "http://bar.buz/desktop"},
+						},
+					}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).Do(func(event *domain.AuditEvent) {
+					require.Equal(t, "http://bar.buz/desktop", event.URL)
+				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN item has variants THEN redirect to variant and variant recorded",
+			key:  "foo",
+			want: want{
+				statusCode: http.StatusFound,
+				headers: map[string]string{
+					headers.Location: "http://bar.buz/b",
+				},
+				emptyBody: true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetURL(gomock.Any(), key).
+					Return(&models.GetURLResponseItem{
+						URL:          fullURL,
+						RedirectCode: http.StatusFound,
+						Variants: []*models.URLVariant{
+							{Name: "A", URL: "http://bar.buz/a", Weight: 0},
+							{Name: "B", URL: "http://bar.buz/b", Weight: 1},
+						},
+					}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).Do(func(event *domain.AuditEvent) {
+					require.Equal(t, "http://bar.buz/b", event.URL)
+					require.Equal(t, "B", event.Variant)
+				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any()).Do(func(click *domain.Click, _ string) {
+					require.Equal(t, "B", click.Variant)
+				})
+			},
+		},
+		{
+			name: "WHEN rule matches THEN variants skipped",
+			key:  "foo",
+			want: want{
+				statusCode: http.StatusFound,
+				headers: map[string]string{
+					headers.Location:  "http://bar.buz/desktop",
+					headers.SetCookie: "",
+				},
+				emptyBody: true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetURL(gomock.Any(), key).
+					Return(&models.GetURLResponseItem{
+						URL:          fullURL,
+						RedirectCode: http.StatusFound,
+						Rules: []*models.RedirectRule{
+							{Platform: "desktop", URL: "http://bar.buz/desktop"},
+						},
+						Variants: []*models.URLVariant{
+							{Name: "A", URL: "http://bar.buz/a", Weight: 1},
+							{Name: "B", URL: "http://bar.buz/b", Weight: 1},
+						},
+					}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).Do(func(event *domain.AuditEvent) {
+					require.Empty(t, event.Variant)
+				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN path without passthrough THEN not found",
+			key:  "foo",
+			path: "v2/auth",
+			want: want{
+				statusCode: http.StatusNotFound,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetURL(gomock.Any(), key).
+					Return(&models.GetURLResponseItem{URL: fullURL, RedirectCode: http.StatusTemporaryRedirect}, nil)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:  "WHEN query without passthrough THEN redirect to stored URL",
+			key:   "foo",
+			query: "x=1",
+			want: want{
+				statusCode: http.StatusTemporaryRedirect,
+				headers: map[string]string{
+					headers.Location: fullURL,
+				},
+				emptyBody: true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetURL(gomock.Any(), key).
+					Return(&models.GetURLResponseItem{URL: fullURL, RedirectCode: http.StatusTemporaryRedirect}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any())
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN invalid passthrough path THEN bad request",
+			key:  "foo",
+			path: "../admin",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				item := &models.GetURLResponseItem{URL: fullURL, RedirectCode: http.StatusTemporaryRedirect, Passthrough: true}
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(item, nil)
+				mock.App.EXPECT().GetPassthroughURL(item, "../admin", gomock.Any()).Return("", ErrInvalidPassthroughPath)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN passthrough app error THEN internal error",
+			key:  "foo",
+			path: "v2",
+			want: want{
+				statusCode: http.StatusInternalServerError,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				item := &models.GetURLResponseItem{URL: fullURL, RedirectCode: http.StatusTemporaryRedirect, Passthrough: true}
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(item, nil)
+				mock.App.EXPECT().GetPassthroughURL(item, "v2", gomock.Any()).Return("", assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:  "WHEN passthrough THEN redirect with path and query",
+			key:   "foo",
+			path:  "v2/auth",
+			query: "x=1",
+			want: want{
+				statusCode: http.StatusTemporaryRedirect,
+				headers: map[string]string{
+					headers.Location: fullURL + "/v2/auth?x=1",
+				},
+				emptyBody: true,
+			},
+			hookBefore: func(key string, mock *mocks.Mock) {
+				item := &models.GetURLResponseItem{URL: fullURL, RedirectCode: http.StatusTemporaryRedirect, Passthrough: true}
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(item, nil)
+				mock.App.EXPECT().
+					GetPassthroughURL(item, "v2/auth", url.Values{"x": {"1"}}).
+					Return(fullURL+"/v2/auth?x=1", nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).Do(func(event *domain.AuditEvent) {
+					require.Equal(t, fullURL+"/v2/auth?x=1", event.URL)
+				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), gomock.Any())
 			},
 		},
 	}
@@ -110,16 +330,16 @@ func TestSimpleAPIHandler_HandleGetRequest(t *testing.T) {
 
 			// Arrange.
 			recorder := httptest.NewRecorder()
-			request := httptest.NewRequest(http.MethodGet, "/foo", nil)
+			request := httptest.NewRequest(http.MethodGet, "/foo?"+tt.query, nil)
 			ctrl := gomock.NewController(t)
 			mock := mocks.NewMock(ctrl)
-			handler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.Logger)
+			handler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.ClickService, mock.Logger)
 			if tt.hookBefore != nil {
 				tt.hookBefore(tt.key, mock)
 			}
 
 			// Act.
-			handler.HandleGetRequest(recorder, request, tt.key)
+			handler.HandleGetRequest(recorder, request, tt.key, tt.path)
 
 			// Assert.
 			result := recorder.Result()
@@ -143,6 +363,60 @@ func TestSimpleAPIHandler_HandleGetRequest(t *testing.T) {
 	}
 }
 
+func TestSimpleAPIHandler_HandleGetRequest_ExpiredAndSwept(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(func(restore func(*domain.ColdStoreEntry)) error {
+		restore(&domain.ColdStoreEntry{
+			Type:      domain.ColdStoreEntryTypePut,
+			Key:       "foo",
+			Value:     "http://foo.bar",
+			ExpiresAt: lo.ToPtr(time.Now().Add(-time.Minute)),
+		})
+		return nil
+	})
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore:          &config.MemoryStoreConfiguration{},
+		ExpiredURLsRetention: time.Hour,
+	}
+	storage := store.NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, storage.Init())
+
+	// Сборщик удаляет только ссылки, истекшие раньше срока хранения.
+	count, err := storage.DeleteExpired(context.Background(), time.Now().Add(-configuration.ExpiredURLsRetention))
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	shortener := NewShortenerApp(
+		mock.Connection,
+		storage,
+		mock.DeleteURLsService,
+		mock.ExpiredURLsService,
+		mock.PurgeURLsService,
+		mock.ClickService,
+		mock.AuditService,
+		mock.Logger,
+		mock.AppParameters,
+		configuration,
+	)
+	handler := NewSimpleAPIHandler(shortener, mock.AuditService, mock.ClickService, mock.Logger)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	// Act.
+	handler.HandleGetRequest(recorder, request, "foo", "")
+
+	// Assert.
+	result := recorder.Result()
+	defer result.Body.Close()
+	require.Equal(t, http.StatusGone, result.StatusCode)
+}
+
 func TestHandler_HandlePostRequest(t *testing.T) {
 	t.Parallel()
 
@@ -246,7 +520,7 @@ func TestHandler_HandlePostRequest(t *testing.T) {
 			body := tt.hookBefore(mock)
 			recorder := httptest.NewRecorder()
 			request := httptest.NewRequest(http.MethodPost, "/", body)
-			handler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.Logger)
+			handler := NewSimpleAPIHandler(mock.App, mock.AuditService, mock.ClickService, mock.Logger)
 
 			// Act.
 			handler.HandlePostRequest(recorder, request)
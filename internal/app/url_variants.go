@@ -0,0 +1,154 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/models"
+)
+
+const (
+	visitorIDCookieName = "X-VisitorID"
+	// Посетитель остается в своем варианте, пока жива cookie.
+	visitorIDCookieMaxAge = 365 * 24 * time.Hour
+)
+
+// getVisitorID возвращает идентификатор посетителя из cookie, новому посетителю выдается новый идентификатор.
+func getVisitorID(response http.ResponseWriter, request *http.Request) string {
+	if cookie, err := request.Cookie(visitorIDCookieName); err == nil && len(cookie.Value) > 0 {
+		return cookie.Value
+	}
+
+	visitorID := uuid.NewString()
+	http.SetCookie(response, &http.Cookie{
+		Name:     visitorIDCookieName,
+		Value:    visitorID,
+		Path:     "/",
+		MaxAge:   int(visitorIDCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return visitorID
+}
+
+// SelectURLVariant подставляет в item адрес варианта, за которым закреплен посетитель, и возвращает имя варианта.
+// Возвращает пустую строку, если у ссылки нет вариантов или веса всех вариантов нулевые.
+func SelectURLVariant(item *models.GetURLResponseItem, key, visitorID string) string {
+	variant := selectURLVariant(item.Variants, key, visitorID)
+	if variant == nil {
+		return ""
+	}
+
+	item.URL = variant.URL
+	return variant.Name
+}
+
+// selectURLVariant выбирает вариант ссылки по хэшу ключа и идентификатора посетителя,
+// так что посетитель всегда попадает на один и тот же вариант, пока не изменятся веса.
+// Возвращает nil, если веса всех вариантов нулевые.
+func selectURLVariant(variants []*models.URLVariant, key, visitorID string) *models.URLVariant {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	// Ключ входит в хэш, чтобы посетитель распределялся по вариантам разных ссылок независимо.
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	hash.Write([]byte{0})
+	hash.Write([]byte(visitorID))
+	point := int(hash.Sum64() % uint64(total))
+	for _, variant := range variants {
+		if point < variant.Weight {
+			return variant
+		}
+
+		point -= variant.Weight
+	}
+
+	return nil
+}
+
+// validateURLVariants проверяет то, что не проверяется тегами модели: имена вариантов не должны повторяться.
+func validateURLVariants(variants []*models.URLVariant) error {
+	names := make(map[string]struct{}, len(variants))
+	for _, variant := range variants {
+		if _, exists := names[variant.Name]; exists {
+			return fmt.Errorf("%w: duplicate name '%v'", ErrInvalidURLVariant, variant.Name)
+		}
+
+		names[variant.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// applyURLVariantWeights возвращает варианты с новыми весами, сохраненные варианты не меняются.
+func applyURLVariantWeights(variants []*domain.URLVariant, weights map[string]int) ([]*domain.URLVariant, error) {
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("%w: URL has no variants", ErrInvalidURLVariant)
+	}
+
+	for name := range weights {
+		if !lo.ContainsBy(variants, func(variant *domain.URLVariant) bool { return variant.Name == name }) {
+			return nil, fmt.Errorf("%w: unknown name '%v'", ErrInvalidURLVariant, name)
+		}
+	}
+
+	return lo.Map(variants, func(variant *domain.URLVariant, _ int) *domain.URLVariant {
+		weight, ok := weights[variant.Name]
+		return &domain.URLVariant{
+			Name:   variant.Name,
+			URL:    variant.URL,
+			Weight: lo.Ternary(ok, weight, variant.Weight),
+		}
+	}), nil
+}
+
+func toDomainURLVariants(variants []*models.URLVariant) []*domain.URLVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	return lo.Map(variants, func(variant *models.URLVariant, _ int) *domain.URLVariant {
+		return &domain.URLVariant{
+			Name:   variant.Name,
+			URL:    variant.URL,
+			Weight: variant.Weight,
+		}
+	})
+}
+
+// toURLVariants добавляет к адресам вариантов параметры кампании так же, как к URL ссылки.
+func toURLVariants(variants []*domain.URLVariant, campaignParameters map[string]string) ([]*models.URLVariant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+
+	result := make([]*models.URLVariant, 0, len(variants))
+	for _, variant := range variants {
+		variantURL, err := appendCampaignParameters(variant.URL, campaignParameters)
+		if err != nil {
+			return nil, fmt.Errorf("toURLVariants, appendCampaignParameters failed: %w", err)
+		}
+
+		result = append(result, &models.URLVariant{
+			Name:   variant.Name,
+			URL:    variantURL,
+			Weight: variant.Weight,
+		})
+	}
+
+	return result, nil
+}
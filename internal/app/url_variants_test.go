@@ -0,0 +1,143 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/models"
+)
+
+func TestURLVariants_getVisitorID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WHEN cookie is set THEN cookie value", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange.
+		request := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		request.AddCookie(&http.Cookie{Name: visitorIDCookieName, Value: "visitor"})
+		recorder := httptest.NewRecorder()
+
+		// Act.
+		visitorID := getVisitorID(recorder, request)
+
+		// Assert.
+		require.Equal(t, "visitor", visitorID)
+		require.Empty(t, recorder.Result().Cookies())
+	})
+
+	t.Run("WHEN no cookie THEN new visitor ID is set", func(t *testing.T) {
+		t.Parallel()
+
+		// Arrange.
+		request := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		recorder := httptest.NewRecorder()
+
+		// Act.
+		visitorID := getVisitorID(recorder, request)
+
+		// Assert.
+		require.NotEmpty(t, visitorID)
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, visitorIDCookieName, cookies[0].Name)
+		require.Equal(t, visitorID, cookies[0].Value)
+		require.Equal(t, "/", cookies[0].Path)
+		require.True(t, cookies[0].HttpOnly)
+	})
+}
+
+func TestURLVariants_selectURLVariant(t *testing.T) {
+	t.Parallel()
+
+	variants := []*models.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "off", URL: "http://foo.bar/off", Weight: 0},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 3},
+	}
+
+	// Act-assert.
+	require.Nil(t, selectURLVariant([]*models.URLVariant{{Name: "A", URL: "http://foo.bar/a"}}, "foo", "visitor"))
+
+	// Посетитель всегда попадает на один и тот же вариант.
+	variant := selectURLVariant(variants, "foo", "visitor")
+	require.NotNil(t, variant)
+	require.Same(t, variant, selectURLVariant(variants, "foo", "visitor"))
+
+	// Вариант с нулевым весом не выбирается, остальные делят посетителей по весам.
+	counts := make(map[string]int)
+	for i := range 4000 {
+		counts[selectURLVariant(variants, "foo", strconv.Itoa(i)).Name]++
+	}
+	require.Zero(t, counts["off"])
+	require.InDelta(t, 1000, counts["A"], 150)
+	require.InDelta(t, 3000, counts["B"], 150)
+}
+
+func TestURLVariants_SelectURLVariant(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	item := &models.GetURLResponseItem{
+		URL: "http://foo.bar",
+		Variants: []*models.URLVariant{
+			{Name: "A", URL: "http://foo.bar/a"},
+			{Name: "B", URL: "http://foo.bar/b", Weight: 1},
+		},
+	}
+	noVariants := &models.GetURLResponseItem{URL: "http://foo.bar"}
+
+	// Act-assert.
+	require.Equal(t, "B", SelectURLVariant(item, "foo", "visitor"))
+	require.Equal(t, "http://foo.bar/b", item.URL)
+	require.Empty(t, SelectURLVariant(noVariants, "foo", "visitor"))
+	require.Equal(t, "http://foo.bar", noVariants.URL)
+}
+
+func TestURLVariants_validateURLVariants(t *testing.T) {
+	t.Parallel()
+
+	// Act-assert.
+	require.NoError(t, validateURLVariants([]*models.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a"},
+		{Name: "B", URL: "http://foo.bar/b"},
+	}))
+	require.ErrorIs(
+		t,
+		validateURLVariants([]*models.URLVariant{
+			{Name: "A", URL: "http://foo.bar/a"},
+			{Name: "A", URL: "http://foo.bar/b"},
+		}),
+		ErrInvalidURLVariant,
+	)
+}
+
+func TestURLVariants_applyURLVariantWeights(t *testing.T) {
+	t.Parallel()
+
+	variants := []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 1},
+	}
+
+	// Act.
+	updated, err := applyURLVariantWeights(variants, map[string]int{"B": 0})
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+	}, updated)
+	require.Equal(t, 1, variants[1].Weight)
+
+	_, err = applyURLVariantWeights(variants, map[string]int{"C": 1})
+	require.ErrorIs(t, err, ErrInvalidURLVariant)
+	_, err = applyURLVariantWeights(nil, map[string]int{"A": 1})
+	require.ErrorIs(t, err, ErrInvalidURLVariant)
+}
@@ -1,49 +1,83 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/middleware"
 	"github.com/aleffnull/shortener/internal/pkg/logger"
 	"github.com/aleffnull/shortener/internal/pkg/utils"
+	"github.com/aleffnull/shortener/internal/service"
+	"github.com/aleffnull/shortener/models"
 	"github.com/go-http-utils/headers"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/ldez/mimetype"
 )
 
+const nextCursorHeader = "X-Next-Cursor"
+
 // UserHandler структура обработчиков запросов, относящихся к пользователю.
 type UserHandler struct {
-	shortener App
-	logger    logger.Logger
+	shortener    App
+	auditService service.AuditService
+	logger       logger.Logger
 }
 
 // NewUserHandler Конструктор.
-func NewUserHandler(shortener App, logger logger.Logger) *UserHandler {
+func NewUserHandler(shortener App, auditService service.AuditService, logger logger.Logger) *UserHandler {
 	return &UserHandler{
-		shortener: shortener,
-		logger:    logger,
+		shortener:    shortener,
+		auditService: auditService,
+		logger:       logger,
 	}
 }
 
-// HandleGetUserURLsRequest обработчик запроса получения всех URL пользователя.
+// HandleGetUserURLsRequest обработчик запроса получения страницы URL пользователя.
+// Курсор следующей страницы возвращается в заголовке X-Next-Cursor.
 func (h *UserHandler) HandleGetUserURLsRequest(response http.ResponseWriter, request *http.Request) {
+	urlsRequest, err := parseUserURLsRequest(request)
+	if err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err = validate.Struct(urlsRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
 	ctx := request.Context()
 	userID := middleware.GetUserIDFromContext(ctx)
-	items, err := h.shortener.GetUserURLs(ctx, userID)
+	urls, err := h.shortener.GetUserURLs(ctx, urlsRequest, userID)
 	if err != nil {
-		utils.HandleServerError(response, err, h.logger)
+		if errors.Is(err, ErrInvalidCursor) {
+			utils.HandleRequestError(response, err, h.logger)
+		} else {
+			utils.HandleServerError(response, err, h.logger)
+		}
 		return
 	}
 
-	if len(items) == 0 {
+	if len(urls.Items) == 0 {
 		response.WriteHeader(http.StatusNoContent)
 		return
 	}
 
+	if len(urls.NextCursor) > 0 {
+		response.Header().Set(nextCursorHeader, urls.NextCursor)
+	}
 	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
 	response.WriteHeader(http.StatusOK)
 
-	if err = json.NewEncoder(response).Encode(items); err != nil {
+	if err = json.NewEncoder(response).Encode(urls.Items); err != nil {
 		utils.HandleServerError(response, err, h.logger)
 		return
 	}
@@ -63,3 +97,590 @@ func (h *UserHandler) HandleBatchDeleteRequest(response http.ResponseWriter, req
 
 	response.WriteHeader(http.StatusAccepted)
 }
+
+// HandleBatchRestoreRequest обработчик запроса пакетного восстановления удаленных ссылок.
+// Возвращает ключи восстановленных ссылок.
+func (h *UserHandler) HandleBatchRestoreRequest(response http.ResponseWriter, request *http.Request) {
+	var keys []string
+	if err := json.NewDecoder(request.Body).Decode(&keys); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	restored, err := h.shortener.RestoreURLs(ctx, keys, userID)
+	if err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+
+	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	response.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(response).Encode(restored); err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+}
+
+// HandleUpdateURLRequest обработчик запроса изменения адреса, на который ведет короткая ссылка.
+// Предыдущий адрес сохраняется в истории версий.
+func (h *UserHandler) HandleUpdateURLRequest(response http.ResponseWriter, request *http.Request, key string) {
+	var updateRequest models.UpdateURLRequest
+	if err := json.NewDecoder(request.Body).Decode(&updateRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(updateRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	updateResponse, err := h.shortener.UpdateURL(ctx, key, &updateRequest, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrURLNotFound):
+			utils.HandleNotFound(response, err, h.logger)
+		case errors.Is(err, ErrURLAccessDenied):
+			utils.HandleForbidden(response, err.Error(), h.logger)
+		case errors.Is(err, ErrDuplicateURL):
+			utils.HandleConflict(response, err, h.logger)
+		default:
+			utils.HandleServerError(response, err, h.logger)
+		}
+		return
+	}
+
+	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	response.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(response).Encode(updateResponse); err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+
+	h.auditService.AuditEvent(&domain.AuditEvent{
+		Timestamp: domain.AuditFormattedTime(time.Now()),
+		Action:    domain.AuditActionUpdate,
+		UserID:    userID,
+		URL:       updateRequest.URL,
+	})
+}
+
+// HandleGetURLVersionsRequest обработчик запроса истории адресов короткой ссылки, последние версии первыми.
+func (h *UserHandler) HandleGetURLVersionsRequest(response http.ResponseWriter, request *http.Request, key string) {
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	versions, err := h.shortener.GetURLVersions(ctx, key, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrURLNotFound):
+			utils.HandleNotFound(response, err, h.logger)
+		case errors.Is(err, ErrURLAccessDenied):
+			utils.HandleForbidden(response, err.Error(), h.logger)
+		default:
+			utils.HandleServerError(response, err, h.logger)
+		}
+		return
+	}
+
+	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	response.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(response).Encode(versions); err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+}
+
+// HandleUpdateURLVariantsRequest обработчик запроса изменения весов вариантов ссылки, короткая ссылка остается прежней.
+func (h *UserHandler) HandleUpdateURLVariantsRequest(response http.ResponseWriter, request *http.Request, key string) {
+	var updateRequest models.UpdateURLVariantsRequest
+	if err := json.NewDecoder(request.Body).Decode(&updateRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(updateRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	ctx := request.Context()
+	variants, err := h.shortener.UpdateURLVariants(ctx, key, &updateRequest, middleware.GetUserIDFromContext(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrURLNotFound):
+			utils.HandleNotFound(response, err, h.logger)
+		case errors.Is(err, ErrURLAccessDenied):
+			utils.HandleForbidden(response, err.Error(), h.logger)
+		case errors.Is(err, ErrInvalidURLVariant):
+			utils.HandleRequestError(response, err, h.logger)
+		default:
+			utils.HandleServerError(response, err, h.logger)
+		}
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, variants)
+}
+
+// HandleGetURLStatisticsRequest обработчик запроса статистики переходов по короткой ссылке.
+func (h *UserHandler) HandleGetURLStatisticsRequest(response http.ResponseWriter, request *http.Request, key string) {
+	statisticsRequest, err := parseClickStatisticsRequest(request)
+	if err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err = validate.Struct(statisticsRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	statistics, err := h.shortener.GetClickStatistics(ctx, key, statisticsRequest, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrURLNotFound):
+			utils.HandleNotFound(response, err, h.logger)
+		case errors.Is(err, ErrURLAccessDenied):
+			utils.HandleForbidden(response, err.Error(), h.logger)
+		case errors.Is(err, ErrInvalidStatisticsInterval):
+			utils.HandleRequestError(response, err, h.logger)
+		default:
+			utils.HandleServerError(response, err, h.logger)
+		}
+		return
+	}
+
+	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	response.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(response).Encode(statistics); err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+}
+
+// HandleCreateCollectionRequest обработчик запроса создания коллекции ссылок.
+func (h *UserHandler) HandleCreateCollectionRequest(response http.ResponseWriter, request *http.Request) {
+	collectionRequest, ok := h.decodeCollectionRequest(response, request)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := h.shortener.CreateCollection(ctx, collectionRequest, userID)
+	if err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusCreated, collection)
+}
+
+// HandleGetCollectionsRequest обработчик запроса коллекций пользователя, упорядоченных по имени.
+func (h *UserHandler) HandleGetCollectionsRequest(response http.ResponseWriter, request *http.Request) {
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	collections, err := h.shortener.GetCollections(ctx, userID)
+	if err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+
+	if len(collections) == 0 {
+		response.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, collections)
+}
+
+// HandleGetCollectionRequest обработчик запроса коллекции пользователя.
+func (h *UserHandler) HandleGetCollectionRequest(response http.ResponseWriter, request *http.Request, id string) {
+	collectionID, ok := h.parseCollectionID(response, id)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := h.shortener.GetCollection(ctx, collectionID, userID)
+	if err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, collection)
+}
+
+// HandleRenameCollectionRequest обработчик запроса переименования коллекции.
+func (h *UserHandler) HandleRenameCollectionRequest(response http.ResponseWriter, request *http.Request, id string) {
+	collectionID, ok := h.parseCollectionID(response, id)
+	if !ok {
+		return
+	}
+
+	collectionRequest, ok := h.decodeCollectionRequest(response, request)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := h.shortener.RenameCollection(ctx, collectionID, collectionRequest, userID)
+	if err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, collection)
+}
+
+// HandleDeleteCollectionRequest обработчик запроса удаления коллекции, ссылки коллекции остаются.
+func (h *UserHandler) HandleDeleteCollectionRequest(response http.ResponseWriter, request *http.Request, id string) {
+	collectionID, ok := h.parseCollectionID(response, id)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	if err := h.shortener.DeleteCollection(ctx, collectionID, userID); err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+// HandleAddCollectionURLsRequest обработчик запроса добавления ссылок в коллекцию.
+// Ключи чужих и несуществующих ссылок пропускаются.
+func (h *UserHandler) HandleAddCollectionURLsRequest(response http.ResponseWriter, request *http.Request, id string) {
+	h.handleCollectionURLsRequest(response, request, id, h.shortener.AddCollectionURLs)
+}
+
+// HandleRemoveCollectionURLsRequest обработчик запроса исключения ссылок из коллекции.
+func (h *UserHandler) HandleRemoveCollectionURLsRequest(response http.ResponseWriter, request *http.Request, id string) {
+	h.handleCollectionURLsRequest(response, request, id, h.shortener.RemoveCollectionURLs)
+}
+
+// HandleDeleteCollectionURLsRequest обработчик запроса удаления всех ссылок коллекции.
+// Ссылки удаляются асинхронно, как при пакетном удалении, в ответе ключи удаляемых ссылок.
+func (h *UserHandler) HandleDeleteCollectionURLsRequest(response http.ResponseWriter, request *http.Request, id string) {
+	collectionID, ok := h.parseCollectionID(response, id)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	keys, err := h.shortener.DeleteCollectionURLs(ctx, collectionID, userID)
+	if err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusAccepted, keys)
+}
+
+// HandleExportCollectionRequest обработчик запроса выгрузки всех живых ссылок коллекции файлом JSON.
+func (h *UserHandler) HandleExportCollectionRequest(response http.ResponseWriter, request *http.Request, id string) {
+	collectionID, ok := h.parseCollectionID(response, id)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	items, err := h.shortener.ExportCollection(ctx, collectionID, userID)
+	if err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	response.Header().Set(headers.ContentDisposition, fmt.Sprintf(`attachment; filename="collection-%v.json"`, collectionID))
+	h.writeJSON(response, http.StatusOK, items)
+}
+
+// HandleCreateCampaignRequest обработчик запроса создания кампании.
+func (h *UserHandler) HandleCreateCampaignRequest(response http.ResponseWriter, request *http.Request) {
+	campaignRequest, ok := h.decodeCampaignRequest(response, request)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	campaign, err := h.shortener.CreateCampaign(ctx, campaignRequest, userID)
+	if err != nil {
+		h.handleCampaignError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusCreated, campaign)
+}
+
+// HandleGetCampaignsRequest обработчик запроса кампаний пользователя, упорядоченных по имени.
+func (h *UserHandler) HandleGetCampaignsRequest(response http.ResponseWriter, request *http.Request) {
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	campaigns, err := h.shortener.GetCampaigns(ctx, userID)
+	if err != nil {
+		utils.HandleServerError(response, err, h.logger)
+		return
+	}
+
+	if len(campaigns) == 0 {
+		response.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, campaigns)
+}
+
+// HandleGetCampaignRequest обработчик запроса кампании пользователя.
+func (h *UserHandler) HandleGetCampaignRequest(response http.ResponseWriter, request *http.Request, id string) {
+	campaignID, ok := h.parseCampaignID(response, id)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	campaign, err := h.shortener.GetCampaign(ctx, campaignID, userID)
+	if err != nil {
+		h.handleCampaignError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, campaign)
+}
+
+// HandleUpdateCampaignRequest обработчик запроса изменения кампании, имя и параметры заменяются целиком.
+func (h *UserHandler) HandleUpdateCampaignRequest(response http.ResponseWriter, request *http.Request, id string) {
+	campaignID, ok := h.parseCampaignID(response, id)
+	if !ok {
+		return
+	}
+
+	campaignRequest, ok := h.decodeCampaignRequest(response, request)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	campaign, err := h.shortener.UpdateCampaign(ctx, campaignID, campaignRequest, userID)
+	if err != nil {
+		h.handleCampaignError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, campaign)
+}
+
+// HandleDeleteCampaignRequest обработчик запроса удаления кампании, ссылки кампании остаются.
+func (h *UserHandler) HandleDeleteCampaignRequest(response http.ResponseWriter, request *http.Request, id string) {
+	campaignID, ok := h.parseCampaignID(response, id)
+	if !ok {
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	if err := h.shortener.DeleteCampaign(ctx, campaignID, userID); err != nil {
+		h.handleCampaignError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) handleCollectionURLsRequest(
+	response http.ResponseWriter,
+	request *http.Request,
+	id string,
+	action func(context.Context, uuid.UUID, []string, uuid.UUID) (*models.CollectionItem, error),
+) {
+	collectionID, ok := h.parseCollectionID(response, id)
+	if !ok {
+		return
+	}
+
+	var keys []string
+	if err := json.NewDecoder(request.Body).Decode(&keys); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return
+	}
+
+	ctx := request.Context()
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := action(ctx, collectionID, keys, userID)
+	if err != nil {
+		h.handleCollectionError(response, err)
+		return
+	}
+
+	h.writeJSON(response, http.StatusOK, collection)
+}
+
+func (h *UserHandler) decodeCollectionRequest(response http.ResponseWriter, request *http.Request) (*models.CollectionRequest, bool) {
+	var collectionRequest models.CollectionRequest
+	if err := json.NewDecoder(request.Body).Decode(&collectionRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return nil, false
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(collectionRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return nil, false
+	}
+
+	return &collectionRequest, true
+}
+
+func (h *UserHandler) parseCollectionID(response http.ResponseWriter, id string) (uuid.UUID, bool) {
+	collectionID, err := uuid.Parse(id)
+	if err != nil {
+		utils.HandleRequestError(response, fmt.Errorf("invalid collection ID: %w", err), h.logger)
+		return uuid.Nil, false
+	}
+
+	return collectionID, true
+}
+
+func (h *UserHandler) handleCollectionError(response http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrCollectionNotFound):
+		utils.HandleNotFound(response, err, h.logger)
+	case errors.Is(err, ErrDuplicateCollection):
+		utils.HandleConflict(response, err, h.logger)
+	default:
+		utils.HandleServerError(response, err, h.logger)
+	}
+}
+
+func (h *UserHandler) decodeCampaignRequest(response http.ResponseWriter, request *http.Request) (*models.CampaignRequest, bool) {
+	var campaignRequest models.CampaignRequest
+	if err := json.NewDecoder(request.Body).Decode(&campaignRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return nil, false
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(campaignRequest); err != nil {
+		utils.HandleRequestError(response, err, h.logger)
+		return nil, false
+	}
+
+	return &campaignRequest, true
+}
+
+func (h *UserHandler) parseCampaignID(response http.ResponseWriter, id string) (uuid.UUID, bool) {
+	campaignID, err := uuid.Parse(id)
+	if err != nil {
+		utils.HandleRequestError(response, fmt.Errorf("invalid campaign ID: %w", err), h.logger)
+		return uuid.Nil, false
+	}
+
+	return campaignID, true
+}
+
+func (h *UserHandler) handleCampaignError(response http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrCampaignNotFound):
+		utils.HandleNotFound(response, err, h.logger)
+	case errors.Is(err, ErrDuplicateCampaign):
+		utils.HandleConflict(response, err, h.logger)
+	case errors.Is(err, ErrInvalidCampaign):
+		utils.HandleRequestError(response, err, h.logger)
+	default:
+		utils.HandleServerError(response, err, h.logger)
+	}
+}
+
+func (h *UserHandler) writeJSON(response http.ResponseWriter, statusCode int, value any) {
+	response.Header().Set(headers.ContentType, mimetype.ApplicationJSON)
+	response.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(response).Encode(value); err != nil {
+		utils.HandleServerError(response, err, h.logger)
+	}
+}
+
+func parseUserURLsRequest(request *http.Request) (*models.UserURLsRequest, error) {
+	query := request.URL.Query()
+	urlsRequest := &models.UserURLsRequest{
+		Cursor:     query.Get("cursor"),
+		Sort:       query.Get("sort"),
+		Order:      query.Get("order"),
+		Status:     query.Get("status"),
+		Search:     query.Get("search"),
+		Tag:        query.Get("tag"),
+		Collection: query.Get("collection"),
+	}
+
+	if value := query.Get("limit"); len(value) > 0 {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'limit' parameter: %w", err)
+		}
+
+		urlsRequest.Limit = limit
+	}
+
+	return urlsRequest, nil
+}
+
+func parseClickStatisticsRequest(request *http.Request) (*models.ClickStatisticsRequest, error) {
+	query := request.URL.Query()
+	statisticsRequest := &models.ClickStatisticsRequest{
+		Bucket: query.Get("bucket"),
+	}
+
+	var err error
+	if statisticsRequest.From, err = parseTimeParameter(query.Get("from")); err != nil {
+		return nil, fmt.Errorf("invalid 'from' parameter: %w", err)
+	}
+
+	if statisticsRequest.To, err = parseTimeParameter(query.Get("to")); err != nil {
+		return nil, fmt.Errorf("invalid 'to' parameter: %w", err)
+	}
+
+	if value := query.Get("top"); len(value) > 0 {
+		top, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'top' parameter: %w", err)
+		}
+
+		statisticsRequest.Top = top
+	}
+
+	return statisticsRequest, nil
+}
+
+func parseTimeParameter(value string) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
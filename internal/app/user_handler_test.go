@@ -2,15 +2,21 @@ package app
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
 	"github.com/aleffnull/shortener/internal/pkg/utils"
 	"github.com/aleffnull/shortener/models"
+	"github.com/go-http-utils/headers"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -25,6 +31,7 @@ func TestUserHandler_HandleGetUserURLsRequest(t *testing.T) {
 	)
 
 	type args struct {
+		target         string
 		responseWriter http.ResponseWriter
 	}
 
@@ -48,7 +55,47 @@ func TestUserHandler_HandleGetUserURLsRequest(t *testing.T) {
 				statusCode: http.StatusInternalServerError,
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN invalid limit THEN bad request",
+			args: args{
+				target:         "/api/user/urls?limit=foo",
+				responseWriter: httptest.NewRecorder(),
+			},
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN invalid sort THEN bad request",
+			args: args{
+				target:         "/api/user/urls?sort=foo",
+				responseWriter: httptest.NewRecorder(),
+			},
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN invalid cursor THEN bad request",
+			args: args{
+				target:         "/api/user/urls?cursor=foo",
+				responseWriter: httptest.NewRecorder(),
+			},
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, ErrInvalidCursor)
 				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
 			},
 		},
@@ -61,7 +108,9 @@ func TestUserHandler_HandleGetUserURLsRequest(t *testing.T) {
 				statusCode: http.StatusNoContent,
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any()).Return([]*models.UserURLsResponseItem{}, nil)
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{},
+				}, nil)
 			},
 		},
 		{
@@ -73,10 +122,12 @@ func TestUserHandler_HandleGetUserURLsRequest(t *testing.T) {
 				statusCode: http.StatusInternalServerError,
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any()).Return([]*models.UserURLsResponseItem{
-					{
-						ShortURL:    shortURL,
-						OriginalURL: fullURL,
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{
+						{
+							ShortURL:    shortURL,
+							OriginalURL: fullURL,
+						},
 					},
 				}, nil)
 				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
@@ -85,20 +136,35 @@ func TestUserHandler_HandleGetUserURLsRequest(t *testing.T) {
 		{
 			name: "WHEN no errors THEN ok",
 			args: args{
+				target:         "/api/user/urls?limit=1&cursor=abc&sort=key&order=desc&status=active&search=foo&tag=work",
 				responseWriter: httptest.NewRecorder(),
 			},
 			want: want{
 				statusCode: http.StatusOK,
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any()).Return([]*models.UserURLsResponseItem{
-					{
-						ShortURL:    shortURL,
-						OriginalURL: fullURL,
+				request := &models.UserURLsRequest{
+					Limit:  1,
+					Cursor: "abc",
+					Sort:   "key",
+					Order:  "desc",
+					Status: "active",
+					Search: "foo",
+					Tag:    "work",
+				}
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), request, gomock.Any()).Return(&models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{
+						{
+							ShortURL:    shortURL,
+							OriginalURL: fullURL,
+						},
 					},
+					NextCursor: "def",
 				}, nil)
 			},
 			hookAfter: func(result *http.Response) {
+				require.Equal(t, "def", result.Header.Get("X-Next-Cursor"))
+
 				var items []*models.UserURLsResponseItem
 				err := json.NewDecoder(result.Body).Decode(&items)
 				require.NoError(t, err)
@@ -119,8 +185,8 @@ func TestUserHandler_HandleGetUserURLsRequest(t *testing.T) {
 			mock := mocks.NewMock(ctrl)
 			tt.hookBefore(mock)
 
-			handler := NewUserHandler(mock.App, mock.Logger)
-			request := httptest.NewRequest(http.MethodGet, "/api/user/urls", nil)
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodGet, cmp.Or(tt.args.target, "/api/user/urls"), nil)
 
 			// Act.
 			handler.HandleGetUserURLsRequest(tt.args.responseWriter, request)
@@ -192,7 +258,7 @@ func TestUserHandler_HandleBatchDeleteRequest(t *testing.T) {
 			body := tt.hookBefore(mock)
 
 			recorder := httptest.NewRecorder()
-			handler := NewUserHandler(mock.App, mock.Logger)
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
 			request := httptest.NewRequest(http.MethodDelete, "/api/user/urls", body)
 
 			// Act.
@@ -206,3 +272,847 @@ func TestUserHandler_HandleBatchDeleteRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_HandleBatchRestoreRequest(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name       string
+		want       want
+		hookBefore func(mock *mocks.Mock) io.Reader
+	}{
+		{
+			name: "WHEN read body error THEN bad request",
+			want: want{
+				statusCode: http.StatusBadRequest,
+			},
+			hookBefore: func(mock *mocks.Mock) io.Reader {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+				return utils.AFaultyReader
+			},
+		},
+		{
+			name: "WHEN app error THEN internal server error",
+			want: want{
+				statusCode: http.StatusInternalServerError,
+			},
+			hookBefore: func(mock *mocks.Mock) io.Reader {
+				mock.App.EXPECT().RestoreURLs(gomock.Any(), []string{"foo"}, gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+				return strings.NewReader(`["foo"]`)
+			},
+		},
+		{
+			name: "WHEN no errors THEN restored keys",
+			want: want{
+				statusCode: http.StatusOK,
+				body:       `["foo"]`,
+			},
+			hookBefore: func(mock *mocks.Mock) io.Reader {
+				mock.App.EXPECT().RestoreURLs(gomock.Any(), []string{"foo", "bar"}, gomock.Any()).Return([]string{"foo"}, nil)
+				return strings.NewReader(`["foo","bar"]`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			body := tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodPost, "/api/user/urls/restore", body)
+
+			// Act.
+			handler.HandleBatchRestoreRequest(recorder, request)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.want.statusCode, result.StatusCode)
+			if len(tt.want.body) > 0 {
+				responseBody, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.want.body, string(responseBody))
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleGetURLStatisticsRequest(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN invalid from THEN bad request",
+			query:      "?from=yesterday",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN invalid top THEN bad request",
+			query:      "?top=many",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN invalid bucket THEN bad request",
+			query:      "?bucket=month",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key not found THEN not found",
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrURLNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key of another user THEN forbidden",
+			wantStatus: http.StatusForbidden,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrURLAccessDenied)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN invalid interval THEN bad request",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrInvalidStatisticsInterval)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN app error THEN internal error",
+			wantStatus: http.StatusInternalServerError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN ok",
+			query:      "?from=2025-03-05T00:00:00Z&to=2025-03-06T00:00:00Z&bucket=hour&top=5",
+			wantStatus: http.StatusOK,
+			hookBefore: func(mock *mocks.Mock) {
+				from := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+				to := from.Add(24 * time.Hour)
+				mock.App.EXPECT().
+					GetClickStatistics(gomock.Any(), key, &models.ClickStatisticsRequest{
+						From:   &from,
+						To:     &to,
+						Bucket: "hour",
+						Top:    5,
+					}, gomock.Any()).
+					Return(&models.ClickStatisticsResponse{TotalClicks: 1}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodGet, "/api/user/urls/"+key+"/stats"+tt.query, nil)
+
+			// Act.
+			handler.HandleGetURLStatisticsRequest(recorder, request, key)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+		})
+	}
+}
+
+func TestUserHandler_HandleUpdateURLRequest(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN invalid json THEN bad request",
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN invalid URL THEN bad request",
+			body:       `{"url":"foo"}`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key not found THEN not found",
+			body:       `{"url":"http://foo.bar"}`,
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrURLNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key of another user THEN forbidden",
+			body:       `{"url":"http://foo.bar"}`,
+			wantStatus: http.StatusForbidden,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrURLAccessDenied)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN URL already shortened THEN conflict",
+			body:       `{"url":"http://foo.bar"}`,
+			wantStatus: http.StatusConflict,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrDuplicateURL)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN app error THEN internal error",
+			body:       `{"url":"http://foo.bar"}`,
+			wantStatus: http.StatusInternalServerError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN ok and audited",
+			body:       `{"url":"http://foo.bar"}`,
+			wantStatus: http.StatusOK,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().
+					UpdateURL(gomock.Any(), key, &models.UpdateURLRequest{URL: "http://foo.bar"}, gomock.Any()).
+					Return(&models.UpdateURLResponse{ShortURL: "http://localhost/abc", OriginalURL: "http://foo.bar", Version: 2}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Cond(func(event *domain.AuditEvent) bool {
+					return event.Action == domain.AuditActionUpdate && event.URL == "http://foo.bar"
+				}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodPatch, "/api/user/urls/"+key, strings.NewReader(tt.body))
+
+			// Act.
+			handler.HandleUpdateURLRequest(recorder, request, key)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+		})
+	}
+}
+
+func TestUserHandler_HandleGetURLVersionsRequest(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+
+	tests := []struct {
+		name       string
+		wantStatus int
+		wantBody   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN key not found THEN not found",
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return(nil, ErrURLNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key of another user THEN forbidden",
+			wantStatus: http.StatusForbidden,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return(nil, ErrURLAccessDenied)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN app error THEN internal error",
+			wantStatus: http.StatusInternalServerError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN versions",
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"version":1,"original_url":"http://foo.bar","replaced_at":"2025-03-05T00:00:00Z"}]`,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return([]*models.URLVersionItem{
+					{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodGet, "/api/user/urls/"+key+"/versions", nil)
+
+			// Act.
+			handler.HandleGetURLVersionsRequest(recorder, request, key)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+			if len(tt.wantBody) > 0 {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleUpdateURLVariantsRequest(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN invalid json THEN bad request",
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN negative weight THEN bad request",
+			body:       `{"weights":{"A":-1}}`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key not found THEN not found",
+			body:       `{"weights":{"A":1}}`,
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURLVariants(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrURLNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN key of another user THEN forbidden",
+			body:       `{"weights":{"A":1}}`,
+			wantStatus: http.StatusForbidden,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURLVariants(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrURLAccessDenied)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN unknown variant THEN bad request",
+			body:       `{"weights":{"C":1}}`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURLVariants(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, ErrInvalidURLVariant)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN app error THEN internal error",
+			body:       `{"weights":{"A":1}}`,
+			wantStatus: http.StatusInternalServerError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURLVariants(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN variants",
+			body:       `{"weights":{"B":0}}`,
+			wantStatus: http.StatusOK,
+			wantBody: `[{"name":"A","url":"http://foo.bar/a","weight":1},` +
+				`{"name":"B","url":"http://foo.bar/b","weight":0}]`,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().
+					UpdateURLVariants(gomock.Any(), key, &models.UpdateURLVariantsRequest{Weights: map[string]int{"B": 0}}, gomock.Any()).
+					Return([]*models.URLVariant{
+						{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+						{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodPut, "/api/user/urls/"+key+"/variants", strings.NewReader(tt.body))
+
+			// Act.
+			handler.HandleUpdateURLVariantsRequest(recorder, request, key)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+			if len(tt.wantBody) > 0 {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleCreateCollectionRequest(t *testing.T) {
+	t.Parallel()
+
+	collection := &models.CollectionItem{
+		ID:        uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d"),
+		Name:      "work",
+		CreatedAt: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN invalid JSON THEN bad request",
+			body:       "{",
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN empty name THEN bad request",
+			body:       `{"name":""}`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN name is taken THEN conflict",
+			body:       `{"name":"work"}`,
+			wantStatus: http.StatusConflict,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().CreateCollection(gomock.Any(), &models.CollectionRequest{Name: "work"}, gomock.Any()).
+					Return(nil, ErrDuplicateCollection)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN created",
+			body:       `{"name":"work"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":"8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d","name":"work","created_at":"2025-03-05T00:00:00Z","url_count":0}`,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().CreateCollection(gomock.Any(), &models.CollectionRequest{Name: "work"}, gomock.Any()).
+					Return(collection, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodPost, "/api/user/collections", strings.NewReader(tt.body))
+
+			// Act.
+			handler.HandleCreateCollectionRequest(recorder, request)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+			if len(tt.wantBody) > 0 {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleUpdateCampaignRequest(t *testing.T) {
+	t.Parallel()
+
+	campaignID := uuid.MustParse("3c9a1e52-7d4b-4f0e-8a61-5b2c9d8e7f10")
+	campaign := &models.CampaignItem{
+		ID:          campaignID,
+		Name:        "spring",
+		UTMSource:   "mail",
+		UTMCampaign: "spring_sale",
+		Parameters:  map[string]string{"ref": "growth"},
+		CreatedAt:   time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+	}
+	campaignRequest := &models.CampaignRequest{
+		Name:        "spring",
+		UTMSource:   "mail",
+		UTMCampaign: "spring_sale",
+		Parameters:  map[string]string{"ref": "growth"},
+	}
+	body := `{"name":"spring","utm_source":"mail","utm_campaign":"spring_sale","parameters":{"ref":"growth"}}`
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+		wantBody   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN invalid ID THEN bad request",
+			id:         "foo",
+			body:       body,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN empty parameter name THEN bad request",
+			id:         campaignID.String(),
+			body:       `{"name":"spring","parameters":{"":"growth"}}`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN UTM parameter among extra parameters THEN bad request",
+			id:         campaignID.String(),
+			body:       `{"name":"spring","parameters":{"utm_source":"mail"}}`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateCampaign(gomock.Any(), campaignID, gomock.Any(), gomock.Any()).
+					Return(nil, ErrInvalidCampaign)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN campaign not found THEN not found",
+			id:         campaignID.String(),
+			body:       body,
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateCampaign(gomock.Any(), campaignID, campaignRequest, gomock.Any()).
+					Return(nil, ErrCampaignNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN name is taken THEN conflict",
+			id:         campaignID.String(),
+			body:       body,
+			wantStatus: http.StatusConflict,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateCampaign(gomock.Any(), campaignID, campaignRequest, gomock.Any()).
+					Return(nil, ErrDuplicateCampaign)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN OK",
+			id:         campaignID.String(),
+			body:       body,
+			wantStatus: http.StatusOK,
+			wantBody: `{"id":"3c9a1e52-7d4b-4f0e-8a61-5b2c9d8e7f10","name":"spring","utm_source":"mail",` +
+				`"utm_campaign":"spring_sale","parameters":{"ref":"growth"},"created_at":"2025-03-05T00:00:00Z"}`,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateCampaign(gomock.Any(), campaignID, campaignRequest, gomock.Any()).
+					Return(campaign, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodPut, "/api/user/campaigns/"+tt.id, strings.NewReader(tt.body))
+
+			// Act.
+			handler.HandleUpdateCampaignRequest(recorder, request, tt.id)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+			if len(tt.wantBody) > 0 {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleAddCollectionURLsRequest(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d")
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+		wantBody   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN invalid ID THEN bad request",
+			id:         "foo",
+			body:       `["abc"]`,
+			wantStatus: http.StatusBadRequest,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN collection not found THEN not found",
+			id:         id.String(),
+			body:       `["abc"]`,
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().AddCollectionURLs(gomock.Any(), id, []string{"abc"}, gomock.Any()).
+					Return(nil, ErrCollectionNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN no errors THEN collection",
+			id:         id.String(),
+			body:       `["abc","def"]`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d","name":"work","created_at":"2025-03-05T00:00:00Z","url_count":2}`,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().AddCollectionURLs(gomock.Any(), id, []string{"abc", "def"}, gomock.Any()).
+					Return(&models.CollectionItem{
+						ID:        id,
+						Name:      "work",
+						CreatedAt: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+						URLCount:  2,
+					}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodPost, "/api/user/collections/"+tt.id+"/urls", strings.NewReader(tt.body))
+
+			// Act.
+			handler.HandleAddCollectionURLsRequest(recorder, request, tt.id)
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+			if len(tt.wantBody) > 0 {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleExportCollectionRequest(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d")
+
+	tests := []struct {
+		name            string
+		wantStatus      int
+		wantBody        string
+		wantDisposition string
+		hookBefore      func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN collection not found THEN not found",
+			wantStatus: http.StatusNotFound,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportCollection(gomock.Any(), id, gomock.Any()).Return(nil, ErrCollectionNotFound)
+				mock.Logger.EXPECT().Warnf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:       "WHEN app error THEN internal error",
+			wantStatus: http.StatusInternalServerError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportCollection(gomock.Any(), id, gomock.Any()).Return(nil, assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:            "WHEN no errors THEN attachment",
+			wantStatus:      http.StatusOK,
+			wantBody:        `[{"short_url":"http://localhost/abc","original_url":"http://foo.bar","created_at":"2025-03-05T00:00:00Z","updated_at":"2025-03-05T00:00:00Z"}]`,
+			wantDisposition: `attachment; filename="collection-8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d.json"`,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportCollection(gomock.Any(), id, gomock.Any()).Return([]*models.UserURLsResponseItem{
+					{
+						ShortURL:    "http://localhost/abc",
+						OriginalURL: "http://foo.bar",
+						CreatedAt:   time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+						UpdatedAt:   time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			recorder := httptest.NewRecorder()
+			handler := NewUserHandler(mock.App, mock.AuditService, mock.Logger)
+			request := httptest.NewRequest(http.MethodGet, "/api/user/collections/"+id.String()+"/export", nil)
+
+			// Act.
+			handler.HandleExportCollectionRequest(recorder, request, id.String())
+
+			// Assert.
+			result := recorder.Result()
+			defer result.Body.Close()
+
+			require.Equal(t, tt.wantStatus, result.StatusCode)
+			require.Equal(t, tt.wantDisposition, result.Header.Get(headers.ContentDisposition))
+			if len(tt.wantBody) > 0 {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package config
 
 import (
+	"cmp"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/go-playground/validator/v10"
@@ -13,21 +15,67 @@ import (
 )
 
 type Configuration struct {
-	ServerAddress     string                      `env:"SERVER_ADDRESS" validate:"required,hostname_port"`
-	ServerAddressGRPC string                      `env:"SERVER_ADDRESS_GRPC" validate:"required,hostname_port"`
-	BaseURL           string                      `env:"BASE_URL" validate:"required,url"`
-	AuditFile         string                      `env:"AUDIT_FILE" validate:"omitempty,filepath"`
-	AuditURL          string                      `env:"AUDIT_URL" validate:"omitempty,url"`
-	MemoryStore       *MemoryStoreConfiguration   `validate:"required"`
-	FileStore         *FileStoreConfiguration     `validate:"required"`
-	DatabaseStore     *DatabaseStoreConfiguration `validate:"required"`
-	HTTPS             *HTTPSConfiguration         `validate:"required"`
-	CPUProfile        string                      `env:"CPU_PROFILE" validate:"omitempty,filepath"`
-	MemoryProfile     string                      `env:"MEMORY_PROFILE" validate:"omitempty,filepath"`
-	TrustedSubnet     string                      `env:"TRUSTED_SUBNET" validate:"omitempty,cidr"`
-	ConfigFile        string                      `env:"CONFIG"`
+	ServerAddress            string                            `env:"SERVER_ADDRESS" validate:"required,hostname_port"`
+	ServerAddressGRPC        string                            `env:"SERVER_ADDRESS_GRPC" validate:"required,hostname_port"`
+	BaseURL                  string                            `env:"BASE_URL" validate:"required,url"`
+	AuditFile                string                            `env:"AUDIT_FILE" validate:"omitempty,filepath"`
+	AuditURL                 string                            `env:"AUDIT_URL" validate:"omitempty,url"`
+	MemoryStore              *MemoryStoreConfiguration         `validate:"required"`
+	FileStore                *FileStoreConfiguration           `validate:"required"`
+	DatabaseStore            *DatabaseStoreConfiguration       `validate:"required"`
+	HTTPS                    *HTTPSConfiguration               `validate:"required"`
+	KeyGenerator             *KeyGeneratorConfiguration        `validate:"required"`
+	URLCanonicalization      *URLCanonicalizationConfiguration `validate:"required"`
+	CPUProfile               string                            `env:"CPU_PROFILE" validate:"omitempty,filepath"`
+	MemoryProfile            string                            `env:"MEMORY_PROFILE" validate:"omitempty,filepath"`
+	TrustedSubnet            string                            `env:"TRUSTED_SUBNET" validate:"omitempty,cidr"`
+	DedupScope               string                            `env:"DEDUP_SCOPE" validate:"oneof=global user none"`
+	ConfigFile               string                            `env:"CONFIG"`
+	ExpiredURLsSweepInterval time.Duration                     `env:"EXPIRED_URLS_SWEEP_INTERVAL" validate:"gt=0"`
+	ExpiredURLsRetention     time.Duration                     `env:"EXPIRED_URLS_RETENTION" validate:"gt=0"`
+	DeletedURLsGracePeriod   time.Duration                     `env:"DELETED_URLS_GRACE_PERIOD" validate:"gt=0"`
+	DeletedURLsRetention     time.Duration                     `env:"DELETED_URLS_RETENTION" validate:"gtefield=DeletedURLsGracePeriod"`
+	DeletedURLsPurgeInterval time.Duration                     `env:"DELETED_URLS_PURGE_INTERVAL" validate:"gt=0"`
+	// RedirectCode is the HTTP status of redirects by links shortened without one.
+	RedirectCode int64 `env:"REDIRECT_CODE" validate:"oneof=301 302 307 308"`
+	// RedirectCacheMaxAge is how long browsers may cache permanent redirects, temporary ones are never cached.
+	RedirectCacheMaxAge time.Duration `env:"REDIRECT_CACHE_MAX_AGE" validate:"gt=0"`
+	// PassthroughQueryMerge tells how the query of a redirect request is merged into the query of a passthrough link.
+	PassthroughQueryMerge string `env:"PASSTHROUGH_QUERY_MERGE" validate:"oneof=override keep append"`
 }
 
+// URL deduplication scopes. A scope change applies to URLs shortened after it.
+const (
+	// DedupScopeGlobal gives the existing key to anyone who shortens the same URL.
+	DedupScopeGlobal = "global"
+	// DedupScopeUser gives the existing key only to the user who shortened the URL.
+	DedupScopeUser = "user"
+	// DedupScopeNone gives a new key every time.
+	DedupScopeNone = "none"
+)
+
+// Merging of a redirect request query parameter with the parameter of the same name in a passthrough link.
+const (
+	// QueryMergeOverride replaces the link values with the request ones.
+	QueryMergeOverride = "override"
+	// QueryMergeKeep keeps the link values and drops the request ones.
+	QueryMergeKeep = "keep"
+	// QueryMergeAppend keeps both, the link values go first.
+	QueryMergeAppend = "append"
+)
+
+const (
+	defaultExpiredURLsSweepInterval = time.Minute
+	defaultExpiredURLsRetention     = 7 * 24 * time.Hour
+	defaultDedupScope               = DedupScopeGlobal
+	defaultDeletedURLsGracePeriod   = 7 * 24 * time.Hour
+	defaultDeletedURLsRetention     = 30 * 24 * time.Hour
+	defaultDeletedURLsPurgeInterval = time.Hour
+	defaultRedirectCode             = 307
+	defaultRedirectCacheMaxAge      = 24 * time.Hour
+	defaultPassthroughQueryMerge    = QueryMergeOverride
+)
+
 func (c *Configuration) String() string {
 	sb := &strings.Builder{}
 	fmt.Fprintf(
@@ -77,6 +125,18 @@ func (c *Configuration) String() string {
 		fmt.Fprintf(sb, " HTTPS:%v", c.HTTPS)
 	}
 
+	if c.KeyGenerator == nil {
+		fmt.Fprintf(sb, " KeyGenerator:<nil>")
+	} else {
+		fmt.Fprintf(sb, " KeyGenerator:%v", c.KeyGenerator)
+	}
+
+	if c.URLCanonicalization == nil {
+		fmt.Fprintf(sb, " URLCanonicalization:<nil>")
+	} else {
+		fmt.Fprintf(sb, " URLCanonicalization:%v", c.URLCanonicalization)
+	}
+
 	if len(c.TrustedSubnet) > 0 {
 		fmt.Fprintf(sb, " TrustedSubnet:%v", c.TrustedSubnet)
 	}
@@ -85,6 +145,21 @@ func (c *Configuration) String() string {
 		fmt.Fprintf(sb, " ConfigFile:%v", c.ConfigFile)
 	}
 
+	fmt.Fprintf(sb, " DedupScope:%v", c.DedupScope)
+
+	fmt.Fprintf(sb, " ExpiredURLsSweepInterval:%v ExpiredURLsRetention:%v", c.ExpiredURLsSweepInterval, c.ExpiredURLsRetention)
+
+	fmt.Fprintf(
+		sb,
+		" DeletedURLsGracePeriod:%v DeletedURLsRetention:%v DeletedURLsPurgeInterval:%v",
+		c.DeletedURLsGracePeriod,
+		c.DeletedURLsRetention,
+		c.DeletedURLsPurgeInterval)
+
+	fmt.Fprintf(sb, " RedirectCode:%v RedirectCacheMaxAge:%v", c.RedirectCode, c.RedirectCacheMaxAge)
+
+	fmt.Fprintf(sb, " PassthroughQueryMerge:%v", c.PassthroughQueryMerge)
+
 	fmt.Fprintf(sb, "}")
 	return sb.String()
 }
@@ -114,6 +189,32 @@ func GetConfiguration() (*Configuration, error) {
 				flagConfig.FileStore.FilePath,
 				fileConfig.FileStore.FilePath,
 			),
+			CompactionSizeThreshold: getNumberValue(
+				envConfig.FileStore.CompactionSizeThreshold,
+				flagConfig.FileStore.CompactionSizeThreshold,
+				fileConfig.FileStore.CompactionSizeThreshold,
+				defaultFileStoreCompactionSizeThreshold,
+			),
+			CompactionGarbageRatio: getNumberValue(
+				envConfig.FileStore.CompactionGarbageRatio,
+				flagConfig.FileStore.CompactionGarbageRatio,
+				fileConfig.FileStore.CompactionGarbageRatio,
+				defaultFileStoreCompactionGarbageRatio,
+			),
+			SyncPolicy: cmp.Or(
+				getStringValue(
+					envConfig.FileStore.SyncPolicy,
+					flagConfig.FileStore.SyncPolicy,
+					fileConfig.FileStore.SyncPolicy,
+				),
+				defaultFileStoreSyncPolicy,
+			),
+			SyncInterval: getNumberValue(
+				envConfig.FileStore.SyncInterval,
+				flagConfig.FileStore.SyncInterval,
+				fileConfig.FileStore.SyncInterval,
+				defaultFileStoreSyncInterval,
+			),
 		},
 		DatabaseStore: NewDatabaseStoreConfiguration(
 			getStringValue(
@@ -134,9 +235,103 @@ func GetConfiguration() (*Configuration, error) {
 				fileConfig.HTTPS.KeyFile,
 			),
 		},
+		KeyGenerator: &KeyGeneratorConfiguration{
+			Type: cmp.Or(
+				getStringValue(
+					envConfig.KeyGenerator.Type,
+					flagConfig.KeyGenerator.Type,
+					fileConfig.KeyGenerator.Type,
+				),
+				defaultKeyGenerator,
+			),
+			Alphabet: getStringValue(
+				envConfig.KeyGenerator.Alphabet,
+				flagConfig.KeyGenerator.Alphabet,
+				fileConfig.KeyGenerator.Alphabet,
+			),
+			Secret: getStringValue(
+				envConfig.KeyGenerator.Secret,
+				flagConfig.KeyGenerator.Secret,
+				fileConfig.KeyGenerator.Secret,
+			),
+			BlockSize: getNumberValue(
+				envConfig.KeyGenerator.BlockSize,
+				flagConfig.KeyGenerator.BlockSize,
+				fileConfig.KeyGenerator.BlockSize,
+				defaultKeyBlockSize,
+			),
+		},
+		URLCanonicalization: &URLCanonicalizationConfiguration{
+			QueryMode: cmp.Or(
+				getStringValue(
+					envConfig.URLCanonicalization.QueryMode,
+					flagConfig.URLCanonicalization.QueryMode,
+					fileConfig.URLCanonicalization.QueryMode,
+				),
+				defaultURLQueryMode,
+			),
+			TrackingParameters: cmp.Or(
+				getStringValue(
+					envConfig.URLCanonicalization.TrackingParameters,
+					flagConfig.URLCanonicalization.TrackingParameters,
+					fileConfig.URLCanonicalization.TrackingParameters,
+				),
+				defaultURLTrackingParameters,
+			),
+		},
 		CPUProfile:    getStringValue(envConfig.CPUProfile, flagConfig.CPUProfile, fileConfig.CPUProfile),
 		MemoryProfile: getStringValue(envConfig.MemoryProfile, flagConfig.MemoryProfile, fileConfig.MemoryProfile),
 		TrustedSubnet: getStringValue(envConfig.TrustedSubnet, flagConfig.TrustedSubnet, fileConfig.TrustedSubnet),
+		DedupScope: cmp.Or(
+			getStringValue(envConfig.DedupScope, flagConfig.DedupScope, fileConfig.DedupScope),
+			defaultDedupScope,
+		),
+		ExpiredURLsSweepInterval: getNumberValue(
+			envConfig.ExpiredURLsSweepInterval,
+			flagConfig.ExpiredURLsSweepInterval,
+			fileConfig.ExpiredURLsSweepInterval,
+			defaultExpiredURLsSweepInterval,
+		),
+		ExpiredURLsRetention: getNumberValue(
+			envConfig.ExpiredURLsRetention,
+			flagConfig.ExpiredURLsRetention,
+			fileConfig.ExpiredURLsRetention,
+			defaultExpiredURLsRetention,
+		),
+		DeletedURLsGracePeriod: getNumberValue(
+			envConfig.DeletedURLsGracePeriod,
+			flagConfig.DeletedURLsGracePeriod,
+			fileConfig.DeletedURLsGracePeriod,
+			defaultDeletedURLsGracePeriod,
+		),
+		DeletedURLsRetention: getNumberValue(
+			envConfig.DeletedURLsRetention,
+			flagConfig.DeletedURLsRetention,
+			fileConfig.DeletedURLsRetention,
+			defaultDeletedURLsRetention,
+		),
+		DeletedURLsPurgeInterval: getNumberValue(
+			envConfig.DeletedURLsPurgeInterval,
+			flagConfig.DeletedURLsPurgeInterval,
+			fileConfig.DeletedURLsPurgeInterval,
+			defaultDeletedURLsPurgeInterval,
+		),
+		RedirectCode: getNumberValue(
+			envConfig.RedirectCode,
+			flagConfig.RedirectCode,
+			fileConfig.RedirectCode,
+			defaultRedirectCode,
+		),
+		RedirectCacheMaxAge: getNumberValue(
+			envConfig.RedirectCacheMaxAge,
+			flagConfig.RedirectCacheMaxAge,
+			fileConfig.RedirectCacheMaxAge,
+			defaultRedirectCacheMaxAge,
+		),
+		PassthroughQueryMerge: cmp.Or(
+			getStringValue(envConfig.PassthroughQueryMerge, flagConfig.PassthroughQueryMerge, fileConfig.PassthroughQueryMerge),
+			defaultPassthroughQueryMerge,
+		),
 	}
 
 	validate := validator.New(validator.WithRequiredStructEnabled())
@@ -145,14 +340,24 @@ func GetConfiguration() (*Configuration, error) {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	if err = configuration.KeyGenerator.ValidateAlphabet(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	if err = configuration.KeyGenerator.ValidateSecret(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
 	return configuration, nil
 }
 
 func parseFlags() *Configuration {
 	configuration := &Configuration{
-		FileStore:     &FileStoreConfiguration{},
-		DatabaseStore: &DatabaseStoreConfiguration{},
-		HTTPS:         &HTTPSConfiguration{},
+		FileStore:           &FileStoreConfiguration{},
+		DatabaseStore:       &DatabaseStoreConfiguration{},
+		HTTPS:               &HTTPSConfiguration{},
+		KeyGenerator:        &KeyGeneratorConfiguration{},
+		URLCanonicalization: &URLCanonicalizationConfiguration{},
 	}
 
 	flag.StringVar(&configuration.ServerAddress, "a", "localhost:8080", "address and port of running server")
@@ -161,14 +366,33 @@ func parseFlags() *Configuration {
 	flag.StringVar(&configuration.AuditFile, "audit-file", "", "audit file path")
 	flag.StringVar(&configuration.AuditURL, "audit-url", "", "audit endpoint URL")
 	flag.StringVar(&configuration.FileStore.FilePath, "f", "shortener.jsonl", "path to storage file")
+	flag.Int64Var(&configuration.FileStore.CompactionSizeThreshold, "file-storage-compaction-size", 0, "storage journal size in bytes that triggers compaction")
+	flag.Float64Var(&configuration.FileStore.CompactionGarbageRatio, "file-storage-compaction-garbage-ratio", 0, "share of superseded storage file records that triggers compaction")
+	flag.StringVar(&configuration.FileStore.SyncPolicy, "file-storage-sync", "", "storage file sync policy: always, batch or never")
+	flag.DurationVar(&configuration.FileStore.SyncInterval, "file-storage-sync-interval", 0, "interval of storage file batch sync")
 	flag.StringVar(&configuration.DatabaseStore.DataSourceName, "d", "", "data source name")
 	flag.BoolVar(&configuration.HTTPS.Enabled, "s", false, "use HTTPS")
 	flag.StringVar(&configuration.HTTPS.CertificateFile, "cert-file", "", "HTTP certificate file")
 	flag.StringVar(&configuration.HTTPS.KeyFile, "key-file", "", "HTTP key file")
+	flag.StringVar(&configuration.KeyGenerator.Type, "key-generator", "", "key generator: random, sequence, hash or obfuscated")
+	flag.StringVar(&configuration.KeyGenerator.Alphabet, "key-alphabet", "", "characters of generated keys")
+	flag.StringVar(&configuration.KeyGenerator.Secret, "key-generator-secret", "", "secret of obfuscated key generator")
+	flag.Int64Var(&configuration.KeyGenerator.BlockSize, "key-block-size", 0, "number of key sequence values leased from storage at once")
+	flag.StringVar(&configuration.URLCanonicalization.QueryMode, "url-query-mode", "", "query of canonical URLs: keep, sort or strip tracking parameters")
+	flag.StringVar(&configuration.URLCanonicalization.TrackingParameters, "url-tracking-parameters", "", "comma separated tracking query parameters, a trailing * matches any suffix")
 	flag.StringVar(&configuration.CPUProfile, "cpu-profile", "", "path to CPU profile file")
 	flag.StringVar(&configuration.MemoryProfile, "memory-profile", "", "path to memory profile file")
 	flag.StringVar(&configuration.TrustedSubnet, "t", "", "trusted subnet CIDR")
 	flag.StringVar(&configuration.ConfigFile, "config", "", "path to configuration file")
+	flag.StringVar(&configuration.DedupScope, "dedup-scope", "", "URL deduplication scope: global, user or none")
+	flag.DurationVar(&configuration.ExpiredURLsSweepInterval, "expired-urls-sweep-interval", 0, "interval of expired URLs removal")
+	flag.DurationVar(&configuration.ExpiredURLsRetention, "expired-urls-retention", 0, "period after which expired URLs are removed")
+	flag.DurationVar(&configuration.DeletedURLsGracePeriod, "deleted-urls-grace-period", 0, "period during which deleted URLs can be restored")
+	flag.DurationVar(&configuration.DeletedURLsRetention, "deleted-urls-retention", 0, "period after which deleted URLs are purged")
+	flag.DurationVar(&configuration.DeletedURLsPurgeInterval, "deleted-urls-purge-interval", 0, "interval of deleted URLs purge")
+	flag.Int64Var(&configuration.RedirectCode, "redirect-code", 0, "default redirect status: 301, 302, 307 or 308")
+	flag.DurationVar(&configuration.RedirectCacheMaxAge, "redirect-cache-max-age", 0, "period during which permanent redirects may be cached")
+	flag.StringVar(&configuration.PassthroughQueryMerge, "passthrough-query-merge", "", "merging of query parameters of passthrough links: override, keep or append")
 	flag.Parse()
 
 	return configuration
@@ -176,9 +400,11 @@ func parseFlags() *Configuration {
 
 func parseEnvironment() (*Configuration, error) {
 	configuration := &Configuration{
-		FileStore:     &FileStoreConfiguration{},
-		DatabaseStore: &DatabaseStoreConfiguration{},
-		HTTPS:         &HTTPSConfiguration{},
+		FileStore:           &FileStoreConfiguration{},
+		DatabaseStore:       &DatabaseStoreConfiguration{},
+		HTTPS:               &HTTPSConfiguration{},
+		KeyGenerator:        &KeyGeneratorConfiguration{},
+		URLCanonicalization: &URLCanonicalizationConfiguration{},
 	}
 	err := env.Parse(configuration)
 
@@ -189,9 +415,11 @@ func parseFile(envConfig *Configuration, flagConfig *Configuration) (*Configurat
 	configFile := lo.Ternary(len(envConfig.ConfigFile) > 0, envConfig.ConfigFile, flagConfig.ConfigFile)
 	if len(configFile) == 0 {
 		return &Configuration{
-			FileStore:     &FileStoreConfiguration{},
-			DatabaseStore: &DatabaseStoreConfiguration{},
-			HTTPS:         &HTTPSConfiguration{},
+			FileStore:           &FileStoreConfiguration{},
+			DatabaseStore:       &DatabaseStoreConfiguration{},
+			HTTPS:               &HTTPSConfiguration{},
+			KeyGenerator:        &KeyGeneratorConfiguration{},
+			URLCanonicalization: &URLCanonicalizationConfiguration{},
 		}, nil
 	}
 
@@ -205,6 +433,41 @@ func parseFile(envConfig *Configuration, flagConfig *Configuration) (*Configurat
 		return nil, fmt.Errorf("failed to parse JSON from config file '%v': %w", configFile, err)
 	}
 
+	expiredURLsSweepInterval, err := parseDuration(configurationFile.ExpiredURLsSweepInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expired_urls_sweep_interval from config file '%v': %w", configFile, err)
+	}
+
+	expiredURLsRetention, err := parseDuration(configurationFile.ExpiredURLsRetention)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expired_urls_retention from config file '%v': %w", configFile, err)
+	}
+
+	fileStoreSyncInterval, err := parseDuration(configurationFile.FileStoreSyncInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file_storage_sync_interval from config file '%v': %w", configFile, err)
+	}
+
+	deletedURLsGracePeriod, err := parseDuration(configurationFile.DeletedURLsGracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deleted_urls_grace_period from config file '%v': %w", configFile, err)
+	}
+
+	deletedURLsRetention, err := parseDuration(configurationFile.DeletedURLsRetention)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deleted_urls_retention from config file '%v': %w", configFile, err)
+	}
+
+	deletedURLsPurgeInterval, err := parseDuration(configurationFile.DeletedURLsPurgeInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deleted_urls_purge_interval from config file '%v': %w", configFile, err)
+	}
+
+	redirectCacheMaxAge, err := parseDuration(configurationFile.RedirectCacheMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redirect_cache_max_age from config file '%v': %w", configFile, err)
+	}
+
 	configuration := &Configuration{
 		ServerAddress:     configurationFile.ServerAddress,
 		ServerAddressGRPC: configurationFile.ServerAddressGRPC,
@@ -212,7 +475,11 @@ func parseFile(envConfig *Configuration, flagConfig *Configuration) (*Configurat
 		AuditFile:         configurationFile.AuditFile,
 		AuditURL:          configurationFile.AuditURL,
 		FileStore: &FileStoreConfiguration{
-			FilePath: configurationFile.FileStoreFilePath,
+			FilePath:                configurationFile.FileStoreFilePath,
+			CompactionSizeThreshold: configurationFile.FileStoreCompactionSize,
+			CompactionGarbageRatio:  configurationFile.FileStoreCompactionRatio,
+			SyncPolicy:              configurationFile.FileStoreSyncPolicy,
+			SyncInterval:            fileStoreSyncInterval,
 		},
 		DatabaseStore: &DatabaseStoreConfiguration{
 			DataSourceName: configurationFile.DatabaseStoreDataSourceName,
@@ -222,9 +489,28 @@ func parseFile(envConfig *Configuration, flagConfig *Configuration) (*Configurat
 			CertificateFile: configurationFile.HTTPSCertificateFile,
 			KeyFile:         configurationFile.HTTPSKeyFile,
 		},
-		CPUProfile:    configurationFile.CPUProfile,
-		MemoryProfile: configurationFile.MemoryProfile,
-		TrustedSubnet: configurationFile.TrustedSubnet,
+		KeyGenerator: &KeyGeneratorConfiguration{
+			Type:      configurationFile.KeyGenerator,
+			Alphabet:  configurationFile.KeyAlphabet,
+			Secret:    configurationFile.KeyGeneratorSecret,
+			BlockSize: configurationFile.KeyBlockSize,
+		},
+		URLCanonicalization: &URLCanonicalizationConfiguration{
+			QueryMode:          configurationFile.URLQueryMode,
+			TrackingParameters: configurationFile.URLTrackingParameters,
+		},
+		CPUProfile:               configurationFile.CPUProfile,
+		MemoryProfile:            configurationFile.MemoryProfile,
+		TrustedSubnet:            configurationFile.TrustedSubnet,
+		DedupScope:               configurationFile.DedupScope,
+		ExpiredURLsSweepInterval: expiredURLsSweepInterval,
+		ExpiredURLsRetention:     expiredURLsRetention,
+		DeletedURLsGracePeriod:   deletedURLsGracePeriod,
+		DeletedURLsRetention:     deletedURLsRetention,
+		DeletedURLsPurgeInterval: deletedURLsPurgeInterval,
+		RedirectCode:             configurationFile.RedirectCode,
+		RedirectCacheMaxAge:      redirectCacheMaxAge,
+		PassthroughQueryMerge:    configurationFile.PassthroughQueryMerge,
 	}
 
 	return configuration, nil
@@ -241,3 +527,27 @@ func getStringValue(envValue string, flagValue string, fileValue string) string
 
 	return fileValue
 }
+
+func getNumberValue[T ~int64 | ~float64](envValue, flagValue, fileValue, defaultValue T) T {
+	if envValue > 0 {
+		return envValue
+	}
+
+	if flagValue > 0 {
+		return flagValue
+	}
+
+	if fileValue > 0 {
+		return fileValue
+	}
+
+	return defaultValue
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	return time.ParseDuration(value)
+}
@@ -1,17 +1,36 @@
 package config
 
 type ConfigurationFile struct {
-	ServerAddress               string `json:"server_address"`
-	ServerAddressGRPC           string `json:"server_address_grpc"`
-	BaseURL                     string `json:"base_url"`
-	AuditFile                   string `json:"audit_file"`
-	AuditURL                    string `json:"audit_url"`
-	FileStoreFilePath           string `json:"file_storage_path"`
-	DatabaseStoreDataSourceName string `json:"database_dsn"`
-	HTTPSEnabled                bool   `json:"enable_https"`
-	HTTPSCertificateFile        string `json:"https_certificate_file"`
-	HTTPSKeyFile                string `json:"https_key_file"`
-	CPUProfile                  string `json:"cpu_profile"`
-	MemoryProfile               string `json:"memory_profile"`
-	TrustedSubnet               string `json:"trusted_subnet"`
+	ServerAddress               string  `json:"server_address"`
+	ServerAddressGRPC           string  `json:"server_address_grpc"`
+	BaseURL                     string  `json:"base_url"`
+	AuditFile                   string  `json:"audit_file"`
+	AuditURL                    string  `json:"audit_url"`
+	FileStoreFilePath           string  `json:"file_storage_path"`
+	FileStoreCompactionSize     int64   `json:"file_storage_compaction_size"`
+	FileStoreCompactionRatio    float64 `json:"file_storage_compaction_garbage_ratio"`
+	FileStoreSyncPolicy         string  `json:"file_storage_sync"`
+	FileStoreSyncInterval       string  `json:"file_storage_sync_interval"`
+	DatabaseStoreDataSourceName string  `json:"database_dsn"`
+	HTTPSEnabled                bool    `json:"enable_https"`
+	HTTPSCertificateFile        string  `json:"https_certificate_file"`
+	HTTPSKeyFile                string  `json:"https_key_file"`
+	KeyGenerator                string  `json:"key_generator"`
+	KeyAlphabet                 string  `json:"key_alphabet"`
+	KeyGeneratorSecret          string  `json:"key_generator_secret"`
+	KeyBlockSize                int64   `json:"key_block_size"`
+	URLQueryMode                string  `json:"url_query_mode"`
+	URLTrackingParameters       string  `json:"url_tracking_parameters"`
+	CPUProfile                  string  `json:"cpu_profile"`
+	MemoryProfile               string  `json:"memory_profile"`
+	TrustedSubnet               string  `json:"trusted_subnet"`
+	DedupScope                  string  `json:"dedup_scope"`
+	ExpiredURLsSweepInterval    string  `json:"expired_urls_sweep_interval"`
+	ExpiredURLsRetention        string  `json:"expired_urls_retention"`
+	DeletedURLsGracePeriod      string  `json:"deleted_urls_grace_period"`
+	DeletedURLsRetention        string  `json:"deleted_urls_retention"`
+	DeletedURLsPurgeInterval    string  `json:"deleted_urls_purge_interval"`
+	RedirectCode                int64   `json:"redirect_code"`
+	RedirectCacheMaxAge         string  `json:"redirect_cache_max_age"`
+	PassthroughQueryMerge       string  `json:"passthrough_query_merge"`
 }
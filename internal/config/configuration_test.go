@@ -4,6 +4,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -50,10 +51,21 @@ func TestConfiguration_String(t *testing.T) {
 					CertificateFile: "server.crt",
 					KeyFile:         "server.key",
 				},
-				CPUProfile:    "profiles/cpu.pprof",
-				MemoryProfile: "profiles/memory.pprof",
-				TrustedSubnet: "192.168.1.0/24",
-				ConfigFile:    "config.json",
+				URLCanonicalization: &URLCanonicalizationConfiguration{
+					QueryMode:          URLQuerySort,
+					TrackingParameters: "utm_*",
+				},
+				CPUProfile:               "profiles/cpu.pprof",
+				MemoryProfile:            "profiles/memory.pprof",
+				TrustedSubnet:            "192.168.1.0/24",
+				ConfigFile:               "config.json",
+				DedupScope:               DedupScopeUser,
+				DeletedURLsGracePeriod:   time.Hour,
+				DeletedURLsRetention:     24 * time.Hour,
+				DeletedURLsPurgeInterval: time.Hour,
+				RedirectCode:             308,
+				RedirectCacheMaxAge:      time.Hour,
+				PassthroughQueryMerge:    QueryMergeAppend,
 			},
 		},
 	}
@@ -94,9 +106,11 @@ func TestConfiguration_parseFile(t *testing.T) {
 		{
 			name: "WHEN no file THEN defaut",
 			want: &Configuration{
-				FileStore:     &FileStoreConfiguration{},
-				DatabaseStore: &DatabaseStoreConfiguration{},
-				HTTPS:         &HTTPSConfiguration{},
+				FileStore:           &FileStoreConfiguration{},
+				DatabaseStore:       &DatabaseStoreConfiguration{},
+				HTTPS:               &HTTPSConfiguration{},
+				KeyGenerator:        &KeyGeneratorConfiguration{},
+				URLCanonicalization: &URLCanonicalizationConfiguration{},
 			},
 			hookBefore: func() (*Configuration, *Configuration) {
 				return &Configuration{}, &Configuration{}
@@ -128,10 +142,12 @@ func TestConfiguration_parseFile(t *testing.T) {
 		{
 			name: "WHEN no errors THEN ok",
 			want: &Configuration{
-				ServerAddress: "http://localhost",
-				FileStore:     &FileStoreConfiguration{},
-				DatabaseStore: &DatabaseStoreConfiguration{},
-				HTTPS:         &HTTPSConfiguration{},
+				ServerAddress:       "http://localhost",
+				FileStore:           &FileStoreConfiguration{},
+				DatabaseStore:       &DatabaseStoreConfiguration{},
+				HTTPS:               &HTTPSConfiguration{},
+				KeyGenerator:        &KeyGeneratorConfiguration{},
+				URLCanonicalization: &URLCanonicalizationConfiguration{},
 			},
 			hookBefore: func() (*Configuration, *Configuration) {
 				filePath := path.Join(t.TempDir(), "config.json")
@@ -216,3 +232,31 @@ func TestConfiguration_getStringValue(t *testing.T) {
 		})
 	}
 }
+
+func TestConfiguration_getNumberValue(t *testing.T) {
+	t.Parallel()
+
+	// Act-assert.
+	require.Equal(t, time.Second, getNumberValue(time.Second, time.Minute, time.Hour, time.Millisecond))
+	require.Equal(t, time.Minute, getNumberValue(0, time.Minute, time.Hour, time.Millisecond))
+	require.Equal(t, time.Hour, getNumberValue(0, 0, time.Hour, time.Millisecond))
+	require.Equal(t, time.Millisecond, getNumberValue(0, 0, 0, time.Millisecond))
+	require.Equal(t, 0.25, getNumberValue(0, 0.25, 0.75, 0.5))
+	require.Equal(t, 0.5, getNumberValue(0, 0, 0, 0.5))
+}
+
+func TestConfiguration_parseDuration(t *testing.T) {
+	t.Parallel()
+
+	// Act-assert.
+	duration, err := parseDuration("")
+	require.Zero(t, duration)
+	require.NoError(t, err)
+
+	duration, err = parseDuration("5m")
+	require.Equal(t, 5*time.Minute, duration)
+	require.NoError(t, err)
+
+	_, err = parseDuration("five minutes")
+	require.Error(t, err)
+}
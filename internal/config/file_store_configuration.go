@@ -1,11 +1,44 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// File store durability policies.
+const (
+	// FileStoreSyncAlways flushes every write to disk before it is acknowledged.
+	FileStoreSyncAlways = "always"
+	// FileStoreSyncBatch flushes concurrent writes to disk with one fsync, every write still waits for it.
+	FileStoreSyncBatch = "batch"
+	// FileStoreSyncNever leaves flushing to the operating system.
+	FileStoreSyncNever = "never"
+)
 
 type FileStoreConfiguration struct {
-	FilePath string `env:"FILE_STORAGE_PATH" validate:"required"`
+	FilePath                string        `env:"FILE_STORAGE_PATH" validate:"required"`
+	CompactionSizeThreshold int64         `env:"FILE_STORAGE_COMPACTION_SIZE" validate:"gte=0"`
+	CompactionGarbageRatio  float64       `env:"FILE_STORAGE_COMPACTION_GARBAGE_RATIO" validate:"gte=0,lte=1"`
+	SyncPolicy              string        `env:"FILE_STORAGE_SYNC" validate:"oneof=always batch never"`
+	SyncInterval            time.Duration `env:"FILE_STORAGE_SYNC_INTERVAL" validate:"gt=0"`
 }
 
+const (
+	// The file is compacted after it grows by this many bytes since the previous compaction.
+	defaultFileStoreCompactionSizeThreshold = 64 * 1024 * 1024
+	// The file is compacted when at least this share of its records is superseded by later ones.
+	defaultFileStoreCompactionGarbageRatio = 0.5
+	defaultFileStoreSyncPolicy             = FileStoreSyncBatch
+	// How long a batch collects writes before they are flushed together.
+	defaultFileStoreSyncInterval = 10 * time.Millisecond
+)
+
 func (c *FileStoreConfiguration) String() string {
-	return fmt.Sprintf("&FileStoreConfiguration{FilePath:%v}", c.FilePath)
+	return fmt.Sprintf(
+		"&FileStoreConfiguration{FilePath:%v CompactionSizeThreshold:%v CompactionGarbageRatio:%v SyncPolicy:%v SyncInterval:%v}",
+		c.FilePath,
+		c.CompactionSizeThreshold,
+		c.CompactionGarbageRatio,
+		c.SyncPolicy,
+		c.SyncInterval)
 }
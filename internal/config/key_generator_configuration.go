@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Key generation strategies.
+const (
+	// KeyGeneratorRandom makes random keys.
+	KeyGeneratorRandom = "random"
+	// KeyGeneratorSequence encodes the next value of a sequence, keys are short and predictable.
+	KeyGeneratorSequence = "sequence"
+	// KeyGeneratorHash derives the key from the URL, the same URL always gets the same key.
+	KeyGeneratorHash = "hash"
+	// KeyGeneratorObfuscated shuffles sequence values with a secret, keys are unique but look random.
+	KeyGeneratorObfuscated = "obfuscated"
+)
+
+// Characters that need no escaping in URL paths. The dot is left out, keys "." and ".." are path segments.
+const keyAlphabetAllowedCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_~"
+
+type KeyGeneratorConfiguration struct {
+	Type string `env:"KEY_GENERATOR" validate:"oneof=random sequence hash obfuscated"`
+	// Empty alphabet means the default one of the generator.
+	Alphabet string `env:"KEY_ALPHABET" validate:"omitempty,min=2"`
+	Secret   string `env:"KEY_GENERATOR_SECRET"`
+	// Number of sequence values leased from the store at once.
+	BlockSize int64 `env:"KEY_BLOCK_SIZE" validate:"gt=0"`
+}
+
+const (
+	defaultKeyGenerator = KeyGeneratorRandom
+	defaultKeyBlockSize = 1000
+)
+
+func (c *KeyGeneratorConfiguration) String() string {
+	secret := lo.Ternary(len(c.Secret) == 0, "", "*****")
+	return fmt.Sprintf(
+		"&KeyGeneratorConfiguration{Type:%v Alphabet:'%v' Secret:'%v' BlockSize:%v}",
+		c.Type,
+		c.Alphabet,
+		secret,
+		c.BlockSize,
+	)
+}
+
+// ValidateSecret requires the secret of the obfuscated generator. Without it keys are a public reversible function
+// of the sequence, and anyone could enumerate every stored URL.
+func (c *KeyGeneratorConfiguration) ValidateSecret() error {
+	if c.Type == KeyGeneratorObfuscated && len(c.Secret) == 0 {
+		return errors.New("key generator secret is required for obfuscated keys")
+	}
+
+	return nil
+}
+
+// ValidateAlphabet checks what the validator tags can't: characters are URL safe and not repeated.
+func (c *KeyGeneratorConfiguration) ValidateAlphabet() error {
+	for i, ch := range c.Alphabet {
+		if !strings.ContainsRune(keyAlphabetAllowedCharacters, ch) {
+			return fmt.Errorf("key alphabet character '%c' is not allowed", ch)
+		}
+
+		if strings.ContainsRune(c.Alphabet[:i], ch) {
+			return fmt.Errorf("key alphabet character '%c' is repeated", ch)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyGeneratorConfiguration_String(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	configuration := KeyGeneratorConfiguration{
+		Type:   KeyGeneratorObfuscated,
+		Secret: "secret",
+	}
+
+	// Act.
+	str := configuration.String()
+
+	// Assert.
+	require.NotContains(t, str, "secret")
+}
+
+func TestKeyGeneratorConfiguration_ValidateSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		configuration *KeyGeneratorConfiguration
+		wantError     bool
+	}{
+		{
+			name:          "WHEN random without secret THEN ok",
+			configuration: &KeyGeneratorConfiguration{Type: KeyGeneratorRandom},
+		},
+		{
+			name:          "WHEN obfuscated with secret THEN ok",
+			configuration: &KeyGeneratorConfiguration{Type: KeyGeneratorObfuscated, Secret: "secret"},
+		},
+		{
+			name:          "WHEN obfuscated with empty secret THEN error",
+			configuration: &KeyGeneratorConfiguration{Type: KeyGeneratorObfuscated},
+			wantError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act.
+			err := tt.configuration.ValidateSecret()
+
+			// Assert.
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestKeyGeneratorConfiguration_ValidateAlphabet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		alphabet  string
+		wantError bool
+	}{
+		{
+			name: "WHEN empty THEN ok",
+		},
+		{
+			name:     "WHEN no ambiguous characters THEN ok",
+			alphabet: "23456789abcdefghijkmnpqrstuvwxyz",
+		},
+		{
+			name:      "WHEN character needs escaping THEN error",
+			alphabet:  "ab/",
+			wantError: true,
+		},
+		{
+			name:      "WHEN dot THEN error",
+			alphabet:  "ab.",
+			wantError: true,
+		},
+		{
+			name:      "WHEN character repeated THEN error",
+			alphabet:  "aba",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			configuration := &KeyGeneratorConfiguration{Alphabet: tt.alphabet}
+
+			// Act.
+			err := configuration.ValidateAlphabet()
+
+			// Assert.
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Handling of query parameters in canonical URLs.
+const (
+	// URLQueryKeep leaves the query as it is.
+	URLQueryKeep = "keep"
+	// URLQuerySort sorts query parameters by name.
+	URLQuerySort = "sort"
+	// URLQueryStrip removes tracking parameters and sorts the rest by name.
+	URLQueryStrip = "strip"
+)
+
+type URLCanonicalizationConfiguration struct {
+	QueryMode string `env:"URL_QUERY_MODE" validate:"oneof=keep sort strip"`
+	// Comma separated names of tracking parameters, a trailing * matches any suffix.
+	TrackingParameters string `env:"URL_TRACKING_PARAMETERS"`
+}
+
+const (
+	defaultURLQueryMode          = URLQueryStrip
+	defaultURLTrackingParameters = "utm_*,fbclid,gclid,dclid,yclid,msclkid,mc_cid,mc_eid,_openstat"
+)
+
+func (c *URLCanonicalizationConfiguration) String() string {
+	return fmt.Sprintf(
+		"&URLCanonicalizationConfiguration{QueryMode:%v TrackingParameters:'%v'}",
+		c.QueryMode,
+		c.TrackingParameters,
+	)
+}
+
+// GetTrackingParameters returns the list of tracking parameter names without empty ones.
+func (c *URLCanonicalizationConfiguration) GetTrackingParameters() []string {
+	parameters := []string{}
+	for parameter := range strings.SplitSeq(c.TrackingParameters, ",") {
+		if parameter = strings.TrimSpace(parameter); len(parameter) > 0 {
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	return parameters
+}
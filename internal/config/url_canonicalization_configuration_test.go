@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLCanonicalizationConfiguration_String(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	configuration := URLCanonicalizationConfiguration{
+		QueryMode:          URLQueryStrip,
+		TrackingParameters: "utm_*",
+	}
+
+	// Act.
+	str := configuration.String()
+
+	// Assert.
+	require.NotEmpty(t, str)
+}
+
+func TestURLCanonicalizationConfiguration_GetTrackingParameters(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	configuration := URLCanonicalizationConfiguration{
+		TrackingParameters: " utm_*, ,fbclid,",
+	}
+
+	// Act.
+	parameters := configuration.GetTrackingParameters()
+
+	// Assert.
+	require.Equal(t, []string{"utm_*", "fbclid"}, parameters)
+}
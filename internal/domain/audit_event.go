@@ -13,6 +13,7 @@ const (
 	AudotActionUnknown AuditAction = iota
 	AuditActionShorten
 	AuditActionFollow
+	AuditActionUpdate
 )
 
 type AuditFormattedTime time.Time
@@ -22,6 +23,7 @@ type AuditEvent struct {
 	Action    AuditAction        `json:"action"`
 	UserID    uuid.UUID          `json:"user_id"`
 	URL       string             `json:"url"`
+	Variant   string             `json:"variant,omitempty"`
 }
 
 func (action AuditAction) MarshalJSON() ([]byte, error) {
@@ -33,6 +35,8 @@ func (action AuditAction) MarshalJSON() ([]byte, error) {
 		str = "shorten"
 	case AuditActionFollow:
 		str = "follow"
+	case AuditActionUpdate:
+		str = "update"
 	default:
 		return nil, fmt.Errorf("unknown action: %v", action)
 	}
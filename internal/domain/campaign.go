@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Campaign is a named set of query parameters of one user, they are added to the URLs of the campaign on redirect.
+type Campaign struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	// Parameters are query parameter names with their values, UTM ones included.
+	Parameters map[string]string
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+type ClickBucket string
+
+const (
+	ClickBucketHour ClickBucket = "hour"
+	ClickBucketDay  ClickBucket = "day"
+	ClickBucketWeek ClickBucket = "week"
+)
+
+// Truncate returns the start of the bucket containing the given time, in UTC.
+// Weeks start on Monday, the same way as date_trunc('week', ...) in Postgres.
+func (b ClickBucket) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch b {
+	case ClickBucketHour:
+		return t.Truncate(time.Hour)
+	case ClickBucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Next returns the start of the bucket following the one that starts at the given time.
+func (b ClickBucket) Next(start time.Time) time.Time {
+	switch b {
+	case ClickBucketHour:
+		return start.Add(time.Hour)
+	case ClickBucketWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+type ClickStatisticsRequest struct {
+	Key      string
+	From     time.Time
+	To       time.Time
+	Bucket   ClickBucket
+	TopCount int
+}
+
+type ClickBucketCount struct {
+	Start time.Time
+	Count int
+}
+
+type ClickValueCount struct {
+	Value string
+	Count int
+}
+
+type ClickStatistics struct {
+	TotalClicks    int
+	UniqueVisitors int
+	Buckets        []*ClickBucketCount
+	TopReferrers   []*ClickValueCount
+	TopUserAgents  []*ClickValueCount
+	// Variants are the clicks of every variant the visitors were sent to.
+	Variants []*ClickValueCount
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection is a named group of URLs of one user. A URL can be in any number of collections.
+type Collection struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	// URLCount is the number of URLs in the collection, it is filled by loading only.
+	URLCount int
+}
@@ -1,34 +1,202 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"cmp"
+	"time"
 
+	"github.com/google/uuid"
+)
+
+type ColdStoreEntryType string
+
+const (
+	// ColdStoreEntryTypePut stores the whole item, replacing the previous one with the same key.
+	// Entries written before the types were introduced have no type and are treated as puts.
+	ColdStoreEntryTypePut ColdStoreEntryType = "put"
+	// ColdStoreEntryTypeDelete marks the item as deleted by its owner.
+	ColdStoreEntryTypeDelete ColdStoreEntryType = "delete"
+	// ColdStoreEntryTypeRestore reverts the deletion of the item by its owner.
+	ColdStoreEntryTypeRestore ColdStoreEntryType = "restore"
+	// ColdStoreEntryTypePurge removes the deleted item for good, its key becomes free.
+	ColdStoreEntryTypePurge ColdStoreEntryType = "purge"
+	// ColdStoreEntryTypeUpdate points the item to another value, the previous one goes to the item versions.
+	ColdStoreEntryTypeUpdate ColdStoreEntryType = "update"
+	// ColdStoreEntryTypeAccess records the last redirect by the item, only a later moment replaces the stored one.
+	ColdStoreEntryTypeAccess ColdStoreEntryType = "access"
+	// ColdStoreEntryTypeUpdateOwner changes the owner of the item.
+	ColdStoreEntryTypeUpdateOwner ColdStoreEntryType = "update_owner"
+	// ColdStoreEntryTypeUpdateVariants replaces the variants of the item, the value stays the same.
+	ColdStoreEntryTypeUpdateVariants ColdStoreEntryType = "update_variants"
+	// ColdStoreEntryTypeCollection stores the whole collection of URLs, replacing the previous one with the same ID.
+	// The entry key is the collection ID, the value is the collection name.
+	ColdStoreEntryTypeCollection ColdStoreEntryType = "collection"
+	// ColdStoreEntryTypeDeleteCollection removes the collection, its URLs are kept.
+	ColdStoreEntryTypeDeleteCollection ColdStoreEntryType = "delete_collection"
+	// ColdStoreEntryTypeCampaign stores the whole campaign, replacing the previous one with the same ID.
+	// The entry key is the campaign ID, the value is the campaign name.
+	ColdStoreEntryTypeCampaign ColdStoreEntryType = "campaign"
+	// ColdStoreEntryTypeDeleteCampaign removes the campaign, its URLs are kept without it.
+	ColdStoreEntryTypeDeleteCampaign ColdStoreEntryType = "delete_campaign"
+	// ColdStoreEntryTypeSequence records the end of the last leased block of key sequence values, it has no key.
+	ColdStoreEntryTypeSequence ColdStoreEntryType = "sequence"
+)
+
+// ColdStoreEntry is appended on every change of a key.
 type ColdStoreEntry struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Type  ColdStoreEntryType `json:"type,omitempty"`
+	Key   string             `json:"key,omitempty"`
+	Value string             `json:"value,omitempty"`
+	// CanonicalURL is the value used for deduplication, empty when it equals the value.
+	CanonicalURL string `json:"canonical_url,omitempty"`
+	// DedupScope is the deduplication scope the item was saved with, absent in entries written before it was recorded.
+	DedupScope string     `json:"dedup_scope,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	UserID     uuid.UUID  `json:"user_id,omitzero"`
+	IsDeleted  bool       `json:"is_deleted,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	// UpdatedAt is the moment of the last change of the item, absent in entries written before it was recorded.
+	UpdatedAt      *time.Time    `json:"updated_at,omitempty"`
+	LastAccessedAt *time.Time    `json:"last_accessed_at,omitempty"`
+	Versions       []*URLVersion `json:"versions,omitempty"`
+	// Version of an update entry is the version of the value it replaces, the update is already applied
+	// when the item has that many previous values. Absent in entries written before it was recorded.
+	Version int      `json:"version,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Notes   string   `json:"notes,omitempty"`
+	// RedirectCode is the HTTP status of redirects by the item, zero means the deployment default.
+	RedirectCode int `json:"redirect_code,omitempty"`
+	// Passthrough appends the rest of the request path and the query to the value on redirect.
+	Passthrough bool `json:"passthrough,omitempty"`
+	// CampaignID is the campaign of the item, its parameters are added to the value on redirect.
+	CampaignID uuid.UUID `json:"campaign_id,omitzero"`
+	// Rules select alternate values on redirect, in order.
+	Rules []*RedirectRule `json:"rules,omitempty"`
+	// Variants split the redirects of the item between weighted values.
+	Variants []*URLVariant `json:"variants,omitempty"`
+	// Keys are the URL keys of a collection entry.
+	Keys []string `json:"keys,omitempty"`
+	// Parameters are the query parameters of a campaign entry.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Sequence   uint64            `json:"sequence,omitempty"`
+}
+
+type SaveRequest struct {
+	OriginalURL string
+	// CanonicalURL is used for deduplication instead of the original URL, when set.
+	CanonicalURL string
+	Alias        string
+	ExpiresAt    *time.Time
+	Title        string
+	// Tags are normalized by the caller: lower case, sorted and without duplicates.
+	Tags  []string
+	Notes string
+	// RedirectCode is the HTTP status of redirects by the item, zero means the deployment default.
+	RedirectCode int
+	// Passthrough appends the rest of the request path and the query to the original URL on redirect.
+	Passthrough bool
+	// CampaignID is the campaign of the user whose parameters are added to the original URL on redirect.
+	CampaignID uuid.UUID
+	// Rules select alternate destinations on redirect, the original URL is the fallback.
+	Rules []*RedirectRule
+	// Variants split the redirects between weighted destinations, the original URL is used when all weights are zero.
+	Variants []*URLVariant
+}
+
+// DedupURL returns the URL which is compared with the stored ones for deduplication.
+func (r *SaveRequest) DedupURL() string {
+	return cmp.Or(r.CanonicalURL, r.OriginalURL)
+}
+
+type UpdateRequest struct {
+	Key         string
+	OriginalURL string
+	// CanonicalURL is used for deduplication instead of the original URL, when set.
+	CanonicalURL string
+}
+
+// DedupURL returns the URL which is compared with the stored ones for deduplication.
+func (r *UpdateRequest) DedupURL() string {
+	return cmp.Or(r.CanonicalURL, r.OriginalURL)
+}
+
+// URLVersion is a previous value of a key. The first value of a key has version 1.
+type URLVersion struct {
+	Version     int    `json:"version"`
+	OriginalURL string `json:"original_url"`
+	// ReplacedAt is the moment the value was replaced by the next one.
+	ReplacedAt time.Time `json:"replaced_at"`
 }
 
 type BatchRequestItem struct {
+	SaveRequest
 	CorrelationID string
-	OriginalURL   string
 }
 
+// BatchItemStatus is the outcome of saving one item of a batch.
+type BatchItemStatus string
+
+const (
+	// BatchItemStatusCreated means the item is saved with a new key.
+	BatchItemStatusCreated BatchItemStatus = "created"
+	// BatchItemStatusExisting means the URL is already saved, the key is the existing one.
+	BatchItemStatusExisting BatchItemStatus = "existing"
+	// BatchItemStatusInvalid means the item is not saved, the reason tells why.
+	BatchItemStatusInvalid BatchItemStatus = "invalid"
+)
+
 type BatchResponseItem struct {
 	CorrelationID string
 	Key           string
+	Status        BatchItemStatus
+	Reason        string
 }
 
 type KeyOriginalURLItem struct {
-	URLKey      string
-	OriginalURL string
+	URLKey         string
+	OriginalURL    string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LastAccessedAt *time.Time
+	Title          string
+	Tags           []string
+	Notes          string
 }
 
 type URLItem struct {
-	URL       string
-	UserID    uuid.UUID
-	IsDeleted bool
+	URL            string
+	UserID         uuid.UUID
+	IsDeleted      bool
+	DeletedAt      *time.Time
+	ExpiresAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LastAccessedAt *time.Time
+	// RedirectCode is zero when the item uses the deployment default.
+	RedirectCode int
+	Passthrough  bool
+	// CampaignParameters are the query parameters of the item campaign, nil when the item has none.
+	CampaignParameters map[string]string
+	Rules              []*RedirectRule
+	Variants           []*URLVariant
+}
+
+func (i *URLItem) IsExpired(now time.Time) bool {
+	return i.ExpiresAt != nil && !i.ExpiresAt.After(now)
 }
 
 type DeleteURLsRequest struct {
 	Keys   []string
 	UserID uuid.UUID
 }
+
+type Click struct {
+	Key       string
+	Timestamp time.Time
+	Referrer  string
+	UserAgent string
+	IPHash    string
+	// Variant is the name of the URL variant the visitor was sent to, empty when the URL has none.
+	Variant string
+}
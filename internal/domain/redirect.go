@@ -0,0 +1,8 @@
+package domain
+
+import "net/http"
+
+// IsPermanentRedirect reports whether browsers and search engines may cache the redirect.
+func IsPermanentRedirect(code int) bool {
+	return code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect
+}
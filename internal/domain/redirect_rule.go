@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Client platforms recognized by the User-Agent header.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformDesktop = "desktop"
+)
+
+// RedirectRule selects an alternate destination of a URL. All the set conditions must match,
+// a rule without conditions is not allowed. The rules of a URL are checked in order, the first match wins.
+type RedirectRule struct {
+	// Platform is one of PlatformIOS, PlatformAndroid and PlatformDesktop.
+	Platform string `json:"platform,omitempty"`
+	// Language is a language tag, it matches the same or a more specific tag from the Accept-Language header.
+	Language string `json:"language,omitempty"`
+	// CIDR is the network of the client IP address.
+	CIDR string `json:"cidr,omitempty"`
+	// From and To bound the time window, the end is exclusive.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+	URL  string     `json:"url"`
+}
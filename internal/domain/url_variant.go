@@ -0,0 +1,9 @@
+package domain
+
+// URLVariant is one of the weighted destinations of a URL. A visitor is assigned to a variant with the probability
+// of its weight share, zero weight takes the variant out of the split. Names are unique within a URL.
+type URLVariant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
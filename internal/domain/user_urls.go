@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserURLsSort string
+
+const (
+	// UserURLsSortCreated orders URLs by creation time, URLs created at the same moment are ordered by key.
+	UserURLsSortCreated UserURLsSort = "created"
+	UserURLsSortKey     UserURLsSort = "key"
+)
+
+type UserURLsStatus string
+
+const (
+	UserURLsStatusAll UserURLsStatus = "all"
+	// UserURLsStatusActive matches URLs which are neither deleted nor expired.
+	UserURLsStatusActive  UserURLsStatus = "active"
+	UserURLsStatusDeleted UserURLsStatus = "deleted"
+)
+
+// UserURLsCursor is the position of the last URL of the previous page, the page starts right after it.
+type UserURLsCursor struct {
+	CreatedAt time.Time
+	URLKey    string
+}
+
+type UserURLsQuery struct {
+	Sort       UserURLsSort
+	Descending bool
+	Status     UserURLsStatus
+	// Search is a list of words, each of them is a case-insensitive word of the original URL,
+	// the title or the notes of a matching URL. Words are made of letters and digits. Empty search matches any URL.
+	Search string
+	// Tag is a tag every matching URL has, empty matches any URL.
+	Tag string
+	// Collection is the ID of a collection every matching URL is in, zero matches any URL.
+	Collection uuid.UUID
+	After      *UserURLsCursor
+	// Limit is the maximum number of URLs to return, zero means no limit.
+	Limit int
+}
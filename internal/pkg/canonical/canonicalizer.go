@@ -0,0 +1,146 @@
+// Package canonical brings URLs to the canonical form used for deduplication.
+package canonical
+
+import (
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	"github.com/aleffnull/shortener/internal/config"
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+type Canonicalizer struct {
+	queryMode          string
+	trackingParameters []string
+}
+
+// NewCanonicalizer creates a canonicalizer, nil configuration keeps the query as it is.
+func NewCanonicalizer(cfg *config.URLCanonicalizationConfiguration) *Canonicalizer {
+	if cfg == nil {
+		return &Canonicalizer{queryMode: config.URLQueryKeep}
+	}
+
+	return &Canonicalizer{
+		queryMode:          cfg.QueryMode,
+		trackingParameters: cfg.GetTrackingParameters(),
+	}
+}
+
+// Canonicalize lowercases the scheme and the host, converts the host to punycode, drops the default port,
+// cleans the path and handles the query according to the configuration.
+// The fragment is kept. URLs which can't be parsed are returned as is.
+func (c *Canonicalizer) Canonicalize(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, ok := canonicalizeHost(u.Scheme, u.Host)
+	if !ok {
+		return rawURL
+	}
+
+	u.Host = host
+	u.Path = canonicalizePath(u.Path)
+	u.RawPath = ""
+	u.ForceQuery = false
+	u.RawQuery = c.canonicalizeQuery(u.RawQuery)
+
+	return u.String()
+}
+
+func canonicalizeHost(scheme, hostPort string) (string, bool) {
+	host, port := hostPort, ""
+	if h, p, err := net.SplitHostPort(hostPort); err == nil {
+		host, port = h, p
+	} else if strings.HasSuffix(hostPort, ":") {
+		host = strings.TrimSuffix(hostPort, ":")
+	}
+
+	host = strings.ToLower(host)
+	if net.ParseIP(host) == nil {
+		ascii, err := idna.Lookup.ToASCII(host)
+		if err != nil {
+			return "", false
+		}
+
+		host = ascii
+	}
+
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+
+	if len(port) == 0 {
+		if strings.Contains(host, ":") {
+			return "[" + host + "]", true
+		}
+
+		return host, true
+	}
+
+	return net.JoinHostPort(host, port), true
+}
+
+func canonicalizePath(p string) string {
+	if len(p) == 0 {
+		return "/"
+	}
+
+	cleaned := path.Clean(p)
+	if !strings.HasPrefix(cleaned, "/") {
+		cleaned = "/" + cleaned
+	}
+
+	return cleaned
+}
+
+func (c *Canonicalizer) canonicalizeQuery(rawQuery string) string {
+	if len(rawQuery) == 0 || c.queryMode == config.URLQueryKeep {
+		return rawQuery
+	}
+
+	parameters := strings.Split(rawQuery, "&")
+	if c.queryMode == config.URLQueryStrip {
+		parameters = slices.DeleteFunc(parameters, func(parameter string) bool {
+			return len(parameter) == 0 || c.isTracking(parameterName(parameter))
+		})
+	}
+
+	slices.SortStableFunc(parameters, func(a, b string) int {
+		return strings.Compare(parameterName(a), parameterName(b))
+	})
+
+	return strings.Join(parameters, "&")
+}
+
+func (c *Canonicalizer) isTracking(name string) bool {
+	name = strings.ToLower(name)
+	return slices.ContainsFunc(c.trackingParameters, func(pattern string) bool {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			return strings.HasPrefix(name, prefix)
+		}
+
+		return name == pattern
+	})
+}
+
+func parameterName(parameter string) string {
+	name, _, _ := strings.Cut(parameter, "=")
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		return unescaped
+	}
+
+	return name
+}
@@ -0,0 +1,141 @@
+package canonical
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/aleffnull/shortener/internal/config"
+)
+
+func TestCanonicalizer_Canonicalize(t *testing.T) {
+	t.Parallel()
+
+	stripConfiguration := &config.URLCanonicalizationConfiguration{
+		QueryMode:          config.URLQueryStrip,
+		TrackingParameters: "utm_*,fbclid",
+	}
+
+	tests := []struct {
+		name          string
+		configuration *config.URLCanonicalizationConfiguration
+		url           string
+		want          string
+	}{
+		{
+			name: "WHEN upper case scheme and host THEN lowercased",
+			url:  "HTTP://Example.COM/Path",
+			want: "http://example.com/Path",
+		},
+		{
+			name: "WHEN default http port THEN dropped",
+			url:  "http://example.com:80/a",
+			want: "http://example.com/a",
+		},
+		{
+			name: "WHEN default https port THEN dropped",
+			url:  "https://example.com:443/a",
+			want: "https://example.com/a",
+		},
+		{
+			name: "WHEN other port THEN kept",
+			url:  "https://example.com:80/a",
+			want: "https://example.com:80/a",
+		},
+		{
+			name: "WHEN empty port THEN dropped",
+			url:  "http://example.com:/a",
+			want: "http://example.com/a",
+		},
+		{
+			name: "WHEN IDN THEN punycode",
+			url:  "http://Пример.рф/a",
+			want: "http://xn--e1afmkfd.xn--p1ai/a",
+		},
+		{
+			name: "WHEN IPv6 with default port THEN brackets kept",
+			url:  "http://[::1]:80/a",
+			want: "http://[::1]/a",
+		},
+		{
+			name: "WHEN trailing slash and dot segments THEN path cleaned",
+			url:  "http://example.com/a/./b/../c//",
+			want: "http://example.com/a/c",
+		},
+		{
+			name: "WHEN empty path THEN root",
+			url:  "http://example.com",
+			want: "http://example.com/",
+		},
+		{
+			name: "WHEN empty query THEN removed",
+			url:  "http://example.com/a?",
+			want: "http://example.com/a",
+		},
+		{
+			name: "WHEN keep mode THEN query as is",
+			configuration: &config.URLCanonicalizationConfiguration{
+				QueryMode:          config.URLQueryKeep,
+				TrackingParameters: "utm_*",
+			},
+			url:  "http://example.com/a?b=1&utm_source=x&a=2",
+			want: "http://example.com/a?b=1&utm_source=x&a=2",
+		},
+		{
+			name: "WHEN sort mode THEN sorted by name",
+			configuration: &config.URLCanonicalizationConfiguration{
+				QueryMode:          config.URLQuerySort,
+				TrackingParameters: "utm_*",
+			},
+			url:  "http://example.com/a?b=1&utm_source=x&a=2&a=1",
+			want: "http://example.com/a?a=2&a=1&b=1&utm_source=x",
+		},
+		{
+			name:          "WHEN strip mode THEN tracking parameters removed",
+			configuration: stripConfiguration,
+			url:           "http://example.com/a?b=1&UTM_Source=x&fbclid=y&a=2",
+			want:          "http://example.com/a?a=2&b=1",
+		},
+		{
+			name:          "WHEN only tracking parameters THEN query removed",
+			configuration: stripConfiguration,
+			url:           "http://example.com/a?utm_source=x&fbclid=y",
+			want:          "http://example.com/a",
+		},
+		{
+			name: "WHEN fragment THEN kept",
+			url:  "http://example.com/a#Top",
+			want: "http://example.com/a#Top",
+		},
+		{
+			name: "WHEN no host THEN as is",
+			url:  "mailto:someone@example.com",
+			want: "mailto:someone@example.com",
+		},
+		{
+			name: "WHEN not parsed THEN as is",
+			url:  "http://example.com/%zz",
+			want: "http://example.com/%zz",
+		},
+		{
+			name: "WHEN invalid IDN THEN as is",
+			url:  "http://xn--a.com/a",
+			want: "http://xn--a.com/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			canonicalizer := NewCanonicalizer(tt.configuration)
+
+			// Act.
+			canonicalURL := canonicalizer.Canonicalize(tt.url)
+
+			// Assert.
+			require.Equal(t, tt.want, canonicalURL)
+		})
+	}
+}
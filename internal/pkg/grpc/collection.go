@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func parseCollectionID(id string) (uuid.UUID, error) {
+	collectionID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "Invalid collection ID: %v", err)
+	}
+
+	return collectionID, nil
+}
+
+func validateCollectionRequest(request *models.CollectionRequest) error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(request); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	return nil
+}
+
+func collectionError(err error) error {
+	switch {
+	case errors.Is(err, app.ErrCollectionNotFound):
+		return status.Errorf(codes.NotFound, "Collection was not found")
+	case errors.Is(err, app.ErrDuplicateCollection):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
+
+func toAPICollection(item *models.CollectionItem) *api.Collection {
+	return &api.Collection{
+		Id:        item.ID.String(),
+		Name:      item.Name,
+		CreatedAt: timestamppb.New(item.CreatedAt),
+		UrlCount:  int64(item.URLCount),
+	}
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ShortenerService) AddCollectionURLs(
+	ctx context.Context,
+	request *api.CollectionURLsRequest,
+) (*api.CollectionResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := request.GetUrlId()
+	if len(keys) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Keys are required")
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := s.shortener.AddCollectionURLs(ctx, collectionID, keys, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionResponse{
+		Collection: toAPICollection(collection),
+	}, nil
+}
+
+func (s *ShortenerService) RemoveCollectionURLs(
+	ctx context.Context,
+	request *api.CollectionURLsRequest,
+) (*api.CollectionResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := request.GetUrlId()
+	if len(keys) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Keys are required")
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := s.shortener.RemoveCollectionURLs(ctx, collectionID, keys, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionResponse{
+		Collection: toAPICollection(collection),
+	}, nil
+}
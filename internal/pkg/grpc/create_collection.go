@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+)
+
+func (s *ShortenerService) CreateCollection(
+	ctx context.Context,
+	request *api.CollectionCreateRequest,
+) (*api.CollectionResponse, error) {
+	collectionRequest := &models.CollectionRequest{
+		Name: request.GetName(),
+	}
+	if err := validateCollectionRequest(collectionRequest); err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := s.shortener.CreateCollection(ctx, collectionRequest, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionResponse{
+		Collection: toAPICollection(collection),
+	}, nil
+}
@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+)
+
+func (s *ShortenerService) DeleteCollection(
+	ctx context.Context,
+	request *api.CollectionRequest,
+) (*api.CollectionDeleteResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if err = s.shortener.DeleteCollection(ctx, collectionID, userID); err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionDeleteResponse{}, nil
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeleteCollection(t *testing.T) {
+	t.Parallel()
+
+	collectionID := uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d")
+
+	type want struct {
+		code     *codes.Code
+		response *api.CollectionDeleteResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.CollectionRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN invalid ID THEN invalid argument",
+			request: &api.CollectionRequest{Id: "foo"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN collection not found THEN not found",
+			request: &api.CollectionRequest{Id: collectionID.String()},
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().DeleteCollection(gomock.Any(), collectionID, gomock.Any()).Return(app.ErrCollectionNotFound)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.CollectionRequest{Id: collectionID.String()},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().DeleteCollection(gomock.Any(), collectionID, gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN empty response",
+			request: &api.CollectionRequest{Id: collectionID.String()},
+			want: &want{
+				response: &api.CollectionDeleteResponse{},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().DeleteCollection(gomock.Any(), collectionID, gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.DeleteCollection(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+)
+
+func (s *ShortenerService) DeleteCollectionURLs(
+	ctx context.Context,
+	request *api.CollectionRequest,
+) (*api.CollectionURLsDeleteResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	keys, err := s.shortener.DeleteCollectionURLs(ctx, collectionID, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionURLsDeleteResponse{
+		UrlId: keys,
+	}, nil
+}
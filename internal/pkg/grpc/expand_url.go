@@ -2,14 +2,22 @@ package grpc
 
 import (
 	"context"
+	"net"
 	"time"
 
+	"github.com/aleffnull/shortener/internal/app"
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+const visitorIDMetadataKey = "x-visitor-id"
+
 func (s *ShortenerService) ExpandURL(
 	ctx context.Context,
 	request *api.URLExpandRequest,
@@ -32,14 +40,85 @@ func (s *ShortenerService) ExpandURL(
 		return nil, status.Errorf(codes.NotFound, "Key was deleted")
 	}
 
+	if item.IsExpired {
+		return nil, status.Errorf(codes.NotFound, "Key has expired")
+	}
+
+	// Правила и варианты выбираются так же, как при переходе по HTTP, по метаданным запроса.
+	now := time.Now()
+	variantName := ""
+	if !app.SelectRedirectURL(item, &app.RedirectClient{
+		UserAgent:      getUserAgent(ctx),
+		AcceptLanguage: getMetadataValue(ctx, "accept-language"),
+		IP:             getClientIP(ctx),
+		Now:            now,
+	}) && len(item.Variants) > 0 {
+		variantName = app.SelectURLVariant(item, key, getVisitorID(ctx))
+	}
+
 	s.auditService.AuditEvent(&domain.AuditEvent{
-		Timestamp: domain.AuditFormattedTime(time.Now()),
+		Timestamp: domain.AuditFormattedTime(now),
 		Action:    domain.AuditActionFollow,
 		UserID:    item.UserID,
 		URL:       item.URL,
+		Variant:   variantName,
 	})
+	s.clickService.RegisterClick(&domain.Click{
+		Key:       key,
+		Timestamp: now,
+		UserAgent: getUserAgent(ctx),
+		Variant:   variantName,
+	}, getClientIP(ctx))
 
 	return &api.URLExpandResponse{
-		Result: item.URL,
+		Result:       item.URL,
+		RedirectCode: int32(item.RedirectCode),
+		CacheMaxAge:  int64(item.CacheMaxAge / time.Second),
 	}, nil
 }
+
+// getVisitorID возвращает идентификатор посетителя из метаданных, новому посетителю выдается новый идентификатор
+// в заголовке ответа. Клиент передает его в следующих запросах, чтобы остаться в своем варианте.
+func getVisitorID(ctx context.Context) string {
+	if visitorID := getMetadataValue(ctx, visitorIDMetadataKey); len(visitorID) > 0 {
+		return visitorID
+	}
+
+	visitorID := uuid.NewString()
+	// Вне вызова сервера заголовок отправить некуда, посетитель просто получит вариант по новому идентификатору.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(visitorIDMetadataKey, visitorID))
+
+	return visitorID
+}
+
+func getUserAgent(ctx context.Context) string {
+	return getMetadataValue(ctx, "user-agent")
+}
+
+// getMetadataValue возвращает первое значение метаданных запроса или пустую строку.
+func getMetadataValue(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func getClientIP(ctx context.Context) string {
+	if ip := getMetadataValue(ctx, "x-real-ip"); len(ip) > 0 {
+		return ip
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
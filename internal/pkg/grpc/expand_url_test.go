@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -33,6 +35,7 @@ func TestExpandURL(t *testing.T) {
 
 	tests := []struct {
 		name       string
+		metadata   metadata.MD
 		want       *want
 		hookBefore func(mock *mocks.Mock) *api.URLExpandRequest
 	}{
@@ -83,16 +86,34 @@ func TestExpandURL(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "WHEN item expired THEN not found error",
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLExpandRequest {
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(&models.GetURLResponseItem{
+					IsExpired: true,
+				}, nil)
+				return &api.URLExpandRequest{
+					Id: key,
+				}
+			},
+		},
 		{
 			name: "WHEN error THEN ok",
 			want: &want{
 				response: &api.URLExpandResponse{
-					Result: fullURL,
+					Result:       fullURL,
+					RedirectCode: http.StatusPermanentRedirect,
+					CacheMaxAge:  3600,
 				},
 			},
 			hookBefore: func(mock *mocks.Mock) *api.URLExpandRequest {
 				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(&models.GetURLResponseItem{
-					URL: fullURL,
+					URL:          fullURL,
+					RedirectCode: http.StatusPermanentRedirect,
+					CacheMaxAge:  time.Hour,
 				}, nil)
 				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).DoAndReturn(func(event *domain.AuditEvent) {
 					require.LessOrEqual(t, event.Timestamp, time.Now())
@@ -100,6 +121,68 @@ func TestExpandURL(t *testing.T) {
 					require.Equal(t, uuid.UUID{}, event.UserID)
 					require.Equal(t, fullURL, event.URL)
 				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), "").Do(func(click *domain.Click, _ string) {
+					require.Equal(t, key, click.Key)
+					require.LessOrEqual(t, click.Timestamp, time.Now())
+				})
+				return &api.URLExpandRequest{
+					Id: key,
+				}
+			},
+		},
+		{
+			name:     "WHEN rule matches metadata THEN rule URL",
+			metadata: metadata.Pairs("user-agent", "Mozilla/5.0 (iPhone)", "x-real-ip", "10.0.0.1"),
+			want: &want{
+				response: &api.URLExpandResponse{
+					Result:       "http://ios.foo.bar",
+					RedirectCode: http.StatusFound,
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLExpandRequest {
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(&models.GetURLResponseItem{
+					URL:          fullURL,
+					RedirectCode: http.StatusFound,
+					Rules: []*models.RedirectRule{
+						{Platform: domain.PlatformIOS, URL: "http://ios.foo.bar"},
+					},
+				}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).Do(func(event *domain.AuditEvent) {
+					require.Equal(t, "http://ios.foo.bar", event.URL)
+				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), "10.0.0.1").Do(func(click *domain.Click, _ string) {
+					require.Equal(t, "Mozilla/5.0 (iPhone)", click.UserAgent)
+				})
+				return &api.URLExpandRequest{
+					Id: key,
+				}
+			},
+		},
+		{
+			name:     "WHEN item has variants THEN variant URL",
+			metadata: metadata.Pairs("x-visitor-id", "visitor"),
+			want: &want{
+				response: &api.URLExpandResponse{
+					Result:       "http://foo.bar/b",
+					RedirectCode: http.StatusFound,
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLExpandRequest {
+				mock.App.EXPECT().GetURL(gomock.Any(), key).Return(&models.GetURLResponseItem{
+					URL:          fullURL,
+					RedirectCode: http.StatusFound,
+					Variants: []*models.URLVariant{
+						{Name: "A", URL: "http://foo.bar/a"},
+						{Name: "B", URL: "http://foo.bar/b", Weight: 1},
+					},
+				}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any()).Do(func(event *domain.AuditEvent) {
+					require.Equal(t, "http://foo.bar/b", event.URL)
+					require.Equal(t, "B", event.Variant)
+				})
+				mock.ClickService.EXPECT().RegisterClick(gomock.Any(), "").Do(func(click *domain.Click, _ string) {
+					require.Equal(t, "B", click.Variant)
+				})
 				return &api.URLExpandRequest{
 					Id: key,
 				}
@@ -115,10 +198,11 @@ func TestExpandURL(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mock := mocks.NewMock(ctrl)
 			request := tt.hookBefore(mock)
-			service := NewShortenerService(mock.App, mock.AuditService)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
 
 			// Act-assert.
-			response, err := service.ExpandURL(context.Background(), request)
+			ctx := metadata.NewIncomingContext(context.Background(), tt.metadata)
+			response, err := service.ExpandURL(ctx, request)
 			if tt.want.code == nil {
 				require.NoError(t, err)
 				require.Equal(t, tt.want.response, response)
@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/samber/lo"
+)
+
+func (s *ShortenerService) ExportCollection(
+	ctx context.Context,
+	request *api.CollectionRequest,
+) (*api.CollectionExportResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	items, err := s.shortener.ExportCollection(ctx, collectionID, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionExportResponse{
+		Url: lo.Map(items, func(item *models.UserURLsResponseItem, _ int) *api.URLData {
+			return toURLData(item)
+		}),
+	}, nil
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestExportCollection(t *testing.T) {
+	t.Parallel()
+
+	collectionID := uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d")
+	createdAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		code     *codes.Code
+		response *api.CollectionExportResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.CollectionRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN invalid ID THEN invalid argument",
+			request: &api.CollectionRequest{Id: "foo"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN collection not found THEN not found",
+			request: &api.CollectionRequest{Id: collectionID.String()},
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportCollection(gomock.Any(), collectionID, gomock.Any()).Return(nil, app.ErrCollectionNotFound)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.CollectionRequest{Id: collectionID.String()},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportCollection(gomock.Any(), collectionID, gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN URLs",
+			request: &api.CollectionRequest{Id: collectionID.String()},
+			want: &want{
+				response: &api.CollectionExportResponse{
+					Url: []*api.URLData{
+						{
+							ShortUrl:    "http://localhost/abc",
+							OriginalUrl: "http://foo.bar",
+							CreatedAt:   timestamppb.New(createdAt),
+							UpdatedAt:   timestamppb.New(createdAt),
+							Title:       "Foo",
+							Tags:        []string{"work"},
+						},
+					},
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().ExportCollection(gomock.Any(), collectionID, gomock.Any()).Return([]*models.UserURLsResponseItem{
+					{
+						ShortURL:    "http://localhost/abc",
+						OriginalURL: "http://foo.bar",
+						CreatedAt:   createdAt,
+						UpdatedAt:   createdAt,
+						Title:       "Foo",
+						Tags:        []string{"work"},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.ExportCollection(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
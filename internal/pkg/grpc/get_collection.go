@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+)
+
+func (s *ShortenerService) GetCollection(
+	ctx context.Context,
+	request *api.CollectionRequest,
+) (*api.CollectionResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := s.shortener.GetCollection(ctx, collectionID, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionResponse{
+		Collection: toAPICollection(collection),
+	}, nil
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/go-playground/validator/v10"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *ShortenerService) GetURLStats(
+	ctx context.Context,
+	request *api.URLStatsRequest,
+) (*api.URLStatsResponse, error) {
+	key := request.GetId()
+	if len(key) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Key is required")
+	}
+
+	statisticsRequest := &models.ClickStatisticsRequest{
+		Bucket: request.GetBucket(),
+		Top:    int(request.GetTop()),
+	}
+	if request.GetFrom() != nil {
+		statisticsRequest.From = lo.ToPtr(request.GetFrom().AsTime())
+	}
+	if request.GetTo() != nil {
+		statisticsRequest.To = lo.ToPtr(request.GetTo().AsTime())
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(statisticsRequest); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	statistics, err := s.shortener.GetClickStatistics(ctx, key, statisticsRequest, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrURLNotFound):
+			return nil, status.Errorf(codes.NotFound, "Key was not found")
+		case errors.Is(err, app.ErrURLAccessDenied):
+			return nil, status.Errorf(codes.PermissionDenied, "Key belongs to another user")
+		case errors.Is(err, app.ErrInvalidStatisticsInterval):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	return &api.URLStatsResponse{
+		TotalClicks:    int64(statistics.TotalClicks),
+		UniqueVisitors: int64(statistics.UniqueVisitors),
+		From:           timestamppb.New(statistics.From),
+		To:             timestamppb.New(statistics.To),
+		Bucket:         statistics.Bucket,
+		Buckets: lo.Map(statistics.Buckets, func(item *models.ClickBucketItem, _ int) *api.ClickBucket {
+			return &api.ClickBucket{
+				Start: timestamppb.New(item.Start),
+				Count: int64(item.Count),
+			}
+		}),
+		TopReferrers:  toClickValues(statistics.TopReferrers),
+		TopUserAgents: toClickValues(statistics.TopUserAgents),
+		Variants:      toClickValues(statistics.Variants),
+	}, nil
+}
+
+func toClickValues(items []*models.ClickValueItem) []*api.ClickValue {
+	return lo.Map(items, func(item *models.ClickValueItem, _ int) *api.ClickValue {
+		return &api.ClickValue{
+			Value: item.Value,
+			Count: int64(item.Count),
+		}
+	})
+}
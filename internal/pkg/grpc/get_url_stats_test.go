@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetURLStats(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+	from := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	type want struct {
+		code     *codes.Code
+		response *api.URLStatsResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.URLStatsRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN no key THEN invalid argument",
+			request: &api.URLStatsRequest{},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+		},
+		{
+			name:    "WHEN invalid bucket THEN invalid argument",
+			request: &api.URLStatsRequest{Id: key, Bucket: "month"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+		},
+		{
+			name:    "WHEN key not found THEN not found",
+			request: &api.URLStatsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, app.ErrURLNotFound)
+			},
+		},
+		{
+			name:    "WHEN key of another user THEN permission denied",
+			request: &api.URLStatsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.PermissionDenied),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, app.ErrURLAccessDenied)
+			},
+		},
+		{
+			name:    "WHEN invalid interval THEN invalid argument",
+			request: &api.URLStatsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, app.ErrInvalidStatisticsInterval)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.URLStatsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetClickStatistics(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			request: &api.URLStatsRequest{
+				Id:     key,
+				From:   timestamppb.New(from),
+				To:     timestamppb.New(to),
+				Bucket: "day",
+				Top:    5,
+			},
+			want: &want{
+				response: &api.URLStatsResponse{
+					TotalClicks:    3,
+					UniqueVisitors: 2,
+					From:           timestamppb.New(from),
+					To:             timestamppb.New(to),
+					Bucket:         "day",
+					Buckets: []*api.ClickBucket{
+						{Start: timestamppb.New(from), Count: 3},
+					},
+					TopReferrers: []*api.ClickValue{
+						{Value: "http://referrer", Count: 2},
+					},
+					TopUserAgents: []*api.ClickValue{},
+					Variants: []*api.ClickValue{
+						{Value: "B", Count: 1},
+					},
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().
+					GetClickStatistics(gomock.Any(), key, &models.ClickStatisticsRequest{
+						From:   &from,
+						To:     &to,
+						Bucket: "day",
+						Top:    5,
+					}, gomock.Any()).
+					Return(&models.ClickStatisticsResponse{
+						TotalClicks:    3,
+						UniqueVisitors: 2,
+						From:           from,
+						To:             to,
+						Bucket:         "day",
+						Buckets: []*models.ClickBucketItem{
+							{Start: from, Count: 3},
+						},
+						TopReferrers: []*models.ClickValueItem{
+							{Value: "http://referrer", Count: 2},
+						},
+						TopUserAgents: []*models.ClickValueItem{},
+						Variants: []*models.ClickValueItem{
+							{Value: "B", Count: 1},
+						},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			if tt.hookBefore != nil {
+				tt.hookBefore(mock)
+			}
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.GetURLStats(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
@@ -16,7 +16,6 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 func TestGRPC(t *testing.T) {
@@ -61,7 +60,7 @@ func TestGRPC(t *testing.T) {
 	require.Equal(t, fullURL, expandResponse.GetResult())
 
 	// Получаем список URL пользователя.
-	listUserURLsResponse, err := client.ListUserURLs(ctx, &emptypb.Empty{})
+	listUserURLsResponse, err := client.ListUserURLs(ctx, &api.UserURLsRequest{})
 	require.NoError(t, err)
 	require.Len(t, listUserURLsResponse.GetUrl(), 1)
 	require.Equal(t, fullURL, listUserURLsResponse.GetUrl()[0].GetOriginalUrl())
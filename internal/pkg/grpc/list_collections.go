@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ShortenerService) ListCollections(
+	ctx context.Context,
+	_ *api.CollectionsRequest,
+) (*api.CollectionsResponse, error) {
+	userID := middleware.GetUserIDFromContext(ctx)
+	collections, err := s.shortener.GetCollections(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &api.CollectionsResponse{
+		Collection: lo.Map(collections, func(item *models.CollectionItem, _ int) *api.Collection {
+			return toAPICollection(item)
+		}),
+	}, nil
+}
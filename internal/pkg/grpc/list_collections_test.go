@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestListCollections(t *testing.T) {
+	t.Parallel()
+
+	collectionID := uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d")
+	createdAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	item := &models.CollectionItem{ID: collectionID, Name: "work", CreatedAt: createdAt, URLCount: 2}
+	collection := &api.Collection{
+		Id:        collectionID.String(),
+		Name:      "work",
+		CreatedAt: timestamppb.New(createdAt),
+		UrlCount:  2,
+	}
+
+	type want struct {
+		code     *codes.Code
+		response *api.CollectionsResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.CollectionsRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.CollectionsRequest{},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetCollections(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN collections",
+			request: &api.CollectionsRequest{},
+			want: &want{
+				response: &api.CollectionsResponse{Collection: []*api.Collection{collection}},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetCollections(gomock.Any(), gomock.Any()).Return([]*models.CollectionItem{item}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.ListCollections(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *ShortenerService) ListURLVersions(
+	ctx context.Context,
+	request *api.URLVersionsRequest,
+) (*api.URLVersionsResponse, error) {
+	key := request.GetId()
+	if len(key) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Key is required")
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	versions, err := s.shortener.GetURLVersions(ctx, key, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrURLNotFound):
+			return nil, status.Errorf(codes.NotFound, "Key was not found")
+		case errors.Is(err, app.ErrURLAccessDenied):
+			return nil, status.Errorf(codes.PermissionDenied, "Key belongs to another user")
+		default:
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	return &api.URLVersionsResponse{
+		Version: lo.Map(versions, func(item *models.URLVersionItem, _ int) *api.URLVersion {
+			return &api.URLVersion{
+				Version:     int32(item.Version),
+				OriginalUrl: item.OriginalURL,
+				ReplacedAt:  timestamppb.New(item.ReplacedAt),
+			}
+		}),
+	}, nil
+}
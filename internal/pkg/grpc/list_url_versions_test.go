@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestListURLVersions(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+	replacedAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		code     *codes.Code
+		response *api.URLVersionsResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.URLVersionsRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN no key THEN invalid argument",
+			request: &api.URLVersionsRequest{},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN key not found THEN not found",
+			request: &api.URLVersionsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return(nil, app.ErrURLNotFound)
+			},
+		},
+		{
+			name:    "WHEN key of another user THEN permission denied",
+			request: &api.URLVersionsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.PermissionDenied),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return(nil, app.ErrURLAccessDenied)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.URLVersionsRequest{Id: key},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN versions",
+			request: &api.URLVersionsRequest{Id: key},
+			want: &want{
+				response: &api.URLVersionsResponse{
+					Version: []*api.URLVersion{
+						{Version: 1, OriginalUrl: "http://foo.bar", ReplacedAt: timestamppb.New(replacedAt)},
+					},
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetURLVersions(gomock.Any(), key, gomock.Any()).Return([]*models.URLVersionItem{
+					{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: replacedAt},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.ListURLVersions(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
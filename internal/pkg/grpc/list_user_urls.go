@@ -2,34 +2,71 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
+	"github.com/aleffnull/shortener/internal/app"
 	"github.com/aleffnull/shortener/internal/middleware"
 	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
 	"github.com/aleffnull/shortener/models"
+	"github.com/go-playground/validator/v10"
 	"github.com/samber/lo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (s *ShortenerService) ListUserURLs(
 	ctx context.Context,
-	_ *emptypb.Empty,
+	request *api.UserURLsRequest,
 ) (*api.UserURLsResponse, error) {
+	urlsRequest := &models.UserURLsRequest{
+		Limit:      int(request.GetLimit()),
+		Cursor:     request.GetCursor(),
+		Sort:       request.GetSort(),
+		Order:      request.GetOrder(),
+		Status:     request.GetStatus(),
+		Search:     request.GetSearch(),
+		Tag:        request.GetTag(),
+		Collection: request.GetCollection(),
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(urlsRequest); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	userID := middleware.GetUserIDFromContext(ctx)
-	items, err := s.shortener.GetUserURLs(ctx, userID)
+	urls, err := s.shortener.GetUserURLs(ctx, urlsRequest, userID)
 	if err != nil {
+		if errors.Is(err, app.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "%v", err)
 	}
 
-	urlData := lo.Map(items, func(item *models.UserURLsResponseItem, _ int) *api.URLData {
-		return &api.URLData{
-			ShortUrl:    item.ShortURL,
-			OriginalUrl: item.OriginalURL,
-		}
+	urlData := lo.Map(urls.Items, func(item *models.UserURLsResponseItem, _ int) *api.URLData {
+		return toURLData(item)
 	})
 
 	return &api.UserURLsResponse{
-		Url: urlData,
+		Url:        urlData,
+		NextCursor: urls.NextCursor,
 	}, nil
 }
+
+func toURLData(item *models.UserURLsResponseItem) *api.URLData {
+	data := &api.URLData{
+		ShortUrl:    item.ShortURL,
+		OriginalUrl: item.OriginalURL,
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+		UpdatedAt:   timestamppb.New(item.UpdatedAt),
+		Title:       item.Title,
+		Tags:        item.Tags,
+		Notes:       item.Notes,
+	}
+	if item.LastAccessedAt != nil {
+		data.LastAccessedAt = timestamppb.New(*item.LastAccessedAt)
+	}
+
+	return data
+}
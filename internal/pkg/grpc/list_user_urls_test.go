@@ -3,7 +3,9 @@ package grpc
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/aleffnull/shortener/internal/app"
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
 	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
 	"github.com/aleffnull/shortener/models"
@@ -13,7 +15,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestListUserURLs(t *testing.T) {
@@ -24,6 +26,8 @@ func TestListUserURLs(t *testing.T) {
 		shortURL = "http://localhost/abc3"
 	)
 
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
 	type want struct {
 		code     *codes.Code
 		response *api.UserURLsResponse
@@ -31,36 +35,102 @@ func TestListUserURLs(t *testing.T) {
 
 	tests := []struct {
 		name       string
+		request    *api.UserURLsRequest
 		want       *want
 		hookBefore func(mock *mocks.Mock)
 	}{
 		{
-			name: "WHEN app error THEN internal error",
+			name:    "WHEN invalid status THEN invalid argument",
+			request: &api.UserURLsRequest{Status: "foo"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(*mocks.Mock) {},
+		},
+		{
+			name:    "WHEN invalid cursor THEN invalid argument",
+			request: &api.UserURLsRequest{Cursor: "foo"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, app.ErrInvalidCursor)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.UserURLsRequest{},
 			want: &want{
 				code: lo.ToPtr(codes.Internal),
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
 			},
 		},
 		{
 			name: "WHEN no errors THEN ok",
+			request: &api.UserURLsRequest{
+				Limit:  1,
+				Cursor: "abc",
+				Sort:   "key",
+				Order:  "desc",
+				Status: "deleted",
+				Search: "foo",
+				Tag:    "work",
+			},
 			want: &want{
 				response: &api.UserURLsResponse{
 					Url: []*api.URLData{
 						{
-							ShortUrl:    shortURL,
+							ShortUrl:       shortURL,
+							OriginalUrl:    fullURL,
+							CreatedAt:      timestamppb.New(createdAt),
+							UpdatedAt:      timestamppb.New(createdAt),
+							LastAccessedAt: timestamppb.New(createdAt.Add(time.Hour)),
+							Title:          "Foo",
+							Tags:           []string{"work"},
+							Notes:          "Bar",
+						},
+						{
+							ShortUrl:    shortURL + "4",
 							OriginalUrl: fullURL,
+							CreatedAt:   timestamppb.New(createdAt),
+							UpdatedAt:   timestamppb.New(createdAt),
 						},
 					},
+					NextCursor: "def",
 				},
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.App.EXPECT().GetUserURLs(gomock.Any(), gomock.Any()).Return([]*models.UserURLsResponseItem{
-					{
-						ShortURL:    shortURL,
-						OriginalURL: fullURL,
+				request := &models.UserURLsRequest{
+					Limit:  1,
+					Cursor: "abc",
+					Sort:   "key",
+					Order:  "desc",
+					Status: "deleted",
+					Search: "foo",
+					Tag:    "work",
+				}
+				mock.App.EXPECT().GetUserURLs(gomock.Any(), request, gomock.Any()).Return(&models.UserURLsResponse{
+					Items: []*models.UserURLsResponseItem{
+						{
+							ShortURL:       shortURL,
+							OriginalURL:    fullURL,
+							CreatedAt:      createdAt,
+							UpdatedAt:      createdAt,
+							LastAccessedAt: lo.ToPtr(createdAt.Add(time.Hour)),
+							Title:          "Foo",
+							Tags:           []string{"work"},
+							Notes:          "Bar",
+						},
+						{
+							ShortURL:    shortURL + "4",
+							OriginalURL: fullURL,
+							CreatedAt:   createdAt,
+							UpdatedAt:   createdAt,
+						},
 					},
+					NextCursor: "def",
 				}, nil)
 			},
 		},
@@ -74,10 +144,10 @@ func TestListUserURLs(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mock := mocks.NewMock(ctrl)
 			tt.hookBefore(mock)
-			service := NewShortenerService(mock.App, mock.AuditService)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
 
 			// Act-assert.
-			response, err := service.ListUserURLs(context.Background(), &emptypb.Empty{})
+			response, err := service.ListUserURLs(context.Background(), tt.request)
 			if tt.want.code == nil {
 				require.NoError(t, err)
 				require.Equal(t, tt.want.response, response)
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+)
+
+func (s *ShortenerService) RenameCollection(
+	ctx context.Context,
+	request *api.CollectionRenameRequest,
+) (*api.CollectionResponse, error) {
+	collectionID, err := parseCollectionID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	collectionRequest := &models.CollectionRequest{
+		Name: request.GetName(),
+	}
+	if err = validateCollectionRequest(collectionRequest); err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	collection, err := s.shortener.RenameCollection(ctx, collectionID, collectionRequest, userID)
+	if err != nil {
+		return nil, collectionError(err)
+	}
+
+	return &api.CollectionResponse{
+		Collection: toAPICollection(collection),
+	}, nil
+}
@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRenameCollection(t *testing.T) {
+	t.Parallel()
+
+	collectionID := uuid.MustParse("8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d")
+	createdAt := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	item := &models.CollectionItem{ID: collectionID, Name: "work", CreatedAt: createdAt, URLCount: 2}
+	collection := &api.Collection{
+		Id:        collectionID.String(),
+		Name:      "work",
+		CreatedAt: timestamppb.New(createdAt),
+		UrlCount:  2,
+	}
+
+	type want struct {
+		code     *codes.Code
+		response *api.CollectionResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.CollectionRenameRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN invalid ID THEN invalid argument",
+			request: &api.CollectionRenameRequest{Id: "foo", Name: "work"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN empty name THEN invalid argument",
+			request: &api.CollectionRenameRequest{Id: collectionID.String()},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN collection not found THEN not found",
+			request: &api.CollectionRenameRequest{Id: collectionID.String(), Name: "work"},
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().RenameCollection(gomock.Any(), collectionID, gomock.Any(), gomock.Any()).Return(nil, app.ErrCollectionNotFound)
+			},
+		},
+		{
+			name:    "WHEN name is taken THEN already exists",
+			request: &api.CollectionRenameRequest{Id: collectionID.String(), Name: "work"},
+			want: &want{
+				code: lo.ToPtr(codes.AlreadyExists),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().RenameCollection(gomock.Any(), collectionID, gomock.Any(), gomock.Any()).Return(nil, app.ErrDuplicateCollection)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.CollectionRenameRequest{Id: collectionID.String(), Name: "work"},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().RenameCollection(gomock.Any(), collectionID, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN collection",
+			request: &api.CollectionRenameRequest{Id: collectionID.String(), Name: "work"},
+			want: &want{
+				response: &api.CollectionResponse{Collection: collection},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().RenameCollection(gomock.Any(), collectionID, &models.CollectionRequest{Name: "work"}, gomock.Any()).Return(item, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.RenameCollection(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ShortenerService) RestoreURLs(
+	ctx context.Context,
+	request *api.URLRestoreRequest,
+) (*api.URLRestoreResponse, error) {
+	keys := request.GetId()
+	if len(keys) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Keys are required")
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	restored, err := s.shortener.RestoreURLs(ctx, keys, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &api.URLRestoreResponse{
+		Id: restored,
+	}, nil
+}
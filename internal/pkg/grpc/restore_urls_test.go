@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRestoreURLs(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		code     *codes.Code
+		response *api.URLRestoreResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.URLRestoreRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN no keys THEN invalid argument",
+			request: &api.URLRestoreRequest{},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.URLRestoreRequest{Id: []string{"foo"}},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().RestoreURLs(gomock.Any(), []string{"foo"}, gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN restored keys",
+			request: &api.URLRestoreRequest{Id: []string{"foo", "bar"}},
+			want: &want{
+				response: &api.URLRestoreResponse{Id: []string{"foo"}},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().RestoreURLs(gomock.Any(), []string{"foo", "bar"}, gomock.Any()).Return([]string{"foo"}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.RestoreURLs(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
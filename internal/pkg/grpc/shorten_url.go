@@ -2,13 +2,18 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/aleffnull/shortener/internal/app"
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/middleware"
 	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/internal/pkg/store"
 	"github.com/aleffnull/shortener/models"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -18,7 +23,51 @@ func (s *ShortenerService) ShortenURL(
 	request *api.URLShortenRequest,
 ) (*api.URLShortenResponse, error) {
 	shortenRequest := &models.ShortenRequest{
-		URL: request.GetUrl(),
+		URL:          request.GetUrl(),
+		Alias:        request.GetAlias(),
+		TTL:          request.GetTtl(),
+		Title:        request.GetTitle(),
+		Tags:         request.GetTags(),
+		Notes:        request.GetNotes(),
+		RedirectCode: int(request.GetRedirectCode()),
+		Passthrough:  request.GetPassthrough(),
+	}
+	if request.GetExpiresAt() != nil {
+		shortenRequest.ExpiresAt = lo.ToPtr(request.GetExpiresAt().AsTime())
+	}
+
+	for _, rule := range request.GetRules() {
+		redirectRule := &models.RedirectRule{
+			Platform: rule.GetPlatform(),
+			Language: rule.GetLanguage(),
+			CIDR:     rule.GetCidr(),
+			URL:      rule.GetUrl(),
+		}
+		if rule.GetFrom() != nil {
+			redirectRule.From = lo.ToPtr(rule.GetFrom().AsTime())
+		}
+		if rule.GetTo() != nil {
+			redirectRule.To = lo.ToPtr(rule.GetTo().AsTime())
+		}
+
+		shortenRequest.Rules = append(shortenRequest.Rules, redirectRule)
+	}
+
+	for _, variant := range request.GetVariants() {
+		shortenRequest.Variants = append(shortenRequest.Variants, &models.URLVariant{
+			Name:   variant.GetName(),
+			URL:    variant.GetUrl(),
+			Weight: int(variant.GetWeight()),
+		})
+	}
+
+	if campaign := request.GetCampaign(); len(campaign) > 0 {
+		campaignID, err := uuid.Parse(campaign)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid campaign ID: %v", err)
+		}
+
+		shortenRequest.Campaign = campaignID
 	}
 
 	validate := validator.New(validator.WithRequiredStructEnabled())
@@ -29,6 +78,18 @@ func (s *ShortenerService) ShortenURL(
 	userID := middleware.GetUserIDFromContext(ctx)
 	shortenerResponse, err := s.shortener.ShortenURL(ctx, shortenRequest, userID)
 	if err != nil {
+		if errors.Is(err, store.ErrInvalidAlias) ||
+			errors.Is(err, app.ErrCampaignNotFound) ||
+			errors.Is(err, app.ErrInvalidRedirectRule) ||
+			errors.Is(err, app.ErrInvalidURLVariant) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err.Error())
+		}
+
+		var aliasConflictError *store.AliasConflictError
+		if errors.As(err, &aliasConflictError) {
+			return nil, status.Errorf(codes.AlreadyExists, "%v", aliasConflictError.Error())
+		}
+
 		return nil, status.Errorf(codes.Internal, "%v", err.Error())
 	}
 
@@ -2,12 +2,15 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/aleffnull/shortener/internal/app"
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
 	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/internal/pkg/store"
 	"github.com/aleffnull/shortener/models"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
@@ -16,6 +19,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestShortenURL(t *testing.T) {
@@ -45,6 +49,98 @@ func TestShortenURL(t *testing.T) {
 				return &api.URLShortenRequest{}
 			},
 		},
+		{
+			name: "WHEN expiration time in the past THEN invalid argument error",
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) *api.URLShortenRequest {
+				return &api.URLShortenRequest{
+					Url:       fullURL,
+					ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour)),
+				}
+			},
+		},
+		{
+			name: "WHEN unsupported redirect code THEN invalid argument error",
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) *api.URLShortenRequest {
+				return &api.URLShortenRequest{
+					Url:          fullURL,
+					RedirectCode: 303,
+				}
+			},
+		},
+		{
+			name: "WHEN invalid redirect rule THEN invalid argument error",
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) *api.URLShortenRequest {
+				return &api.URLShortenRequest{
+					Url:   fullURL,
+					Rules: []*api.RedirectRule{{Platform: "windows", Url: fullURL}},
+				}
+			},
+		},
+		{
+			name: "GIVEN redirect rules WHEN no errors THEN rules requested",
+			want: &want{
+				response: &api.URLShortenResponse{
+					Result: shortURL,
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLShortenRequest {
+				to := time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), &models.ShortenRequest{
+						URL: fullURL,
+						Rules: []*models.RedirectRule{
+							{Platform: "android", URL: "https://play.google.com/store/apps"},
+							{To: &to, URL: "http://foo.bar/sale"},
+						},
+					}, gomock.Any()).
+					Return(&models.ShortenResponse{Result: shortURL}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any())
+				return &api.URLShortenRequest{
+					Url: fullURL,
+					Rules: []*api.RedirectRule{
+						{Platform: "android", Url: "https://play.google.com/store/apps"},
+						{To: timestamppb.New(to), Url: "http://foo.bar/sale"},
+					},
+				}
+			},
+		},
+		{
+			name: "WHEN invalid campaign ID THEN invalid argument error",
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) *api.URLShortenRequest {
+				return &api.URLShortenRequest{
+					Url:      fullURL,
+					Campaign: "foo",
+				}
+			},
+		},
+		{
+			name: "WHEN campaign not found THEN invalid argument error",
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLShortenRequest {
+				campaignID := uuid.New()
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), &models.ShortenRequest{URL: fullURL, Campaign: campaignID}, gomock.Any()).
+					Return(nil, app.ErrCampaignNotFound)
+				return &api.URLShortenRequest{
+					Url:      fullURL,
+					Campaign: campaignID.String(),
+				}
+			},
+		},
 		{
 			name: "WHEN app error THEN internal error",
 			want: &want{
@@ -57,6 +153,36 @@ func TestShortenURL(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "WHEN invalid alias THEN invalid argument error",
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLShortenRequest {
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: reserved", store.ErrInvalidAlias))
+				return &api.URLShortenRequest{
+					Url:   fullURL,
+					Alias: "api",
+				}
+			},
+		},
+		{
+			name: "WHEN alias is taken THEN already exists error",
+			want: &want{
+				code: lo.ToPtr(codes.AlreadyExists),
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLShortenRequest {
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), &models.ShortenRequest{URL: fullURL, Alias: "spring-sale"}, gomock.Any()).
+					Return(nil, store.NewAliasConflictError("spring-sale"))
+				return &api.URLShortenRequest{
+					Url:   fullURL,
+					Alias: "spring-sale",
+				}
+			},
+		},
 		{
 			name: "GIVEN duplicate url WHEN no errors THEN ok",
 			want: &want{
@@ -76,6 +202,26 @@ func TestShortenURL(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "GIVEN passthrough WHEN no errors THEN passthrough requested",
+			want: &want{
+				response: &api.URLShortenResponse{
+					Result: shortURL,
+				},
+			},
+			hookBefore: func(mock *mocks.Mock) *api.URLShortenRequest {
+				mock.App.EXPECT().
+					ShortenURL(gomock.Any(), &models.ShortenRequest{URL: fullURL, Passthrough: true}, gomock.Any()).
+					Return(&models.ShortenResponse{
+						Result: shortURL,
+					}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Any())
+				return &api.URLShortenRequest{
+					Url:         fullURL,
+					Passthrough: true,
+				}
+			},
+		},
 		{
 			name: "GIVEN unique url WHEN no errors THEN ok",
 			want: &want{
@@ -110,7 +256,7 @@ func TestShortenURL(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mock := mocks.NewMock(ctrl)
 			request := tt.hookBefore(mock)
-			service := NewShortenerService(mock.App, mock.AuditService)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
 
 			// Act-assert.
 			response, err := service.ShortenURL(context.Background(), request)
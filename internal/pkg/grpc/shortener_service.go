@@ -9,14 +9,20 @@ import (
 type ShortenerService struct {
 	shortener    app.App
 	auditService service.AuditService
+	clickService service.ClickService
 	api.UnimplementedShortenerServiceServer
 }
 
 var _ api.ShortenerServiceServer = (*ShortenerService)(nil)
 
-func NewShortenerService(shortener app.App, auditService service.AuditService) *ShortenerService {
+func NewShortenerService(
+	shortener app.App,
+	auditService service.AuditService,
+	clickService service.ClickService,
+) *ShortenerService {
 	return &ShortenerService{
 		shortener:    shortener,
 		auditService: auditService,
+		clickService: clickService,
 	}
 }
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/middleware"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ShortenerService) UpdateURL(
+	ctx context.Context,
+	request *api.URLUpdateRequest,
+) (*api.URLUpdateResponse, error) {
+	key := request.GetId()
+	if len(key) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Key is required")
+	}
+
+	updateRequest := &models.UpdateURLRequest{
+		URL: request.GetUrl(),
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(updateRequest); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	updateResponse, err := s.shortener.UpdateURL(ctx, key, updateRequest, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrURLNotFound):
+			return nil, status.Errorf(codes.NotFound, "Key was not found")
+		case errors.Is(err, app.ErrURLAccessDenied):
+			return nil, status.Errorf(codes.PermissionDenied, "Key belongs to another user")
+		case errors.Is(err, app.ErrDuplicateURL):
+			return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	s.auditService.AuditEvent(&domain.AuditEvent{
+		Timestamp: domain.AuditFormattedTime(time.Now()),
+		Action:    domain.AuditActionUpdate,
+		UserID:    userID,
+		URL:       updateRequest.URL,
+	})
+
+	return &api.URLUpdateResponse{
+		Result:  updateResponse.ShortURL,
+		Version: int32(updateResponse.Version),
+	}, nil
+}
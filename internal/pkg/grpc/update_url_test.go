@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aleffnull/shortener/internal/app"
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/aleffnull/shortener/internal/pkg/pb/shortener/api"
+	"github.com/aleffnull/shortener/models"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateURL(t *testing.T) {
+	t.Parallel()
+
+	const key = "abc"
+
+	type want struct {
+		code     *codes.Code
+		response *api.URLUpdateResponse
+	}
+
+	tests := []struct {
+		name       string
+		request    *api.URLUpdateRequest
+		want       *want
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:    "WHEN no key THEN invalid argument",
+			request: &api.URLUpdateRequest{Url: "http://foo.bar"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN invalid URL THEN invalid argument",
+			request: &api.URLUpdateRequest{Id: key, Url: "foo"},
+			want: &want{
+				code: lo.ToPtr(codes.InvalidArgument),
+			},
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:    "WHEN key not found THEN not found",
+			request: &api.URLUpdateRequest{Id: key, Url: "http://foo.bar"},
+			want: &want{
+				code: lo.ToPtr(codes.NotFound),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, app.ErrURLNotFound)
+			},
+		},
+		{
+			name:    "WHEN key of another user THEN permission denied",
+			request: &api.URLUpdateRequest{Id: key, Url: "http://foo.bar"},
+			want: &want{
+				code: lo.ToPtr(codes.PermissionDenied),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, app.ErrURLAccessDenied)
+			},
+		},
+		{
+			name:    "WHEN URL already shortened THEN already exists",
+			request: &api.URLUpdateRequest{Id: key, Url: "http://foo.bar"},
+			want: &want{
+				code: lo.ToPtr(codes.AlreadyExists),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, app.ErrDuplicateURL)
+			},
+		},
+		{
+			name:    "WHEN app error THEN internal error",
+			request: &api.URLUpdateRequest{Id: key, Url: "http://foo.bar"},
+			want: &want{
+				code: lo.ToPtr(codes.Internal),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().UpdateURL(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name:    "WHEN no errors THEN new version",
+			request: &api.URLUpdateRequest{Id: key, Url: "http://foo.bar"},
+			want: &want{
+				response: &api.URLUpdateResponse{Result: "http://localhost/abc", Version: 2},
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.App.EXPECT().
+					UpdateURL(gomock.Any(), key, &models.UpdateURLRequest{URL: "http://foo.bar"}, gomock.Any()).
+					Return(&models.UpdateURLResponse{ShortURL: "http://localhost/abc", OriginalURL: "http://foo.bar", Version: 2}, nil)
+				mock.AuditService.EXPECT().AuditEvent(gomock.Cond(func(event *domain.AuditEvent) bool {
+					return event.Action == domain.AuditActionUpdate && event.URL == "http://foo.bar"
+				}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewShortenerService(mock.App, mock.AuditService, mock.ClickService)
+
+			// Act-assert.
+			response, err := service.UpdateURL(context.Background(), tt.request)
+			if tt.want.code == nil {
+				require.NoError(t, err)
+				require.Equal(t, tt.want.response, response)
+			} else {
+				require.Error(t, err)
+				code, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, *tt.want.code, code.Code())
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ type Mock struct {
 	App                  *MockApp
 	AppParameters        *MockAppParameters
 	DeleteURLsService    *MockDeleteURLsService
+	ExpiredURLsService   *MockExpiredURLsService
+	PurgeURLsService     *MockPurgeURLsService
+	ClickService         *MockClickService
 	AuditService         *MockAuditService
 	AuthorizationService *MockAuthorizationService
 	Connection           *MockConnection
@@ -21,6 +24,9 @@ func NewMock(ctrl *gomock.Controller) *Mock {
 		App:                  NewMockApp(ctrl),
 		AppParameters:        NewMockAppParameters(ctrl),
 		DeleteURLsService:    NewMockDeleteURLsService(ctrl),
+		ExpiredURLsService:   NewMockExpiredURLsService(ctrl),
+		PurgeURLsService:     NewMockPurgeURLsService(ctrl),
+		ClickService:         NewMockClickService(ctrl),
 		AuditService:         NewMockAuditService(ctrl),
 		AuthorizationService: NewMockAuthorizationService(ctrl),
 		Connection:           NewMockConnection(ctrl),
@@ -11,6 +11,8 @@ package mocks
 
 import (
 	context "context"
+	io "io"
+	url "net/url"
 	reflect "reflect"
 
 	models "github.com/aleffnull/shortener/models"
@@ -42,6 +44,21 @@ func (m *MockApp) EXPECT() *MockAppMockRecorder {
 	return m.recorder
 }
 
+// AddCollectionURLs mocks base method.
+func (m *MockApp) AddCollectionURLs(arg0 context.Context, arg1 uuid.UUID, arg2 []string, arg3 uuid.UUID) (*models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollectionURLs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCollectionURLs indicates an expected call of AddCollectionURLs.
+func (mr *MockAppMockRecorder) AddCollectionURLs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionURLs", reflect.TypeOf((*MockApp)(nil).AddCollectionURLs), arg0, arg1, arg2, arg3)
+}
+
 // CheckStore mocks base method.
 func (m *MockApp) CheckStore(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -56,6 +73,93 @@ func (mr *MockAppMockRecorder) CheckStore(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckStore", reflect.TypeOf((*MockApp)(nil).CheckStore), arg0)
 }
 
+// CompactStore mocks base method.
+func (m *MockApp) CompactStore(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactStore", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompactStore indicates an expected call of CompactStore.
+func (mr *MockAppMockRecorder) CompactStore(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactStore", reflect.TypeOf((*MockApp)(nil).CompactStore), arg0)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockApp) CreateCampaign(arg0 context.Context, arg1 *models.CampaignRequest, arg2 uuid.UUID) (*models.CampaignItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.CampaignItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockAppMockRecorder) CreateCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockApp)(nil).CreateCampaign), arg0, arg1, arg2)
+}
+
+// CreateCollection mocks base method.
+func (m *MockApp) CreateCollection(arg0 context.Context, arg1 *models.CollectionRequest, arg2 uuid.UUID) (*models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockAppMockRecorder) CreateCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockApp)(nil).CreateCollection), arg0, arg1, arg2)
+}
+
+// DeleteCampaign mocks base method.
+func (m *MockApp) DeleteCampaign(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCampaign indicates an expected call of DeleteCampaign.
+func (mr *MockAppMockRecorder) DeleteCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCampaign", reflect.TypeOf((*MockApp)(nil).DeleteCampaign), arg0, arg1, arg2)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockApp) DeleteCollection(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockAppMockRecorder) DeleteCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockApp)(nil).DeleteCollection), arg0, arg1, arg2)
+}
+
+// DeleteCollectionURLs mocks base method.
+func (m *MockApp) DeleteCollectionURLs(arg0 context.Context, arg1, arg2 uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollectionURLs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCollectionURLs indicates an expected call of DeleteCollectionURLs.
+func (mr *MockAppMockRecorder) DeleteCollectionURLs(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollectionURLs", reflect.TypeOf((*MockApp)(nil).DeleteCollectionURLs), arg0, arg1, arg2)
+}
+
 // DeleteURLs mocks base method.
 func (m *MockApp) DeleteURLs(arg0 []string, arg1 uuid.UUID) {
 	m.ctrl.T.Helper()
@@ -68,6 +172,125 @@ func (mr *MockAppMockRecorder) DeleteURLs(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLs", reflect.TypeOf((*MockApp)(nil).DeleteURLs), arg0, arg1)
 }
 
+// ExportCollection mocks base method.
+func (m *MockApp) ExportCollection(arg0 context.Context, arg1, arg2 uuid.UUID) ([]*models.UserURLsResponseItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.UserURLsResponseItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportCollection indicates an expected call of ExportCollection.
+func (mr *MockAppMockRecorder) ExportCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCollection", reflect.TypeOf((*MockApp)(nil).ExportCollection), arg0, arg1, arg2)
+}
+
+// ExportStore mocks base method.
+func (m *MockApp) ExportStore(arg0 context.Context, arg1 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportStore indicates an expected call of ExportStore.
+func (mr *MockAppMockRecorder) ExportStore(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStore", reflect.TypeOf((*MockApp)(nil).ExportStore), arg0, arg1)
+}
+
+// GetCampaign mocks base method.
+func (m *MockApp) GetCampaign(arg0 context.Context, arg1, arg2 uuid.UUID) (*models.CampaignItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.CampaignItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockAppMockRecorder) GetCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockApp)(nil).GetCampaign), arg0, arg1, arg2)
+}
+
+// GetCampaigns mocks base method.
+func (m *MockApp) GetCampaigns(arg0 context.Context, arg1 uuid.UUID) ([]*models.CampaignItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaigns", arg0, arg1)
+	ret0, _ := ret[0].([]*models.CampaignItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaigns indicates an expected call of GetCampaigns.
+func (mr *MockAppMockRecorder) GetCampaigns(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaigns", reflect.TypeOf((*MockApp)(nil).GetCampaigns), arg0, arg1)
+}
+
+// GetClickStatistics mocks base method.
+func (m *MockApp) GetClickStatistics(arg0 context.Context, arg1 string, arg2 *models.ClickStatisticsRequest, arg3 uuid.UUID) (*models.ClickStatisticsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStatistics", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.ClickStatisticsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStatistics indicates an expected call of GetClickStatistics.
+func (mr *MockAppMockRecorder) GetClickStatistics(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStatistics", reflect.TypeOf((*MockApp)(nil).GetClickStatistics), arg0, arg1, arg2, arg3)
+}
+
+// GetCollection mocks base method.
+func (m *MockApp) GetCollection(arg0 context.Context, arg1, arg2 uuid.UUID) (*models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockAppMockRecorder) GetCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockApp)(nil).GetCollection), arg0, arg1, arg2)
+}
+
+// GetCollections mocks base method.
+func (m *MockApp) GetCollections(arg0 context.Context, arg1 uuid.UUID) ([]*models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollections", arg0, arg1)
+	ret0, _ := ret[0].([]*models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollections indicates an expected call of GetCollections.
+func (mr *MockAppMockRecorder) GetCollections(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollections", reflect.TypeOf((*MockApp)(nil).GetCollections), arg0, arg1)
+}
+
+// GetPassthroughURL mocks base method.
+func (m *MockApp) GetPassthroughURL(arg0 *models.GetURLResponseItem, arg1 string, arg2 url.Values) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPassthroughURL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPassthroughURL indicates an expected call of GetPassthroughURL.
+func (mr *MockAppMockRecorder) GetPassthroughURL(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPassthroughURL", reflect.TypeOf((*MockApp)(nil).GetPassthroughURL), arg0, arg1, arg2)
+}
+
 // GetStatistics mocks base method.
 func (m *MockApp) GetStatistics(arg0 context.Context) (*models.Statistics, error) {
 	m.ctrl.T.Helper()
@@ -98,19 +321,34 @@ func (mr *MockAppMockRecorder) GetURL(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURL", reflect.TypeOf((*MockApp)(nil).GetURL), arg0, arg1)
 }
 
+// GetURLVersions mocks base method.
+func (m *MockApp) GetURLVersions(arg0 context.Context, arg1 string, arg2 uuid.UUID) ([]*models.URLVersionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.URLVersionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLVersions indicates an expected call of GetURLVersions.
+func (mr *MockAppMockRecorder) GetURLVersions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLVersions", reflect.TypeOf((*MockApp)(nil).GetURLVersions), arg0, arg1, arg2)
+}
+
 // GetUserURLs mocks base method.
-func (m *MockApp) GetUserURLs(arg0 context.Context, arg1 uuid.UUID) ([]*models.UserURLsResponseItem, error) {
+func (m *MockApp) GetUserURLs(arg0 context.Context, arg1 *models.UserURLsRequest, arg2 uuid.UUID) (*models.UserURLsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserURLs", arg0, arg1)
-	ret0, _ := ret[0].([]*models.UserURLsResponseItem)
+	ret := m.ctrl.Call(m, "GetUserURLs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.UserURLsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserURLs indicates an expected call of GetUserURLs.
-func (mr *MockAppMockRecorder) GetUserURLs(arg0, arg1 any) *gomock.Call {
+func (mr *MockAppMockRecorder) GetUserURLs(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserURLs", reflect.TypeOf((*MockApp)(nil).GetUserURLs), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserURLs", reflect.TypeOf((*MockApp)(nil).GetUserURLs), arg0, arg1, arg2)
 }
 
 // Init mocks base method.
@@ -127,6 +365,51 @@ func (mr *MockAppMockRecorder) Init(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockApp)(nil).Init), ctx)
 }
 
+// RemoveCollectionURLs mocks base method.
+func (m *MockApp) RemoveCollectionURLs(arg0 context.Context, arg1 uuid.UUID, arg2 []string, arg3 uuid.UUID) (*models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionURLs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCollectionURLs indicates an expected call of RemoveCollectionURLs.
+func (mr *MockAppMockRecorder) RemoveCollectionURLs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionURLs", reflect.TypeOf((*MockApp)(nil).RemoveCollectionURLs), arg0, arg1, arg2, arg3)
+}
+
+// RenameCollection mocks base method.
+func (m *MockApp) RenameCollection(arg0 context.Context, arg1 uuid.UUID, arg2 *models.CollectionRequest, arg3 uuid.UUID) (*models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCollection", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCollection indicates an expected call of RenameCollection.
+func (mr *MockAppMockRecorder) RenameCollection(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCollection", reflect.TypeOf((*MockApp)(nil).RenameCollection), arg0, arg1, arg2, arg3)
+}
+
+// RestoreURLs mocks base method.
+func (m *MockApp) RestoreURLs(arg0 context.Context, arg1 []string, arg2 uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreURLs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreURLs indicates an expected call of RestoreURLs.
+func (mr *MockAppMockRecorder) RestoreURLs(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreURLs", reflect.TypeOf((*MockApp)(nil).RestoreURLs), arg0, arg1, arg2)
+}
+
 // ShortenURL mocks base method.
 func (m *MockApp) ShortenURL(arg0 context.Context, arg1 *models.ShortenRequest, arg2 uuid.UUID) (*models.ShortenResponse, error) {
 	m.ctrl.T.Helper()
@@ -168,3 +451,48 @@ func (mr *MockAppMockRecorder) Shutdown() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockApp)(nil).Shutdown))
 }
+
+// UpdateCampaign mocks base method.
+func (m *MockApp) UpdateCampaign(arg0 context.Context, arg1 uuid.UUID, arg2 *models.CampaignRequest, arg3 uuid.UUID) (*models.CampaignItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCampaign", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CampaignItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCampaign indicates an expected call of UpdateCampaign.
+func (mr *MockAppMockRecorder) UpdateCampaign(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCampaign", reflect.TypeOf((*MockApp)(nil).UpdateCampaign), arg0, arg1, arg2, arg3)
+}
+
+// UpdateURL mocks base method.
+func (m *MockApp) UpdateURL(arg0 context.Context, arg1 string, arg2 *models.UpdateURLRequest, arg3 uuid.UUID) (*models.UpdateURLResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURL", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.UpdateURLResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURL indicates an expected call of UpdateURL.
+func (mr *MockAppMockRecorder) UpdateURL(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURL", reflect.TypeOf((*MockApp)(nil).UpdateURL), arg0, arg1, arg2, arg3)
+}
+
+// UpdateURLVariants mocks base method.
+func (m *MockApp) UpdateURLVariants(arg0 context.Context, arg1 string, arg2 *models.UpdateURLVariantsRequest, arg3 uuid.UUID) ([]*models.URLVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURLVariants", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*models.URLVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURLVariants indicates an expected call of UpdateURLVariants.
+func (mr *MockAppMockRecorder) UpdateURLVariants(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURLVariants", reflect.TypeOf((*MockApp)(nil).UpdateURLVariants), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/click_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/service/click_service.go -destination internal/pkg/mocks/mock_click_service.go -package mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/aleffnull/shortener/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClickService is a mock of ClickService interface.
+type MockClickService struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockClickServiceMockRecorder is the mock recorder for MockClickService.
+type MockClickServiceMockRecorder struct {
+	mock *MockClickService
+}
+
+// NewMockClickService creates a new mock instance.
+func NewMockClickService(ctrl *gomock.Controller) *MockClickService {
+	mock := &MockClickService{ctrl: ctrl}
+	mock.recorder = &MockClickServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClickService) EXPECT() *MockClickServiceMockRecorder {
+	return m.recorder
+}
+
+// Init mocks base method.
+func (m *MockClickService) Init() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Init")
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockClickServiceMockRecorder) Init() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockClickService)(nil).Init))
+}
+
+// RegisterClick mocks base method.
+func (m *MockClickService) RegisterClick(click *domain.Click, clientIP string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterClick", click, clientIP)
+}
+
+// RegisterClick indicates an expected call of RegisterClick.
+func (mr *MockClickServiceMockRecorder) RegisterClick(click, clientIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClick", reflect.TypeOf((*MockClickService)(nil).RegisterClick), click, clientIP)
+}
+
+// Shutdown mocks base method.
+func (m *MockClickService) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockClickServiceMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockClickService)(nil).Shutdown))
+}
@@ -179,6 +179,26 @@ func (mr *MockConnectionMockRecorder) QueryRows(ctx, arg1 any, args ...any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRows", reflect.TypeOf((*MockConnection)(nil).QueryRows), varargs...)
 }
 
+// QueryRowsTx mocks base method.
+func (m *MockConnection) QueryRowsTx(ctx context.Context, tx *sql.Tx, arg2 string, args ...any) (*sql.Rows, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, tx, arg2}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryRowsTx", varargs...)
+	ret0, _ := ret[0].(*sql.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryRowsTx indicates an expected call of QueryRowsTx.
+func (mr *MockConnectionMockRecorder) QueryRowsTx(ctx, tx, arg2 any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, tx, arg2}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowsTx", reflect.TypeOf((*MockConnection)(nil).QueryRowsTx), varargs...)
+}
+
 // Shutdown mocks base method.
 func (m *MockConnection) Shutdown() {
 	m.ctrl.T.Helper()
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/expired_urls_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/service/expired_urls_service.go -destination internal/pkg/mocks/mock_expired_urls_service.go -package mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExpiredURLsService is a mock of ExpiredURLsService interface.
+type MockExpiredURLsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpiredURLsServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockExpiredURLsServiceMockRecorder is the mock recorder for MockExpiredURLsService.
+type MockExpiredURLsServiceMockRecorder struct {
+	mock *MockExpiredURLsService
+}
+
+// NewMockExpiredURLsService creates a new mock instance.
+func NewMockExpiredURLsService(ctrl *gomock.Controller) *MockExpiredURLsService {
+	mock := &MockExpiredURLsService{ctrl: ctrl}
+	mock.recorder = &MockExpiredURLsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpiredURLsService) EXPECT() *MockExpiredURLsServiceMockRecorder {
+	return m.recorder
+}
+
+// Init mocks base method.
+func (m *MockExpiredURLsService) Init() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Init")
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockExpiredURLsServiceMockRecorder) Init() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockExpiredURLsService)(nil).Init))
+}
+
+// Shutdown mocks base method.
+func (m *MockExpiredURLsService) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockExpiredURLsServiceMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockExpiredURLsService)(nil).Shutdown))
+}
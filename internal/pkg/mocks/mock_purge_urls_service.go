@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/purge_urls_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/service/purge_urls_service.go -destination internal/pkg/mocks/mock_purge_urls_service.go -package mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPurgeURLsService is a mock of PurgeURLsService interface.
+type MockPurgeURLsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPurgeURLsServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockPurgeURLsServiceMockRecorder is the mock recorder for MockPurgeURLsService.
+type MockPurgeURLsServiceMockRecorder struct {
+	mock *MockPurgeURLsService
+}
+
+// NewMockPurgeURLsService creates a new mock instance.
+func NewMockPurgeURLsService(ctrl *gomock.Controller) *MockPurgeURLsService {
+	mock := &MockPurgeURLsService{ctrl: ctrl}
+	mock.recorder = &MockPurgeURLsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPurgeURLsService) EXPECT() *MockPurgeURLsServiceMockRecorder {
+	return m.recorder
+}
+
+// Init mocks base method.
+func (m *MockPurgeURLsService) Init() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Init")
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockPurgeURLsServiceMockRecorder) Init() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockPurgeURLsService)(nil).Init))
+}
+
+// Shutdown mocks base method.
+func (m *MockPurgeURLsService) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockPurgeURLsServiceMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockPurgeURLsService)(nil).Shutdown))
+}
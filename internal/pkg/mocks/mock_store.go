@@ -11,7 +11,9 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/aleffnull/shortener/internal/domain"
 	uuid "github.com/google/uuid"
@@ -56,6 +58,34 @@ func (mr *MockStoreManagerMockRecorder) CheckAvailability(arg0 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAvailability", reflect.TypeOf((*MockStoreManager)(nil).CheckAvailability), arg0)
 }
 
+// Compact mocks base method.
+func (m *MockStoreManager) Compact(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockStoreManagerMockRecorder) Compact(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockStoreManager)(nil).Compact), arg0)
+}
+
+// Export mocks base method.
+func (m *MockStoreManager) Export(arg0 context.Context, arg1 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockStoreManagerMockRecorder) Export(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockStoreManager)(nil).Export), arg0, arg1)
+}
+
 // Init mocks base method.
 func (m *MockStoreManager) Init() error {
 	m.ctrl.T.Helper()
@@ -70,6 +100,18 @@ func (mr *MockStoreManagerMockRecorder) Init() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockStoreManager)(nil).Init))
 }
 
+// Shutdown mocks base method.
+func (m *MockStoreManager) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockStoreManagerMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockStoreManager)(nil).Shutdown))
+}
+
 // MockDataStore is a mock of DataStore interface.
 type MockDataStore struct {
 	ctrl     *gomock.Controller
@@ -94,6 +136,36 @@ func (m *MockDataStore) EXPECT() *MockDataStoreMockRecorder {
 	return m.recorder
 }
 
+// AddCollectionURLs mocks base method.
+func (m *MockDataStore) AddCollectionURLs(arg0 context.Context, arg1 uuid.UUID, arg2 []string, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollectionURLs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCollectionURLs indicates an expected call of AddCollectionURLs.
+func (mr *MockDataStoreMockRecorder) AddCollectionURLs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionURLs", reflect.TypeOf((*MockDataStore)(nil).AddCollectionURLs), arg0, arg1, arg2, arg3)
+}
+
+// BackfillCanonicalURLs mocks base method.
+func (m *MockDataStore) BackfillCanonicalURLs(arg0 context.Context, arg1 func(string) string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillCanonicalURLs", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillCanonicalURLs indicates an expected call of BackfillCanonicalURLs.
+func (mr *MockDataStoreMockRecorder) BackfillCanonicalURLs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillCanonicalURLs", reflect.TypeOf((*MockDataStore)(nil).BackfillCanonicalURLs), arg0, arg1)
+}
+
 // DeleteBatch mocks base method.
 func (m *MockDataStore) DeleteBatch(arg0 context.Context, arg1 []string, arg2 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -108,6 +180,66 @@ func (mr *MockDataStoreMockRecorder) DeleteBatch(arg0, arg1, arg2 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockDataStore)(nil).DeleteBatch), arg0, arg1, arg2)
 }
 
+// DeleteCampaign mocks base method.
+func (m *MockDataStore) DeleteCampaign(arg0 context.Context, arg1, arg2 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCampaign indicates an expected call of DeleteCampaign.
+func (mr *MockDataStoreMockRecorder) DeleteCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCampaign", reflect.TypeOf((*MockDataStore)(nil).DeleteCampaign), arg0, arg1, arg2)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockDataStore) DeleteCollection(arg0 context.Context, arg1, arg2 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockDataStoreMockRecorder) DeleteCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockDataStore)(nil).DeleteCollection), arg0, arg1, arg2)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockDataStore) DeleteExpired(arg0 context.Context, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockDataStoreMockRecorder) DeleteExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockDataStore)(nil).DeleteExpired), arg0, arg1)
+}
+
+// GetClickStatistics mocks base method.
+func (m *MockDataStore) GetClickStatistics(arg0 context.Context, arg1 *domain.ClickStatisticsRequest) (*domain.ClickStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStatistics", arg0, arg1)
+	ret0, _ := ret[0].(*domain.ClickStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStatistics indicates an expected call of GetClickStatistics.
+func (mr *MockDataStoreMockRecorder) GetClickStatistics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStatistics", reflect.TypeOf((*MockDataStore)(nil).GetClickStatistics), arg0, arg1)
+}
+
 // GetStatistics mocks base method.
 func (m *MockDataStore) GetStatistics(arg0 context.Context) (int, int, error) {
 	m.ctrl.T.Helper()
@@ -140,22 +272,157 @@ func (mr *MockDataStoreMockRecorder) Load(arg0, arg1 any) *gomock.Call {
 }
 
 // LoadAllByUserID mocks base method.
-func (m *MockDataStore) LoadAllByUserID(arg0 context.Context, arg1 uuid.UUID) ([]*domain.KeyOriginalURLItem, error) {
+func (m *MockDataStore) LoadAllByUserID(arg0 context.Context, arg1 uuid.UUID, arg2 *domain.UserURLsQuery) ([]*domain.KeyOriginalURLItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadAllByUserID", arg0, arg1)
+	ret := m.ctrl.Call(m, "LoadAllByUserID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*domain.KeyOriginalURLItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LoadAllByUserID indicates an expected call of LoadAllByUserID.
-func (mr *MockDataStoreMockRecorder) LoadAllByUserID(arg0, arg1 any) *gomock.Call {
+func (mr *MockDataStoreMockRecorder) LoadAllByUserID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAllByUserID", reflect.TypeOf((*MockDataStore)(nil).LoadAllByUserID), arg0, arg1, arg2)
+}
+
+// LoadCampaign mocks base method.
+func (m *MockDataStore) LoadCampaign(arg0 context.Context, arg1, arg2 uuid.UUID) (*domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCampaign indicates an expected call of LoadCampaign.
+func (mr *MockDataStoreMockRecorder) LoadCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCampaign", reflect.TypeOf((*MockDataStore)(nil).LoadCampaign), arg0, arg1, arg2)
+}
+
+// LoadCampaigns mocks base method.
+func (m *MockDataStore) LoadCampaigns(arg0 context.Context, arg1 uuid.UUID) ([]*domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCampaigns", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCampaigns indicates an expected call of LoadCampaigns.
+func (mr *MockDataStoreMockRecorder) LoadCampaigns(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCampaigns", reflect.TypeOf((*MockDataStore)(nil).LoadCampaigns), arg0, arg1)
+}
+
+// LoadCollection mocks base method.
+func (m *MockDataStore) LoadCollection(arg0 context.Context, arg1, arg2 uuid.UUID) (*domain.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCollection indicates an expected call of LoadCollection.
+func (mr *MockDataStoreMockRecorder) LoadCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCollection", reflect.TypeOf((*MockDataStore)(nil).LoadCollection), arg0, arg1, arg2)
+}
+
+// LoadCollections mocks base method.
+func (m *MockDataStore) LoadCollections(arg0 context.Context, arg1 uuid.UUID) ([]*domain.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCollections", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCollections indicates an expected call of LoadCollections.
+func (mr *MockDataStoreMockRecorder) LoadCollections(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCollections", reflect.TypeOf((*MockDataStore)(nil).LoadCollections), arg0, arg1)
+}
+
+// LoadVersions mocks base method.
+func (m *MockDataStore) LoadVersions(arg0 context.Context, arg1 string) ([]*domain.URLVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadVersions", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.URLVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadVersions indicates an expected call of LoadVersions.
+func (mr *MockDataStoreMockRecorder) LoadVersions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadVersions", reflect.TypeOf((*MockDataStore)(nil).LoadVersions), arg0, arg1)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockDataStore) PurgeDeleted(arg0 context.Context, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockDataStoreMockRecorder) PurgeDeleted(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAllByUserID", reflect.TypeOf((*MockDataStore)(nil).LoadAllByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockDataStore)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// RemoveCollectionURLs mocks base method.
+func (m *MockDataStore) RemoveCollectionURLs(arg0 context.Context, arg1 uuid.UUID, arg2 []string, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionURLs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCollectionURLs indicates an expected call of RemoveCollectionURLs.
+func (mr *MockDataStoreMockRecorder) RemoveCollectionURLs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionURLs", reflect.TypeOf((*MockDataStore)(nil).RemoveCollectionURLs), arg0, arg1, arg2, arg3)
+}
+
+// RenameCollection mocks base method.
+func (m *MockDataStore) RenameCollection(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCollection", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCollection indicates an expected call of RenameCollection.
+func (mr *MockDataStoreMockRecorder) RenameCollection(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCollection", reflect.TypeOf((*MockDataStore)(nil).RenameCollection), arg0, arg1, arg2, arg3)
+}
+
+// RestoreBatch mocks base method.
+func (m *MockDataStore) RestoreBatch(arg0 context.Context, arg1 []string, arg2 uuid.UUID, arg3 time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBatch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBatch indicates an expected call of RestoreBatch.
+func (mr *MockDataStoreMockRecorder) RestoreBatch(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBatch", reflect.TypeOf((*MockDataStore)(nil).RestoreBatch), arg0, arg1, arg2, arg3)
 }
 
 // Save mocks base method.
-func (m *MockDataStore) Save(arg0 context.Context, arg1 string, arg2 uuid.UUID) (string, error) {
+func (m *MockDataStore) Save(arg0 context.Context, arg1 *domain.SaveRequest, arg2 uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Save", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
@@ -184,6 +451,93 @@ func (mr *MockDataStoreMockRecorder) SaveBatch(arg0, arg1, arg2 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockDataStore)(nil).SaveBatch), arg0, arg1, arg2)
 }
 
+// SaveCampaign mocks base method.
+func (m *MockDataStore) SaveCampaign(arg0 context.Context, arg1 *domain.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCampaign", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCampaign indicates an expected call of SaveCampaign.
+func (mr *MockDataStoreMockRecorder) SaveCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCampaign", reflect.TypeOf((*MockDataStore)(nil).SaveCampaign), arg0, arg1)
+}
+
+// SaveClicks mocks base method.
+func (m *MockDataStore) SaveClicks(arg0 context.Context, arg1 []*domain.Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveClicks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveClicks indicates an expected call of SaveClicks.
+func (mr *MockDataStoreMockRecorder) SaveClicks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveClicks", reflect.TypeOf((*MockDataStore)(nil).SaveClicks), arg0, arg1)
+}
+
+// SaveCollection mocks base method.
+func (m *MockDataStore) SaveCollection(arg0 context.Context, arg1 *domain.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCollection indicates an expected call of SaveCollection.
+func (mr *MockDataStoreMockRecorder) SaveCollection(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCollection", reflect.TypeOf((*MockDataStore)(nil).SaveCollection), arg0, arg1)
+}
+
+// UpdateCampaign mocks base method.
+func (m *MockDataStore) UpdateCampaign(arg0 context.Context, arg1 *domain.Campaign) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCampaign", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCampaign indicates an expected call of UpdateCampaign.
+func (mr *MockDataStoreMockRecorder) UpdateCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCampaign", reflect.TypeOf((*MockDataStore)(nil).UpdateCampaign), arg0, arg1)
+}
+
+// UpdateURL mocks base method.
+func (m *MockDataStore) UpdateURL(arg0 context.Context, arg1 *domain.UpdateRequest, arg2 uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURL indicates an expected call of UpdateURL.
+func (mr *MockDataStoreMockRecorder) UpdateURL(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURL", reflect.TypeOf((*MockDataStore)(nil).UpdateURL), arg0, arg1, arg2)
+}
+
+// UpdateVariants mocks base method.
+func (m *MockDataStore) UpdateVariants(arg0 context.Context, arg1 string, arg2 []*domain.URLVariant, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariants", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVariants indicates an expected call of UpdateVariants.
+func (mr *MockDataStoreMockRecorder) UpdateVariants(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariants", reflect.TypeOf((*MockDataStore)(nil).UpdateVariants), arg0, arg1, arg2, arg3)
+}
+
 // MockStore is a mock of Store interface.
 type MockStore struct {
 	ctrl     *gomock.Controller
@@ -208,6 +562,36 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddCollectionURLs mocks base method.
+func (m *MockStore) AddCollectionURLs(arg0 context.Context, arg1 uuid.UUID, arg2 []string, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollectionURLs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCollectionURLs indicates an expected call of AddCollectionURLs.
+func (mr *MockStoreMockRecorder) AddCollectionURLs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionURLs", reflect.TypeOf((*MockStore)(nil).AddCollectionURLs), arg0, arg1, arg2, arg3)
+}
+
+// BackfillCanonicalURLs mocks base method.
+func (m *MockStore) BackfillCanonicalURLs(arg0 context.Context, arg1 func(string) string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillCanonicalURLs", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BackfillCanonicalURLs indicates an expected call of BackfillCanonicalURLs.
+func (mr *MockStoreMockRecorder) BackfillCanonicalURLs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillCanonicalURLs", reflect.TypeOf((*MockStore)(nil).BackfillCanonicalURLs), arg0, arg1)
+}
+
 // CheckAvailability mocks base method.
 func (m *MockStore) CheckAvailability(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -222,6 +606,20 @@ func (mr *MockStoreMockRecorder) CheckAvailability(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAvailability", reflect.TypeOf((*MockStore)(nil).CheckAvailability), arg0)
 }
 
+// Compact mocks base method.
+func (m *MockStore) Compact(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockStoreMockRecorder) Compact(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockStore)(nil).Compact), arg0)
+}
+
 // DeleteBatch mocks base method.
 func (m *MockStore) DeleteBatch(arg0 context.Context, arg1 []string, arg2 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -236,6 +634,80 @@ func (mr *MockStoreMockRecorder) DeleteBatch(arg0, arg1, arg2 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockStore)(nil).DeleteBatch), arg0, arg1, arg2)
 }
 
+// DeleteCampaign mocks base method.
+func (m *MockStore) DeleteCampaign(arg0 context.Context, arg1, arg2 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCampaign indicates an expected call of DeleteCampaign.
+func (mr *MockStoreMockRecorder) DeleteCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCampaign", reflect.TypeOf((*MockStore)(nil).DeleteCampaign), arg0, arg1, arg2)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockStore) DeleteCollection(arg0 context.Context, arg1, arg2 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockStoreMockRecorder) DeleteCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockStore)(nil).DeleteCollection), arg0, arg1, arg2)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockStore) DeleteExpired(arg0 context.Context, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockStoreMockRecorder) DeleteExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockStore)(nil).DeleteExpired), arg0, arg1)
+}
+
+// Export mocks base method.
+func (m *MockStore) Export(arg0 context.Context, arg1 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockStoreMockRecorder) Export(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockStore)(nil).Export), arg0, arg1)
+}
+
+// GetClickStatistics mocks base method.
+func (m *MockStore) GetClickStatistics(arg0 context.Context, arg1 *domain.ClickStatisticsRequest) (*domain.ClickStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStatistics", arg0, arg1)
+	ret0, _ := ret[0].(*domain.ClickStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStatistics indicates an expected call of GetClickStatistics.
+func (mr *MockStoreMockRecorder) GetClickStatistics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStatistics", reflect.TypeOf((*MockStore)(nil).GetClickStatistics), arg0, arg1)
+}
+
 // GetStatistics mocks base method.
 func (m *MockStore) GetStatistics(arg0 context.Context) (int, int, error) {
 	m.ctrl.T.Helper()
@@ -282,22 +754,157 @@ func (mr *MockStoreMockRecorder) Load(arg0, arg1 any) *gomock.Call {
 }
 
 // LoadAllByUserID mocks base method.
-func (m *MockStore) LoadAllByUserID(arg0 context.Context, arg1 uuid.UUID) ([]*domain.KeyOriginalURLItem, error) {
+func (m *MockStore) LoadAllByUserID(arg0 context.Context, arg1 uuid.UUID, arg2 *domain.UserURLsQuery) ([]*domain.KeyOriginalURLItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadAllByUserID", arg0, arg1)
+	ret := m.ctrl.Call(m, "LoadAllByUserID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*domain.KeyOriginalURLItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LoadAllByUserID indicates an expected call of LoadAllByUserID.
-func (mr *MockStoreMockRecorder) LoadAllByUserID(arg0, arg1 any) *gomock.Call {
+func (mr *MockStoreMockRecorder) LoadAllByUserID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAllByUserID", reflect.TypeOf((*MockStore)(nil).LoadAllByUserID), arg0, arg1, arg2)
+}
+
+// LoadCampaign mocks base method.
+func (m *MockStore) LoadCampaign(arg0 context.Context, arg1, arg2 uuid.UUID) (*domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCampaign", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCampaign indicates an expected call of LoadCampaign.
+func (mr *MockStoreMockRecorder) LoadCampaign(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCampaign", reflect.TypeOf((*MockStore)(nil).LoadCampaign), arg0, arg1, arg2)
+}
+
+// LoadCampaigns mocks base method.
+func (m *MockStore) LoadCampaigns(arg0 context.Context, arg1 uuid.UUID) ([]*domain.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCampaigns", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCampaigns indicates an expected call of LoadCampaigns.
+func (mr *MockStoreMockRecorder) LoadCampaigns(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCampaigns", reflect.TypeOf((*MockStore)(nil).LoadCampaigns), arg0, arg1)
+}
+
+// LoadCollection mocks base method.
+func (m *MockStore) LoadCollection(arg0 context.Context, arg1, arg2 uuid.UUID) (*domain.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCollection indicates an expected call of LoadCollection.
+func (mr *MockStoreMockRecorder) LoadCollection(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCollection", reflect.TypeOf((*MockStore)(nil).LoadCollection), arg0, arg1, arg2)
+}
+
+// LoadCollections mocks base method.
+func (m *MockStore) LoadCollections(arg0 context.Context, arg1 uuid.UUID) ([]*domain.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCollections", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCollections indicates an expected call of LoadCollections.
+func (mr *MockStoreMockRecorder) LoadCollections(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCollections", reflect.TypeOf((*MockStore)(nil).LoadCollections), arg0, arg1)
+}
+
+// LoadVersions mocks base method.
+func (m *MockStore) LoadVersions(arg0 context.Context, arg1 string) ([]*domain.URLVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadVersions", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.URLVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadVersions indicates an expected call of LoadVersions.
+func (mr *MockStoreMockRecorder) LoadVersions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadVersions", reflect.TypeOf((*MockStore)(nil).LoadVersions), arg0, arg1)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockStore) PurgeDeleted(arg0 context.Context, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockStoreMockRecorder) PurgeDeleted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockStore)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// RemoveCollectionURLs mocks base method.
+func (m *MockStore) RemoveCollectionURLs(arg0 context.Context, arg1 uuid.UUID, arg2 []string, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionURLs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCollectionURLs indicates an expected call of RemoveCollectionURLs.
+func (mr *MockStoreMockRecorder) RemoveCollectionURLs(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionURLs", reflect.TypeOf((*MockStore)(nil).RemoveCollectionURLs), arg0, arg1, arg2, arg3)
+}
+
+// RenameCollection mocks base method.
+func (m *MockStore) RenameCollection(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCollection", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCollection indicates an expected call of RenameCollection.
+func (mr *MockStoreMockRecorder) RenameCollection(arg0, arg1, arg2, arg3 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAllByUserID", reflect.TypeOf((*MockStore)(nil).LoadAllByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCollection", reflect.TypeOf((*MockStore)(nil).RenameCollection), arg0, arg1, arg2, arg3)
+}
+
+// RestoreBatch mocks base method.
+func (m *MockStore) RestoreBatch(arg0 context.Context, arg1 []string, arg2 uuid.UUID, arg3 time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBatch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBatch indicates an expected call of RestoreBatch.
+func (mr *MockStoreMockRecorder) RestoreBatch(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBatch", reflect.TypeOf((*MockStore)(nil).RestoreBatch), arg0, arg1, arg2, arg3)
 }
 
 // Save mocks base method.
-func (m *MockStore) Save(arg0 context.Context, arg1 string, arg2 uuid.UUID) (string, error) {
+func (m *MockStore) Save(arg0 context.Context, arg1 *domain.SaveRequest, arg2 uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Save", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
@@ -326,6 +933,105 @@ func (mr *MockStoreMockRecorder) SaveBatch(arg0, arg1, arg2 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockStore)(nil).SaveBatch), arg0, arg1, arg2)
 }
 
+// SaveCampaign mocks base method.
+func (m *MockStore) SaveCampaign(arg0 context.Context, arg1 *domain.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCampaign", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCampaign indicates an expected call of SaveCampaign.
+func (mr *MockStoreMockRecorder) SaveCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCampaign", reflect.TypeOf((*MockStore)(nil).SaveCampaign), arg0, arg1)
+}
+
+// SaveClicks mocks base method.
+func (m *MockStore) SaveClicks(arg0 context.Context, arg1 []*domain.Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveClicks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveClicks indicates an expected call of SaveClicks.
+func (mr *MockStoreMockRecorder) SaveClicks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveClicks", reflect.TypeOf((*MockStore)(nil).SaveClicks), arg0, arg1)
+}
+
+// SaveCollection mocks base method.
+func (m *MockStore) SaveCollection(arg0 context.Context, arg1 *domain.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCollection indicates an expected call of SaveCollection.
+func (mr *MockStoreMockRecorder) SaveCollection(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCollection", reflect.TypeOf((*MockStore)(nil).SaveCollection), arg0, arg1)
+}
+
+// Shutdown mocks base method.
+func (m *MockStore) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockStoreMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockStore)(nil).Shutdown))
+}
+
+// UpdateCampaign mocks base method.
+func (m *MockStore) UpdateCampaign(arg0 context.Context, arg1 *domain.Campaign) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCampaign", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCampaign indicates an expected call of UpdateCampaign.
+func (mr *MockStoreMockRecorder) UpdateCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCampaign", reflect.TypeOf((*MockStore)(nil).UpdateCampaign), arg0, arg1)
+}
+
+// UpdateURL mocks base method.
+func (m *MockStore) UpdateURL(arg0 context.Context, arg1 *domain.UpdateRequest, arg2 uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateURL", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateURL indicates an expected call of UpdateURL.
+func (mr *MockStoreMockRecorder) UpdateURL(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateURL", reflect.TypeOf((*MockStore)(nil).UpdateURL), arg0, arg1, arg2)
+}
+
+// UpdateVariants mocks base method.
+func (m *MockStore) UpdateVariants(arg0 context.Context, arg1 string, arg2 []*domain.URLVariant, arg3 uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariants", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVariants indicates an expected call of UpdateVariants.
+func (mr *MockStoreMockRecorder) UpdateVariants(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariants", reflect.TypeOf((*MockStore)(nil).UpdateVariants), arg0, arg1, arg2, arg3)
+}
+
 // MockColdStore is a mock of ColdStore interface.
 type MockColdStore struct {
 	ctrl     *gomock.Controller
@@ -350,19 +1056,46 @@ func (m *MockColdStore) EXPECT() *MockColdStoreMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockColdStore) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockColdStoreMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockColdStore)(nil).Close))
+}
+
+// Compact mocks base method.
+func (m *MockColdStore) Compact() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockColdStoreMockRecorder) Compact() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockColdStore)(nil).Compact))
+}
+
 // LoadAll mocks base method.
-func (m *MockColdStore) LoadAll() ([]*domain.ColdStoreEntry, error) {
+func (m *MockColdStore) LoadAll(restore func(*domain.ColdStoreEntry)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadAll")
-	ret0, _ := ret[0].([]*domain.ColdStoreEntry)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "LoadAll", restore)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // LoadAll indicates an expected call of LoadAll.
-func (mr *MockColdStoreMockRecorder) LoadAll() *gomock.Call {
+func (mr *MockColdStoreMockRecorder) LoadAll(restore any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAll", reflect.TypeOf((*MockColdStore)(nil).LoadAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAll", reflect.TypeOf((*MockColdStore)(nil).LoadAll), restore)
 }
 
 // Save mocks base method.
@@ -9,7 +9,7 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,8 +23,30 @@ const (
 )
 
 type URLShortenRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Желаемый ключ короткой ссылки (необязательно).
+	Alias string `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Время жизни ссылки в секундах (необязательно).
+	Ttl int64 `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Момент, после которого ссылка перестает работать (необязательно).
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Заголовок ссылки (необязательно).
+	Title string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	// Метки ссылки, регистр не учитывается (необязательно).
+	Tags []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Заметки к ссылке (необязательно).
+	Notes string `protobuf:"bytes,7,opt,name=notes,proto3" json:"notes,omitempty"`
+	// HTTP-статус перенаправления: 301, 302, 307 или 308 (необязательно, по умолчанию задается конфигурацией).
+	RedirectCode int32 `protobuf:"varint,8,opt,name=redirect_code,json=redirectCode,proto3" json:"redirect_code,omitempty"`
+	// Добавлять при переходе остаток пути и параметры запроса к URL (необязательно).
+	Passthrough bool `protobuf:"varint,9,opt,name=passthrough,proto3" json:"passthrough,omitempty"`
+	// Идентификатор кампании, ее параметры добавляются к URL при переходе (необязательно).
+	Campaign string `protobuf:"bytes,10,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	// Правила выбора другого адреса перехода, проверяются по порядку (необязательно).
+	Rules []*RedirectRule `protobuf:"bytes,11,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Адреса для A/B-теста, посетитель закрепляется за вариантом по cookie (необязательно, от 2 до 10).
+	Variants      []*URLVariant `protobuf:"bytes,12,rep,name=variants,proto3" json:"variants,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +88,237 @@ func (x *URLShortenRequest) GetUrl() string {
 	return ""
 }
 
+func (x *URLShortenRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *URLShortenRequest) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *URLShortenRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *URLShortenRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *URLShortenRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *URLShortenRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *URLShortenRequest) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *URLShortenRequest) GetPassthrough() bool {
+	if x != nil {
+		return x.Passthrough
+	}
+	return false
+}
+
+func (x *URLShortenRequest) GetCampaign() string {
+	if x != nil {
+		return x.Campaign
+	}
+	return ""
+}
+
+func (x *URLShortenRequest) GetRules() []*RedirectRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *URLShortenRequest) GetVariants() []*URLVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// Правило выбора адреса перехода. Должны выполняться все заданные условия, хотя бы одно условие обязательно.
+type RedirectRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Платформа клиента по User-Agent: ios, android или desktop.
+	Platform string `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	// Предпочтительный язык клиента из Accept-Language, например de или pt-BR.
+	Language string `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	// Сеть IP-адреса клиента.
+	Cidr string `protobuf:"bytes,3,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	// Начало интервала времени.
+	From *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	// Конец интервала времени, не входит в интервал.
+	To *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	// Адрес перехода при срабатывании правила.
+	Url           string `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedirectRule) Reset() {
+	*x = RedirectRule{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedirectRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedirectRule) ProtoMessage() {}
+
+func (x *RedirectRule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedirectRule.ProtoReflect.Descriptor instead.
+func (*RedirectRule) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RedirectRule) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *RedirectRule) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *RedirectRule) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *RedirectRule) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *RedirectRule) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *RedirectRule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Вариант адреса перехода, посетитель попадает на него с вероятностью, пропорциональной весу.
+type URLVariant struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Имя варианта, уникально в пределах ссылки.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url  string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Вес варианта, вариант с нулевым весом не выбирается.
+	Weight        int32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLVariant) Reset() {
+	*x = URLVariant{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLVariant) ProtoMessage() {}
+
+func (x *URLVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLVariant.ProtoReflect.Descriptor instead.
+func (*URLVariant) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *URLVariant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *URLVariant) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *URLVariant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
 type URLShortenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Result        string                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
@@ -75,7 +328,7 @@ type URLShortenResponse struct {
 
 func (x *URLShortenResponse) Reset() {
 	*x = URLShortenResponse{}
-	mi := &file_api_shortener_shortener_proto_msgTypes[1]
+	mi := &file_api_shortener_shortener_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +340,7 @@ func (x *URLShortenResponse) String() string {
 func (*URLShortenResponse) ProtoMessage() {}
 
 func (x *URLShortenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_shortener_shortener_proto_msgTypes[1]
+	mi := &file_api_shortener_shortener_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +353,7 @@ func (x *URLShortenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use URLShortenResponse.ProtoReflect.Descriptor instead.
 func (*URLShortenResponse) Descriptor() ([]byte, []int) {
-	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{1}
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *URLShortenResponse) GetResult() string {
@@ -119,7 +372,7 @@ type URLExpandRequest struct {
 
 func (x *URLExpandRequest) Reset() {
 	*x = URLExpandRequest{}
-	mi := &file_api_shortener_shortener_proto_msgTypes[2]
+	mi := &file_api_shortener_shortener_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -131,7 +384,7 @@ func (x *URLExpandRequest) String() string {
 func (*URLExpandRequest) ProtoMessage() {}
 
 func (x *URLExpandRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_shortener_shortener_proto_msgTypes[2]
+	mi := &file_api_shortener_shortener_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -144,7 +397,7 @@ func (x *URLExpandRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use URLExpandRequest.ProtoReflect.Descriptor instead.
 func (*URLExpandRequest) Descriptor() ([]byte, []int) {
-	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{2}
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *URLExpandRequest) GetId() string {
@@ -155,15 +408,19 @@ func (x *URLExpandRequest) GetId() string {
 }
 
 type URLExpandResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        string                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result string                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// HTTP-статус перенаправления по ссылке.
+	RedirectCode int32 `protobuf:"varint,2,opt,name=redirect_code,json=redirectCode,proto3" json:"redirect_code,omitempty"`
+	// Время в секундах, в течение которого перенаправление можно кэшировать, ноль - кэшировать нельзя.
+	CacheMaxAge   int64 `protobuf:"varint,3,opt,name=cache_max_age,json=cacheMaxAge,proto3" json:"cache_max_age,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *URLExpandResponse) Reset() {
 	*x = URLExpandResponse{}
-	mi := &file_api_shortener_shortener_proto_msgTypes[3]
+	mi := &file_api_shortener_shortener_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -175,7 +432,7 @@ func (x *URLExpandResponse) String() string {
 func (*URLExpandResponse) ProtoMessage() {}
 
 func (x *URLExpandResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_shortener_shortener_proto_msgTypes[3]
+	mi := &file_api_shortener_shortener_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -188,7 +445,7 @@ func (x *URLExpandResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use URLExpandResponse.ProtoReflect.Descriptor instead.
 func (*URLExpandResponse) Descriptor() ([]byte, []int) {
-	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{3}
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *URLExpandResponse) GetResult() string {
@@ -198,28 +455,59 @@ func (x *URLExpandResponse) GetResult() string {
 	return ""
 }
 
-type UserURLsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           []*URLData             `protobuf:"bytes,1,rep,name=url,proto3" json:"url,omitempty"`
+func (x *URLExpandResponse) GetRedirectCode() int32 {
+	if x != nil {
+		return x.RedirectCode
+	}
+	return 0
+}
+
+func (x *URLExpandResponse) GetCacheMaxAge() int64 {
+	if x != nil {
+		return x.CacheMaxAge
+	}
+	return 0
+}
+
+type UserURLsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Размер страницы (необязательно, не больше 1000). Без размера и курсора возвращается весь список,
+	// с курсором без размера - страница из 100 ссылок.
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Курсор следующей страницы из предыдущего ответа (необязательно).
+	Cursor string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Сортировка: created или key (необязательно, по умолчанию created).
+	Sort string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Порядок: asc или desc (необязательно, по умолчанию asc).
+	Order string `protobuf:"bytes,4,opt,name=order,proto3" json:"order,omitempty"`
+	// Состояние ссылок: all, active или deleted (необязательно, по умолчанию all).
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// Слова из букв и цифр, каждое из которых должно быть словом исходного URL, заголовка или заметок
+	// без учета регистра (необязательно).
+	Search string `protobuf:"bytes,6,opt,name=search,proto3" json:"search,omitempty"`
+	// Метка ссылок (необязательно).
+	Tag string `protobuf:"bytes,7,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Идентификатор коллекции ссылок (необязательно).
+	Collection    string `protobuf:"bytes,8,opt,name=collection,proto3" json:"collection,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserURLsResponse) Reset() {
-	*x = UserURLsResponse{}
-	mi := &file_api_shortener_shortener_proto_msgTypes[4]
+func (x *UserURLsRequest) Reset() {
+	*x = UserURLsRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserURLsResponse) String() string {
+func (x *UserURLsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserURLsResponse) ProtoMessage() {}
+func (*UserURLsRequest) ProtoMessage() {}
 
-func (x *UserURLsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_shortener_shortener_proto_msgTypes[4]
+func (x *UserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -230,41 +518,91 @@ func (x *UserURLsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserURLsResponse.ProtoReflect.Descriptor instead.
-func (*UserURLsResponse) Descriptor() ([]byte, []int) {
-	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use UserURLsRequest.ProtoReflect.Descriptor instead.
+func (*UserURLsRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UserURLsResponse) GetUrl() []*URLData {
+func (x *UserURLsRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Url
+		return x.Limit
 	}
-	return nil
+	return 0
 }
 
-type URLData struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
-	OriginalUrl   string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+func (x *UserURLsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *UserURLsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *UserURLsRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *UserURLsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UserURLsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *UserURLsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *UserURLsRequest) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+type UserURLsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   []*URLData             `protobuf:"bytes,1,rep,name=url,proto3" json:"url,omitempty"`
+	// Курсор следующей страницы, пустой для последней страницы.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *URLData) Reset() {
-	*x = URLData{}
-	mi := &file_api_shortener_shortener_proto_msgTypes[5]
+func (x *UserURLsResponse) Reset() {
+	*x = UserURLsResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLData) String() string {
+func (x *UserURLsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLData) ProtoMessage() {}
+func (*UserURLsResponse) ProtoMessage() {}
 
-func (x *URLData) ProtoReflect() protoreflect.Message {
-	mi := &file_api_shortener_shortener_proto_msgTypes[5]
+func (x *UserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,48 +613,1434 @@ func (x *URLData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use URLData.ProtoReflect.Descriptor instead.
-func (*URLData) Descriptor() ([]byte, []int) {
-	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use UserURLsResponse.ProtoReflect.Descriptor instead.
+func (*UserURLsResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *URLData) GetShortUrl() string {
+func (x *UserURLsResponse) GetUrl() []*URLData {
 	if x != nil {
-		return x.ShortUrl
+		return x.Url
+	}
+	return nil
+}
+
+func (x *UserURLsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type URLData struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl    string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	OriginalUrl string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Момент последнего перехода, отсутствует, если переходов не было.
+	LastAccessedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	Title          string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Tags           []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Notes          string                 `protobuf:"bytes,8,opt,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *URLData) Reset() {
+	*x = URLData{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLData) ProtoMessage() {}
+
+func (x *URLData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLData.ProtoReflect.Descriptor instead.
+func (*URLData) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *URLData) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *URLData) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *URLData) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *URLData) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *URLData) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *URLData) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *URLData) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *URLData) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+type URLStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Начало интервала (необязательно, по умолчанию неделя до конца интервала).
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// Конец интервала (необязательно, по умолчанию текущий момент).
+	To *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Размер интервала группировки: hour, day или week (необязательно, по умолчанию day).
+	Bucket string `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	// Количество самых частых рефереров и user agent (необязательно, по умолчанию 10).
+	Top           int32 `protobuf:"varint,5,opt,name=top,proto3" json:"top,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLStatsRequest) Reset() {
+	*x = URLStatsRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLStatsRequest) ProtoMessage() {}
+
+func (x *URLStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLStatsRequest.ProtoReflect.Descriptor instead.
+func (*URLStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *URLStatsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *URLStatsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *URLStatsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *URLStatsRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *URLStatsRequest) GetTop() int32 {
+	if x != nil {
+		return x.Top
+	}
+	return 0
+}
+
+type URLStatsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TotalClicks    int64                  `protobuf:"varint,1,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	UniqueVisitors int64                  `protobuf:"varint,2,opt,name=unique_visitors,json=uniqueVisitors,proto3" json:"unique_visitors,omitempty"`
+	From           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To             *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Bucket         string                 `protobuf:"bytes,5,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Buckets        []*ClickBucket         `protobuf:"bytes,6,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	TopReferrers   []*ClickValue          `protobuf:"bytes,7,rep,name=top_referrers,json=topReferrers,proto3" json:"top_referrers,omitempty"`
+	TopUserAgents  []*ClickValue          `protobuf:"bytes,8,rep,name=top_user_agents,json=topUserAgents,proto3" json:"top_user_agents,omitempty"`
+	// Переходы по вариантам ссылки.
+	Variants      []*ClickValue `protobuf:"bytes,9,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLStatsResponse) Reset() {
+	*x = URLStatsResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLStatsResponse) ProtoMessage() {}
+
+func (x *URLStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLStatsResponse.ProtoReflect.Descriptor instead.
+func (*URLStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *URLStatsResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *URLStatsResponse) GetUniqueVisitors() int64 {
+	if x != nil {
+		return x.UniqueVisitors
+	}
+	return 0
+}
+
+func (x *URLStatsResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *URLStatsResponse) GetBuckets() []*ClickBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetTopReferrers() []*ClickValue {
+	if x != nil {
+		return x.TopReferrers
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetTopUserAgents() []*ClickValue {
+	if x != nil {
+		return x.TopUserAgents
+	}
+	return nil
+}
+
+func (x *URLStatsResponse) GetVariants() []*ClickValue {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type ClickBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClickBucket) Reset() {
+	*x = ClickBucket{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickBucket) ProtoMessage() {}
+
+func (x *ClickBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClickBucket.ProtoReflect.Descriptor instead.
+func (*ClickBucket) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ClickBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ClickBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ClickValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClickValue) Reset() {
+	*x = ClickValue{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickValue) ProtoMessage() {}
+
+func (x *ClickValue) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClickValue.ProtoReflect.Descriptor instead.
+func (*ClickValue) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClickValue) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ClickValue) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type URLRestoreRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            []string               `protobuf:"bytes,1,rep,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLRestoreRequest) Reset() {
+	*x = URLRestoreRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLRestoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLRestoreRequest) ProtoMessage() {}
+
+func (x *URLRestoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLRestoreRequest.ProtoReflect.Descriptor instead.
+func (*URLRestoreRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *URLRestoreRequest) GetId() []string {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type URLRestoreResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ключи восстановленных ссылок.
+	Id            []string `protobuf:"bytes,1,rep,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLRestoreResponse) Reset() {
+	*x = URLRestoreResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLRestoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLRestoreResponse) ProtoMessage() {}
+
+func (x *URLRestoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLRestoreResponse.ProtoReflect.Descriptor instead.
+func (*URLRestoreResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *URLRestoreResponse) GetId() []string {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type URLUpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Новый адрес короткой ссылки.
+	Url           string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLUpdateRequest) Reset() {
+	*x = URLUpdateRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLUpdateRequest) ProtoMessage() {}
+
+func (x *URLUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLUpdateRequest.ProtoReflect.Descriptor instead.
+func (*URLUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *URLUpdateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *URLUpdateRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type URLUpdateResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result string                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Номер версии нового адреса, первая версия ссылки имеет номер 1.
+	Version       int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLUpdateResponse) Reset() {
+	*x = URLUpdateResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLUpdateResponse) ProtoMessage() {}
+
+func (x *URLUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLUpdateResponse.ProtoReflect.Descriptor instead.
+func (*URLUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *URLUpdateResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *URLUpdateResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type URLVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLVersionsRequest) Reset() {
+	*x = URLVersionsRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLVersionsRequest) ProtoMessage() {}
+
+func (x *URLVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLVersionsRequest.ProtoReflect.Descriptor instead.
+func (*URLVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *URLVersionsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type URLVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       []*URLVersion          `protobuf:"bytes,1,rep,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLVersionsResponse) Reset() {
+	*x = URLVersionsResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLVersionsResponse) ProtoMessage() {}
+
+func (x *URLVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLVersionsResponse.ProtoReflect.Descriptor instead.
+func (*URLVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *URLVersionsResponse) GetVersion() []*URLVersion {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+type URLVersion struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Version     int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	OriginalUrl string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	// Момент, когда адрес был заменен следующей версией.
+	ReplacedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=replaced_at,json=replacedAt,proto3" json:"replaced_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *URLVersion) Reset() {
+	*x = URLVersion{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLVersion) ProtoMessage() {}
+
+func (x *URLVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use URLVersion.ProtoReflect.Descriptor instead.
+func (*URLVersion) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *URLVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *URLVersion) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *URLVersion) GetReplacedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReplacedAt
+	}
+	return nil
+}
+
+type Collection struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Количество ссылок в коллекции.
+	UrlCount      int64 `protobuf:"varint,4,opt,name=url_count,json=urlCount,proto3" json:"url_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Collection) Reset() {
+	*x = Collection{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Collection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Collection) ProtoMessage() {}
+
+func (x *Collection) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Collection.ProtoReflect.Descriptor instead.
+func (*Collection) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Collection) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Collection) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Collection) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Collection) GetUrlCount() int64 {
+	if x != nil {
+		return x.UrlCount
+	}
+	return 0
+}
+
+type CollectionCreateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionCreateRequest) Reset() {
+	*x = CollectionCreateRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionCreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionCreateRequest) ProtoMessage() {}
+
+func (x *CollectionCreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionCreateRequest.ProtoReflect.Descriptor instead.
+func (*CollectionCreateRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CollectionCreateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CollectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionsRequest) Reset() {
+	*x = CollectionsRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionsRequest) ProtoMessage() {}
+
+func (x *CollectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionsRequest.ProtoReflect.Descriptor instead.
+func (*CollectionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{22}
+}
+
+type CollectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Collection    []*Collection          `protobuf:"bytes,1,rep,name=collection,proto3" json:"collection,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionsResponse) Reset() {
+	*x = CollectionsResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionsResponse) ProtoMessage() {}
+
+func (x *CollectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionsResponse.ProtoReflect.Descriptor instead.
+func (*CollectionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CollectionsResponse) GetCollection() []*Collection {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+type CollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionRequest) Reset() {
+	*x = CollectionRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionRequest) ProtoMessage() {}
+
+func (x *CollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionRequest.ProtoReflect.Descriptor instead.
+func (*CollectionRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CollectionRequest) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
-func (x *URLData) GetOriginalUrl() string {
+type CollectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Collection    *Collection            `protobuf:"bytes,1,opt,name=collection,proto3" json:"collection,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionResponse) Reset() {
+	*x = CollectionResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionResponse) ProtoMessage() {}
+
+func (x *CollectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[25]
 	if x != nil {
-		return x.OriginalUrl
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionResponse.ProtoReflect.Descriptor instead.
+func (*CollectionResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CollectionResponse) GetCollection() *Collection {
+	if x != nil {
+		return x.Collection
+	}
+	return nil
+}
+
+type CollectionRenameRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Новое имя коллекции.
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionRenameRequest) Reset() {
+	*x = CollectionRenameRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionRenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionRenameRequest) ProtoMessage() {}
+
+func (x *CollectionRenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionRenameRequest.ProtoReflect.Descriptor instead.
+func (*CollectionRenameRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CollectionRenameRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CollectionRenameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CollectionDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionDeleteResponse) Reset() {
+	*x = CollectionDeleteResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionDeleteResponse) ProtoMessage() {}
+
+func (x *CollectionDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionDeleteResponse.ProtoReflect.Descriptor instead.
+func (*CollectionDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{27}
+}
+
+type CollectionURLsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Ключи ссылок, чужие и несуществующие ключи пропускаются.
+	UrlId         []string `protobuf:"bytes,2,rep,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionURLsRequest) Reset() {
+	*x = CollectionURLsRequest{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionURLsRequest) ProtoMessage() {}
+
+func (x *CollectionURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionURLsRequest.ProtoReflect.Descriptor instead.
+func (*CollectionURLsRequest) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CollectionURLsRequest) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
+func (x *CollectionURLsRequest) GetUrlId() []string {
+	if x != nil {
+		return x.UrlId
+	}
+	return nil
+}
+
+type CollectionURLsDeleteResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ключи удаляемых ссылок.
+	UrlId         []string `protobuf:"bytes,1,rep,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionURLsDeleteResponse) Reset() {
+	*x = CollectionURLsDeleteResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionURLsDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionURLsDeleteResponse) ProtoMessage() {}
+
+func (x *CollectionURLsDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionURLsDeleteResponse.ProtoReflect.Descriptor instead.
+func (*CollectionURLsDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CollectionURLsDeleteResponse) GetUrlId() []string {
+	if x != nil {
+		return x.UrlId
+	}
+	return nil
+}
+
+type CollectionExportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           []*URLData             `protobuf:"bytes,1,rep,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectionExportResponse) Reset() {
+	*x = CollectionExportResponse{}
+	mi := &file_api_shortener_shortener_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectionExportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectionExportResponse) ProtoMessage() {}
+
+func (x *CollectionExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_shortener_shortener_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectionExportResponse.ProtoReflect.Descriptor instead.
+func (*CollectionExportResponse) Descriptor() ([]byte, []int) {
+	return file_api_shortener_shortener_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CollectionExportResponse) GetUrl() []*URLData {
+	if x != nil {
+		return x.Url
+	}
+	return nil
+}
+
 var File_api_shortener_shortener_proto protoreflect.FileDescriptor
 
 const file_api_shortener_shortener_proto_rawDesc = "" +
 	"\n" +
-	"\x1dapi/shortener/shortener.proto\x12\tshortener\x1a\x1bgoogle/protobuf/empty.proto\"%\n" +
+	"\x1dapi/shortener/shortener.proto\x12\tshortener\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\x03\n" +
 	"\x11URLShortenRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\",\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x10\n" +
+	"\x03ttl\x18\x03 \x01(\x03R\x03ttl\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x14\n" +
+	"\x05notes\x18\a \x01(\tR\x05notes\x12#\n" +
+	"\rredirect_code\x18\b \x01(\x05R\fredirectCode\x12 \n" +
+	"\vpassthrough\x18\t \x01(\bR\vpassthrough\x12\x1a\n" +
+	"\bcampaign\x18\n" +
+	" \x01(\tR\bcampaign\x12-\n" +
+	"\x05rules\x18\v \x03(\v2\x17.shortener.RedirectRuleR\x05rules\x121\n" +
+	"\bvariants\x18\f \x03(\v2\x15.shortener.URLVariantR\bvariants\"\xc8\x01\n" +
+	"\fRedirectRule\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x12\n" +
+	"\x04cidr\x18\x03 \x01(\tR\x04cidr\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\"J\n" +
+	"\n" +
+	"URLVariant\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\",\n" +
 	"\x12URLShortenResponse\x12\x16\n" +
 	"\x06result\x18\x01 \x01(\tR\x06result\"\"\n" +
 	"\x10URLExpandRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"+\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"t\n" +
 	"\x11URLExpandResponse\x12\x16\n" +
-	"\x06result\x18\x01 \x01(\tR\x06result\"8\n" +
+	"\x06result\x18\x01 \x01(\tR\x06result\x12#\n" +
+	"\rredirect_code\x18\x02 \x01(\x05R\fredirectCode\x12\"\n" +
+	"\rcache_max_age\x18\x03 \x01(\x03R\vcacheMaxAge\"\xcb\x01\n" +
+	"\x0fUserURLsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\tR\x05order\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
+	"\x06search\x18\x06 \x01(\tR\x06search\x12\x10\n" +
+	"\x03tag\x18\a \x01(\tR\x03tag\x12\x1e\n" +
+	"\n" +
+	"collection\x18\b \x01(\tR\n" +
+	"collection\"Y\n" +
 	"\x10UserURLsResponse\x12$\n" +
-	"\x03url\x18\x01 \x03(\v2\x12.shortener.URLDataR\x03url\"I\n" +
+	"\x03url\x18\x01 \x03(\v2\x12.shortener.URLDataR\x03url\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xc5\x02\n" +
 	"\aURLData\x12\x1b\n" +
 	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
-	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl2\xea\x01\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12D\n" +
+	"\x10last_accessed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x14\n" +
+	"\x05notes\x18\b \x01(\tR\x05notes\"\xa7\x01\n" +
+	"\x0fURLStatsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x16\n" +
+	"\x06bucket\x18\x04 \x01(\tR\x06bucket\x12\x10\n" +
+	"\x03top\x18\x05 \x01(\x05R\x03top\"\xb2\x03\n" +
+	"\x10URLStatsResponse\x12!\n" +
+	"\ftotal_clicks\x18\x01 \x01(\x03R\vtotalClicks\x12'\n" +
+	"\x0funique_visitors\x18\x02 \x01(\x03R\x0euniqueVisitors\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x16\n" +
+	"\x06bucket\x18\x05 \x01(\tR\x06bucket\x120\n" +
+	"\abuckets\x18\x06 \x03(\v2\x16.shortener.ClickBucketR\abuckets\x12:\n" +
+	"\rtop_referrers\x18\a \x03(\v2\x15.shortener.ClickValueR\ftopReferrers\x12=\n" +
+	"\x0ftop_user_agents\x18\b \x03(\v2\x15.shortener.ClickValueR\rtopUserAgents\x121\n" +
+	"\bvariants\x18\t \x03(\v2\x15.shortener.ClickValueR\bvariants\"U\n" +
+	"\vClickBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"8\n" +
+	"\n" +
+	"ClickValue\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"#\n" +
+	"\x11URLRestoreRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x03(\tR\x02id\"$\n" +
+	"\x12URLRestoreResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x03(\tR\x02id\"4\n" +
+	"\x10URLUpdateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"E\n" +
+	"\x11URLUpdateResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\tR\x06result\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"$\n" +
+	"\x12URLVersionsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"F\n" +
+	"\x13URLVersionsResponse\x12/\n" +
+	"\aversion\x18\x01 \x03(\v2\x15.shortener.URLVersionR\aversion\"\x86\x01\n" +
+	"\n" +
+	"URLVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12;\n" +
+	"\vreplaced_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"replacedAt\"\x88\x01\n" +
+	"\n" +
+	"Collection\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
+	"\turl_count\x18\x04 \x01(\x03R\burlCount\"-\n" +
+	"\x17CollectionCreateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x14\n" +
+	"\x12CollectionsRequest\"L\n" +
+	"\x13CollectionsResponse\x125\n" +
+	"\n" +
+	"collection\x18\x01 \x03(\v2\x15.shortener.CollectionR\n" +
+	"collection\"#\n" +
+	"\x11CollectionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"K\n" +
+	"\x12CollectionResponse\x125\n" +
+	"\n" +
+	"collection\x18\x01 \x01(\v2\x15.shortener.CollectionR\n" +
+	"collection\"=\n" +
+	"\x17CollectionRenameRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x1a\n" +
+	"\x18CollectionDeleteResponse\">\n" +
+	"\x15CollectionURLsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06url_id\x18\x02 \x03(\tR\x05urlId\"5\n" +
+	"\x1cCollectionURLsDeleteResponse\x12\x15\n" +
+	"\x06url_id\x18\x01 \x03(\tR\x05urlId\"@\n" +
+	"\x18CollectionExportResponse\x12$\n" +
+	"\x03url\x18\x01 \x03(\v2\x12.shortener.URLDataR\x03url2\xa6\n" +
+	"\n" +
 	"\x10ShortenerService\x12I\n" +
 	"\n" +
 	"ShortenURL\x12\x1c.shortener.URLShortenRequest\x1a\x1d.shortener.URLShortenResponse\x12F\n" +
-	"\tExpandURL\x12\x1b.shortener.URLExpandRequest\x1a\x1c.shortener.URLExpandResponse\x12C\n" +
-	"\fListUserURLs\x12\x16.google.protobuf.Empty\x1a\x1b.shortener.UserURLsResponseB\x0fZ\rshortener/apib\x06proto3"
+	"\tExpandURL\x12\x1b.shortener.URLExpandRequest\x1a\x1c.shortener.URLExpandResponse\x12G\n" +
+	"\fListUserURLs\x12\x1a.shortener.UserURLsRequest\x1a\x1b.shortener.UserURLsResponse\x12F\n" +
+	"\vGetURLStats\x12\x1a.shortener.URLStatsRequest\x1a\x1b.shortener.URLStatsResponse\x12J\n" +
+	"\vRestoreURLs\x12\x1c.shortener.URLRestoreRequest\x1a\x1d.shortener.URLRestoreResponse\x12F\n" +
+	"\tUpdateURL\x12\x1b.shortener.URLUpdateRequest\x1a\x1c.shortener.URLUpdateResponse\x12P\n" +
+	"\x0fListURLVersions\x12\x1d.shortener.URLVersionsRequest\x1a\x1e.shortener.URLVersionsResponse\x12U\n" +
+	"\x10CreateCollection\x12\".shortener.CollectionCreateRequest\x1a\x1d.shortener.CollectionResponse\x12P\n" +
+	"\x0fListCollections\x12\x1d.shortener.CollectionsRequest\x1a\x1e.shortener.CollectionsResponse\x12L\n" +
+	"\rGetCollection\x12\x1c.shortener.CollectionRequest\x1a\x1d.shortener.CollectionResponse\x12U\n" +
+	"\x10RenameCollection\x12\".shortener.CollectionRenameRequest\x1a\x1d.shortener.CollectionResponse\x12U\n" +
+	"\x10DeleteCollection\x12\x1c.shortener.CollectionRequest\x1a#.shortener.CollectionDeleteResponse\x12T\n" +
+	"\x11AddCollectionURLs\x12 .shortener.CollectionURLsRequest\x1a\x1d.shortener.CollectionResponse\x12W\n" +
+	"\x14RemoveCollectionURLs\x12 .shortener.CollectionURLsRequest\x1a\x1d.shortener.CollectionResponse\x12]\n" +
+	"\x14DeleteCollectionURLs\x12\x1c.shortener.CollectionRequest\x1a'.shortener.CollectionURLsDeleteResponse\x12U\n" +
+	"\x10ExportCollection\x12\x1c.shortener.CollectionRequest\x1a#.shortener.CollectionExportResponseB\x0fZ\rshortener/apib\x06proto3"
 
 var (
 	file_api_shortener_shortener_proto_rawDescOnce sync.Once
@@ -330,29 +2054,103 @@ func file_api_shortener_shortener_proto_rawDescGZIP() []byte {
 	return file_api_shortener_shortener_proto_rawDescData
 }
 
-var file_api_shortener_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_shortener_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_api_shortener_shortener_proto_goTypes = []any{
-	(*URLShortenRequest)(nil),  // 0: shortener.URLShortenRequest
-	(*URLShortenResponse)(nil), // 1: shortener.URLShortenResponse
-	(*URLExpandRequest)(nil),   // 2: shortener.URLExpandRequest
-	(*URLExpandResponse)(nil),  // 3: shortener.URLExpandResponse
-	(*UserURLsResponse)(nil),   // 4: shortener.UserURLsResponse
-	(*URLData)(nil),            // 5: shortener.URLData
-	(*emptypb.Empty)(nil),      // 6: google.protobuf.Empty
+	(*URLShortenRequest)(nil),            // 0: shortener.URLShortenRequest
+	(*RedirectRule)(nil),                 // 1: shortener.RedirectRule
+	(*URLVariant)(nil),                   // 2: shortener.URLVariant
+	(*URLShortenResponse)(nil),           // 3: shortener.URLShortenResponse
+	(*URLExpandRequest)(nil),             // 4: shortener.URLExpandRequest
+	(*URLExpandResponse)(nil),            // 5: shortener.URLExpandResponse
+	(*UserURLsRequest)(nil),              // 6: shortener.UserURLsRequest
+	(*UserURLsResponse)(nil),             // 7: shortener.UserURLsResponse
+	(*URLData)(nil),                      // 8: shortener.URLData
+	(*URLStatsRequest)(nil),              // 9: shortener.URLStatsRequest
+	(*URLStatsResponse)(nil),             // 10: shortener.URLStatsResponse
+	(*ClickBucket)(nil),                  // 11: shortener.ClickBucket
+	(*ClickValue)(nil),                   // 12: shortener.ClickValue
+	(*URLRestoreRequest)(nil),            // 13: shortener.URLRestoreRequest
+	(*URLRestoreResponse)(nil),           // 14: shortener.URLRestoreResponse
+	(*URLUpdateRequest)(nil),             // 15: shortener.URLUpdateRequest
+	(*URLUpdateResponse)(nil),            // 16: shortener.URLUpdateResponse
+	(*URLVersionsRequest)(nil),           // 17: shortener.URLVersionsRequest
+	(*URLVersionsResponse)(nil),          // 18: shortener.URLVersionsResponse
+	(*URLVersion)(nil),                   // 19: shortener.URLVersion
+	(*Collection)(nil),                   // 20: shortener.Collection
+	(*CollectionCreateRequest)(nil),      // 21: shortener.CollectionCreateRequest
+	(*CollectionsRequest)(nil),           // 22: shortener.CollectionsRequest
+	(*CollectionsResponse)(nil),          // 23: shortener.CollectionsResponse
+	(*CollectionRequest)(nil),            // 24: shortener.CollectionRequest
+	(*CollectionResponse)(nil),           // 25: shortener.CollectionResponse
+	(*CollectionRenameRequest)(nil),      // 26: shortener.CollectionRenameRequest
+	(*CollectionDeleteResponse)(nil),     // 27: shortener.CollectionDeleteResponse
+	(*CollectionURLsRequest)(nil),        // 28: shortener.CollectionURLsRequest
+	(*CollectionURLsDeleteResponse)(nil), // 29: shortener.CollectionURLsDeleteResponse
+	(*CollectionExportResponse)(nil),     // 30: shortener.CollectionExportResponse
+	(*timestamppb.Timestamp)(nil),        // 31: google.protobuf.Timestamp
 }
 var file_api_shortener_shortener_proto_depIdxs = []int32{
-	5, // 0: shortener.UserURLsResponse.url:type_name -> shortener.URLData
-	0, // 1: shortener.ShortenerService.ShortenURL:input_type -> shortener.URLShortenRequest
-	2, // 2: shortener.ShortenerService.ExpandURL:input_type -> shortener.URLExpandRequest
-	6, // 3: shortener.ShortenerService.ListUserURLs:input_type -> google.protobuf.Empty
-	1, // 4: shortener.ShortenerService.ShortenURL:output_type -> shortener.URLShortenResponse
-	3, // 5: shortener.ShortenerService.ExpandURL:output_type -> shortener.URLExpandResponse
-	4, // 6: shortener.ShortenerService.ListUserURLs:output_type -> shortener.UserURLsResponse
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	31, // 0: shortener.URLShortenRequest.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: shortener.URLShortenRequest.rules:type_name -> shortener.RedirectRule
+	2,  // 2: shortener.URLShortenRequest.variants:type_name -> shortener.URLVariant
+	31, // 3: shortener.RedirectRule.from:type_name -> google.protobuf.Timestamp
+	31, // 4: shortener.RedirectRule.to:type_name -> google.protobuf.Timestamp
+	8,  // 5: shortener.UserURLsResponse.url:type_name -> shortener.URLData
+	31, // 6: shortener.URLData.created_at:type_name -> google.protobuf.Timestamp
+	31, // 7: shortener.URLData.updated_at:type_name -> google.protobuf.Timestamp
+	31, // 8: shortener.URLData.last_accessed_at:type_name -> google.protobuf.Timestamp
+	31, // 9: shortener.URLStatsRequest.from:type_name -> google.protobuf.Timestamp
+	31, // 10: shortener.URLStatsRequest.to:type_name -> google.protobuf.Timestamp
+	31, // 11: shortener.URLStatsResponse.from:type_name -> google.protobuf.Timestamp
+	31, // 12: shortener.URLStatsResponse.to:type_name -> google.protobuf.Timestamp
+	11, // 13: shortener.URLStatsResponse.buckets:type_name -> shortener.ClickBucket
+	12, // 14: shortener.URLStatsResponse.top_referrers:type_name -> shortener.ClickValue
+	12, // 15: shortener.URLStatsResponse.top_user_agents:type_name -> shortener.ClickValue
+	12, // 16: shortener.URLStatsResponse.variants:type_name -> shortener.ClickValue
+	31, // 17: shortener.ClickBucket.start:type_name -> google.protobuf.Timestamp
+	19, // 18: shortener.URLVersionsResponse.version:type_name -> shortener.URLVersion
+	31, // 19: shortener.URLVersion.replaced_at:type_name -> google.protobuf.Timestamp
+	31, // 20: shortener.Collection.created_at:type_name -> google.protobuf.Timestamp
+	20, // 21: shortener.CollectionsResponse.collection:type_name -> shortener.Collection
+	20, // 22: shortener.CollectionResponse.collection:type_name -> shortener.Collection
+	8,  // 23: shortener.CollectionExportResponse.url:type_name -> shortener.URLData
+	0,  // 24: shortener.ShortenerService.ShortenURL:input_type -> shortener.URLShortenRequest
+	4,  // 25: shortener.ShortenerService.ExpandURL:input_type -> shortener.URLExpandRequest
+	6,  // 26: shortener.ShortenerService.ListUserURLs:input_type -> shortener.UserURLsRequest
+	9,  // 27: shortener.ShortenerService.GetURLStats:input_type -> shortener.URLStatsRequest
+	13, // 28: shortener.ShortenerService.RestoreURLs:input_type -> shortener.URLRestoreRequest
+	15, // 29: shortener.ShortenerService.UpdateURL:input_type -> shortener.URLUpdateRequest
+	17, // 30: shortener.ShortenerService.ListURLVersions:input_type -> shortener.URLVersionsRequest
+	21, // 31: shortener.ShortenerService.CreateCollection:input_type -> shortener.CollectionCreateRequest
+	22, // 32: shortener.ShortenerService.ListCollections:input_type -> shortener.CollectionsRequest
+	24, // 33: shortener.ShortenerService.GetCollection:input_type -> shortener.CollectionRequest
+	26, // 34: shortener.ShortenerService.RenameCollection:input_type -> shortener.CollectionRenameRequest
+	24, // 35: shortener.ShortenerService.DeleteCollection:input_type -> shortener.CollectionRequest
+	28, // 36: shortener.ShortenerService.AddCollectionURLs:input_type -> shortener.CollectionURLsRequest
+	28, // 37: shortener.ShortenerService.RemoveCollectionURLs:input_type -> shortener.CollectionURLsRequest
+	24, // 38: shortener.ShortenerService.DeleteCollectionURLs:input_type -> shortener.CollectionRequest
+	24, // 39: shortener.ShortenerService.ExportCollection:input_type -> shortener.CollectionRequest
+	3,  // 40: shortener.ShortenerService.ShortenURL:output_type -> shortener.URLShortenResponse
+	5,  // 41: shortener.ShortenerService.ExpandURL:output_type -> shortener.URLExpandResponse
+	7,  // 42: shortener.ShortenerService.ListUserURLs:output_type -> shortener.UserURLsResponse
+	10, // 43: shortener.ShortenerService.GetURLStats:output_type -> shortener.URLStatsResponse
+	14, // 44: shortener.ShortenerService.RestoreURLs:output_type -> shortener.URLRestoreResponse
+	16, // 45: shortener.ShortenerService.UpdateURL:output_type -> shortener.URLUpdateResponse
+	18, // 46: shortener.ShortenerService.ListURLVersions:output_type -> shortener.URLVersionsResponse
+	25, // 47: shortener.ShortenerService.CreateCollection:output_type -> shortener.CollectionResponse
+	23, // 48: shortener.ShortenerService.ListCollections:output_type -> shortener.CollectionsResponse
+	25, // 49: shortener.ShortenerService.GetCollection:output_type -> shortener.CollectionResponse
+	25, // 50: shortener.ShortenerService.RenameCollection:output_type -> shortener.CollectionResponse
+	27, // 51: shortener.ShortenerService.DeleteCollection:output_type -> shortener.CollectionDeleteResponse
+	25, // 52: shortener.ShortenerService.AddCollectionURLs:output_type -> shortener.CollectionResponse
+	25, // 53: shortener.ShortenerService.RemoveCollectionURLs:output_type -> shortener.CollectionResponse
+	29, // 54: shortener.ShortenerService.DeleteCollectionURLs:output_type -> shortener.CollectionURLsDeleteResponse
+	30, // 55: shortener.ShortenerService.ExportCollection:output_type -> shortener.CollectionExportResponse
+	40, // [40:56] is the sub-list for method output_type
+	24, // [24:40] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_api_shortener_shortener_proto_init() }
@@ -366,7 +2164,7 @@ func file_api_shortener_shortener_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_shortener_shortener_proto_rawDesc), len(file_api_shortener_shortener_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
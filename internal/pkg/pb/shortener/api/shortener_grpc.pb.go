@@ -11,7 +11,6 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -20,9 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ShortenerService_ShortenURL_FullMethodName   = "/shortener.ShortenerService/ShortenURL"
-	ShortenerService_ExpandURL_FullMethodName    = "/shortener.ShortenerService/ExpandURL"
-	ShortenerService_ListUserURLs_FullMethodName = "/shortener.ShortenerService/ListUserURLs"
+	ShortenerService_ShortenURL_FullMethodName           = "/shortener.ShortenerService/ShortenURL"
+	ShortenerService_ExpandURL_FullMethodName            = "/shortener.ShortenerService/ExpandURL"
+	ShortenerService_ListUserURLs_FullMethodName         = "/shortener.ShortenerService/ListUserURLs"
+	ShortenerService_GetURLStats_FullMethodName          = "/shortener.ShortenerService/GetURLStats"
+	ShortenerService_RestoreURLs_FullMethodName          = "/shortener.ShortenerService/RestoreURLs"
+	ShortenerService_UpdateURL_FullMethodName            = "/shortener.ShortenerService/UpdateURL"
+	ShortenerService_ListURLVersions_FullMethodName      = "/shortener.ShortenerService/ListURLVersions"
+	ShortenerService_CreateCollection_FullMethodName     = "/shortener.ShortenerService/CreateCollection"
+	ShortenerService_ListCollections_FullMethodName      = "/shortener.ShortenerService/ListCollections"
+	ShortenerService_GetCollection_FullMethodName        = "/shortener.ShortenerService/GetCollection"
+	ShortenerService_RenameCollection_FullMethodName     = "/shortener.ShortenerService/RenameCollection"
+	ShortenerService_DeleteCollection_FullMethodName     = "/shortener.ShortenerService/DeleteCollection"
+	ShortenerService_AddCollectionURLs_FullMethodName    = "/shortener.ShortenerService/AddCollectionURLs"
+	ShortenerService_RemoveCollectionURLs_FullMethodName = "/shortener.ShortenerService/RemoveCollectionURLs"
+	ShortenerService_DeleteCollectionURLs_FullMethodName = "/shortener.ShortenerService/DeleteCollectionURLs"
+	ShortenerService_ExportCollection_FullMethodName     = "/shortener.ShortenerService/ExportCollection"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -33,10 +45,37 @@ const (
 type ShortenerServiceClient interface {
 	// Сократить URL.
 	ShortenURL(ctx context.Context, in *URLShortenRequest, opts ...grpc.CallOption) (*URLShortenResponse, error)
-	// Получить полный URL.
+	// Получить полный URL. Правила выбираются по метаданным user-agent, accept-language и x-real-ip,
+	// вариант - по x-visitor-id; новому посетителю идентификатор возвращается в заголовке x-visitor-id.
 	ExpandURL(ctx context.Context, in *URLExpandRequest, opts ...grpc.CallOption) (*URLExpandResponse, error)
-	// Список всех URL пользователя.
-	ListUserURLs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UserURLsResponse, error)
+	// Страница URL пользователя.
+	ListUserURLs(ctx context.Context, in *UserURLsRequest, opts ...grpc.CallOption) (*UserURLsResponse, error)
+	// Статистика переходов по короткой ссылке пользователя.
+	GetURLStats(ctx context.Context, in *URLStatsRequest, opts ...grpc.CallOption) (*URLStatsResponse, error)
+	// Восстановить удаленные ссылки пользователя в течение периода восстановления.
+	RestoreURLs(ctx context.Context, in *URLRestoreRequest, opts ...grpc.CallOption) (*URLRestoreResponse, error)
+	// Изменить адрес, на который ведет короткая ссылка пользователя.
+	UpdateURL(ctx context.Context, in *URLUpdateRequest, opts ...grpc.CallOption) (*URLUpdateResponse, error)
+	// История адресов короткой ссылки пользователя, последние версии первыми.
+	ListURLVersions(ctx context.Context, in *URLVersionsRequest, opts ...grpc.CallOption) (*URLVersionsResponse, error)
+	// Создать коллекцию ссылок пользователя.
+	CreateCollection(ctx context.Context, in *CollectionCreateRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	// Коллекции пользователя, упорядоченные по имени.
+	ListCollections(ctx context.Context, in *CollectionsRequest, opts ...grpc.CallOption) (*CollectionsResponse, error)
+	// Получить коллекцию пользователя.
+	GetCollection(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	// Переименовать коллекцию пользователя.
+	RenameCollection(ctx context.Context, in *CollectionRenameRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	// Удалить коллекцию пользователя, ссылки коллекции остаются.
+	DeleteCollection(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionDeleteResponse, error)
+	// Добавить ссылки пользователя в коллекцию.
+	AddCollectionURLs(ctx context.Context, in *CollectionURLsRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	// Исключить ссылки из коллекции.
+	RemoveCollectionURLs(ctx context.Context, in *CollectionURLsRequest, opts ...grpc.CallOption) (*CollectionResponse, error)
+	// Удалить все ссылки коллекции.
+	DeleteCollectionURLs(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionURLsDeleteResponse, error)
+	// Выгрузить все живые ссылки коллекции.
+	ExportCollection(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionExportResponse, error)
 }
 
 type shortenerServiceClient struct {
@@ -67,7 +106,7 @@ func (c *shortenerServiceClient) ExpandURL(ctx context.Context, in *URLExpandReq
 	return out, nil
 }
 
-func (c *shortenerServiceClient) ListUserURLs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UserURLsResponse, error) {
+func (c *shortenerServiceClient) ListUserURLs(ctx context.Context, in *UserURLsRequest, opts ...grpc.CallOption) (*UserURLsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UserURLsResponse)
 	err := c.cc.Invoke(ctx, ShortenerService_ListUserURLs_FullMethodName, in, out, cOpts...)
@@ -77,6 +116,136 @@ func (c *shortenerServiceClient) ListUserURLs(ctx context.Context, in *emptypb.E
 	return out, nil
 }
 
+func (c *shortenerServiceClient) GetURLStats(ctx context.Context, in *URLStatsRequest, opts ...grpc.CallOption) (*URLStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URLStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetURLStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RestoreURLs(ctx context.Context, in *URLRestoreRequest, opts ...grpc.CallOption) (*URLRestoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URLRestoreResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RestoreURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) UpdateURL(ctx context.Context, in *URLUpdateRequest, opts ...grpc.CallOption) (*URLUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URLUpdateResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_UpdateURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) ListURLVersions(ctx context.Context, in *URLVersionsRequest, opts ...grpc.CallOption) (*URLVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(URLVersionsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_ListURLVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) CreateCollection(ctx context.Context, in *CollectionCreateRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_CreateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) ListCollections(ctx context.Context, in *CollectionsRequest, opts ...grpc.CallOption) (*CollectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_ListCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetCollection(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RenameCollection(ctx context.Context, in *CollectionRenameRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RenameCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) DeleteCollection(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionDeleteResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_DeleteCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) AddCollectionURLs(ctx context.Context, in *CollectionURLsRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_AddCollectionURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RemoveCollectionURLs(ctx context.Context, in *CollectionURLsRequest, opts ...grpc.CallOption) (*CollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RemoveCollectionURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) DeleteCollectionURLs(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionURLsDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionURLsDeleteResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_DeleteCollectionURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) ExportCollection(ctx context.Context, in *CollectionRequest, opts ...grpc.CallOption) (*CollectionExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectionExportResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_ExportCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortenerServiceServer is the server API for ShortenerService service.
 // All implementations must embed UnimplementedShortenerServiceServer
 // for forward compatibility.
@@ -85,10 +254,37 @@ func (c *shortenerServiceClient) ListUserURLs(ctx context.Context, in *emptypb.E
 type ShortenerServiceServer interface {
 	// Сократить URL.
 	ShortenURL(context.Context, *URLShortenRequest) (*URLShortenResponse, error)
-	// Получить полный URL.
+	// Получить полный URL. Правила выбираются по метаданным user-agent, accept-language и x-real-ip,
+	// вариант - по x-visitor-id; новому посетителю идентификатор возвращается в заголовке x-visitor-id.
 	ExpandURL(context.Context, *URLExpandRequest) (*URLExpandResponse, error)
-	// Список всех URL пользователя.
-	ListUserURLs(context.Context, *emptypb.Empty) (*UserURLsResponse, error)
+	// Страница URL пользователя.
+	ListUserURLs(context.Context, *UserURLsRequest) (*UserURLsResponse, error)
+	// Статистика переходов по короткой ссылке пользователя.
+	GetURLStats(context.Context, *URLStatsRequest) (*URLStatsResponse, error)
+	// Восстановить удаленные ссылки пользователя в течение периода восстановления.
+	RestoreURLs(context.Context, *URLRestoreRequest) (*URLRestoreResponse, error)
+	// Изменить адрес, на который ведет короткая ссылка пользователя.
+	UpdateURL(context.Context, *URLUpdateRequest) (*URLUpdateResponse, error)
+	// История адресов короткой ссылки пользователя, последние версии первыми.
+	ListURLVersions(context.Context, *URLVersionsRequest) (*URLVersionsResponse, error)
+	// Создать коллекцию ссылок пользователя.
+	CreateCollection(context.Context, *CollectionCreateRequest) (*CollectionResponse, error)
+	// Коллекции пользователя, упорядоченные по имени.
+	ListCollections(context.Context, *CollectionsRequest) (*CollectionsResponse, error)
+	// Получить коллекцию пользователя.
+	GetCollection(context.Context, *CollectionRequest) (*CollectionResponse, error)
+	// Переименовать коллекцию пользователя.
+	RenameCollection(context.Context, *CollectionRenameRequest) (*CollectionResponse, error)
+	// Удалить коллекцию пользователя, ссылки коллекции остаются.
+	DeleteCollection(context.Context, *CollectionRequest) (*CollectionDeleteResponse, error)
+	// Добавить ссылки пользователя в коллекцию.
+	AddCollectionURLs(context.Context, *CollectionURLsRequest) (*CollectionResponse, error)
+	// Исключить ссылки из коллекции.
+	RemoveCollectionURLs(context.Context, *CollectionURLsRequest) (*CollectionResponse, error)
+	// Удалить все ссылки коллекции.
+	DeleteCollectionURLs(context.Context, *CollectionRequest) (*CollectionURLsDeleteResponse, error)
+	// Выгрузить все живые ссылки коллекции.
+	ExportCollection(context.Context, *CollectionRequest) (*CollectionExportResponse, error)
 	mustEmbedUnimplementedShortenerServiceServer()
 }
 
@@ -105,9 +301,48 @@ func (UnimplementedShortenerServiceServer) ShortenURL(context.Context, *URLShort
 func (UnimplementedShortenerServiceServer) ExpandURL(context.Context, *URLExpandRequest) (*URLExpandResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ExpandURL not implemented")
 }
-func (UnimplementedShortenerServiceServer) ListUserURLs(context.Context, *emptypb.Empty) (*UserURLsResponse, error) {
+func (UnimplementedShortenerServiceServer) ListUserURLs(context.Context, *UserURLsRequest) (*UserURLsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListUserURLs not implemented")
 }
+func (UnimplementedShortenerServiceServer) GetURLStats(context.Context, *URLStatsRequest) (*URLStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetURLStats not implemented")
+}
+func (UnimplementedShortenerServiceServer) RestoreURLs(context.Context, *URLRestoreRequest) (*URLRestoreResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestoreURLs not implemented")
+}
+func (UnimplementedShortenerServiceServer) UpdateURL(context.Context, *URLUpdateRequest) (*URLUpdateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateURL not implemented")
+}
+func (UnimplementedShortenerServiceServer) ListURLVersions(context.Context, *URLVersionsRequest) (*URLVersionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListURLVersions not implemented")
+}
+func (UnimplementedShortenerServiceServer) CreateCollection(context.Context, *CollectionCreateRequest) (*CollectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCollection not implemented")
+}
+func (UnimplementedShortenerServiceServer) ListCollections(context.Context, *CollectionsRequest) (*CollectionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCollections not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetCollection(context.Context, *CollectionRequest) (*CollectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCollection not implemented")
+}
+func (UnimplementedShortenerServiceServer) RenameCollection(context.Context, *CollectionRenameRequest) (*CollectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameCollection not implemented")
+}
+func (UnimplementedShortenerServiceServer) DeleteCollection(context.Context, *CollectionRequest) (*CollectionDeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteCollection not implemented")
+}
+func (UnimplementedShortenerServiceServer) AddCollectionURLs(context.Context, *CollectionURLsRequest) (*CollectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddCollectionURLs not implemented")
+}
+func (UnimplementedShortenerServiceServer) RemoveCollectionURLs(context.Context, *CollectionURLsRequest) (*CollectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveCollectionURLs not implemented")
+}
+func (UnimplementedShortenerServiceServer) DeleteCollectionURLs(context.Context, *CollectionRequest) (*CollectionURLsDeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteCollectionURLs not implemented")
+}
+func (UnimplementedShortenerServiceServer) ExportCollection(context.Context, *CollectionRequest) (*CollectionExportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportCollection not implemented")
+}
 func (UnimplementedShortenerServiceServer) mustEmbedUnimplementedShortenerServiceServer() {}
 func (UnimplementedShortenerServiceServer) testEmbeddedByValue()                          {}
 
@@ -166,7 +401,7 @@ func _ShortenerService_ExpandURL_Handler(srv interface{}, ctx context.Context, d
 }
 
 func _ShortenerService_ListUserURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(UserURLsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -178,7 +413,241 @@ func _ShortenerService_ListUserURLs_Handler(srv interface{}, ctx context.Context
 		FullMethod: ShortenerService_ListUserURLs_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ShortenerServiceServer).ListUserURLs(ctx, req.(*emptypb.Empty))
+		return srv.(ShortenerServiceServer).ListUserURLs(ctx, req.(*UserURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetURLStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetURLStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetURLStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetURLStats(ctx, req.(*URLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RestoreURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URLRestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RestoreURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RestoreURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RestoreURLs(ctx, req.(*URLRestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_UpdateURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URLUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).UpdateURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_UpdateURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).UpdateURL(ctx, req.(*URLUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_ListURLVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URLVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).ListURLVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_ListURLVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).ListURLVersions(ctx, req.(*URLVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_CreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).CreateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_CreateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).CreateCollection(ctx, req.(*CollectionCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_ListCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).ListCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_ListCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).ListCollections(ctx, req.(*CollectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetCollection(ctx, req.(*CollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RenameCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionRenameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RenameCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RenameCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RenameCollection(ctx, req.(*CollectionRenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_DeleteCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).DeleteCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_DeleteCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).DeleteCollection(ctx, req.(*CollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_AddCollectionURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).AddCollectionURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_AddCollectionURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).AddCollectionURLs(ctx, req.(*CollectionURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RemoveCollectionURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RemoveCollectionURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RemoveCollectionURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RemoveCollectionURLs(ctx, req.(*CollectionURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_DeleteCollectionURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).DeleteCollectionURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_DeleteCollectionURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).DeleteCollectionURLs(ctx, req.(*CollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_ExportCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).ExportCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_ExportCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).ExportCollection(ctx, req.(*CollectionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -202,6 +671,58 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListUserURLs",
 			Handler:    _ShortenerService_ListUserURLs_Handler,
 		},
+		{
+			MethodName: "GetURLStats",
+			Handler:    _ShortenerService_GetURLStats_Handler,
+		},
+		{
+			MethodName: "RestoreURLs",
+			Handler:    _ShortenerService_RestoreURLs_Handler,
+		},
+		{
+			MethodName: "UpdateURL",
+			Handler:    _ShortenerService_UpdateURL_Handler,
+		},
+		{
+			MethodName: "ListURLVersions",
+			Handler:    _ShortenerService_ListURLVersions_Handler,
+		},
+		{
+			MethodName: "CreateCollection",
+			Handler:    _ShortenerService_CreateCollection_Handler,
+		},
+		{
+			MethodName: "ListCollections",
+			Handler:    _ShortenerService_ListCollections_Handler,
+		},
+		{
+			MethodName: "GetCollection",
+			Handler:    _ShortenerService_GetCollection_Handler,
+		},
+		{
+			MethodName: "RenameCollection",
+			Handler:    _ShortenerService_RenameCollection_Handler,
+		},
+		{
+			MethodName: "DeleteCollection",
+			Handler:    _ShortenerService_DeleteCollection_Handler,
+		},
+		{
+			MethodName: "AddCollectionURLs",
+			Handler:    _ShortenerService_AddCollectionURLs_Handler,
+		},
+		{
+			MethodName: "RemoveCollectionURLs",
+			Handler:    _ShortenerService_RemoveCollectionURLs_Handler,
+		},
+		{
+			MethodName: "DeleteCollectionURLs",
+			Handler:    _ShortenerService_DeleteCollectionURLs_Handler,
+		},
+		{
+			MethodName: "ExportCollection",
+			Handler:    _ShortenerService_ExportCollection_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/shortener/shortener.proto",
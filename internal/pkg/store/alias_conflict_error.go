@@ -0,0 +1,17 @@
+package store
+
+import "fmt"
+
+type AliasConflictError struct {
+	Alias string
+}
+
+func NewAliasConflictError(alias string) *AliasConflictError {
+	return &AliasConflictError{
+		Alias: alias,
+	}
+}
+
+func (e *AliasConflictError) Error() string {
+	return fmt.Sprintf("alias %v is already taken", e.Alias)
+}
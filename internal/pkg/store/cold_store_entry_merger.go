@@ -0,0 +1,159 @@
+package store
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/aleffnull/shortener/internal/domain"
+)
+
+// coldStoreEntryMerger replays cold store entries in the order they were written
+// and keeps one put entry per key with all later updates applied and the last entry of every collection and campaign.
+type coldStoreEntryMerger struct {
+	keys       []string
+	keyToEntry map[string]*domain.ColdStoreEntry
+	// Collection entries are kept apart, their keys are collection IDs.
+	collectionIDs  []string
+	idToCollection map[string]*domain.ColdStoreEntry
+	// Campaign entries are kept the same way.
+	campaignIDs  []string
+	idToCampaign map[string]*domain.ColdStoreEntry
+	// The highest leased key sequence value.
+	sequence uint64
+}
+
+func newColdStoreEntryMerger() *coldStoreEntryMerger {
+	return &coldStoreEntryMerger{
+		keyToEntry:     make(map[string]*domain.ColdStoreEntry),
+		idToCollection: make(map[string]*domain.ColdStoreEntry),
+		idToCampaign:   make(map[string]*domain.ColdStoreEntry),
+	}
+}
+
+func (m *coldStoreEntryMerger) add(entry *domain.ColdStoreEntry) {
+	switch entry.Type {
+	case domain.ColdStoreEntryTypeSequence:
+		m.sequence = max(m.sequence, entry.Sequence)
+	case domain.ColdStoreEntryTypeDelete:
+		if existing, ok := m.keyToEntry[entry.Key]; ok {
+			existing.IsDeleted = true
+			existing.DeletedAt = entry.DeletedAt
+			existing.UpdatedAt = entry.DeletedAt
+		}
+	case domain.ColdStoreEntryTypeRestore:
+		if existing, ok := m.keyToEntry[entry.Key]; ok {
+			existing.IsDeleted = false
+			existing.DeletedAt = nil
+			existing.UpdatedAt = entry.UpdatedAt
+		}
+	case domain.ColdStoreEntryTypeUpdate:
+		if existing, ok := m.keyToEntry[entry.Key]; ok && !isUpdateApplied(entry, len(existing.Versions)) {
+			replacedAt := time.Time{}
+			if entry.UpdatedAt != nil {
+				replacedAt = *entry.UpdatedAt
+			}
+
+			// The versions of the entry may be shared with the replayed one, so they are copied.
+			existing.Versions = append(slices.Clone(existing.Versions), &domain.URLVersion{
+				Version:     len(existing.Versions) + 1,
+				OriginalURL: existing.Value,
+				ReplacedAt:  replacedAt,
+			})
+			existing.Value = entry.Value
+			existing.CanonicalURL = entry.CanonicalURL
+			existing.UpdatedAt = entry.UpdatedAt
+		}
+	case domain.ColdStoreEntryTypeAccess:
+		if existing, ok := m.keyToEntry[entry.Key]; ok && laterTime(entry.LastAccessedAt, existing.LastAccessedAt) {
+			existing.LastAccessedAt = entry.LastAccessedAt
+		}
+	case domain.ColdStoreEntryTypePurge:
+		// The key is left in keys, a key put again after the purge is there twice.
+		delete(m.keyToEntry, entry.Key)
+		for _, collection := range m.idToCollection {
+			// The keys of the collection may be shared with the replayed entry, so they are copied.
+			collection.Keys = slices.DeleteFunc(slices.Clone(collection.Keys), func(key string) bool {
+				return key == entry.Key
+			})
+		}
+	case domain.ColdStoreEntryTypeCollection:
+		if _, ok := m.idToCollection[entry.Key]; !ok {
+			m.collectionIDs = append(m.collectionIDs, entry.Key)
+		}
+
+		collection := *entry
+		m.idToCollection[entry.Key] = &collection
+	case domain.ColdStoreEntryTypeDeleteCollection:
+		// Collection IDs are never reused, so the ID is left in collectionIDs.
+		delete(m.idToCollection, entry.Key)
+	case domain.ColdStoreEntryTypeCampaign:
+		if _, ok := m.idToCampaign[entry.Key]; !ok {
+			m.campaignIDs = append(m.campaignIDs, entry.Key)
+		}
+
+		campaign := *entry
+		m.idToCampaign[entry.Key] = &campaign
+	case domain.ColdStoreEntryTypeDeleteCampaign:
+		// Campaign IDs are never reused, so the ID is left in campaignIDs.
+		delete(m.idToCampaign, entry.Key)
+		for _, existing := range m.keyToEntry {
+			if existing.CampaignID.String() == entry.Key {
+				existing.CampaignID = uuid.Nil
+			}
+		}
+	case domain.ColdStoreEntryTypeUpdateOwner:
+		if existing, ok := m.keyToEntry[entry.Key]; ok {
+			existing.UserID = entry.UserID
+		}
+	case domain.ColdStoreEntryTypeUpdateVariants:
+		if existing, ok := m.keyToEntry[entry.Key]; ok {
+			existing.Variants = entry.Variants
+			existing.UpdatedAt = entry.UpdatedAt
+		}
+	default:
+		if _, ok := m.keyToEntry[entry.Key]; !ok {
+			m.keys = append(m.keys, entry.Key)
+		}
+
+		put := *entry
+		put.Type = domain.ColdStoreEntryTypePut
+		m.keyToEntry[entry.Key] = &put
+	}
+}
+
+// isUpdateApplied reports whether the item with the given number of previous values already has the update.
+// It happens when the journal is replayed over the snapshot it was folded into.
+func isUpdateApplied(entry *domain.ColdStoreEntry, versions int) bool {
+	return entry.Version > 0 && versions >= entry.Version
+}
+
+func (m *coldStoreEntryMerger) entries() []*domain.ColdStoreEntry {
+	entries := make([]*domain.ColdStoreEntry, 0, len(m.keyToEntry)+len(m.idToCollection)+len(m.idToCampaign))
+	seen := make(map[string]struct{}, len(m.keyToEntry))
+	for _, key := range m.keys {
+		entry, ok := m.keyToEntry[key]
+		if _, done := seen[key]; !ok || done {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		entries = append(entries, entry)
+	}
+
+	// Collections and then campaigns go last, the snapshot keeps them after all URLs.
+	for _, id := range m.collectionIDs {
+		if collection, ok := m.idToCollection[id]; ok {
+			entries = append(entries, collection)
+		}
+	}
+
+	for _, id := range m.campaignIDs {
+		if campaign, ok := m.idToCampaign[id]; ok {
+			entries = append(entries, campaign)
+		}
+	}
+
+	return entries
+}
@@ -1,10 +1,16 @@
 package store
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
@@ -21,21 +27,38 @@ type DatabaseStore struct {
 	connection    repository.Connection
 	configuration *config.DatabaseStoreConfiguration
 	logger        logger.Logger
+	dedupScope    string
 }
 
 type executorFunc func(ctx context.Context, sql string, args ...any) error
 
+// Unique constraints of the collection and campaign names of a user.
+const (
+	collectionNameConstraint = "collections_user_name_unique"
+	campaignNameConstraint   = "campaigns_user_name_unique"
+)
+
+// Unique indexes of the deduplication scopes, a violation means the URL is already shortened.
+var dedupIndexes = []string{
+	"urls_canonical_url_global_unique",
+	"urls_user_canonical_url_unique",
+}
+
 var _ Store = (*DatabaseStore)(nil)
 
 func NewDatabaseStore(connection repository.Connection, configuration *config.Configuration, logger logger.Logger) Store {
 	store := &DatabaseStore{
 		keyStore: keyStore{
-			configuration: &configuration.DatabaseStore.KeyStoreConfiguration,
+			configuration:   &configuration.DatabaseStore.KeyStoreConfiguration,
+			aliasCharacters: getAliasCharacters(configuration.KeyGenerator),
 		},
 		connection:    connection,
 		configuration: configuration.DatabaseStore,
 		logger:        logger,
+		dedupScope:    cmp.Or(configuration.DedupScope, config.DedupScopeGlobal),
 	}
+	allocator := newIDAllocator(configuration.KeyGenerator, store.leaseKeySequence)
+	store.generator = NewKeyGenerator(configuration.KeyGenerator, allocator.allocate)
 
 	return store
 }
@@ -44,6 +67,10 @@ func (s *DatabaseStore) Init() error {
 	return nil
 }
 
+func (s *DatabaseStore) Shutdown() {
+	// The connection is closed by its owner.
+}
+
 func (s *DatabaseStore) CheckAvailability(ctx context.Context) error {
 	err := s.connection.Ping(ctx)
 	if err != nil {
@@ -53,10 +80,191 @@ func (s *DatabaseStore) CheckAvailability(ctx context.Context) error {
 	return nil
 }
 
+func (s *DatabaseStore) Compact(context.Context) error {
+	// Postgres reclaims the space of deleted rows by itself.
+	return nil
+}
+
+// Export writes all URLs that are not purged yet, then all collections and campaigns as JSON lines in the file store journal format,
+// so the export can be used to seed a file-backed instance.
+func (s *DatabaseStore) Export(ctx context.Context, writer io.Writer) error {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select url_key, original_url, canonical_url, user_id, is_deleted, deleted_at, expires_at, "+
+			"created_at, updated_at, last_accessed_at, "+
+			"(select json_agg(json_build_object('version', v.version, 'original_url', v.original_url, 'replaced_at', v.replaced_at) "+
+			"order by v.version) from url_versions v where v.url_key = urls.url_key), "+
+			"coalesce(m.title, ''), coalesce(m.notes, ''), "+
+			"(select json_agg(t.tag order by t.tag) from url_tags t where t.url_key = urls.url_key), redirect_code, passthrough, "+
+			"campaign_id, redirect_rules, url_variants, dedup_scope "+
+			"from urls left join url_metadata m using (url_key) order by url_key",
+	)
+	if err != nil {
+		return fmt.Errorf("DatabaseStore.Export, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	encoder := json.NewEncoder(writer)
+	for rows.Next() {
+		entry := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut}
+		var versions, tags, rules, variants []byte
+		err = rows.Scan(
+			&entry.Key,
+			&entry.Value,
+			&entry.CanonicalURL,
+			&entry.UserID,
+			&entry.IsDeleted,
+			&entry.DeletedAt,
+			&entry.ExpiresAt,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+			&entry.LastAccessedAt,
+			&versions,
+			&entry.Title,
+			&entry.Notes,
+			&tags,
+			&entry.RedirectCode,
+			&entry.Passthrough,
+			&entry.CampaignID,
+			&rules,
+			&variants,
+			&entry.DedupScope,
+		)
+		if err != nil {
+			return fmt.Errorf("DatabaseStore.Export, rows.Scan failed: %w", err)
+		}
+
+		if len(versions) > 0 {
+			if err = json.Unmarshal(versions, &entry.Versions); err != nil {
+				return fmt.Errorf("DatabaseStore.Export, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		if len(tags) > 0 {
+			if err = json.Unmarshal(tags, &entry.Tags); err != nil {
+				return fmt.Errorf("DatabaseStore.Export, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		if len(rules) > 0 {
+			if err = json.Unmarshal(rules, &entry.Rules); err != nil {
+				return fmt.Errorf("DatabaseStore.Export, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		if len(variants) > 0 {
+			if err = json.Unmarshal(variants, &entry.Variants); err != nil {
+				return fmt.Errorf("DatabaseStore.Export, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		// The journal keeps the canonical URL only when it differs from the original one.
+		if entry.CanonicalURL == entry.Value {
+			entry.CanonicalURL = ""
+		}
+
+		if err = encoder.Encode(entry); err != nil {
+			return fmt.Errorf("DatabaseStore.Export, encoder.Encode failed: %w", err)
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("DatabaseStore.Export, rows.Err failed: %w", err)
+	}
+
+	if err = s.exportCollections(ctx, encoder); err != nil {
+		return fmt.Errorf("DatabaseStore.Export, exportCollections failed: %w", err)
+	}
+
+	if err = s.exportCampaigns(ctx, encoder); err != nil {
+		return fmt.Errorf("DatabaseStore.Export, exportCampaigns failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DatabaseStore) exportCollections(ctx context.Context, encoder *json.Encoder) error {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select c.id, c.user_id, c.name, c.created_at, "+
+			"(select json_agg(cu.url_key order by cu.url_key) from collection_urls cu where cu.collection_id = c.id) "+
+			"from collections c order by c.id",
+	)
+	if err != nil {
+		return fmt.Errorf("DatabaseStore.exportCollections, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection}
+		var keys []byte
+		if err = rows.Scan(&entry.Key, &entry.UserID, &entry.Value, &entry.CreatedAt, &keys); err != nil {
+			return fmt.Errorf("DatabaseStore.exportCollections, rows.Scan failed: %w", err)
+		}
+
+		if len(keys) > 0 {
+			if err = json.Unmarshal(keys, &entry.Keys); err != nil {
+				return fmt.Errorf("DatabaseStore.exportCollections, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		if err = encoder.Encode(entry); err != nil {
+			return fmt.Errorf("DatabaseStore.exportCollections, encoder.Encode failed: %w", err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("DatabaseStore.exportCollections, rows.Err failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DatabaseStore) exportCampaigns(ctx context.Context, encoder *json.Encoder) error {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select id, user_id, name, created_at, parameters from campaigns order by id",
+	)
+	if err != nil {
+		return fmt.Errorf("DatabaseStore.exportCampaigns, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCampaign}
+		var parameters []byte
+		if err = rows.Scan(&entry.Key, &entry.UserID, &entry.Value, &entry.CreatedAt, &parameters); err != nil {
+			return fmt.Errorf("DatabaseStore.exportCampaigns, rows.Scan failed: %w", err)
+		}
+
+		if err = json.Unmarshal(parameters, &entry.Parameters); err != nil {
+			return fmt.Errorf("DatabaseStore.exportCampaigns, json.Unmarshal failed: %w", err)
+		}
+
+		if err = encoder.Encode(entry); err != nil {
+			return fmt.Errorf("DatabaseStore.exportCampaigns, encoder.Encode failed: %w", err)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("DatabaseStore.exportCampaigns, rows.Err failed: %w", err)
+	}
+
+	return nil
+}
+
+// Load joins the campaign of the URL, so the redirect needs one query.
 func (s *DatabaseStore) Load(ctx context.Context, key string) (*domain.URLItem, error) {
 	rows, err := s.connection.QueryRows(
 		ctx,
-		"select original_url, user_id, is_deleted from urls where url_key = $1",
+		"select original_url, urls.user_id, is_deleted, deleted_at, expires_at, urls.created_at, updated_at, last_accessed_at, "+
+			"redirect_code, passthrough, c.parameters, redirect_rules, url_variants "+
+			"from urls left join campaigns c on c.id = urls.campaign_id "+
+			"where url_key = $1",
 		key,
 	)
 	if err != nil {
@@ -68,11 +276,44 @@ func (s *DatabaseStore) Load(ctx context.Context, key string) (*domain.URLItem,
 	var item *domain.URLItem
 	for rows.Next() {
 		item = &domain.URLItem{}
-		err = rows.Scan(&item.URL, &item.UserID, &item.IsDeleted)
+		var parameters, rules, variants []byte
+		err = rows.Scan(
+			&item.URL,
+			&item.UserID,
+			&item.IsDeleted,
+			&item.DeletedAt,
+			&item.ExpiresAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.LastAccessedAt,
+			&item.RedirectCode,
+			&item.Passthrough,
+			&parameters,
+			&rules,
+			&variants,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("DatabaseStore.Load, rows.Scan failed: %w", err)
 		}
 
+		if len(parameters) > 0 {
+			if err = json.Unmarshal(parameters, &item.CampaignParameters); err != nil {
+				return nil, fmt.Errorf("DatabaseStore.Load, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		if len(rules) > 0 {
+			if err = json.Unmarshal(rules, &item.Rules); err != nil {
+				return nil, fmt.Errorf("DatabaseStore.Load, json.Unmarshal failed: %w", err)
+			}
+		}
+
+		if len(variants) > 0 {
+			if err = json.Unmarshal(variants, &item.Variants); err != nil {
+				return nil, fmt.Errorf("DatabaseStore.Load, json.Unmarshal failed: %w", err)
+			}
+		}
+
 		// It should be only one item.
 	}
 
@@ -84,12 +325,13 @@ func (s *DatabaseStore) Load(ctx context.Context, key string) (*domain.URLItem,
 	return item, nil
 }
 
-func (s *DatabaseStore) LoadAllByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.KeyOriginalURLItem, error) {
-	rows, err := s.connection.QueryRows(
-		ctx,
-		"select url_key, original_url from urls where user_id = $1",
-		userID,
-	)
+func (s *DatabaseStore) LoadAllByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	query *domain.UserURLsQuery,
+) ([]*domain.KeyOriginalURLItem, error) {
+	statement, args := userURLsSQL(userID, query)
+	rows, err := s.connection.QueryRows(ctx, statement, args...)
 	if err != nil {
 		return nil, fmt.Errorf("DatabaseStore.LoadAllByUserID, connection.QueryRows failed: %w", err)
 	}
@@ -99,11 +341,27 @@ func (s *DatabaseStore) LoadAllByUserID(ctx context.Context, userID uuid.UUID) (
 	items := []*domain.KeyOriginalURLItem{}
 	for rows.Next() {
 		item := &domain.KeyOriginalURLItem{}
-		err = rows.Scan(&item.URLKey, &item.OriginalURL)
+		var tags []byte
+		err = rows.Scan(
+			&item.URLKey,
+			&item.OriginalURL,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.LastAccessedAt,
+			&item.Title,
+			&item.Notes,
+			&tags,
+		)
 		if err != nil {
 			return nil, fmt.Errorf("DatabaseStore.LoadAllByUserID, rows.Scan failed: %w", err)
 		}
 
+		if len(tags) > 0 {
+			if err = json.Unmarshal(tags, &item.Tags); err != nil {
+				return nil, fmt.Errorf("DatabaseStore.LoadAllByUserID, json.Unmarshal failed: %w", err)
+			}
+		}
+
 		items = append(items, item)
 	}
 
@@ -115,15 +373,16 @@ func (s *DatabaseStore) LoadAllByUserID(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
-func (s *DatabaseStore) Save(ctx context.Context, value string, userID uuid.UUID) (string, error) {
-	key, err := s.saveWithUniqueKey(ctx, value, func(ctx context.Context, key, value string) (bool, error) {
-		return s.saver(ctx, s.connection.Exec, key, value, userID)
+func (s *DatabaseStore) Save(ctx context.Context, request *domain.SaveRequest, userID uuid.UUID) (string, error) {
+	value := request.OriginalURL
+	key, err := s.saveWithAlias(ctx, request.Alias, value, func(ctx context.Context, key, value string) (bool, error) {
+		return s.saver(ctx, s.connection.Exec, key, value, request, userID)
 	})
 
 	if err != nil {
 		var duplicateURLError *DuplicateURLError
 		if errors.As(err, &duplicateURLError) {
-			existingKey, err := s.getExistingKeyByValue(ctx, value)
+			existingKey, err := s.getExistingKeyByValue(ctx, request.DedupURL(), userID)
 			if err != nil {
 				return "", fmt.Errorf("Save, getExistingKeyByValue error: %w", err)
 			}
@@ -137,26 +396,69 @@ func (s *DatabaseStore) Save(ctx context.Context, value string, userID uuid.UUID
 	return key, nil
 }
 
+// SaveBatch inserts all items with a few set-based statements: the whole batch is inserted at once,
+// and only the items whose keys turned out to be taken are inserted again with new keys.
+// Items that can't be saved are reported in their results and don't stop the others.
 func (s *DatabaseStore) SaveBatch(ctx context.Context, requestItems []*domain.BatchRequestItem, userID uuid.UUID) ([]*domain.BatchResponseItem, error) {
-	responseItems := make([]*domain.BatchResponseItem, 0, len(requestItems))
+	responseItems := make([]*domain.BatchResponseItem, len(requestItems))
+	pending := make([]int, 0, len(requestItems))
+	aliases := make(map[string]struct{})
+	for i, requestItem := range requestItems {
+		if len(requestItem.Alias) > 0 {
+			if err := s.validateAlias(requestItem.Alias); err != nil {
+				responseItems[i] = newInvalidBatchItem(requestItem.CorrelationID, err)
+				continue
+			}
+
+			// The same alias twice in a batch fails the same way as with separate saves.
+			if _, exists := aliases[requestItem.Alias]; exists {
+				responseItems[i] = newInvalidBatchItem(requestItem.CorrelationID, NewAliasConflictError(requestItem.Alias))
+				continue
+			}
+			aliases[requestItem.Alias] = struct{}{}
+		}
+
+		responseItems[i] = &domain.BatchResponseItem{CorrelationID: requestItem.CorrelationID}
+		pending = append(pending, i)
+	}
+
 	err := s.connection.DoInTx(
 		ctx,
 		func(tx *sql.Tx) error {
-			for _, requestItem := range requestItems {
-				key, err := s.saveWithUniqueKey(ctx, requestItem.OriginalURL, func(ctx context.Context, key, value string) (bool, error) {
-					executor := func(ctx context.Context, sql string, args ...any) error {
-						return s.connection.ExecTx(ctx, tx, sql, args...)
+			for len(pending) > 0 {
+				if s.dedupScope == config.DedupScopeNone {
+					return s.insertBatch(ctx, tx, requestItems, pending, responseItems, userID)
+				}
+
+				// Only the first item of a canonical URL is inserted, the rest get its key as an existing one.
+				// If the first item is invalid, the next one of the URL is inserted in the next round.
+				first := make([]int, 0, len(pending))
+				valueToIndex := make(map[string]int, len(pending))
+				for _, i := range pending {
+					if _, exists := valueToIndex[requestItems[i].DedupURL()]; !exists {
+						valueToIndex[requestItems[i].DedupURL()] = i
+						first = append(first, i)
 					}
-					return s.saver(ctx, executor, key, value, userID)
-				})
-				if err != nil {
-					return fmt.Errorf("DatabaseStore.SaveBatch, s.saveWithUniqueKey failed: %w", err)
 				}
 
-				responseItems = append(responseItems, &domain.BatchResponseItem{
-					CorrelationID: requestItem.CorrelationID,
-					Key:           key,
-				})
+				if err := s.insertBatch(ctx, tx, requestItems, first, responseItems, userID); err != nil {
+					return err
+				}
+
+				next := make([]int, 0)
+				for _, i := range pending {
+					firstItem := responseItems[valueToIndex[requestItems[i].DedupURL()]]
+					switch {
+					case firstItem == responseItems[i]:
+					case firstItem.Status == domain.BatchItemStatusInvalid:
+						next = append(next, i)
+					default:
+						responseItems[i].Key = firstItem.Key
+						responseItems[i].Status = domain.BatchItemStatusExisting
+					}
+				}
+
+				pending = next
 			}
 
 			return nil
@@ -173,7 +475,8 @@ func (s *DatabaseStore) SaveBatch(ctx context.Context, requestItems []*domain.Ba
 func (s *DatabaseStore) DeleteBatch(ctx context.Context, keys []string, userID uuid.UUID) error {
 	err := s.connection.Exec(
 		ctx,
-		"update urls set is_deleted = true where url_key = any($1) and user_id = $2",
+		"update urls set is_deleted = true, deleted_at = now(), updated_at = now() "+
+			"where url_key = any($1) and user_id = $2 and not is_deleted",
 		keys,
 		userID)
 	if err != nil {
@@ -183,60 +486,1226 @@ func (s *DatabaseStore) DeleteBatch(ctx context.Context, keys []string, userID u
 	return nil
 }
 
-func (s *DatabaseStore) GetStatistics(ctx context.Context) (int, int, error) {
-	var urlsCount, usersCount int
-	err := s.connection.QueryRow2(
+func (s *DatabaseStore) RestoreBatch(ctx context.Context, keys []string, userID uuid.UUID, deletedAfter time.Time) ([]string, error) {
+	rows, err := s.connection.QueryRows(
 		ctx,
-		&urlsCount,
-		&usersCount,
-		"select count(url_key), count(distinct user_id) from urls u",
+		"update urls set is_deleted = false, deleted_at = null, updated_at = now() "+
+			"where url_key = any($1) and user_id = $2 and is_deleted and deleted_at > $3 returning url_key",
+		keys,
+		userID,
+		deletedAfter,
 	)
-
 	if err != nil {
-		return 0, 0, fmt.Errorf("GetStatistics, connection.QueryRow2 failed: %w", err)
+		return nil, fmt.Errorf("DatabaseStore.RestoreBatch, connection.QueryRows failed: %w", err)
 	}
 
-	return urlsCount, usersCount, nil
+	defer rows.Close()
+
+	restored := []string{}
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("DatabaseStore.RestoreBatch, rows.Scan failed: %w", err)
+		}
+
+		restored = append(restored, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DatabaseStore.RestoreBatch, rows.Err failed: %w", err)
+	}
+
+	return restored, nil
 }
 
-func (s *DatabaseStore) saver(ctx context.Context, executor executorFunc, key, value string, userID uuid.UUID) (bool, error) {
-	err := executor(
+// UpdateURL moves the previous value to the versions table and stores the new one in the same transaction.
+// The row is locked, so concurrent updates of a key get consecutive versions.
+func (s *DatabaseStore) UpdateURL(ctx context.Context, request *domain.UpdateRequest, userID uuid.UUID) (int, error) {
+	var version int
+	err := s.connection.DoInTx(
 		ctx,
-		"insert into urls (url_key, original_url, user_id) values ($1, $2, $3)",
-		key, value, userID.String(),
+		func(tx *sql.Tx) error {
+			value, count, found, err := s.lockURLForUpdate(ctx, tx, request.Key, userID)
+			if err != nil || !found {
+				return err
+			}
+
+			version = count + 1
+			if value == request.OriginalURL {
+				return nil
+			}
+
+			err = s.connection.ExecTx(
+				ctx,
+				tx,
+				"insert into url_versions (url_key, version, original_url, replaced_at) values ($1, $2, $3, now())",
+				request.Key, version, value,
+			)
+			if err != nil {
+				return fmt.Errorf("DatabaseStore.UpdateURL, connection.ExecTx failed: %w", err)
+			}
+
+			err = s.connection.ExecTx(
+				ctx,
+				tx,
+				"update urls set original_url = $2, canonical_url = $3, updated_at = now(), search_vector = url_search_vector($2, "+
+					"(select title from url_metadata where url_key = $1), (select notes from url_metadata where url_key = $1)) "+
+					"where url_key = $1",
+				request.Key, request.OriginalURL, request.DedupURL(),
+			)
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation && slices.Contains(dedupIndexes, pgErr.ConstraintName) {
+					return NewDuplicateURLError("", request.OriginalURL)
+				}
+
+				return fmt.Errorf("DatabaseStore.UpdateURL, connection.ExecTx failed: %w", err)
+			}
+
+			version++
+			return nil
+		},
 	)
 
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			if pgErr.ConstraintName == "urls_original_url_unique" {
-				return false, NewDuplicateURLError("", value)
+		var duplicateURLError *DuplicateURLError
+		if errors.As(err, &duplicateURLError) {
+			existingKey, err := s.getExistingKeyByValue(ctx, request.DedupURL(), userID)
+			if err != nil {
+				return 0, fmt.Errorf("DatabaseStore.UpdateURL, getExistingKeyByValue failed: %w", err)
 			}
-			return true, nil
+			duplicateURLError.Key = existingKey
+			return 0, duplicateURLError
 		}
 
-		return false, fmt.Errorf("saver, db.Exec failed: %w", err)
+		return 0, fmt.Errorf("DatabaseStore.UpdateURL, connection.DoInTx failed: %w", err)
 	}
 
-	return false, nil
+	return version, nil
 }
 
-func (s *DatabaseStore) getExistingKeyByValue(ctx context.Context, value string) (string, error) {
-	var key string
+func (s *DatabaseStore) LoadVersions(ctx context.Context, key string) ([]*domain.URLVersion, error) {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select version, original_url, replaced_at from url_versions where url_key = $1 order by version desc",
+		key,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.LoadVersions, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	versions := []*domain.URLVersion{}
+	for rows.Next() {
+		version := &domain.URLVersion{}
+		if err = rows.Scan(&version.Version, &version.OriginalURL, &version.ReplacedAt); err != nil {
+			return nil, fmt.Errorf("DatabaseStore.LoadVersions, rows.Scan failed: %w", err)
+		}
+
+		versions = append(versions, version)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DatabaseStore.LoadVersions, rows.Err failed: %w", err)
+	}
+
+	return versions, nil
+}
+
+func (s *DatabaseStore) UpdateVariants(
+	ctx context.Context,
+	key string,
+	variants []*domain.URLVariant,
+	userID uuid.UUID,
+) (bool, error) {
+	variantsText, err := urlVariantsText(variants)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.UpdateVariants, urlVariantsText failed: %w", err)
+	}
+
+	var count int
+	err = s.connection.QueryRow(
+		ctx,
+		&count,
+		`with updated as (
+			update urls set url_variants = nullif($3::text, '')::jsonb, updated_at = now()
+			where url_key = $1 and user_id = $2 and not is_deleted and (expires_at is null or expires_at > now())
+			returning 1
+		)
+		select count(*) from updated`,
+		key,
+		userID.String(),
+		variantsText,
+	)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.UpdateVariants, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// PurgeDeleted deletes the rows, their clicks are removed by the foreign key cascade.
+func (s *DatabaseStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var count int
 	err := s.connection.QueryRow(
 		ctx,
-		&key,
-		"select url_key from urls where original_url = $1",
-		value,
+		&count,
+		"with purged as (delete from urls where is_deleted and deleted_at <= $1 returning 1) select count(*) from purged",
+		deletedBefore,
 	)
+	if err != nil {
+		return 0, fmt.Errorf("DatabaseStore.PurgeDeleted, connection.QueryRow failed: %w", err)
+	}
+
+	return count, nil
+}
 
+// DeleteExpired deletes the rows, their clicks are removed by the foreign key cascade.
+func (s *DatabaseStore) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int, error) {
+	var count int
+	err := s.connection.QueryRow(
+		ctx,
+		&count,
+		"with deleted as (delete from urls where expires_at <= $1 returning 1) select count(*) from deleted",
+		expiredBefore,
+	)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", fmt.Errorf("getExistingKeyByValue, row.Scan: failed to find existing key %v", key)
+		return 0, fmt.Errorf("DatabaseStore.DeleteExpired, connection.QueryRow failed: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *DatabaseStore) SaveCollection(ctx context.Context, collection *domain.Collection) error {
+	err := s.connection.Exec(
+		ctx,
+		"insert into collections (id, user_id, name, created_at) values ($1, $2, $3, $4)",
+		collection.ID.String(),
+		collection.UserID.String(),
+		collection.Name,
+		collection.CreatedAt,
+	)
+	if err != nil {
+		if isCollectionNameViolation(err) {
+			return ErrCollectionExists
 		}
 
-		return "", fmt.Errorf("getExistingKeyByValue, row.Scan failed: %w", err)
+		return fmt.Errorf("DatabaseStore.SaveCollection, connection.Exec failed: %w", err)
 	}
 
-	return key, nil
+	return nil
+}
+
+func (s *DatabaseStore) LoadCollections(ctx context.Context, userID uuid.UUID) ([]*domain.Collection, error) {
+	collections, err := s.loadCollections(ctx, " order by c.name", userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.LoadCollections, loadCollections failed: %w", err)
+	}
+
+	return collections, nil
+}
+
+func (s *DatabaseStore) LoadCollection(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Collection, error) {
+	collections, err := s.loadCollections(ctx, " and c.id = $2", userID.String(), id.String())
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.LoadCollection, loadCollections failed: %w", err)
+	}
+
+	if len(collections) == 0 {
+		return nil, nil
+	}
+
+	return collections[0], nil
+}
+
+func (s *DatabaseStore) RenameCollection(ctx context.Context, id uuid.UUID, name string, userID uuid.UUID) (bool, error) {
+	var count int
+	err := s.connection.QueryRow(
+		ctx,
+		&count,
+		"with renamed as (update collections set name = $3 where id = $1 and user_id = $2 returning 1) "+
+			"select count(*) from renamed",
+		id.String(),
+		userID.String(),
+		name,
+	)
+	if err != nil {
+		if isCollectionNameViolation(err) {
+			return false, ErrCollectionExists
+		}
+
+		return false, fmt.Errorf("DatabaseStore.RenameCollection, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// DeleteCollection deletes the row, the links to its URLs are removed by the foreign key cascade.
+func (s *DatabaseStore) DeleteCollection(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	var count int
+	err := s.connection.QueryRow(
+		ctx,
+		&count,
+		"with deleted as (delete from collections where id = $1 and user_id = $2 returning 1) select count(*) from deleted",
+		id.String(),
+		userID.String(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.DeleteCollection, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// AddCollectionURLs checks the collection owner and inserts the links in one statement.
+func (s *DatabaseStore) AddCollectionURLs(ctx context.Context, id uuid.UUID, keys []string, userID uuid.UUID) (bool, error) {
+	var count int
+	err := s.connection.QueryRow(
+		ctx,
+		&count,
+		"with target as (select id from collections where id = $1 and user_id = $2), "+
+			"added as (insert into collection_urls (collection_id, url_key) "+
+			"select t.id, u.url_key from target t join urls u on u.url_key = any($3) and u.user_id = $2 "+
+			"on conflict do nothing) "+
+			"select count(*) from target",
+		id.String(),
+		userID.String(),
+		keys,
+	)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.AddCollectionURLs, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (s *DatabaseStore) RemoveCollectionURLs(ctx context.Context, id uuid.UUID, keys []string, userID uuid.UUID) (bool, error) {
+	var count int
+	err := s.connection.QueryRow(
+		ctx,
+		&count,
+		"with target as (select id from collections where id = $1 and user_id = $2), "+
+			"removed as (delete from collection_urls where collection_id in (select id from target) and url_key = any($3)) "+
+			"select count(*) from target",
+		id.String(),
+		userID.String(),
+		keys,
+	)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.RemoveCollectionURLs, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (s *DatabaseStore) SaveCampaign(ctx context.Context, campaign *domain.Campaign) error {
+	parameters, err := marshalCampaignParameters(campaign.Parameters)
+	if err != nil {
+		return fmt.Errorf("DatabaseStore.SaveCampaign, marshalCampaignParameters failed: %w", err)
+	}
+
+	err = s.connection.Exec(
+		ctx,
+		"insert into campaigns (id, user_id, name, parameters, created_at) values ($1, $2, $3, $4, $5)",
+		campaign.ID.String(),
+		campaign.UserID.String(),
+		campaign.Name,
+		parameters,
+		campaign.CreatedAt,
+	)
+	if err != nil {
+		if isCampaignNameViolation(err) {
+			return ErrCampaignExists
+		}
+
+		return fmt.Errorf("DatabaseStore.SaveCampaign, connection.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DatabaseStore) LoadCampaigns(ctx context.Context, userID uuid.UUID) ([]*domain.Campaign, error) {
+	campaigns, err := s.loadCampaigns(ctx, " order by name", userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.LoadCampaigns, loadCampaigns failed: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+func (s *DatabaseStore) LoadCampaign(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Campaign, error) {
+	campaigns, err := s.loadCampaigns(ctx, " and id = $2", userID.String(), id.String())
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.LoadCampaign, loadCampaigns failed: %w", err)
+	}
+
+	if len(campaigns) == 0 {
+		return nil, nil
+	}
+
+	return campaigns[0], nil
+}
+
+func (s *DatabaseStore) UpdateCampaign(ctx context.Context, campaign *domain.Campaign) (bool, error) {
+	parameters, err := marshalCampaignParameters(campaign.Parameters)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.UpdateCampaign, marshalCampaignParameters failed: %w", err)
+	}
+
+	var count int
+	err = s.connection.QueryRow(
+		ctx,
+		&count,
+		"with updated as (update campaigns set name = $3, parameters = $4 where id = $1 and user_id = $2 returning 1) "+
+			"select count(*) from updated",
+		campaign.ID.String(),
+		campaign.UserID.String(),
+		campaign.Name,
+		parameters,
+	)
+	if err != nil {
+		if isCampaignNameViolation(err) {
+			return false, ErrCampaignExists
+		}
+
+		return false, fmt.Errorf("DatabaseStore.UpdateCampaign, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// DeleteCampaign deletes the row, its URLs are unlinked by the foreign key.
+func (s *DatabaseStore) DeleteCampaign(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	var count int
+	err := s.connection.QueryRow(
+		ctx,
+		&count,
+		"with deleted as (delete from campaigns where id = $1 and user_id = $2 returning 1) select count(*) from deleted",
+		id.String(),
+		userID.String(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("DatabaseStore.DeleteCampaign, connection.QueryRow failed: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (s *DatabaseStore) SaveClicks(ctx context.Context, clicks []*domain.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(clicks))
+	timestamps := make([]time.Time, 0, len(clicks))
+	referrers := make([]string, 0, len(clicks))
+	userAgents := make([]string, 0, len(clicks))
+	ipHashes := make([]string, 0, len(clicks))
+	variants := make([]string, 0, len(clicks))
+	for _, click := range clicks {
+		keys = append(keys, click.Key)
+		timestamps = append(timestamps, click.Timestamp)
+		referrers = append(referrers, click.Referrer)
+		userAgents = append(userAgents, click.UserAgent)
+		ipHashes = append(ipHashes, click.IPHash)
+		variants = append(variants, click.Variant)
+	}
+
+	// Clicks of the keys removed after the redirect are skipped.
+	// The last access of the keys is moved forward in the same statement.
+	err := s.connection.Exec(
+		ctx,
+		`with inserted as (
+			insert into clicks (url_key, clicked_at, referrer, user_agent, ip_hash, variant)
+			select c.url_key, c.clicked_at, c.referrer, c.user_agent, c.ip_hash, c.variant
+			from unnest($1::text[], $2::timestamptz[], $3::text[], $4::text[], $5::text[], $6::text[])
+				as c(url_key, clicked_at, referrer, user_agent, ip_hash, variant)
+			where exists (select 1 from urls u where u.url_key = c.url_key)
+			returning url_key, clicked_at
+		)
+		update urls u set last_accessed_at = greatest(u.last_accessed_at, i.clicked_at)
+		from (select url_key, max(clicked_at) as clicked_at from inserted group by url_key) i
+		where u.url_key = i.url_key`,
+		keys, timestamps, referrers, userAgents, ipHashes, variants,
+	)
+	if err != nil {
+		return fmt.Errorf("DatabaseStore.SaveClicks, connection.Exec failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *DatabaseStore) GetClickStatistics(
+	ctx context.Context,
+	request *domain.ClickStatisticsRequest,
+) (*domain.ClickStatistics, error) {
+	statistics := &domain.ClickStatistics{}
+	err := s.connection.QueryRow2(
+		ctx,
+		&statistics.TotalClicks,
+		&statistics.UniqueVisitors,
+		`select count(*), count(distinct nullif(ip_hash, ''))
+		from clicks
+		where url_key = $1 and clicked_at >= $2 and clicked_at < $3`,
+		request.Key, request.From, request.To,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.GetClickStatistics, connection.QueryRow2 failed: %w", err)
+	}
+
+	statistics.Buckets, err = s.loadClickBuckets(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.GetClickStatistics, loadClickBuckets failed: %w", err)
+	}
+
+	statistics.TopReferrers, err = s.loadTopClickValues(ctx, "referrer", request, request.TopCount)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.GetClickStatistics, loadTopClickValues failed: %w", err)
+	}
+
+	statistics.TopUserAgents, err = s.loadTopClickValues(ctx, "user_agent", request, request.TopCount)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.GetClickStatistics, loadTopClickValues failed: %w", err)
+	}
+
+	statistics.Variants, err = s.loadTopClickValues(ctx, "variant", request, 0)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.GetClickStatistics, loadTopClickValues failed: %w", err)
+	}
+
+	return statistics, nil
+}
+
+func (s *DatabaseStore) GetStatistics(ctx context.Context) (int, int, error) {
+	var urlsCount, usersCount int
+	err := s.connection.QueryRow2(
+		ctx,
+		&urlsCount,
+		&usersCount,
+		"select count(url_key), count(distinct user_id) from urls u",
+	)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("GetStatistics, connection.QueryRow2 failed: %w", err)
+	}
+
+	return urlsCount, usersCount, nil
+}
+
+// saver inserts the URL with its metadata in one statement, so a URL is never left without its metadata.
+func (s *DatabaseStore) saver(
+	ctx context.Context,
+	executor executorFunc,
+	key, value string,
+	request *domain.SaveRequest,
+	userID uuid.UUID,
+) (bool, error) {
+	rules, err := redirectRulesText(request.Rules)
+	if err != nil {
+		return false, fmt.Errorf("saver, redirectRulesText failed: %w", err)
+	}
+
+	variants, err := urlVariantsText(request.Variants)
+	if err != nil {
+		return false, fmt.Errorf("saver, urlVariantsText failed: %w", err)
+	}
+
+	err = executor(
+		ctx,
+		`with inserted as (
+			insert into urls (url_key, original_url, canonical_url, user_id, expires_at, dedup_scope, redirect_code, passthrough,
+				campaign_id, redirect_rules, url_variants, search_vector)
+			values ($1, $2, $3, $4, $5, $6, $10, $11, nullif($12::text, '')::uuid, nullif($13::text, '')::jsonb,
+				nullif($14::text, '')::jsonb, url_search_vector($2, $7::text, $8::text))
+			returning url_key
+		), metadata as (
+			insert into url_metadata (url_key, title, notes)
+			select url_key, $7::text, $8::text from inserted where $7::text <> '' or $8::text <> ''
+		)
+		insert into url_tags (url_key, tag) select i.url_key, t.tag from inserted i, unnest($9::text[]) as t(tag)`,
+		key, value, request.DedupURL(), userID.String(), request.ExpiresAt, s.dedupScope,
+		request.Title, request.Notes, request.Tags, request.RedirectCode, request.Passthrough, campaignIDText(request.CampaignID),
+		rules, variants,
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			if slices.Contains(dedupIndexes, pgErr.ConstraintName) {
+				return false, NewDuplicateURLError("", value)
+			}
+			return true, nil
+		}
+
+		return false, fmt.Errorf("saver, db.Exec failed: %w", err)
+	}
+
+	return false, nil
+}
+
+// Pending URLs canonicalized at once by BackfillCanonicalURLs.
+const canonicalBackfillBlockSize = 1000
+
+// BackfillCanonicalURLs canonicalizes the URLs the canonical URL migration copied the original URL for,
+// a block at a time. Every pending URL is processed once, even when its canonical form is taken.
+func (s *DatabaseStore) BackfillCanonicalURLs(ctx context.Context, canonicalize func(string) string) (int, error) {
+	count := 0
+	for {
+		keys, values, err := s.loadCanonicalPendingURLs(ctx)
+		if err != nil {
+			return count, fmt.Errorf("DatabaseStore.BackfillCanonicalURLs, loadCanonicalPendingURLs failed: %w", err)
+		}
+
+		if len(keys) == 0 {
+			return count, nil
+		}
+
+		for i, key := range keys {
+			canonicalURL := canonicalize(values[i])
+			err = s.connection.Exec(
+				ctx,
+				"update urls set canonical_url = $2, canonical_pending = false where url_key = $1",
+				key,
+				canonicalURL,
+			)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation && slices.Contains(dedupIndexes, pgErr.ConstraintName) {
+				err = s.connection.Exec(ctx, "update urls set canonical_pending = false where url_key = $1", key)
+				canonicalURL = values[i]
+			}
+
+			if err != nil {
+				return count, fmt.Errorf("DatabaseStore.BackfillCanonicalURLs, connection.Exec failed: %w", err)
+			}
+
+			if canonicalURL != values[i] {
+				count++
+			}
+		}
+	}
+}
+
+func (s *DatabaseStore) loadCanonicalPendingURLs(ctx context.Context) ([]string, []string, error) {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select url_key, original_url from urls where canonical_pending order by url_key limit $1",
+		canonicalBackfillBlockSize,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DatabaseStore.loadCanonicalPendingURLs, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	keys := []string{}
+	values := []string{}
+	for rows.Next() {
+		var key, value string
+		if err = rows.Scan(&key, &value); err != nil {
+			return nil, nil, fmt.Errorf("DatabaseStore.loadCanonicalPendingURLs, rows.Scan failed: %w", err)
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("DatabaseStore.loadCanonicalPendingURLs, rows.Err failed: %w", err)
+	}
+
+	return keys, values, nil
+}
+
+// insertBatch saves the items with the given indexes, their canonical URLs must be distinct. Keys are regenerated
+// the same way saveWithUniqueKey does it, but for all the rejected items of a round at once.
+func (s *DatabaseStore) insertBatch(
+	ctx context.Context,
+	tx *sql.Tx,
+	requestItems []*domain.BatchRequestItem,
+	pending []int,
+	responseItems []*domain.BatchResponseItem,
+	userID uuid.UUID,
+) error {
+	keys := make(map[int]string, len(pending))
+	length := s.configuration.KeyLength
+	iteration := 0
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if length > s.configuration.KeyMaxLength {
+			return errors.New("failed to generate unique key")
+		}
+
+		// Reserved keys are not inserted, they go to the next round right away.
+		candidates := make([]int, 0, len(pending))
+		rejected := make([]int, 0)
+		for _, i := range pending {
+			keys[i] = requestItems[i].Alias
+			if len(keys[i]) == 0 {
+				key, err := s.generator.Generate(ctx, requestItems[i].OriginalURL, length, attempt)
+				if err != nil {
+					return fmt.Errorf("DatabaseStore.insertBatch, generator.Generate failed: %w", err)
+				}
+
+				keys[i] = key
+				if isReservedAlias(key) {
+					rejected = append(rejected, i)
+					continue
+				}
+			}
+
+			candidates = append(candidates, i)
+		}
+
+		inserted, err := s.insertURLs(ctx, tx, requestItems, candidates, keys, userID)
+		if err != nil {
+			return fmt.Errorf("DatabaseStore.insertBatch, insertURLs failed: %w", err)
+		}
+
+		for _, i := range candidates {
+			if value, ok := inserted[keys[i]]; ok && value == requestItems[i].OriginalURL {
+				responseItems[i].Key = keys[i]
+				responseItems[i].Status = domain.BatchItemStatusCreated
+			} else {
+				rejected = append(rejected, i)
+			}
+		}
+
+		if len(rejected) == 0 {
+			return nil
+		}
+
+		// An item is rejected either because its URL exists or because its key is taken.
+		rejectedValues := make([]string, 0, len(rejected))
+		for _, i := range rejected {
+			rejectedValues = append(rejectedValues, requestItems[i].DedupURL())
+		}
+
+		existingKeys, err := s.loadKeysByValues(ctx, tx, rejectedValues, userID)
+		if err != nil {
+			return fmt.Errorf("DatabaseStore.insertBatch, loadKeysByValues failed: %w", err)
+		}
+
+		pending = pending[:0]
+		for _, i := range rejected {
+			if existingKey, ok := existingKeys[requestItems[i].DedupURL()]; ok {
+				responseItems[i].Key = existingKey
+				responseItems[i].Status = domain.BatchItemStatusExisting
+			} else if len(requestItems[i].Alias) > 0 {
+				*responseItems[i] = *newInvalidBatchItem(requestItems[i].CorrelationID, NewAliasConflictError(requestItems[i].Alias))
+			} else {
+				pending = append(pending, i)
+			}
+		}
+
+		iteration++
+		if iteration >= s.configuration.KeyMaxIterations {
+			length = s.nextKeyLength(length)
+			iteration = 0
+		}
+	}
+
+	return nil
+}
+
+// loadCollections loads the collections of the user, the condition and the order go after the user filter.
+// Only the URLs of the collection owner are counted, the owner of a URL can change.
+func (s *DatabaseStore) loadCollections(ctx context.Context, condition string, args ...any) ([]*domain.Collection, error) {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select c.id, c.user_id, c.name, c.created_at, "+
+			"(select count(*) from collection_urls cu join urls u on u.url_key = cu.url_key "+
+			"where cu.collection_id = c.id and u.user_id = c.user_id) "+
+			"from collections c where c.user_id = $1"+condition,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadCollections, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	collections := []*domain.Collection{}
+	for rows.Next() {
+		collection := &domain.Collection{}
+		err = rows.Scan(&collection.ID, &collection.UserID, &collection.Name, &collection.CreatedAt, &collection.URLCount)
+		if err != nil {
+			return nil, fmt.Errorf("DatabaseStore.loadCollections, rows.Scan failed: %w", err)
+		}
+
+		collections = append(collections, collection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadCollections, rows.Err failed: %w", err)
+	}
+
+	return collections, nil
+}
+
+// loadCampaigns loads the campaigns of the user, the condition and the order go after the user filter.
+func (s *DatabaseStore) loadCampaigns(ctx context.Context, condition string, args ...any) ([]*domain.Campaign, error) {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		"select id, user_id, name, created_at, parameters from campaigns where user_id = $1"+condition,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadCampaigns, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	campaigns := []*domain.Campaign{}
+	for rows.Next() {
+		campaign := &domain.Campaign{}
+		var parameters []byte
+		err = rows.Scan(&campaign.ID, &campaign.UserID, &campaign.Name, &campaign.CreatedAt, &parameters)
+		if err != nil {
+			return nil, fmt.Errorf("DatabaseStore.loadCampaigns, rows.Scan failed: %w", err)
+		}
+
+		if err = json.Unmarshal(parameters, &campaign.Parameters); err != nil {
+			return nil, fmt.Errorf("DatabaseStore.loadCampaigns, json.Unmarshal failed: %w", err)
+		}
+
+		campaigns = append(campaigns, campaign)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadCampaigns, rows.Err failed: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+func isCampaignNameViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation && pgErr.ConstraintName == campaignNameConstraint
+}
+
+func isCollectionNameViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation && pgErr.ConstraintName == collectionNameConstraint
+}
+
+// userURLsSQL builds the query of a page of the user's URLs. Pages are read with keyset pagination:
+// the page starts right after the cursor row, so the cost doesn't depend on the page number.
+func userURLsSQL(userID uuid.UUID, query *domain.UserURLsQuery) (string, []any) {
+	args := []any{userID.String()}
+	var builder strings.Builder
+	builder.WriteString("select url_key, original_url, created_at, updated_at, last_accessed_at, " +
+		"coalesce(m.title, ''), coalesce(m.notes, ''), " +
+		"(select json_agg(t.tag order by t.tag) from url_tags t where t.url_key = urls.url_key) " +
+		"from urls left join url_metadata m using (url_key) where user_id = $1")
+
+	switch query.Status {
+	case domain.UserURLsStatusActive:
+		builder.WriteString(" and not is_deleted and (expires_at is null or expires_at > now())")
+	case domain.UserURLsStatusDeleted:
+		builder.WriteString(" and is_deleted")
+	}
+
+	if len(query.Tag) > 0 {
+		args = append(args, query.Tag)
+		fmt.Fprintf(&builder, " and exists (select 1 from url_tags t where t.url_key = urls.url_key and t.tag = $%v)", len(args))
+	}
+
+	if query.Collection != uuid.Nil {
+		// The collection of another user matches nothing.
+		args = append(args, query.Collection.String())
+		fmt.Fprintf(&builder, " and exists (select 1 from collection_urls cu join collections c on c.id = cu.collection_id "+
+			"where cu.url_key = urls.url_key and c.id = $%v and c.user_id = $1)", len(args))
+	}
+
+	if words := searchWords(query.Search); len(words) > 0 {
+		// The words are already split the way url_search_vector splits the text, so every one of them must match.
+		args = append(args, strings.Join(words, " "))
+		fmt.Fprintf(&builder, " and search_vector @@ plainto_tsquery('simple', $%v)", len(args))
+	}
+
+	comparison, direction := ">", "asc"
+	if query.Descending {
+		comparison, direction = "<", "desc"
+	}
+
+	if query.Sort == domain.UserURLsSortKey {
+		if query.After != nil {
+			args = append(args, query.After.URLKey)
+			fmt.Fprintf(&builder, " and url_key %v $%v", comparison, len(args))
+		}
+		fmt.Fprintf(&builder, " order by url_key %v", direction)
+	} else {
+		if query.After != nil {
+			args = append(args, query.After.CreatedAt, query.After.URLKey)
+			fmt.Fprintf(&builder, " and (created_at, url_key) %v ($%v, $%v)", comparison, len(args)-1, len(args))
+		}
+		fmt.Fprintf(&builder, " order by created_at %v, url_key %v", direction, direction)
+	}
+
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		fmt.Fprintf(&builder, " limit $%v", len(args))
+	}
+
+	return builder.String(), args
+}
+
+// insertURLs inserts the given items in one statement, conflicting rows are skipped.
+// Returns the inserted keys with their values.
+func (s *DatabaseStore) insertURLs(
+	ctx context.Context,
+	tx *sql.Tx,
+	requestItems []*domain.BatchRequestItem,
+	indexes []int,
+	keys map[int]string,
+	userID uuid.UUID,
+) (map[string]string, error) {
+	if len(indexes) == 0 {
+		return map[string]string{}, nil
+	}
+
+	itemKeys := make([]string, 0, len(indexes))
+	values := make([]string, 0, len(indexes))
+	canonicalValues := make([]string, 0, len(indexes))
+	expiresAt := make([]*time.Time, 0, len(indexes))
+	titles := make([]string, 0, len(indexes))
+	notes := make([]string, 0, len(indexes))
+	redirectCodes := make([]int, 0, len(indexes))
+	passthrough := make([]bool, 0, len(indexes))
+	campaignIDs := make([]string, 0, len(indexes))
+	rules := make([]string, 0, len(indexes))
+	variants := make([]string, 0, len(indexes))
+	// Tags are passed as pairs of flat arrays, one pair per tag.
+	tagKeys := []string{}
+	tags := []string{}
+	for _, i := range indexes {
+		itemKeys = append(itemKeys, keys[i])
+		values = append(values, requestItems[i].OriginalURL)
+		canonicalValues = append(canonicalValues, requestItems[i].DedupURL())
+		expiresAt = append(expiresAt, requestItems[i].ExpiresAt)
+		titles = append(titles, requestItems[i].Title)
+		notes = append(notes, requestItems[i].Notes)
+		redirectCodes = append(redirectCodes, requestItems[i].RedirectCode)
+		passthrough = append(passthrough, requestItems[i].Passthrough)
+		campaignIDs = append(campaignIDs, campaignIDText(requestItems[i].CampaignID))
+		itemRules, err := redirectRulesText(requestItems[i].Rules)
+		if err != nil {
+			return nil, fmt.Errorf("DatabaseStore.insertURLs, redirectRulesText failed: %w", err)
+		}
+		rules = append(rules, itemRules)
+		itemVariants, err := urlVariantsText(requestItems[i].Variants)
+		if err != nil {
+			return nil, fmt.Errorf("DatabaseStore.insertURLs, urlVariantsText failed: %w", err)
+		}
+		variants = append(variants, itemVariants)
+		for _, tag := range requestItems[i].Tags {
+			tagKeys = append(tagKeys, keys[i])
+			tags = append(tags, tag)
+		}
+	}
+
+	// Metadata is inserted only for the rows which are inserted themselves.
+	rows, err := s.connection.QueryRowsTx(
+		ctx,
+		tx,
+		`with source as (
+			select * from unnest($1::text[], $2::text[], $3::text[], $4::timestamptz[], $7::text[], $8::text[], $11::int[], $12::bool[],
+				$13::text[], $14::text[], $15::text[])
+				as u(url_key, original_url, canonical_url, expires_at, title, notes, redirect_code, passthrough, campaign_id,
+					redirect_rules, url_variants)
+		), inserted as (
+			insert into urls (url_key, original_url, canonical_url, user_id, expires_at, dedup_scope, redirect_code, passthrough,
+				campaign_id, redirect_rules, url_variants, search_vector)
+			select s.url_key, s.original_url, s.canonical_url, $5::uuid, s.expires_at, $6, s.redirect_code, s.passthrough,
+				nullif(s.campaign_id, '')::uuid, nullif(s.redirect_rules, '')::jsonb, nullif(s.url_variants, '')::jsonb,
+				url_search_vector(s.original_url, s.title, s.notes)
+			from source s
+			on conflict do nothing
+			returning url_key, original_url
+		), metadata as (
+			insert into url_metadata (url_key, title, notes)
+			select s.url_key, s.title, s.notes from source s join inserted i using (url_key)
+			where s.title <> '' or s.notes <> ''
+		), tags as (
+			insert into url_tags (url_key, tag)
+			select t.url_key, t.tag from unnest($9::text[], $10::text[]) as t(url_key, tag) join inserted i using (url_key)
+		)
+		select url_key, original_url from inserted`,
+		itemKeys, values, canonicalValues, expiresAt, userID.String(), s.dedupScope, titles, notes, tagKeys, tags,
+		redirectCodes, passthrough, campaignIDs, rules, variants,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.insertURLs, connection.QueryRowsTx failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	inserted := make(map[string]string, len(indexes))
+	for rows.Next() {
+		var key, value string
+		if err = rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("DatabaseStore.insertURLs, rows.Scan failed: %w", err)
+		}
+
+		inserted[key] = value
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DatabaseStore.insertURLs, rows.Err failed: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// loadKeysByValues returns keys of the canonical URLs that are already stored in the deduplication scope.
+func (s *DatabaseStore) loadKeysByValues(ctx context.Context, tx *sql.Tx, values []string, userID uuid.UUID) (map[string]string, error) {
+	var rows *sql.Rows
+	var err error
+	switch s.dedupScope {
+	case config.DedupScopeNone:
+		return map[string]string{}, nil
+	case config.DedupScopeUser:
+		rows, err = s.connection.QueryRowsTx(
+			ctx,
+			tx,
+			"select url_key, canonical_url from urls where canonical_url = any($1) and user_id = $2 and dedup_scope = 'user'",
+			values,
+			userID.String(),
+		)
+	default:
+		rows, err = s.connection.QueryRowsTx(
+			ctx,
+			tx,
+			"select url_key, canonical_url from urls where canonical_url = any($1) and dedup_scope = 'global'",
+			values,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadKeysByValues, connection.QueryRowsTx failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	keys := make(map[string]string, len(values))
+	for rows.Next() {
+		var key, value string
+		if err = rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("DatabaseStore.loadKeysByValues, rows.Scan failed: %w", err)
+		}
+
+		keys[value] = key
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadKeysByValues, rows.Err failed: %w", err)
+	}
+
+	return keys, nil
+}
+
+// lockURLForUpdate locks the live row of the user's key and returns its value with the number of the previous versions.
+func (s *DatabaseStore) lockURLForUpdate(ctx context.Context, tx *sql.Tx, key string, userID uuid.UUID) (string, int, bool, error) {
+	rows, err := s.connection.QueryRowsTx(
+		ctx,
+		tx,
+		`select u.original_url, (select count(*) from url_versions v where v.url_key = u.url_key)
+		from urls u
+		where u.url_key = $1 and u.user_id = $2 and not u.is_deleted and (u.expires_at is null or u.expires_at > now())
+		for update of u`,
+		key,
+		userID.String(),
+	)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("DatabaseStore.lockURLForUpdate, connection.QueryRowsTx failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	var value string
+	var count int
+	found := false
+	for rows.Next() {
+		if err = rows.Scan(&value, &count); err != nil {
+			return "", 0, false, fmt.Errorf("DatabaseStore.lockURLForUpdate, rows.Scan failed: %w", err)
+		}
+
+		found = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", 0, false, fmt.Errorf("DatabaseStore.lockURLForUpdate, rows.Err failed: %w", err)
+	}
+
+	return value, count, found, nil
+}
+
+// leaseKeySequence moves the shared block counter forward in a single statement,
+// so concurrent instances never get overlapping blocks.
+func (s *DatabaseStore) leaseKeySequence(ctx context.Context, size uint64) (uint64, error) {
+	var start int64
+	err := s.connection.QueryRow(
+		ctx,
+		&start,
+		"update key_blocks set next_value = next_value + $1 where id = 1 returning next_value - $1",
+		int64(size),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("DatabaseStore.leaseKeySequence, connection.QueryRow failed: %w", err)
+	}
+
+	return uint64(start), nil
+}
+
+func (s *DatabaseStore) getExistingKeyByValue(ctx context.Context, value string, userID uuid.UUID) (string, error) {
+	var key string
+	var err error
+	if s.dedupScope == config.DedupScopeUser {
+		err = s.connection.QueryRow(
+			ctx,
+			&key,
+			"select url_key from urls where canonical_url = $1 and user_id = $2 and dedup_scope = 'user'",
+			value,
+			userID.String(),
+		)
+	} else {
+		err = s.connection.QueryRow(
+			ctx,
+			&key,
+			"select url_key from urls where canonical_url = $1 and dedup_scope = 'global'",
+			value,
+		)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("getExistingKeyByValue, row.Scan: failed to find existing key %v", key)
+		}
+
+		return "", fmt.Errorf("getExistingKeyByValue, row.Scan failed: %w", err)
+	}
+
+	return key, nil
+}
+
+func (s *DatabaseStore) loadClickBuckets(
+	ctx context.Context,
+	request *domain.ClickStatisticsRequest,
+) ([]*domain.ClickBucketCount, error) {
+	rows, err := s.connection.QueryRows(
+		ctx,
+		`select date_trunc($4, clicked_at at time zone 'UTC') as bucket, count(*)
+		from clicks
+		where url_key = $1 and clicked_at >= $2 and clicked_at < $3
+		group by bucket
+		order by bucket`,
+		request.Key, request.From, request.To, string(request.Bucket),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadClickBuckets, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	buckets := []*domain.ClickBucketCount{}
+	for rows.Next() {
+		bucket := &domain.ClickBucketCount{}
+		err = rows.Scan(&bucket.Start, &bucket.Count)
+		if err != nil {
+			return nil, fmt.Errorf("DatabaseStore.loadClickBuckets, rows.Scan failed: %w", err)
+		}
+
+		bucket.Start = bucket.Start.UTC()
+		buckets = append(buckets, bucket)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadClickBuckets, rows.Err failed: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// loadTopClickValues returns the most frequent values of the column, all of them for zero limit.
+func (s *DatabaseStore) loadTopClickValues(
+	ctx context.Context,
+	column string,
+	request *domain.ClickStatisticsRequest,
+	limit int,
+) ([]*domain.ClickValueCount, error) {
+	// The column name comes from the code, not from the request.
+	rows, err := s.connection.QueryRows(
+		ctx,
+		fmt.Sprintf(
+			`select %[1]s, count(*) as clicks_count
+			from clicks
+			where url_key = $1 and clicked_at >= $2 and clicked_at < $3 and %[1]s <> ''
+			group by %[1]s
+			order by clicks_count desc, %[1]s
+			limit nullif($4::int, 0)`,
+			column),
+		request.Key, request.From, request.To, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadTopClickValues, connection.QueryRows failed: %w", err)
+	}
+
+	defer rows.Close()
+
+	values := []*domain.ClickValueCount{}
+	for rows.Next() {
+		value := &domain.ClickValueCount{}
+		err = rows.Scan(&value.Value, &value.Count)
+		if err != nil {
+			return nil, fmt.Errorf("DatabaseStore.loadTopClickValues, rows.Scan failed: %w", err)
+		}
+
+		values = append(values, value)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("DatabaseStore.loadTopClickValues, rows.Err failed: %w", err)
+	}
+
+	return values, nil
+}
+
+// campaignIDText returns the campaign ID as a query argument, empty for no campaign.
+func campaignIDText(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+// redirectRulesText returns the rules as a JSON array query argument, empty for no rules.
+func redirectRulesText(rules []*domain.RedirectRule) (string, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(rules)
+	return string(data), err
+}
+
+// urlVariantsText returns the variants as a JSON array query argument, empty for no variants.
+func urlVariantsText(variants []*domain.URLVariant) (string, error) {
+	if len(variants) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(variants)
+	return string(data), err
+}
+
+// marshalCampaignParameters returns the parameters as a JSON object, an empty one when there are none.
+func marshalCampaignParameters(parameters map[string]string) (string, error) {
+	if parameters == nil {
+		parameters = map[string]string{}
+	}
+
+	data, err := json.Marshal(parameters)
+	return string(data), err
 }
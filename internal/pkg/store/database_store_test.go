@@ -1,13 +1,19 @@
 package store
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/domain"
 	"github.com/aleffnull/shortener/internal/pkg/mocks"
 	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -81,6 +87,52 @@ func TestDatabaseStore_CheckAvailability(t *testing.T) {
 	}
 }
 
+func TestDatabaseStore_Compact(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act-assert.
+	require.NoError(t, store.Compact(context.Background()))
+}
+
+func TestDatabaseStore_Export(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(
+			gomock.Any(),
+			"select url_key, original_url, canonical_url, user_id, is_deleted, deleted_at, expires_at, "+
+				"created_at, updated_at, last_accessed_at, "+
+				"(select json_agg(json_build_object('version', v.version, 'original_url', v.original_url, 'replaced_at', v.replaced_at) "+
+				"order by v.version) from url_versions v where v.url_key = urls.url_key), "+
+				"coalesce(m.title, ''), coalesce(m.notes, ''), "+
+				"(select json_agg(t.tag order by t.tag) from url_tags t where t.url_key = urls.url_key), redirect_code, passthrough, "+
+				"campaign_id, redirect_rules, url_variants, dedup_scope "+
+				"from urls left join url_metadata m using (url_key) order by url_key",
+		).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	err := store.Export(context.Background(), &bytes.Buffer{})
+
+	// Assert.
+	require.ErrorIs(t, err, assert.AnError)
+}
+
 func TestDatabaseStore_Load(t *testing.T) {
 	t.Parallel()
 
@@ -105,7 +157,10 @@ func TestDatabaseStore_Load(t *testing.T) {
 				mock.Connection.EXPECT().
 					QueryRows(
 						gomock.Any(),
-						"select original_url, user_id, is_deleted from urls where url_key = $1",
+						"select original_url, urls.user_id, is_deleted, deleted_at, expires_at, urls.created_at, updated_at, last_accessed_at, "+
+							"redirect_code, passthrough, c.parameters, redirect_rules, url_variants "+
+							"from urls left join campaigns c on c.id = urls.campaign_id "+
+							"where url_key = $1",
 						args.key,
 					).
 					Return(nil, assert.AnError)
@@ -140,6 +195,365 @@ func TestDatabaseStore_Load(t *testing.T) {
 	}
 }
 
+func TestDatabaseStore_LoadAllByUserID(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(
+			gomock.Any(),
+			userURLsSelect+"where user_id = $1 order by created_at asc, url_key asc",
+			userID.String(),
+		).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	items, err := store.LoadAllByUserID(context.Background(), userID, &domain.UserURLsQuery{})
+
+	// Assert.
+	require.Nil(t, items)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+// userURLsSelect is the select list of the user URLs query up to the filters.
+const userURLsSelect = "select url_key, original_url, created_at, updated_at, last_accessed_at, " +
+	"coalesce(m.title, ''), coalesce(m.notes, ''), " +
+	"(select json_agg(t.tag order by t.tag) from url_tags t where t.url_key = urls.url_key) " +
+	"from urls left join url_metadata m using (url_key) "
+
+func TestUserURLsSQL(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	collectionID := uuid.New()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		query    *domain.UserURLsQuery
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "WHEN no options THEN all user URLs by creation time",
+			query:    &domain.UserURLsQuery{},
+			wantSQL:  userURLsSelect + "where user_id = $1 order by created_at asc, url_key asc",
+			wantArgs: []any{userID.String()},
+		},
+		{
+			name: "WHEN creation time cursor descending THEN rows before the cursor",
+			query: &domain.UserURLsQuery{
+				Descending: true,
+				After:      &domain.UserURLsCursor{CreatedAt: createdAt, URLKey: "foo"},
+				Limit:      10,
+			},
+			wantSQL: userURLsSelect + "where user_id = $1 " +
+				"and (created_at, url_key) < ($2, $3) order by created_at desc, url_key desc limit $4",
+			wantArgs: []any{userID.String(), createdAt, "foo", 10},
+		},
+		{
+			name: "WHEN key cursor with filters THEN filtered rows after the cursor",
+			query: &domain.UserURLsQuery{
+				Sort:   domain.UserURLsSortKey,
+				Status: domain.UserURLsStatusActive,
+				Search: "Foo.Bar  Docs",
+				Tag:    "work",
+				After:  &domain.UserURLsCursor{CreatedAt: createdAt, URLKey: "foo"},
+				Limit:  10,
+			},
+			wantSQL: userURLsSelect + "where user_id = $1 " +
+				"and not is_deleted and (expires_at is null or expires_at > now()) " +
+				"and exists (select 1 from url_tags t where t.url_key = urls.url_key and t.tag = $2) " +
+				"and search_vector @@ plainto_tsquery('simple', $3) " +
+				"and url_key > $4 order by url_key asc limit $5",
+			wantArgs: []any{userID.String(), "work", "foo bar docs", "foo", 10},
+		},
+		{
+			name:  "WHEN collection THEN rows of the user collection",
+			query: &domain.UserURLsQuery{Collection: collectionID},
+			wantSQL: userURLsSelect + "where user_id = $1 " +
+				"and exists (select 1 from collection_urls cu join collections c on c.id = cu.collection_id " +
+				"where cu.url_key = urls.url_key and c.id = $2 and c.user_id = $1) " +
+				"order by created_at asc, url_key asc",
+			wantArgs: []any{userID.String(), collectionID.String()},
+		},
+		{
+			name:     "WHEN deleted status THEN deleted rows only",
+			query:    &domain.UserURLsQuery{Sort: domain.UserURLsSortKey, Status: domain.UserURLsStatusDeleted},
+			wantSQL:  userURLsSelect + "where user_id = $1 and is_deleted order by url_key asc",
+			wantArgs: []any{userID.String()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act.
+			gotSQL, gotArgs := userURLsSQL(userID, tt.query)
+
+			// Assert.
+			require.Equal(t, tt.wantSQL, gotSQL)
+			require.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestDatabaseStore_Save_Duplicate(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	campaignID := uuid.New()
+	tests := []struct {
+		name       string
+		dedupScope string
+		constraint string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN global scope THEN existing key of any user",
+			dedupScope: config.DedupScopeGlobal,
+			constraint: "urls_canonical_url_global_unique",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), "select url_key from urls where canonical_url = $1 and dedup_scope = 'global'", "http://foo.bar/").
+					DoAndReturn(func(_ context.Context, result *string, _ string, _ ...any) error {
+						*result = "foo"
+						return nil
+					})
+			},
+		},
+		{
+			name:       "WHEN user scope THEN existing key of the user",
+			dedupScope: config.DedupScopeUser,
+			constraint: "urls_user_canonical_url_unique",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(
+						gomock.Any(),
+						gomock.Any(),
+						"select url_key from urls where canonical_url = $1 and user_id = $2 and dedup_scope = 'user'",
+						"http://foo.bar/",
+						userID.String(),
+					).
+					DoAndReturn(func(_ context.Context, result *string, _ string, _ ...any) error {
+						*result = "foo"
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.Connection.EXPECT().
+				Exec(
+					gomock.Any(),
+					gomock.Any(),
+					"foo", "http://Foo.bar", "http://foo.bar/", userID.String(), gomock.Nil(), tt.dedupScope,
+					"Foo", "", []string{"bar"}, 301, true, campaignID.String(),
+					`[{"platform":"ios","url":"https://apps.apple.com/app/foo"}]`,
+					`[{"name":"A","url":"http://foo.bar/a","weight":1},{"name":"B","url":"http://foo.bar/b","weight":3}]`,
+				).
+				Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: tt.constraint})
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{
+					KeyStoreConfiguration: config.KeyStoreConfiguration{
+						KeyLength:        8,
+						KeyMaxLength:     100,
+						KeyMaxIterations: 10,
+					},
+				},
+				DedupScope: tt.dedupScope,
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			request := &domain.SaveRequest{
+				OriginalURL:  "http://Foo.bar",
+				CanonicalURL: "http://foo.bar/",
+				Alias:        "foo",
+				Title:        "Foo",
+				Tags:         []string{"bar"},
+				RedirectCode: 301,
+				Passthrough:  true,
+				CampaignID:   campaignID,
+				Rules:        []*domain.RedirectRule{{Platform: domain.PlatformIOS, URL: "https://apps.apple.com/app/foo"}},
+				Variants: []*domain.URLVariant{
+					{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+					{Name: "B", URL: "http://foo.bar/b", Weight: 3},
+				},
+			}
+			_, err := store.Save(context.Background(), request, userID)
+
+			// Assert.
+			var duplicateURLError *DuplicateURLError
+			require.ErrorAs(t, err, &duplicateURLError)
+			require.Equal(t, "foo", duplicateURLError.Key)
+		})
+	}
+}
+
+func TestDatabaseStore_BackfillCanonicalURLs(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(
+			gomock.Any(),
+			"select url_key, original_url from urls where canonical_pending order by url_key limit $1",
+			canonicalBackfillBlockSize,
+		).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	count, err := store.BackfillCanonicalURLs(context.Background(), strings.ToLower)
+
+	// Assert.
+	require.ErrorIs(t, err, assert.AnError)
+	require.Zero(t, count)
+}
+
+func TestDatabaseStore_SaveBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		requestItems []*domain.BatchRequestItem
+		checkResult  func(responseItems []*domain.BatchResponseItem, err error)
+		hookBefore   func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN invalid alias THEN invalid item",
+			requestItems: []*domain.BatchRequestItem{
+				{CorrelationID: "1", SaveRequest: domain.SaveRequest{OriginalURL: "http://foo.bar", Alias: "api"}},
+			},
+			checkResult: func(responseItems []*domain.BatchResponseItem, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*domain.BatchResponseItem{
+					{CorrelationID: "1", Status: domain.BatchItemStatusInvalid, Reason: "invalid alias: 'api' is reserved"},
+				}, responseItems)
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().DoInTx(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "WHEN same alias twice THEN second item invalid",
+			requestItems: []*domain.BatchRequestItem{
+				{CorrelationID: "1", SaveRequest: domain.SaveRequest{OriginalURL: "http://foo.bar", Alias: "foo"}},
+				{CorrelationID: "2", SaveRequest: domain.SaveRequest{OriginalURL: "http://bar.buz", Alias: "foo"}},
+			},
+			checkResult: func(responseItems []*domain.BatchResponseItem, err error) {
+				require.NoError(t, err)
+				require.Len(t, responseItems, 2)
+				require.Equal(t, &domain.BatchResponseItem{
+					CorrelationID: "2",
+					Status:        domain.BatchItemStatusInvalid,
+					Reason:        "alias foo is already taken",
+				}, responseItems[1])
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().DoInTx(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "WHEN insert error THEN error",
+			requestItems: []*domain.BatchRequestItem{
+				{
+					CorrelationID: "1",
+					SaveRequest: domain.SaveRequest{
+						OriginalURL:  "http://foo.bar",
+						CanonicalURL: "http://foo.bar/",
+						Alias:        "foo",
+						Notes:        "Foo",
+						Tags:         []string{"bar", "buz"},
+					},
+				},
+				{CorrelationID: "2", SaveRequest: domain.SaveRequest{OriginalURL: "http://Foo.bar:80", CanonicalURL: "http://foo.bar/"}},
+			},
+			checkResult: func(_ []*domain.BatchResponseItem, err error) {
+				require.ErrorIs(t, err, assert.AnError)
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					DoInTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, action func(*sql.Tx) error) error {
+						return action(nil)
+					})
+				// Only the first item of a canonical URL is inserted.
+				mock.Connection.EXPECT().
+					QueryRowsTx(
+						gomock.Any(),
+						gomock.Any(),
+						gomock.Any(),
+						[]string{"foo"},
+						[]string{"http://foo.bar"},
+						[]string{"http://foo.bar/"},
+						gomock.Any(),
+						gomock.Any(),
+						config.DedupScopeGlobal,
+						[]string{""},
+						[]string{"Foo"},
+						[]string{"foo", "foo"},
+						[]string{"bar", "buz"},
+						[]int{0},
+						[]bool{false},
+						[]string{""},
+						[]string{""},
+						[]string{""},
+					).
+					Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{
+					KeyStoreConfiguration: config.KeyStoreConfiguration{
+						KeyLength:        8,
+						KeyMaxLength:     100,
+						KeyMaxIterations: 10,
+					},
+				},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			responseItems, err := store.SaveBatch(context.Background(), tt.requestItems, uuid.New())
+
+			// Assert.
+			tt.checkResult(responseItems, err)
+		})
+	}
+}
+
 func TestDatabaseStore_DeleteBatch(t *testing.T) {
 	t.Parallel()
 
@@ -193,42 +607,63 @@ func TestDatabaseStore_DeleteBatch(t *testing.T) {
 	}
 }
 
-func TestDatabaseStore_GetStatistics(t *testing.T) {
+func TestDatabaseStore_RestoreBatch(t *testing.T) {
 	t.Parallel()
 
-	type want struct {
-		urlsCount  int
-		usersCount int
+	// Arrange.
+	userID := uuid.New()
+	deletedAfter := time.Now().Add(-time.Hour)
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(gomock.Any(), gomock.Any(), []string{"foo"}, userID, deletedAfter).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
 	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	restored, err := store.RestoreBatch(context.Background(), []string{"foo"}, userID, deletedAfter)
+
+	// Assert.
+	require.Nil(t, restored)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDatabaseStore_UpdateURL(t *testing.T) {
+	t.Parallel()
 
+	userID := uuid.New()
 	tests := []struct {
-		name       string
-		want       *want
-		wantError  bool
-		hookBefore func(mock *mocks.Mock)
+		name          string
+		wantDuplicate string
+		hookBefore    func(mock *mocks.Mock)
 	}{
 		{
-			name:      "WHEN connection error THEN error",
-			want:      &want{},
-			wantError: true,
+			name: "WHEN row lock error THEN error",
 			hookBefore: func(mock *mocks.Mock) {
 				mock.Connection.EXPECT().
-					QueryRow2(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(assert.AnError)
+					DoInTx(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, action func(*sql.Tx) error) error {
+						return action(nil)
+					})
+				mock.Connection.EXPECT().
+					QueryRowsTx(gomock.Any(), gomock.Nil(), gomock.Any(), "foo", userID.String()).
+					Return(nil, assert.AnError)
 			},
 		},
 		{
-			name: "WHEN no errors THEN ok",
-			want: &want{
-				urlsCount:  1,
-				usersCount: 2,
-			},
+			name:          "WHEN duplicate URL THEN existing key",
+			wantDuplicate: "bar",
 			hookBefore: func(mock *mocks.Mock) {
 				mock.Connection.EXPECT().
-					QueryRow2(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-					DoAndReturn(func(ctx context.Context, result1 *int, result2 *int, sql string, args ...any) error {
-						*result1 = 1
-						*result2 = 2
+					DoInTx(gomock.Any(), gomock.Any()).
+					Return(NewDuplicateURLError("", "http://Bar.buz"))
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), "select url_key from urls where canonical_url = $1 and dedup_scope = 'global'", "http://bar.buz/").
+					DoAndReturn(func(_ context.Context, result *string, _ string, _ ...any) error {
+						*result = "bar"
 						return nil
 					})
 			},
@@ -245,17 +680,936 @@ func TestDatabaseStore_GetStatistics(t *testing.T) {
 			tt.hookBefore(mock)
 			configuration := &config.Configuration{
 				DatabaseStore: &config.DatabaseStoreConfiguration{},
+				DedupScope:    config.DedupScopeGlobal,
 			}
 			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
 
 			// Act.
-			urlsCount, usersCount, err := store.GetStatistics(context.Background())
+			request := &domain.UpdateRequest{Key: "foo", OriginalURL: "http://Bar.buz", CanonicalURL: "http://bar.buz/"}
+			version, err := store.UpdateURL(context.Background(), request, userID)
 
 			// Assert.
-			require.Equal(t, tt.want.urlsCount, urlsCount)
-			require.Equal(t, tt.want.usersCount, usersCount)
-			if tt.wantError {
-				require.Error(t, err)
+			require.Zero(t, version)
+			if len(tt.wantDuplicate) > 0 {
+				var duplicateURLError *DuplicateURLError
+				require.ErrorAs(t, err, &duplicateURLError)
+				require.Equal(t, tt.wantDuplicate, duplicateURLError.Key)
+			} else {
+				require.ErrorIs(t, err, assert.AnError)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_LoadVersions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(
+			gomock.Any(),
+			"select version, original_url, replaced_at from url_versions where url_key = $1 order by version desc",
+			"foo",
+		).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	versions, err := store.LoadVersions(context.Background(), "foo")
+
+	// Assert.
+	require.Nil(t, versions)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDatabaseStore_UpdateVariants(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	variants := []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 0},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 1},
+	}
+	variantsJSON := `[{"name":"A","url":"http://foo.bar/a","weight":0},{"name":"B","url":"http://foo.bar/b","weight":1}]`
+
+	tests := []struct {
+		name       string
+		want       bool
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN query error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "foo", userID.String(), variantsJSON).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no such URL THEN false",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "foo", userID.String(), variantsJSON).
+					Return(nil)
+			},
+		},
+		{
+			name: "WHEN updated THEN true",
+			want: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "foo", userID.String(), variantsJSON).
+					DoAndReturn(func(_ context.Context, result *int, _ string, _ ...any) error {
+						*result = 1
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			updated, err := store.UpdateVariants(context.Background(), "foo", variants, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, updated)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	deletedBefore := time.Now()
+
+	tests := []struct {
+		name       string
+		want       int
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN connection error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), deletedBefore).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			want: 2,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), deletedBefore).
+					DoAndReturn(func(_ context.Context, result *int, _ string, _ ...any) error {
+						*result = 2
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			count, err := store.PurgeDeleted(context.Background(), deletedBefore)
+
+			// Assert.
+			require.Equal(t, tt.want, count)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_DeleteExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		want       int
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN connection error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), now).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			want: 3,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), now).
+					DoAndReturn(func(ctx context.Context, result *int, sql string, args ...any) error {
+						*result = 3
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			count, err := store.DeleteExpired(context.Background(), now)
+
+			// Assert.
+			require.Equal(t, tt.want, count)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_SaveClicks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	clicks := []*domain.Click{
+		{
+			Key:       "foo",
+			Timestamp: now,
+			Referrer:  "http://referrer",
+			UserAgent: "agent",
+			IPHash:    "hash",
+			Variant:   "B",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		clicks     []*domain.Click
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN no clicks THEN ok",
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:      "WHEN connection error THEN error",
+			clicks:    clicks,
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name:   "WHEN no errors THEN ok",
+			clicks: clicks,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(
+						gomock.Any(),
+						gomock.Any(),
+						[]string{"foo"},
+						[]time.Time{now},
+						[]string{"http://referrer"},
+						[]string{"agent"},
+						[]string{"hash"},
+						[]string{"B"},
+					).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			err := store.SaveClicks(context.Background(), tt.clicks)
+
+			// Assert.
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_GetClickStatistics(t *testing.T) {
+	t.Parallel()
+
+	request := &domain.ClickStatisticsRequest{
+		Key:      "foo",
+		From:     time.Now().Add(-time.Hour),
+		To:       time.Now(),
+		Bucket:   domain.ClickBucketHour,
+		TopCount: 10,
+	}
+
+	tests := []struct {
+		name       string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN totals error THEN error",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow2(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), request.Key, request.From, request.To).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN buckets error THEN error",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow2(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), request.Key, request.From, request.To).
+					Return(nil)
+				mock.Connection.EXPECT().
+					QueryRows(gomock.Any(), gomock.Any(), request.Key, request.From, request.To, "hour").
+					Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			statistics, err := store.GetClickStatistics(context.Background(), request)
+
+			// Assert.
+			require.Nil(t, statistics)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDatabaseStore_GetStatistics(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		urlsCount  int
+		usersCount int
+	}
+
+	tests := []struct {
+		name       string
+		want       *want
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN connection error THEN error",
+			want:      &want{},
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow2(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			want: &want{
+				urlsCount:  1,
+				usersCount: 2,
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow2(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, result1 *int, result2 *int, sql string, args ...any) error {
+						*result1 = 1
+						*result2 = 2
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			urlsCount, usersCount, err := store.GetStatistics(context.Background())
+
+			// Assert.
+			require.Equal(t, tt.want.urlsCount, urlsCount)
+			require.Equal(t, tt.want.usersCount, usersCount)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_LeaseKeySequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		want       uint64
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN connection error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), int64(100)).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN block start",
+			want: 501,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), int64(100)).
+					DoAndReturn(func(ctx context.Context, result *int64, sql string, args ...any) error {
+						*result = 501
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger).(*DatabaseStore)
+
+			// Act.
+			start, err := store.leaseKeySequence(context.Background(), 100)
+
+			// Assert.
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, start)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_SaveCollection(t *testing.T) {
+	t.Parallel()
+
+	collection := &domain.Collection{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Name:      "foo",
+		CreatedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name       string
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN connection error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), collection.ID.String(), collection.UserID.String(), "foo", collection.CreatedAt).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name:      "WHEN name taken THEN collection exists",
+			wantError: ErrCollectionExists,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: collectionNameConstraint})
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			err := store.SaveCollection(context.Background(), collection)
+
+			// Assert.
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_LoadCollections(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(
+			gomock.Any(),
+			"select c.id, c.user_id, c.name, c.created_at, "+
+				"(select count(*) from collection_urls cu join urls u on u.url_key = cu.url_key "+
+				"where cu.collection_id = c.id and u.user_id = c.user_id) "+
+				"from collections c where c.user_id = $1 order by c.name",
+			userID.String(),
+		).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	collections, err := store.LoadCollections(context.Background(), userID)
+
+	// Assert.
+	require.Nil(t, collections)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDatabaseStore_RenameCollection(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		want       bool
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN name taken THEN collection exists",
+			wantError: ErrCollectionExists,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), id.String(), userID.String(), "bar").
+					Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: collectionNameConstraint})
+			},
+		},
+		{
+			name: "WHEN no such collection THEN false",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), id.String(), userID.String(), "bar").
+					Return(nil)
+			},
+		},
+		{
+			name: "WHEN renamed THEN true",
+			want: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), id.String(), userID.String(), "bar").
+					DoAndReturn(func(_ context.Context, result *int, _ string, _ ...any) error {
+						*result = 1
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			renamed, err := store.RenameCollection(context.Background(), id, "bar", userID)
+
+			// Assert.
+			require.Equal(t, tt.want, renamed)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_CollectionURLs(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	userID := uuid.New()
+	keys := []string{"foo", "bar"}
+
+	tests := []struct {
+		name     string
+		action   func(store Store) (bool, error)
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "WHEN delete THEN collection deleted",
+			action: func(store Store) (bool, error) {
+				return store.DeleteCollection(context.Background(), id, userID)
+			},
+			wantSQL: "with deleted as (delete from collections where id = $1 and user_id = $2 returning 1) " +
+				"select count(*) from deleted",
+			wantArgs: []any{id.String(), userID.String()},
+		},
+		{
+			name: "WHEN add THEN own URLs inserted",
+			action: func(store Store) (bool, error) {
+				return store.AddCollectionURLs(context.Background(), id, keys, userID)
+			},
+			wantSQL: "with target as (select id from collections where id = $1 and user_id = $2), " +
+				"added as (insert into collection_urls (collection_id, url_key) " +
+				"select t.id, u.url_key from target t join urls u on u.url_key = any($3) and u.user_id = $2 " +
+				"on conflict do nothing) " +
+				"select count(*) from target",
+			wantArgs: []any{id.String(), userID.String(), keys},
+		},
+		{
+			name: "WHEN remove THEN links deleted",
+			action: func(store Store) (bool, error) {
+				return store.RemoveCollectionURLs(context.Background(), id, keys, userID)
+			},
+			wantSQL: "with target as (select id from collections where id = $1 and user_id = $2), " +
+				"removed as (delete from collection_urls where collection_id in (select id from target) and url_key = any($3)) " +
+				"select count(*) from target",
+			wantArgs: []any{id.String(), userID.String(), keys},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, found := range []bool{false, true} {
+				// Arrange.
+				ctrl := gomock.NewController(t)
+				mock := mocks.NewMock(ctrl)
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), tt.wantSQL, tt.wantArgs...).
+					DoAndReturn(func(_ context.Context, result *int, _ string, _ ...any) error {
+						if found {
+							*result = 1
+						}
+						return nil
+					})
+				configuration := &config.Configuration{
+					DatabaseStore: &config.DatabaseStoreConfiguration{},
+				}
+				store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+				// Act.
+				result, err := tt.action(store)
+
+				// Assert.
+				require.NoError(t, err)
+				require.Equal(t, found, result)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_SaveCampaign(t *testing.T) {
+	t.Parallel()
+
+	campaign := &domain.Campaign{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Name:       "foo",
+		CreatedAt:  time.Now(),
+		Parameters: map[string]string{"utm_source": "bar"},
+	}
+
+	tests := []struct {
+		name       string
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN connection error THEN error",
+			wantError: assert.AnError,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(
+						gomock.Any(),
+						gomock.Any(),
+						campaign.ID.String(),
+						campaign.UserID.String(),
+						"foo",
+						`{"utm_source":"bar"}`,
+						campaign.CreatedAt,
+					).
+					Return(assert.AnError)
+			},
+		},
+		{
+			name:      "WHEN name taken THEN campaign exists",
+			wantError: ErrCampaignExists,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: campaignNameConstraint})
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			err := store.SaveCampaign(context.Background(), campaign)
+
+			// Assert.
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDatabaseStore_LoadCampaigns(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Connection.EXPECT().
+		QueryRows(
+			gomock.Any(),
+			"select id, user_id, name, created_at, parameters from campaigns where user_id = $1 order by name",
+			userID.String(),
+		).
+		Return(nil, assert.AnError)
+	configuration := &config.Configuration{
+		DatabaseStore: &config.DatabaseStoreConfiguration{},
+	}
+	store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+	// Act.
+	campaigns, err := store.LoadCampaigns(context.Background(), userID)
+
+	// Assert.
+	require.Nil(t, campaigns)
+	require.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDatabaseStore_UpdateCampaign(t *testing.T) {
+	t.Parallel()
+
+	campaign := &domain.Campaign{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		Name:   "bar",
+	}
+
+	tests := []struct {
+		name       string
+		want       bool
+		wantError  error
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN name taken THEN campaign exists",
+			wantError: ErrCampaignExists,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), campaign.ID.String(), campaign.UserID.String(), "bar", "{}").
+					Return(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: campaignNameConstraint})
+			},
+		},
+		{
+			name: "WHEN no such campaign THEN false",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), campaign.ID.String(), campaign.UserID.String(), "bar", "{}").
+					Return(nil)
+			},
+		},
+		{
+			name: "WHEN updated THEN true",
+			want: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Connection.EXPECT().
+					QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), campaign.ID.String(), campaign.UserID.String(), "bar", "{}").
+					DoAndReturn(func(_ context.Context, result *int, _ string, _ ...any) error {
+						*result = 1
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DatabaseStore: &config.DatabaseStoreConfiguration{},
+			}
+			store := NewDatabaseStore(mock.Connection, configuration, mock.Logger)
+
+			// Act.
+			updated, err := store.UpdateCampaign(context.Background(), campaign)
+
+			// Assert.
+			require.Equal(t, tt.want, updated)
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
 			} else {
 				require.NoError(t, err)
 			}
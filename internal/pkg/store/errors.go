@@ -0,0 +1,10 @@
+package store
+
+import "errors"
+
+var (
+	ErrInvalidAlias      = errors.New("invalid alias")
+	ErrSnapshotCorrupted = errors.New("snapshot is corrupted")
+	ErrCollectionExists  = errors.New("collection already exists")
+	ErrCampaignExists    = errors.New("campaign already exists")
+)
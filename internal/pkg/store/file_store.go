@@ -2,87 +2,593 @@ package store
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/logger"
 )
 
+// Garbage ratio is not checked for small files, compacting them gains nothing.
+const minRecordsForGarbageCompaction = 1000
+
+// FileStore keeps the state in two files: a binary snapshot with one record per live key
+// and a JSON lines journal with all the changes made after the snapshot was written.
+// Compaction folds the journal into a new snapshot. A journal without a snapshot is
+// the legacy layout and is loaded as is.
 type FileStore struct {
 	configuration *config.FileStoreConfiguration
+	logger        logger.Logger
 	mutex         sync.Mutex
+	// Only one compaction at a time.
+	compactionMutex sync.Mutex
+	isCompacting    atomic.Bool
+	// Guarded by mutex.
+	journal         *os.File
+	journalSize     int64
+	records         int64
+	garbageRecords  int64
+	writtenSequence uint64
+	// Group commit state, guarded by syncMutex. Never taken before mutex.
+	syncMutex      sync.Mutex
+	syncCond       *sync.Cond
+	isSyncing      bool
+	syncedSequence uint64
+}
+
+// journalReadResult describes the part of the journal that was read.
+type journalReadResult struct {
+	records        int64
+	garbageRecords int64
+	// Size of the complete records. A torn record after them is not counted.
+	validSize int64
 }
 
 var _ ColdStore = (*FileStore)(nil)
 
-func NewFileStore(configuration *config.Configuration) ColdStore {
-	return &FileStore{
+func NewFileStore(configuration *config.Configuration, logger logger.Logger) ColdStore {
+	store := &FileStore{
 		configuration: configuration.FileStore,
+		logger:        logger,
 	}
+	store.syncCond = sync.NewCond(&store.syncMutex)
+
+	return store
 }
 
-func (s *FileStore) LoadAll() ([]*domain.ColdStoreEntry, error) {
+func (s *FileStore) LoadAll(restore func(*domain.ColdStoreEntry)) error {
 	// Called only during startup, so no need for mutex locking.
 
-	if _, err := os.Stat(s.configuration.FilePath); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return []*domain.ColdStoreEntry{}, nil
-		} else {
-			return nil, fmt.Errorf("LoadAll, os.Stat failed: %w", err)
+	snapshotRecords, err := s.readSnapshot(restore)
+	if err != nil {
+		return fmt.Errorf("LoadAll, readSnapshot failed: %w", err)
+	}
+
+	journalSize, err := s.getJournalSize()
+	if err != nil {
+		return fmt.Errorf("LoadAll, getJournalSize failed: %w", err)
+	}
+
+	result, err := s.readJournal(journalSize, restore)
+	if err != nil {
+		return fmt.Errorf("LoadAll, readJournal failed: %w", err)
+	}
+
+	if result.validSize < journalSize {
+		// The process was killed in the middle of a write, the record was never acknowledged.
+		s.logger.Warnf(
+			"Storage file %v has a torn record at offset %v, %v bytes truncated",
+			s.configuration.FilePath, result.validSize, journalSize-result.validSize)
+
+		if err = os.Truncate(s.configuration.FilePath, result.validSize); err != nil {
+			return fmt.Errorf("LoadAll, os.Truncate failed: %w", err)
 		}
 	}
 
-	file, err := os.Open(s.configuration.FilePath)
+	if err = s.openJournal(); err != nil {
+		return fmt.Errorf("LoadAll, openJournal failed: %w", err)
+	}
+
+	s.records = snapshotRecords + result.records
+	s.garbageRecords = result.garbageRecords
+
+	return nil
+}
+
+func (s *FileStore) Save(entry *domain.ColdStoreEntry) error {
+	data, err := json.Marshal(entry)
 	if err != nil {
-		return nil, fmt.Errorf("LoadAll, os.Open failed: %w", err)
+		return fmt.Errorf("Save, json.Marshal failed: %w", err)
 	}
 
-	defer file.Close()
+	sequence, err := s.write(append(data, '\n'), isUpdateEntry(entry))
+	if err != nil {
+		return fmt.Errorf("Save, write failed: %w", err)
+	}
 
-	entries := []*domain.ColdStoreEntry{}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		data := scanner.Bytes()
-		entry := &domain.ColdStoreEntry{}
-		if err := json.Unmarshal(data, entry); err != nil {
-			return nil, fmt.Errorf("LoadAll, json.Unmarshal failed: %w", err)
+	if s.configuration.SyncPolicy == config.FileStoreSyncBatch {
+		if err = s.waitSynced(sequence); err != nil {
+			return fmt.Errorf("Save, waitSynced failed: %w", err)
 		}
+	}
+
+	return nil
+}
+
+// Compact folds the snapshot and the journal into a new snapshot with one record per live key,
+// then cuts the folded part off the journal. Writes are blocked only while the journal tail
+// written during the compaction is moved to the new journal.
+//
+// If the process stops between the two renames, the old journal is replayed over the new
+// snapshot on startup. Replaying is idempotent, update records carry the version they replace
+// and are skipped once applied, so the loaded state is the same.
+func (s *FileStore) Compact() error {
+	s.compactionMutex.Lock()
+	defer s.compactionMutex.Unlock()
+
+	// Everything before this offset goes to the snapshot, the rest stays in the journal.
+	s.mutex.Lock()
+	offset, err := s.getJournalSize()
+	s.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("Compact, getJournalSize failed: %w", err)
+	}
 
-		entries = append(entries, entry)
+	merger := newColdStoreEntryMerger()
+	if _, err = s.readSnapshot(merger.add); err != nil {
+		return fmt.Errorf("Compact, readSnapshot failed: %w", err)
 	}
 
-	if scanner.Err() != nil {
-		return nil, fmt.Errorf("LoadAll, scanner.Err() returned an error: %w", err)
+	result, err := s.readJournal(offset, merger.add)
+	if err != nil {
+		return fmt.Errorf("Compact, readJournal failed: %w", err)
 	}
 
-	return entries, nil
+	if result.validSize < offset {
+		return fmt.Errorf("Compact, journal has a torn record at offset %v", result.validSize)
+	}
+
+	snapshotRecords, snapshotSize, err := s.writeSnapshot(merger.entries(), merger.sequence)
+	if err != nil {
+		return fmt.Errorf("Compact, writeSnapshot failed: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tailSize, tailResult, err := s.cutJournal(offset)
+	if err != nil {
+		return fmt.Errorf("Compact, cutJournal failed: %w", err)
+	}
+
+	s.logger.Infof(
+		"Storage compacted from %v records (%v journal bytes) to %v records (%v snapshot bytes, %v journal bytes)",
+		s.records, s.journalSize, snapshotRecords+tailResult.records, snapshotSize, tailSize)
+
+	s.journalSize = tailSize
+	s.records = snapshotRecords + tailResult.records
+	s.garbageRecords = tailResult.garbageRecords
+
+	return nil
 }
 
-func (s *FileStore) Save(entry *domain.ColdStoreEntry) error {
+// Close flushes the journal to disk and closes it.
+func (s *FileStore) Close() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	data, err := json.Marshal(entry)
+	if s.journal == nil {
+		return nil
+	}
+
+	err := errors.Join(s.journal.Sync(), s.journal.Close())
+	s.journal = nil
 	if err != nil {
-		return fmt.Errorf("Save, json.Marshal failed: %w", err)
+		return fmt.Errorf("Close, journal close failed: %w", err)
+	}
+
+	return nil
+}
+
+// write appends data to the journal and returns the sequence number of the write.
+func (s *FileStore) write(data []byte, isUpdate bool) (uint64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.journal == nil {
+		if err := s.openJournal(); err != nil {
+			return 0, fmt.Errorf("write, openJournal failed: %w", err)
+		}
+	}
+
+	if _, err := s.journal.Write(data); err != nil {
+		// Cut off a partially written record, otherwise the next one would be glued to it.
+		return 0, errors.Join(
+			fmt.Errorf("write, journal.Write failed: %w", err),
+			s.journal.Truncate(s.journalSize))
+	}
+
+	if s.configuration.SyncPolicy == config.FileStoreSyncAlways {
+		if err := s.journal.Sync(); err != nil {
+			return 0, fmt.Errorf("write, journal.Sync failed: %w", err)
+		}
+	}
+
+	s.journalSize += int64(len(data))
+	s.records++
+	if isUpdate {
+		// Updates are merged into the put entry during compaction.
+		s.garbageRecords++
+	}
+	s.writtenSequence++
+
+	if s.needsCompaction() && s.isCompacting.CompareAndSwap(false, true) {
+		go func() {
+			defer s.isCompacting.Store(false)
+
+			if err := s.Compact(); err != nil {
+				s.logger.Errorf("FileStore.Save, Compact failed: %v", err)
+			}
+		}()
+	}
+
+	return s.writtenSequence, nil
+}
+
+// waitSynced blocks until the write with the given sequence number is flushed to disk.
+// The first waiter becomes the leader: it waits for more writes to join the batch
+// and flushes all of them with one fsync, the rest wait for the leader.
+func (s *FileStore) waitSynced(sequence uint64) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	for s.syncedSequence < sequence {
+		if s.isSyncing {
+			s.syncCond.Wait()
+			continue
+		}
+
+		s.isSyncing = true
+		s.syncMutex.Unlock()
+
+		time.Sleep(s.configuration.SyncInterval)
+		syncedSequence, err := s.syncJournal()
+
+		s.syncMutex.Lock()
+		s.isSyncing = false
+		s.syncedSequence = max(s.syncedSequence, syncedSequence)
+		s.syncCond.Broadcast()
+
+		if err != nil {
+			return fmt.Errorf("waitSynced, syncJournal failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// syncJournal flushes the journal and returns the sequence number of the last flushed write.
+func (s *FileStore) syncJournal() (uint64, error) {
+	// Writes are blocked during fsync, the journal may be replaced by compaction otherwise.
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.journal == nil {
+		return s.writtenSequence, nil
+	}
+
+	if err := s.journal.Sync(); err != nil {
+		return 0, err
+	}
+
+	return s.writtenSequence, nil
+}
+
+// openJournal opens the journal for appending. Called with mutex locked or during startup.
+func (s *FileStore) openJournal() error {
+	journal, err := os.OpenFile(s.configuration.FilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("openJournal, os.OpenFile failed: %w", err)
+	}
+
+	fileInfo, err := journal.Stat()
+	if err != nil {
+		journal.Close()
+		return fmt.Errorf("openJournal, journal.Stat failed: %w", err)
+	}
+
+	size := fileInfo.Size()
+	if size > 0 {
+		// A record written by hand may lack the line break, the next record must not be glued to it.
+		lastByte := make([]byte, 1)
+		if _, err = journal.ReadAt(lastByte, size-1); err != nil {
+			journal.Close()
+			return fmt.Errorf("openJournal, journal.ReadAt failed: %w", err)
+		}
+
+		if lastByte[0] != '\n' {
+			if _, err = journal.Write([]byte{'\n'}); err != nil {
+				journal.Close()
+				return fmt.Errorf("openJournal, journal.Write failed: %w", err)
+			}
+
+			size++
+		}
+	}
+
+	s.journal = journal
+	s.journalSize = size
+
+	return nil
+}
+
+func (s *FileStore) needsCompaction() bool {
+	// The journal only holds changes made after the last compaction.
+	threshold := s.configuration.CompactionSizeThreshold
+	if threshold > 0 && s.journalSize >= threshold {
+		return true
+	}
+
+	ratio := s.configuration.CompactionGarbageRatio
+	return ratio > 0 &&
+		s.records >= minRecordsForGarbageCompaction &&
+		float64(s.garbageRecords)/float64(s.records) >= ratio
+}
+
+func (s *FileStore) getSnapshotPath() string {
+	return s.configuration.FilePath + ".snapshot"
+}
+
+func (s *FileStore) getJournalSize() (int64, error) {
+	fileInfo, err := os.Stat(s.configuration.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, err
 	}
 
-	file, err := os.OpenFile(s.configuration.FilePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	return fileInfo.Size(), nil
+}
+
+// readSnapshot passes all snapshot entries to restore. A missing snapshot has no entries.
+func (s *FileStore) readSnapshot(restore func(*domain.ColdStoreEntry)) (int64, error) {
+	file, err := os.Open(s.getSnapshotPath())
 	if err != nil {
-		return fmt.Errorf("Save, os.OpenFile failed: %w", err)
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("readSnapshot, os.Open failed: %w", err)
 	}
 
 	defer file.Close()
 
-	data = append(data, '\n')
-	_, err = file.Write(data)
+	records, err := readSnapshot(file, restore)
 	if err != nil {
-		return fmt.Errorf("Save, file.Write failed: %w", err)
+		return 0, fmt.Errorf("readSnapshot, %v: %w", file.Name(), err)
 	}
 
-	return nil
+	return records, nil
+}
+
+// readJournal passes the entries from the first size bytes of the journal to restore.
+func (s *FileStore) readJournal(size int64, restore func(*domain.ColdStoreEntry)) (*journalReadResult, error) {
+	file, err := os.Open(s.configuration.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &journalReadResult{}, nil
+		}
+
+		return nil, fmt.Errorf("readJournal, os.Open failed: %w", err)
+	}
+
+	defer file.Close()
+
+	return readJournalEntries(io.LimitReader(file, size), restore)
+}
+
+func (s *FileStore) writeSnapshot(entries []*domain.ColdStoreEntry, sequence uint64) (int64, int64, error) {
+	snapshotPath := s.getSnapshotPath()
+	temporaryPath := snapshotPath + ".tmp"
+	file, err := os.OpenFile(temporaryPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, os.OpenFile failed: %w", err)
+	}
+
+	defer func() {
+		file.Close()
+		os.Remove(temporaryPath)
+	}()
+
+	writer, err := newSnapshotWriter(file, sequence)
+	if err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, newSnapshotWriter failed: %w", err)
+	}
+
+	for _, entry := range entries {
+		if err = writer.write(entry); err != nil {
+			return 0, 0, fmt.Errorf("writeSnapshot, writer.write failed: %w", err)
+		}
+	}
+
+	if err = writer.close(); err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, writer.close failed: %w", err)
+	}
+
+	if err = file.Sync(); err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, file.Sync failed: %w", err)
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, file.Stat failed: %w", err)
+	}
+
+	if err = os.Rename(temporaryPath, snapshotPath); err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, os.Rename failed: %w", err)
+	}
+
+	if err = syncDirectory(snapshotPath); err != nil {
+		return 0, 0, fmt.Errorf("writeSnapshot, syncDirectory failed: %w", err)
+	}
+
+	return writer.records, fileInfo.Size(), nil
+}
+
+// cutJournal replaces the journal with everything written after the given offset
+// and switches writes to the new journal. Called with mutex locked.
+func (s *FileStore) cutJournal(offset int64) (int64, *journalReadResult, error) {
+	journal, err := os.Open(s.configuration.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, &journalReadResult{}, nil
+		}
+
+		return 0, nil, fmt.Errorf("cutJournal, os.Open failed: %w", err)
+	}
+
+	defer journal.Close()
+
+	if _, err = journal.Seek(offset, io.SeekStart); err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, journal.Seek failed: %w", err)
+	}
+
+	// The tail is what was written during the compaction, it is small.
+	tail, err := io.ReadAll(journal)
+	if err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, io.ReadAll failed: %w", err)
+	}
+
+	result, err := readJournalEntries(bytes.NewReader(tail), func(*domain.ColdStoreEntry) {})
+	if err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, readJournalEntries failed: %w", err)
+	}
+
+	temporaryPath := s.configuration.FilePath + ".compact"
+	file, err := os.OpenFile(temporaryPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, os.OpenFile failed: %w", err)
+	}
+
+	isReplaced := false
+	defer func() {
+		if !isReplaced {
+			file.Close()
+			os.Remove(temporaryPath)
+		}
+	}()
+
+	if _, err = file.Write(tail); err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, file.Write failed: %w", err)
+	}
+
+	if err = file.Sync(); err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, file.Sync failed: %w", err)
+	}
+
+	if err = os.Rename(temporaryPath, s.configuration.FilePath); err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, os.Rename failed: %w", err)
+	}
+
+	isReplaced = true
+	if s.journal != nil {
+		s.journal.Close()
+	}
+	s.journal = file
+
+	// The new journal is already on disk, writes waiting for a batch sync are durable.
+	s.syncMutex.Lock()
+	s.syncedSequence = s.writtenSequence
+	s.syncCond.Broadcast()
+	s.syncMutex.Unlock()
+
+	if err = syncDirectory(s.configuration.FilePath); err != nil {
+		return 0, nil, fmt.Errorf("cutJournal, syncDirectory failed: %w", err)
+	}
+
+	return int64(len(tail)), result, nil
+}
+
+// readJournalEntries decodes JSON lines of any length and passes them to restore.
+// An undecodable last record is a torn write, it is reported by validSize instead of an error.
+func readJournalEntries(reader io.Reader, restore func(*domain.ColdStoreEntry)) (*journalReadResult, error) {
+	result := &journalReadResult{}
+	bufferedReader := bufio.NewReaderSize(reader, snapshotBlockSize)
+	offset := int64(0)
+	var tornRecordError error
+	for {
+		line, err := bufferedReader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("readJournalEntries, reader.ReadBytes failed: %w", err)
+		}
+
+		offset += int64(len(line))
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if tornRecordError != nil {
+				// Records after the broken one mean it is not a torn write, but a damaged file.
+				return nil, tornRecordError
+			}
+
+			entry := &domain.ColdStoreEntry{}
+			if unmarshalErr := json.Unmarshal(line, entry); unmarshalErr != nil {
+				tornRecordError = fmt.Errorf("readJournalEntries, json.Unmarshal failed: %w", unmarshalErr)
+			} else {
+				restore(entry)
+				result.records++
+				if isUpdateEntry(entry) {
+					result.garbageRecords++
+				}
+			}
+		}
+
+		if tornRecordError == nil {
+			result.validSize = offset
+		}
+
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+	}
+}
+
+// syncDirectory makes a rename of the file durable.
+func syncDirectory(path string) error {
+	directory, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	defer directory.Close()
+
+	return directory.Sync()
+}
+
+// isUpdateEntry reports entries that are folded into others during compaction.
+func isUpdateEntry(entry *domain.ColdStoreEntry) bool {
+	switch entry.Type {
+	case domain.ColdStoreEntryTypeDelete,
+		domain.ColdStoreEntryTypeRestore,
+		domain.ColdStoreEntryTypePurge,
+		domain.ColdStoreEntryTypeUpdate,
+		domain.ColdStoreEntryTypeAccess,
+		domain.ColdStoreEntryTypeUpdateOwner,
+		domain.ColdStoreEntryTypeUpdateVariants,
+		domain.ColdStoreEntryTypeDeleteCollection,
+		domain.ColdStoreEntryTypeDeleteCampaign,
+		domain.ColdStoreEntryTypeSequence:
+		return true
+	default:
+		return false
+	}
 }
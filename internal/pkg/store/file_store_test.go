@@ -1,13 +1,21 @@
 package store
 
 import (
+	"fmt"
 	"os"
 	"path"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestFileStore_LoadAll(t *testing.T) {
@@ -44,11 +52,12 @@ func TestFileStore_LoadAll(t *testing.T) {
 			},
 		},
 		{
-			name:      "WHEN unmarshal error THEN error",
+			name:      "WHEN broken record before the last one THEN error",
 			wantError: true,
 			hookBefore: func() *config.Configuration {
 				filePath := path.Join(t.TempDir(), "store.jsonl")
-				require.NoError(t, os.WriteFile(filePath, []byte("foo"), 0644))
+				lines := "foo\n" + `{"key":"foo","value":"http://bar.buz"}` + "\n"
+				require.NoError(t, os.WriteFile(filePath, []byte(lines), 0644))
 				return &config.Configuration{
 					FileStore: &config.FileStoreConfiguration{
 						FilePath: filePath,
@@ -60,6 +69,7 @@ func TestFileStore_LoadAll(t *testing.T) {
 			name: "WHEN no errors THEN ok",
 			want: []*domain.ColdStoreEntry{
 				{
+					Type:  domain.ColdStoreEntryTypePut,
 					Key:   "foo",
 					Value: "http://bar.buz",
 				},
@@ -74,6 +84,39 @@ func TestFileStore_LoadAll(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "WHEN typed entries THEN merged by key",
+			want: []*domain.ColdStoreEntry{
+				{
+					Type:      domain.ColdStoreEntryTypePut,
+					Key:       "foo",
+					Value:     "http://foo.new",
+					UserID:    uuid.MustParse("2b0c1d4e-8d6c-4a55-9d87-5a8f0c4e7f11"),
+					IsDeleted: true,
+				},
+				{
+					Type:  domain.ColdStoreEntryTypePut,
+					Key:   "bar",
+					Value: "http://bar.buz",
+				},
+			},
+			hookBefore: func() *config.Configuration {
+				filePath := path.Join(t.TempDir(), "store.jsonl")
+				lines := `{"type":"put","key":"foo","value":"http://foo.bar"}
+{"key":"bar","value":"http://bar.buz"}
+{"type":"put","key":"foo","value":"http://foo.new"}
+{"type":"update_owner","key":"foo","user_id":"2b0c1d4e-8d6c-4a55-9d87-5a8f0c4e7f11"}
+{"type":"delete","key":"foo"}
+{"type":"delete","key":"missing"}
+`
+				require.NoError(t, os.WriteFile(filePath, []byte(lines), 0644))
+				return &config.Configuration{
+					FileStore: &config.FileStoreConfiguration{
+						FilePath: filePath,
+					},
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -81,10 +124,12 @@ func TestFileStore_LoadAll(t *testing.T) {
 			t.Parallel()
 
 			// Arrange.
-			store := NewFileStore(tt.hookBefore())
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			store := NewFileStore(tt.hookBefore(), mock.Logger)
 
 			// Act.
-			entries, err := store.LoadAll()
+			entries, err := loadMerged(store)
 
 			// Assert.
 			require.ElementsMatch(t, tt.want, entries)
@@ -97,6 +142,129 @@ func TestFileStore_LoadAll(t *testing.T) {
 	}
 }
 
+func TestFileStore_LoadAll_TruncatesTornRecord(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	record := `{"type":"put","key":"foo","value":"http://foo.bar"}` + "\n"
+	require.NoError(t, os.WriteFile(filePath, []byte(record+`{"type":"put","key":"bar","val`), 0644))
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Warnf(gomock.Any(), filePath, int64(len(record)), gomock.Any())
+	store := NewFileStore(configuration, mock.Logger)
+
+	// Act.
+	entries, err := loadMerged(store)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+	}, entries)
+
+	// New records go right after the last complete one.
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"}))
+	require.NoError(t, store.Close())
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, record+`{"type":"put","key":"bar","value":"http://bar.buz"}`+"\n", string(data))
+}
+
+func TestFileStore_LoadAll_AddsMissingLineBreak(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	record := `{"type":"put","key":"foo","value":"http://foo.bar"}`
+	require.NoError(t, os.WriteFile(filePath, []byte(record), 0644))
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.LoadAll(func(*domain.ColdStoreEntry) {}))
+
+	// Act.
+	err := store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"})
+
+	// Assert.
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	entries, err := loadMerged(NewFileStore(configuration, mock.Logger))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestFileStore_Save_SyncPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		syncPolicy string
+	}{
+		{
+			name:       "WHEN always THEN saved",
+			syncPolicy: config.FileStoreSyncAlways,
+		},
+		{
+			name:       "WHEN batch THEN saved",
+			syncPolicy: config.FileStoreSyncBatch,
+		},
+		{
+			name:       "WHEN never THEN saved",
+			syncPolicy: config.FileStoreSyncNever,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			configuration := &config.Configuration{
+				FileStore: &config.FileStoreConfiguration{
+					FilePath:     path.Join(t.TempDir(), "store.jsonl"),
+					SyncPolicy:   tt.syncPolicy,
+					SyncInterval: time.Millisecond,
+				},
+			}
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			store := NewFileStore(configuration, mock.Logger)
+
+			// Act.
+			var wg sync.WaitGroup
+			for i := range 50 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					require.NoError(t, store.Save(&domain.ColdStoreEntry{
+						Type:  domain.ColdStoreEntryTypePut,
+						Key:   fmt.Sprintf("key%v", i),
+						Value: fmt.Sprintf("http://foo.bar/%v", i),
+					}))
+				}()
+			}
+			wg.Wait()
+
+			// Assert.
+			require.NoError(t, store.Close())
+			entries, err := loadMerged(NewFileStore(configuration, mock.Logger))
+			require.NoError(t, err)
+			require.Len(t, entries, 50)
+		})
+	}
+}
+
 func TestFileStore_Save(t *testing.T) {
 	t.Parallel()
 
@@ -107,7 +275,9 @@ func TestFileStore_Save(t *testing.T) {
 			FilePath: filePath,
 		},
 	}
-	store := NewFileStore(configuration)
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	store := NewFileStore(configuration, mock.Logger)
 
 	// Act.
 	err := store.Save(&domain.ColdStoreEntry{
@@ -121,3 +291,547 @@ func TestFileStore_Save(t *testing.T) {
 	require.NoError(t, err)
 	require.Greater(t, fileInfo.Size(), int64(0))
 }
+
+func TestFileStore_SaveLoadAll(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: path.Join(t.TempDir(), "store.jsonl"),
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	store := NewFileStore(configuration, mock.Logger)
+	userID := uuid.New()
+	entries := []*domain.ColdStoreEntry{
+		{
+			Type:   domain.ColdStoreEntryTypePut,
+			Key:    "foo",
+			Value:  "http://bar.buz",
+			UserID: uuid.New(),
+		},
+		{
+			Type:   domain.ColdStoreEntryTypeUpdateOwner,
+			Key:    "foo",
+			UserID: userID,
+		},
+		{
+			Type: domain.ColdStoreEntryTypeDelete,
+			Key:  "foo",
+		},
+	}
+
+	// Act.
+	for _, entry := range entries {
+		require.NoError(t, store.Save(entry))
+	}
+	loadedEntries, err := loadMerged(store)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{
+			Type:      domain.ColdStoreEntryTypePut,
+			Key:       "foo",
+			Value:     "http://bar.buz",
+			UserID:    userID,
+			IsDeleted: true,
+		},
+	}, loadedEntries)
+}
+
+func TestFileStore_Compact(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any()).Times(2)
+	store := NewFileStore(configuration, mock.Logger)
+	entries := []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+		{Type: domain.ColdStoreEntryTypeDelete, Key: "foo"},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", ExpiresAt: lo.ToPtr(time.Now().Add(-time.Hour))},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buz", Value: "http://buz.foo"},
+		{Type: domain.ColdStoreEntryTypePurge, Key: "bar"},
+	}
+	for _, entry := range entries {
+		require.NoError(t, store.Save(entry))
+	}
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+
+	journal, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Empty(t, journal)
+
+	snapshot, err := os.ReadFile(filePath + ".snapshot")
+	require.NoError(t, err)
+
+	loadedEntries, err := loadMerged(store)
+	require.NoError(t, err)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", IsDeleted: true},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buz", Value: "http://buz.foo"},
+	}, loadedEntries)
+
+	// Compacting an already compacted store changes nothing.
+	require.NoError(t, store.Compact())
+	compactedSnapshot, err := os.ReadFile(filePath + ".snapshot")
+	require.NoError(t, err)
+	require.Equal(t, snapshot, compactedSnapshot)
+}
+
+func TestFileStore_Compact_KeepsJournalWrittenAfterSnapshot(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	store := NewFileStore(configuration, mock.Logger)
+	userID := uuid.New()
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Compact())
+
+	// Act.
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeUpdateOwner, Key: "foo", UserID: userID}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"}))
+	entries := []*domain.ColdStoreEntry{}
+	err := NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	})
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+		{Type: domain.ColdStoreEntryTypeUpdateOwner, Key: "foo", UserID: userID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"},
+	}, entries)
+}
+
+func TestFileStore_Compact_KeepsKeySequence(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeSequence, Sequence: 1000}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeSequence, Sequence: 2000}))
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+	entries := []*domain.ColdStoreEntry{}
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	}))
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypeSequence, Sequence: 2000},
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+	}, entries)
+}
+
+func TestFileStore_Compact_DropsPurgedKeys(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	deletedAt := time.Unix(0, time.Now().UnixNano())
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeDelete, Key: "foo", DeletedAt: &deletedAt}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePurge, Key: "foo"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeDelete, Key: "bar", DeletedAt: &deletedAt}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeRestore, Key: "bar"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.new"}))
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+	entries := []*domain.ColdStoreEntry{}
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	}))
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.new"},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"},
+	}, entries)
+}
+
+func TestFileStore_Compact_KeepsLastAccess(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	accessedAt := time.Unix(0, time.Now().UnixNano())
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeAccess, Key: "foo", LastAccessedAt: &accessedAt}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{
+		Type:           domain.ColdStoreEntryTypeAccess,
+		Key:            "foo",
+		LastAccessedAt: lo.ToPtr(accessedAt.Add(-time.Minute)),
+	}))
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+	entries := []*domain.ColdStoreEntry{}
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	}))
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", LastAccessedAt: &accessedAt},
+	}, entries)
+}
+
+func TestFileStore_Compact_KeepsVersions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	firstAt := time.Unix(0, time.Now().Add(-time.Hour).UnixNano())
+	secondAt := time.Unix(0, time.Now().UnixNano())
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeUpdate, Key: "foo", Value: "http://bar.buz", UpdatedAt: &firstAt}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{
+		Type:         domain.ColdStoreEntryTypeUpdate,
+		Key:          "foo",
+		Value:        "http://Buz.qux",
+		CanonicalURL: "http://buz.qux/",
+		UpdatedAt:    &secondAt,
+	}))
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+	entries := []*domain.ColdStoreEntry{}
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	}))
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{
+			Type:         domain.ColdStoreEntryTypePut,
+			Key:          "foo",
+			Value:        "http://Buz.qux",
+			CanonicalURL: "http://buz.qux/",
+			UpdatedAt:    &secondAt,
+			Versions: []*domain.URLVersion{
+				{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: firstAt},
+				{Version: 2, OriginalURL: "http://bar.buz", ReplacedAt: secondAt},
+			},
+		},
+	}, entries)
+}
+
+func TestFileStore_Compact_ReplaysOldJournalOverNewSnapshot(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	updatedAt := time.Unix(0, time.Now().UnixNano())
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Compact())
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypeUpdate,
+		Key:       "foo",
+		Value:     "http://bar.buz",
+		UpdatedAt: &updatedAt,
+		Version:   1,
+	}))
+	journal, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(t, store.Compact())
+
+	// Act.
+	// The process stopped after the new snapshot was renamed, but before the journal was cut.
+	require.NoError(t, os.WriteFile(filePath, journal, 0o600))
+	merger := newColdStoreEntryMerger()
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(merger.add))
+
+	// Assert.
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{
+			Type:      domain.ColdStoreEntryTypePut,
+			Key:       "foo",
+			Value:     "http://bar.buz",
+			UpdatedAt: &updatedAt,
+			Versions: []*domain.URLVersion{
+				{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: updatedAt},
+			},
+		},
+	}, merger.entries())
+}
+
+func TestFileStore_Compact_KeepsCollections(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	createdAt := time.Unix(0, time.Now().UnixNano())
+	kept := &domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypeCollection,
+		Key:       uuid.NewString(),
+		Value:     "Foo",
+		CreatedAt: &createdAt,
+		Keys:      []string{"bar", "foo"},
+	}
+	deleted := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection, Key: uuid.NewString(), Value: "Bar", CreatedAt: &createdAt}
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"}))
+	require.NoError(t, store.Save(deleted))
+	require.NoError(t, store.Save(kept))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePurge, Key: "foo"}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeDeleteCollection, Key: deleted.Key}))
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+	entries := []*domain.ColdStoreEntry{}
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	}))
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"},
+		{
+			Type:      domain.ColdStoreEntryTypeCollection,
+			Key:       kept.Key,
+			Value:     "Foo",
+			CreatedAt: &createdAt,
+			Keys:      []string{"bar"},
+		},
+	}, entries)
+}
+
+func TestFileStore_Compact_KeepsCampaigns(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	createdAt := time.Unix(0, time.Now().UnixNano())
+	keptID := uuid.New()
+	deletedID := uuid.New()
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{
+		Type:       domain.ColdStoreEntryTypeCampaign,
+		Key:        keptID.String(),
+		Value:      "Foo",
+		CreatedAt:  &createdAt,
+		Parameters: map[string]string{"utm_source": "foo"},
+	}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypeCampaign,
+		Key:       deletedID.String(),
+		Value:     "Bar",
+		CreatedAt: &createdAt,
+	}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", CampaignID: keptID}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", CampaignID: deletedID}))
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeDeleteCampaign, Key: deletedID.String()}))
+
+	// Act.
+	err := store.Compact()
+
+	// Assert.
+	require.NoError(t, err)
+	entries := []*domain.ColdStoreEntry{}
+	require.NoError(t, NewFileStore(configuration, mock.Logger).LoadAll(func(entry *domain.ColdStoreEntry) {
+		entries = append(entries, entry)
+	}))
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", CampaignID: keptID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"},
+		{
+			Type:       domain.ColdStoreEntryTypeCampaign,
+			Key:        keptID.String(),
+			Value:      "Foo",
+			CreatedAt:  &createdAt,
+			Parameters: map[string]string{"utm_source": "foo"},
+		},
+	}, entries)
+}
+
+func TestFileStore_LoadAll_CorruptedSnapshot(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	store := NewFileStore(configuration, mock.Logger)
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, store.Compact())
+
+	snapshot, err := os.ReadFile(filePath + ".snapshot")
+	require.NoError(t, err)
+	// Damage the first record of the first block.
+	snapshot[snapshotHeaderSize+snapshotBlockHeaderSize+2] ^= 0xff
+	require.NoError(t, os.WriteFile(filePath+".snapshot", snapshot, 0644))
+
+	// Act.
+	_, err = loadMerged(NewFileStore(configuration, mock.Logger))
+
+	// Assert.
+	require.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestFileStore_LoadAll_LongLine(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	value := "http://foo.bar/" + strings.Repeat("a", 1024*1024)
+	line := `{"type":"put","key":"foo","value":"` + value + `"}` + "\n"
+	require.NoError(t, os.WriteFile(filePath, []byte(line), 0644))
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath: filePath,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	store := NewFileStore(configuration, mock.Logger)
+
+	// Act.
+	entries, err := loadMerged(store)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: value},
+	}, entries)
+}
+
+func TestFileStore_Save_CompactsBySizeThreshold(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	filePath := path.Join(t.TempDir(), "store.jsonl")
+	configuration := &config.Configuration{
+		FileStore: &config.FileStoreConfiguration{
+			FilePath:                filePath,
+			CompactionSizeThreshold: 1,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	compacted := make(chan struct{})
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any()).Do(func(string, ...any) {
+		close(compacted)
+	})
+	store := NewFileStore(configuration, mock.Logger)
+
+	// Act.
+	require.NoError(t, store.Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+
+	// Assert.
+	select {
+	case <-compacted:
+	case <-time.After(time.Second):
+		require.Fail(t, "compaction was not started")
+	}
+}
+
+// loadMerged loads all entries and merges them into one per key.
+func loadMerged(store ColdStore) ([]*domain.ColdStoreEntry, error) {
+	merger := newColdStoreEntryMerger()
+	err := store.LoadAll(merger.add)
+	return merger.entries(), err
+}
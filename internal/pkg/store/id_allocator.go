@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aleffnull/shortener/internal/config"
+)
+
+// Sequence values leased at once when the block size is not configured.
+const defaultKeyBlockSize = 1000
+
+// leaseFunc reserves size consecutive sequence values and returns the first of them.
+// Reserved values are never returned again, even if they were not used.
+type leaseFunc func(ctx context.Context, size uint64) (uint64, error)
+
+// idAllocator hands out sequence values from a block leased from the store,
+// so the store is only involved once per block.
+type idAllocator struct {
+	mutex     sync.Mutex
+	lease     leaseFunc
+	blockSize uint64
+	// The next value to hand out and the end of the leased block, guarded by mutex.
+	next uint64
+	end  uint64
+}
+
+func newIDAllocator(configuration *config.KeyGeneratorConfiguration, lease leaseFunc) *idAllocator {
+	blockSize := uint64(defaultKeyBlockSize)
+	if configuration != nil && configuration.BlockSize > 0 {
+		blockSize = uint64(configuration.BlockSize)
+	}
+
+	return &idAllocator{
+		lease:     lease,
+		blockSize: blockSize,
+	}
+}
+
+func (a *idAllocator) allocate(ctx context.Context) (uint64, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.next == a.end {
+		start, err := a.lease(ctx, a.blockSize)
+		if err != nil {
+			return 0, fmt.Errorf("idAllocator.allocate, lease failed: %w", err)
+		}
+
+		a.next = start
+		a.end = start + a.blockSize
+	}
+
+	value := a.next
+	a.next++
+
+	return value, nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aleffnull/shortener/internal/config"
+)
+
+func TestIDAllocator_Allocate(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	leases := []uint64{}
+	next := uint64(1)
+	allocator := newIDAllocator(&config.KeyGeneratorConfiguration{BlockSize: 3}, func(_ context.Context, size uint64) (uint64, error) {
+		leases = append(leases, size)
+		start := next
+		next += size
+		return start, nil
+	})
+
+	// Act.
+	values := []uint64{}
+	for range 7 {
+		value, err := allocator.allocate(context.Background())
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+
+	// Assert.
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7}, values)
+	require.Equal(t, []uint64{3, 3, 3}, leases)
+}
+
+func TestIDAllocator_Allocate_DefaultBlockSize(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	var leasedSize uint64
+	allocator := newIDAllocator(nil, func(_ context.Context, size uint64) (uint64, error) {
+		leasedSize = size
+		return 1, nil
+	})
+
+	// Act.
+	_, err := allocator.allocate(context.Background())
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, uint64(defaultKeyBlockSize), leasedSize)
+}
+
+func TestIDAllocator_Allocate_LeaseError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	fail := true
+	allocator := newIDAllocator(&config.KeyGeneratorConfiguration{BlockSize: 2}, func(context.Context, uint64) (uint64, error) {
+		if fail {
+			return 0, assert.AnError
+		}
+		return 10, nil
+	})
+
+	// Act.
+	_, err := allocator.allocate(context.Background())
+	fail = false
+	value, retryErr := allocator.allocate(context.Background())
+
+	// Assert.
+	require.ErrorIs(t, err, assert.AnError)
+	require.NoError(t, retryErr)
+	require.Equal(t, uint64(10), value)
+}
+
+func TestIDAllocator_Allocate_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	var next uint64
+	allocator := newIDAllocator(&config.KeyGeneratorConfiguration{BlockSize: 7}, func(_ context.Context, size uint64) (uint64, error) {
+		start := next
+		next += size
+		return start, nil
+	})
+
+	// Act.
+	mutex := sync.Mutex{}
+	values := make(map[uint64]struct{})
+	wg := sync.WaitGroup{}
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				value, err := allocator.allocate(context.Background())
+				assert.NoError(t, err)
+				mutex.Lock()
+				values[value] = struct{}{}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert.
+	require.Len(t, values, 1000)
+}
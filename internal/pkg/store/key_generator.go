@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/aleffnull/shortener/internal/config"
+)
+
+const (
+	// Legacy alphabet of random keys, kept for them to look the same.
+	defaultRandomKeyAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// Base62, the default alphabet of the other generators.
+	defaultKeyAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// KeyGenerator produces candidate keys for a value. Attempt is the number of candidates
+// already rejected because the key was taken, length is the desired key length.
+type KeyGenerator interface {
+	Generate(ctx context.Context, value string, length, attempt int) (string, error)
+}
+
+// sequenceFunc returns the next value of a sequence that is never repeated.
+type sequenceFunc func(ctx context.Context) (uint64, error)
+
+// NewKeyGenerator creates the generator selected in configuration, sequence based generators
+// take values from the given sequence. Nil configuration means the random generator.
+func NewKeyGenerator(configuration *config.KeyGeneratorConfiguration, sequence sequenceFunc) KeyGenerator {
+	if configuration == nil {
+		return &randomKeyGenerator{alphabet: defaultRandomKeyAlphabet}
+	}
+
+	alphabet := configuration.Alphabet
+	if len(alphabet) == 0 {
+		alphabet = defaultKeyAlphabet
+	}
+
+	switch configuration.Type {
+	case config.KeyGeneratorSequence:
+		return &sequenceKeyGenerator{alphabet: alphabet, sequence: sequence}
+	case config.KeyGeneratorHash:
+		return &hashKeyGenerator{alphabet: alphabet}
+	case config.KeyGeneratorObfuscated:
+		return &obfuscatedKeyGenerator{alphabet: alphabet, sequence: sequence, secret: hashSecret(configuration.Secret)}
+	default:
+		if len(configuration.Alphabet) == 0 {
+			alphabet = defaultRandomKeyAlphabet
+		}
+
+		return &randomKeyGenerator{alphabet: alphabet}
+	}
+}
+
+type randomKeyGenerator struct {
+	alphabet string
+}
+
+func (g *randomKeyGenerator) Generate(_ context.Context, _ string, length, _ int) (string, error) {
+	key := make([]byte, length)
+	for i := range key {
+		key[i] = g.alphabet[rand.IntN(len(g.alphabet))]
+	}
+
+	return string(key), nil
+}
+
+type sequenceKeyGenerator struct {
+	alphabet string
+	sequence sequenceFunc
+}
+
+func (g *sequenceKeyGenerator) Generate(ctx context.Context, _ string, _, _ int) (string, error) {
+	value, err := g.sequence(ctx)
+	if err != nil {
+		return "", fmt.Errorf("sequenceKeyGenerator.Generate, sequence failed: %w", err)
+	}
+
+	// Keys grow with the sequence, the length setting is not used.
+	return encodeNumber(value, g.alphabet, 1), nil
+}
+
+type hashKeyGenerator struct {
+	alphabet string
+}
+
+func (g *hashKeyGenerator) Generate(_ context.Context, value string, length, attempt int) (string, error) {
+	// Taken keys are resolved by hashing the value again with the attempt number.
+	seed := sha256.Sum256(fmt.Appendf(nil, "%v\x00%v", attempt, value))
+
+	// Bytes above the largest multiple of the alphabet size are skipped, so all characters are equally likely.
+	limit := 256 - 256%len(g.alphabet)
+	key := make([]byte, 0, length)
+	for block := uint64(0); len(key) < length; block++ {
+		digest := sha256.Sum256(binary.BigEndian.AppendUint64(seed[:], block))
+		for _, b := range digest {
+			if int(b) >= limit {
+				continue
+			}
+
+			key = append(key, g.alphabet[int(b)%len(g.alphabet)])
+			if len(key) == length {
+				break
+			}
+		}
+	}
+
+	return string(key), nil
+}
+
+// isLeasedKeyGenerator tells whether the generator takes its keys from the leased sequence.
+// Such keys are never issued twice, only an alias or a key of a former generator can hold one.
+func isLeasedKeyGenerator(generator KeyGenerator) bool {
+	switch generator.(type) {
+	case *sequenceKeyGenerator, *obfuscatedKeyGenerator:
+		return true
+	default:
+		return false
+	}
+}
+
+// obfuscatedKeyGenerator maps sequence values to keys of a fixed length with a secret
+// bijection, so keys never collide until the key space of the length is exhausted.
+type obfuscatedKeyGenerator struct {
+	alphabet string
+	sequence sequenceFunc
+	secret   uint64
+}
+
+func (g *obfuscatedKeyGenerator) Generate(ctx context.Context, _ string, length, _ int) (string, error) {
+	value, err := g.sequence(ctx)
+	if err != nil {
+		return "", fmt.Errorf("obfuscatedKeyGenerator.Generate, sequence failed: %w", err)
+	}
+
+	// The bijection works on the widest bit range that fits into keys of the length. Once the sequence
+	// passes that range, keys grow by a character, so a longer key never repeats an issued one.
+	keyBits := keySpaceBits(len(g.alphabet), length)
+	for keyBits < 64 && value >= 1<<keyBits {
+		length++
+		keyBits = keySpaceBits(len(g.alphabet), length)
+	}
+
+	return encodeNumber(permute(value, keyBits, g.secret), g.alphabet, length), nil
+}
+
+// permute is a bijection on numbers of the given bit width, the value must fit into the width.
+func permute(value uint64, width int, secret uint64) uint64 {
+	mask := uint64(math.MaxUint64)
+	if width < 64 {
+		mask = 1<<width - 1
+	}
+
+	shift := (width + 1) / 2
+	value = (value ^ secret) & mask
+	// Multiplication by an odd number and xor with own high bits are both reversible.
+	value = (value * 0x9e3779b97f4a7c15) & mask
+	value ^= value >> shift
+	value = (value * 0xbf58476d1ce4e5b9) & mask
+	value ^= value >> shift
+
+	return value
+}
+
+// keySpaceBits returns the largest bit width whose numbers all fit into keys of the length.
+func keySpaceBits(base, length int) int {
+	space := uint64(1)
+	for range length {
+		high, low := bits.Mul64(space, uint64(base))
+		if high != 0 {
+			return 64
+		}
+
+		space = low
+	}
+
+	return bits.Len64(space) - 1
+}
+
+// encodeNumber writes the value in the alphabet as a positional number, left-padded to the minimum length.
+func encodeNumber(value uint64, alphabet string, minLength int) string {
+	base := uint64(len(alphabet))
+	key := []byte{}
+	for value > 0 || len(key) < minLength {
+		key = append(key, alphabet[value%base])
+		value /= base
+	}
+
+	for i, j := 0, len(key)-1; i < j; i, j = i+1, j-1 {
+		key[i], key[j] = key[j], key[i]
+	}
+
+	return string(key)
+}
+
+func hashSecret(secret string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(secret))
+	return hash.Sum64()
+}
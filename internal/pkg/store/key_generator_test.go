@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aleffnull/shortener/internal/config"
+)
+
+func TestNewKeyGenerator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		configuration *config.KeyGeneratorConfiguration
+		want          KeyGenerator
+	}{
+		{
+			name: "WHEN no configuration THEN random with legacy alphabet",
+			want: &randomKeyGenerator{alphabet: defaultRandomKeyAlphabet},
+		},
+		{
+			name:          "WHEN random THEN legacy alphabet",
+			configuration: &config.KeyGeneratorConfiguration{Type: config.KeyGeneratorRandom},
+			want:          &randomKeyGenerator{alphabet: defaultRandomKeyAlphabet},
+		},
+		{
+			name:          "WHEN random with alphabet THEN alphabet",
+			configuration: &config.KeyGeneratorConfiguration{Type: config.KeyGeneratorRandom, Alphabet: "abc"},
+			want:          &randomKeyGenerator{alphabet: "abc"},
+		},
+		{
+			name:          "WHEN hash THEN base62",
+			configuration: &config.KeyGeneratorConfiguration{Type: config.KeyGeneratorHash},
+			want:          &hashKeyGenerator{alphabet: defaultKeyAlphabet},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act.
+			generator := NewKeyGenerator(tt.configuration, nil)
+
+			// Assert.
+			require.Equal(t, tt.want, generator)
+		})
+	}
+}
+
+func TestRandomKeyGenerator_Generate(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	generator := &randomKeyGenerator{alphabet: "xyz"}
+
+	// Act.
+	key, err := generator.Generate(context.Background(), "http://foo.bar", 10, 0)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Len(t, key, 10)
+	require.Empty(t, strings.Trim(key, "xyz"))
+}
+
+func TestSequenceKeyGenerator_Generate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		sequence  sequenceFunc
+		want      []string
+		wantError bool
+	}{
+		{
+			name: "WHEN sequence error THEN error",
+			sequence: func(context.Context) (uint64, error) {
+				return 0, assert.AnError
+			},
+			want:      []string{""},
+			wantError: true,
+		},
+		{
+			name:     "WHEN sequence values THEN encoded in base62",
+			sequence: sequenceOf(0, 61, 62, 3843, 3844),
+			want:     []string{"0", "Z", "10", "ZZ", "100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			generator := &sequenceKeyGenerator{alphabet: defaultKeyAlphabet, sequence: tt.sequence}
+
+			for _, want := range tt.want {
+				// Act.
+				key, err := generator.Generate(context.Background(), "http://foo.bar", 8, 0)
+
+				// Assert.
+				require.Equal(t, want, key)
+				if tt.wantError {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+		})
+	}
+}
+
+func TestHashKeyGenerator_Generate(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	generator := &hashKeyGenerator{alphabet: defaultKeyAlphabet}
+	ctx := context.Background()
+
+	// Act.
+	key, err := generator.Generate(ctx, "http://foo.bar", 8, 0)
+	require.NoError(t, err)
+	sameKey, err := generator.Generate(ctx, "http://foo.bar", 8, 0)
+	require.NoError(t, err)
+	retryKey, err := generator.Generate(ctx, "http://foo.bar", 8, 1)
+	require.NoError(t, err)
+	otherKey, err := generator.Generate(ctx, "http://bar.buz", 8, 0)
+	require.NoError(t, err)
+	longKey, err := generator.Generate(ctx, "http://foo.bar", 100, 0)
+	require.NoError(t, err)
+
+	// Assert.
+	require.Len(t, key, 8)
+	require.Equal(t, key, sameKey)
+	require.NotEqual(t, key, retryKey)
+	require.NotEqual(t, key, otherKey)
+	require.Len(t, longKey, 100)
+	require.True(t, strings.HasPrefix(longKey, key))
+}
+
+func TestObfuscatedKeyGenerator_Generate(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	const count = 10000
+	values := make([]uint64, count)
+	for i := range values {
+		values[i] = uint64(i + 1)
+	}
+	generator := &obfuscatedKeyGenerator{
+		alphabet: "23456789abcdefghijkmnpqrstuvwxyz",
+		sequence: sequenceOf(values...),
+		secret:   hashSecret("secret"),
+	}
+
+	// Act.
+	keys := make(map[string]struct{}, count)
+	previousKey := ""
+	for range count {
+		key, err := generator.Generate(context.Background(), "", 4, 0)
+		require.NoError(t, err)
+
+		// Assert.
+		require.Len(t, key, 4)
+		require.NotEqual(t, previousKey, key)
+		require.Empty(t, strings.Trim(key, generator.alphabet))
+		keys[key] = struct{}{}
+		previousKey = key
+	}
+
+	// Sequential values never map to the same key.
+	require.Len(t, keys, count)
+}
+
+func TestObfuscatedKeyGenerator_Generate_GrowsPastKeySpace(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	// Keys of two binary digits hold two bits, the last two values do not fit.
+	const count = 6
+	values := make([]uint64, count)
+	for i := range values {
+		values[i] = uint64(i)
+	}
+	generator := &obfuscatedKeyGenerator{
+		alphabet: "01",
+		sequence: sequenceOf(values...),
+		secret:   hashSecret("secret"),
+	}
+
+	// Act.
+	keys := make(map[string]struct{}, count)
+	lengths := []int{}
+	for range count {
+		key, err := generator.Generate(context.Background(), "", 2, 0)
+		require.NoError(t, err)
+		keys[key] = struct{}{}
+		lengths = append(lengths, len(key))
+	}
+
+	// Assert.
+	require.Len(t, keys, count)
+	require.Equal(t, []int{2, 2, 2, 2, 3, 3}, lengths)
+}
+
+func Test_permute(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	const width = 10
+	seen := make(map[uint64]struct{}, 1<<width)
+
+	// Act.
+	for value := range uint64(1 << width) {
+		permuted := permute(value, width, 12345)
+
+		// Assert.
+		require.Less(t, permuted, uint64(1<<width))
+		seen[permuted] = struct{}{}
+	}
+
+	require.Len(t, seen, 1<<width)
+}
+
+func Test_keySpaceBits(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 5, keySpaceBits(62, 1))
+	require.Equal(t, 47, keySpaceBits(62, 8))
+	require.Equal(t, 64, keySpaceBits(62, 20))
+}
+
+func sequenceOf(values ...uint64) sequenceFunc {
+	return func(context.Context) (uint64, error) {
+		value := values[0]
+		values = values[1:]
+		return value, nil
+	}
+}
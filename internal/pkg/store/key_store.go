@@ -4,39 +4,88 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand/v2"
+	"strings"
 
 	"github.com/aleffnull/shortener/internal/config"
+	"github.com/aleffnull/shortener/internal/domain"
 )
 
-const (
-	alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-)
+// Word separators aliases may contain besides the key alphabet.
+const aliasSeparators = "-_"
+
+// Aliases that clash with service routes.
+var reservedAliases = []string{
+	"api",
+	"debug",
+	"ping",
+}
 
 type saverFunc func(ctx context.Context, key, value string) (bool, error)
 
 type keyStore struct {
-	configuration *config.KeyStoreConfiguration
+	configuration   *config.KeyStoreConfiguration
+	generator       KeyGenerator
+	aliasCharacters string
+}
+
+// getAliasCharacters returns the characters of the configured key alphabet and word separators,
+// so aliases and generated keys look alike.
+func getAliasCharacters(configuration *config.KeyGeneratorConfiguration) string {
+	if configuration == nil || len(configuration.Alphabet) == 0 {
+		return defaultKeyAlphabet + aliasSeparators
+	}
+
+	return configuration.Alphabet + aliasSeparators
+}
+
+func (s *keyStore) saveWithAlias(ctx context.Context, alias, value string, saver saverFunc) (string, error) {
+	if len(alias) == 0 {
+		return s.saveWithUniqueKey(ctx, value, saver)
+	}
+
+	if err := s.validateAlias(alias); err != nil {
+		return "", err
+	}
+
+	exists, err := saver(ctx, alias, value)
+	if err != nil {
+		return "", fmt.Errorf("saveWithAlias, saver failed: %w", err)
+	}
+
+	if exists {
+		return "", NewAliasConflictError(alias)
+	}
+
+	return alias, nil
 }
 
 func (s *keyStore) saveWithUniqueKey(ctx context.Context, value string, saver saverFunc) (string, error) {
 	length := s.configuration.KeyLength
 	i := 0
+	attempt := 0
 
 	for length <= s.configuration.KeyMaxLength {
-		key := randomString(length)
-		exists, err := saver(ctx, key, value)
+		key, err := s.generator.Generate(ctx, value, length, attempt)
 		if err != nil {
-			return "", fmt.Errorf("saveWithUniqueKey, saver failed: %w", err)
+			return "", fmt.Errorf("saveWithUniqueKey, generator.Generate failed: %w", err)
+		}
+
+		exists := isReservedAlias(key)
+		if !exists {
+			exists, err = saver(ctx, key, value)
+			if err != nil {
+				return "", fmt.Errorf("saveWithUniqueKey, saver failed: %w", err)
+			}
 		}
 
 		if !exists {
 			return key, nil
 		}
 
+		attempt++
 		i++
 		if i >= s.configuration.KeyMaxIterations {
-			length *= 2
+			length = s.nextKeyLength(length)
 			i = 0
 		}
 	}
@@ -44,11 +93,50 @@ func (s *keyStore) saveWithUniqueKey(ctx context.Context, value string, saver sa
 	return "", errors.New("failed to generate unique key")
 }
 
-func randomString(length int) string {
-	var arr = make([]byte, length)
-	for i := range arr {
-		arr[i] = alphabet[rand.IntN(len(alphabet))]
+// nextKeyLength returns the key length to try after KeyMaxIterations taken keys. Random and hash keys
+// collide with each other, so they get longer. Leased keys are skipped with the next sequence value
+// at the same length, and running out of attempts means the aliases hold too many of them.
+func (s *keyStore) nextKeyLength(length int) int {
+	if isLeasedKeyGenerator(s.generator) {
+		return s.configuration.KeyMaxLength + 1
+	}
+
+	return length * 2
+}
+
+func (s *keyStore) validateAlias(alias string) error {
+	if len(alias) > s.configuration.KeyMaxLength {
+		return fmt.Errorf("%w: alias is longer than %v characters", ErrInvalidAlias, s.configuration.KeyMaxLength)
+	}
+
+	for _, ch := range alias {
+		if !strings.ContainsRune(s.aliasCharacters, ch) {
+			return fmt.Errorf("%w: character '%c' is not allowed", ErrInvalidAlias, ch)
+		}
+	}
+
+	if isReservedAlias(alias) {
+		return fmt.Errorf("%w: '%v' is reserved", ErrInvalidAlias, alias)
+	}
+
+	return nil
+}
+
+func isReservedAlias(key string) bool {
+	for _, reserved := range reservedAliases {
+		if strings.EqualFold(key, reserved) {
+			return true
+		}
 	}
 
-	return string(arr)
+	return false
+}
+
+// newInvalidBatchItem reports a batch item that is not saved, with the error as the reason.
+func newInvalidBatchItem(correlationID string, err error) *domain.BatchResponseItem {
+	return &domain.BatchResponseItem{
+		CorrelationID: correlationID,
+		Status:        domain.BatchItemStatusInvalid,
+		Reason:        err.Error(),
+	}
 }
@@ -31,7 +31,7 @@ func BenchmarkRandomString(b *testing.B) {
 func randomStringByBuffer(length int) string {
 	var arr = make([]byte, length)
 	for i := range arr {
-		arr[i] = alphabet[rand.IntN(len(alphabet))]
+		arr[i] = defaultRandomKeyAlphabet[rand.IntN(len(defaultRandomKeyAlphabet))]
 	}
 
 	return string(arr)
@@ -40,7 +40,7 @@ func randomStringByBuffer(length int) string {
 func randomStringByConcatenation(length int) string {
 	result := ""
 	for range length {
-		result += string(alphabet[rand.IntN(len(alphabet))])
+		result += string(defaultRandomKeyAlphabet[rand.IntN(len(defaultRandomKeyAlphabet))])
 	}
 
 	return result
@@ -49,7 +49,7 @@ func randomStringByConcatenation(length int) string {
 func randomStringByStringBuilder(length int) string {
 	sb := strings.Builder{}
 	for range length {
-		sb.WriteByte(alphabet[rand.IntN(len(alphabet))])
+		sb.WriteByte(defaultRandomKeyAlphabet[rand.IntN(len(defaultRandomKeyAlphabet))])
 	}
 
 	return sb.String()
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/aleffnull/shortener/internal/config"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -14,6 +15,7 @@ func Test_saveWithUniqueKey(t *testing.T) {
 
 	type args struct {
 		configuration *config.KeyStoreConfiguration
+		generator     KeyGenerator
 		value         string
 		saver         saverFunc
 	}
@@ -84,6 +86,70 @@ func Test_saveWithUniqueKey(t *testing.T) {
 			},
 			wantKeyLength: 2,
 		},
+		{
+			name: "WHEN leased key exists THEN next value with the same length",
+			args: &args{
+				configuration: &config.KeyStoreConfiguration{
+					KeyLength:        2,
+					KeyMaxLength:     10,
+					KeyMaxIterations: 2,
+				},
+				generator: &obfuscatedKeyGenerator{
+					alphabet: defaultKeyAlphabet,
+					sequence: sequenceOf(1, 2),
+				},
+				value: "foo",
+				saver: func() saverFunc {
+					calls := 0
+					return func(_ context.Context, _ string, _ string) (bool, error) {
+						calls++
+						return calls == 1, nil
+					}
+				}(),
+			},
+			wantKeyLength: 2,
+		},
+		{
+			name: "WHEN leased keys always exist THEN error without longer keys",
+			args: &args{
+				configuration: &config.KeyStoreConfiguration{
+					KeyLength:        2,
+					KeyMaxLength:     10,
+					KeyMaxIterations: 2,
+				},
+				generator: &obfuscatedKeyGenerator{
+					alphabet: defaultKeyAlphabet,
+					sequence: sequenceOf(1, 2),
+				},
+				value: "foo",
+				saver: func(_ context.Context, key string, _ string) (bool, error) {
+					require.Len(t, key, 2)
+					return true, nil
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "WHEN generated key is reserved THEN skipped",
+			args: &args{
+				configuration: &config.KeyStoreConfiguration{
+					KeyLength:        3,
+					KeyMaxLength:     3,
+					KeyMaxIterations: 2,
+				},
+				generator: &sequenceKeyGenerator{
+					// 11 is "api", 12 is "aap".
+					alphabet: "pai",
+					sequence: sequenceOf(11, 12),
+				},
+				value: "foo",
+				saver: func(_ context.Context, key string, _ string) (bool, error) {
+					require.NotEqual(t, "api", key)
+					return false, nil
+				},
+			},
+			wantKeyLength: 3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,6 +158,7 @@ func Test_saveWithUniqueKey(t *testing.T) {
 
 			keyStore := &keyStore{
 				configuration: tt.args.configuration,
+				generator:     lo.Ternary(tt.args.generator == nil, NewKeyGenerator(nil, nil), tt.args.generator),
 			}
 
 			key, err := keyStore.saveWithUniqueKey(context.Background(), tt.args.value, tt.args.saver)
@@ -106,9 +173,160 @@ func Test_saveWithUniqueKey(t *testing.T) {
 	}
 }
 
-func Test_randomString(t *testing.T) {
+func Test_saveWithAlias(t *testing.T) {
+	t.Parallel()
+
+	configuration := &config.KeyStoreConfiguration{
+		KeyLength:        8,
+		KeyMaxLength:     16,
+		KeyMaxIterations: 1,
+	}
+
+	tests := []struct {
+		name       string
+		alias      string
+		saver      saverFunc
+		want       string
+		checkError func(err error)
+	}{
+		{
+			name:  "WHEN no alias THEN random key",
+			alias: "",
+			saver: func(_ context.Context, _ string, _ string) (bool, error) {
+				return false, nil
+			},
+		},
+		{
+			name:  "WHEN alias is too long THEN invalid alias error",
+			alias: "abcdefghijklmnopq",
+			checkError: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidAlias)
+			},
+		},
+		{
+			name:  "WHEN alias has wrong characters THEN invalid alias error",
+			alias: "spring/sale",
+			checkError: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidAlias)
+			},
+		},
+		{
+			name:  "WHEN alias is reserved THEN invalid alias error",
+			alias: "API",
+			checkError: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidAlias)
+			},
+		},
+		{
+			name:  "WHEN saver error THEN error",
+			alias: "spring-sale",
+			saver: func(_ context.Context, _ string, _ string) (bool, error) {
+				return false, assert.AnError
+			},
+			checkError: func(err error) {
+				require.ErrorIs(t, err, assert.AnError)
+			},
+		},
+		{
+			name:  "WHEN alias exists THEN alias conflict error",
+			alias: "spring-sale",
+			saver: func(_ context.Context, _ string, _ string) (bool, error) {
+				return true, nil
+			},
+			checkError: func(err error) {
+				var aliasConflictError *AliasConflictError
+				require.ErrorAs(t, err, &aliasConflictError)
+				require.Equal(t, "spring-sale", aliasConflictError.Alias)
+			},
+		},
+		{
+			name:  "WHEN alias is free THEN alias is key",
+			alias: "spring-sale",
+			saver: func(_ context.Context, key string, _ string) (bool, error) {
+				require.Equal(t, "spring-sale", key)
+				return false, nil
+			},
+			want: "spring-sale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keyStore := &keyStore{
+				configuration:   configuration,
+				generator:       NewKeyGenerator(nil, nil),
+				aliasCharacters: getAliasCharacters(nil),
+			}
+
+			key, err := keyStore.saveWithAlias(context.Background(), tt.alias, "foo", tt.saver)
+			if tt.checkError != nil {
+				tt.checkError(err)
+				require.Empty(t, key)
+				return
+			}
+
+			require.NoError(t, err)
+			if len(tt.want) == 0 {
+				require.Len(t, key, configuration.KeyLength)
+			} else {
+				require.Equal(t, tt.want, key)
+			}
+		})
+	}
+}
+
+func TestKeyStore_validateAlias_ConfiguredAlphabet(t *testing.T) {
 	t.Parallel()
 
-	str := randomString(10)
-	require.Len(t, str, 10)
+	tests := []struct {
+		name      string
+		alias     string
+		wantError bool
+	}{
+		{
+			name:  "WHEN alias uses alphabet and separators THEN ok",
+			alias: "summer-25",
+		},
+		{
+			name:      "WHEN character is not in alphabet THEN invalid alias error",
+			alias:     "spring-sale",
+			wantError: true,
+		},
+		{
+			name:      "WHEN default alphabet character THEN invalid alias error",
+			alias:     "Summer",
+			wantError: true,
+		},
+		{
+			name:  "WHEN alphabet character outside default alphabet THEN ok",
+			alias: "summer~25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			keyStore := &keyStore{
+				configuration: &config.KeyStoreConfiguration{KeyMaxLength: 16},
+				// No ambiguous characters, 'l' is left out.
+				aliasCharacters: getAliasCharacters(&config.KeyGeneratorConfiguration{
+					Alphabet: "23456789abcdefghijkmnpqrstuvwxyz~",
+				}),
+			}
+
+			// Act.
+			err := keyStore.validateAlias(tt.alias)
+
+			// Assert.
+			if tt.wantError {
+				require.ErrorIs(t, err, ErrInvalidAlias)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
 }
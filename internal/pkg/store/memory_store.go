@@ -1,9 +1,18 @@
 package store
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 
@@ -12,14 +21,64 @@ import (
 	"github.com/aleffnull/shortener/internal/pkg/logger"
 )
 
+type memoryItem struct {
+	value string
+	// Deduplicated form of the value, empty when it equals the value.
+	canonicalValue string
+	// Deduplication scope the item was saved with, see dedupKey.
+	dedupScope     string
+	expiresAt      *time.Time
+	userID         uuid.UUID
+	isDeleted      bool
+	deletedAt      *time.Time
+	createdAt      time.Time
+	updatedAt      time.Time
+	lastAccessedAt *time.Time
+	// Previous values, the oldest first.
+	versions []*domain.URLVersion
+	title    string
+	tags     []string
+	notes    string
+	// Zero means the deployment default.
+	redirectCode int
+	passthrough  bool
+	campaignID   uuid.UUID
+	rules        []*domain.RedirectRule
+	variants     []*domain.URLVariant
+}
+
+type memoryCollection struct {
+	userID    uuid.UUID
+	name      string
+	createdAt time.Time
+	// Keys of the URLs, sorted.
+	keys []string
+}
+
+type memoryCampaign struct {
+	userID     uuid.UUID
+	name       string
+	createdAt  time.Time
+	parameters map[string]string
+}
+
 type MemoryStore struct {
 	keyStore
 	coldStore     ColdStore
 	configuration *config.MemoryStoreConfiguration
 	logger        logger.Logger
-	keyToValueMap map[string]string
+	keyToItemMap  map[string]*memoryItem
+	// Keys of deduplicated values, see dedupKey.
 	valueToKeyMap map[string]string
-	mutex         *sync.RWMutex
+	keyToClicks   map[string][]*domain.Click
+	// Collections by ID, keys of removed URLs are removed from them.
+	idToCollection map[uuid.UUID]*memoryCollection
+	// Campaigns by ID, URLs of removed campaigns are left without campaign.
+	idToCampaign map[uuid.UUID]*memoryCampaign
+	dedupScope   string
+	// Last leased value of the key generator sequence, guarded by mutex.
+	keySequence uint64
+	mutex       *sync.RWMutex
 }
 
 var _ Store = (*MemoryStore)(nil)
@@ -27,104 +86,883 @@ var _ Store = (*MemoryStore)(nil)
 func NewMemoryStore(coldStore ColdStore, configuration *config.Configuration, logger logger.Logger) Store {
 	store := &MemoryStore{
 		keyStore: keyStore{
-			configuration: &configuration.MemoryStore.KeyStoreConfiguration,
+			configuration:   &configuration.MemoryStore.KeyStoreConfiguration,
+			aliasCharacters: getAliasCharacters(configuration.KeyGenerator),
 		},
-		coldStore:     coldStore,
-		configuration: configuration.MemoryStore,
-		logger:        logger,
-		keyToValueMap: make(map[string]string),
-		valueToKeyMap: make(map[string]string),
-		mutex:         &sync.RWMutex{},
+		coldStore:      coldStore,
+		configuration:  configuration.MemoryStore,
+		logger:         logger,
+		keyToItemMap:   make(map[string]*memoryItem),
+		valueToKeyMap:  make(map[string]string),
+		keyToClicks:    make(map[string][]*domain.Click),
+		idToCollection: make(map[uuid.UUID]*memoryCollection),
+		idToCampaign:   make(map[uuid.UUID]*memoryCampaign),
+		dedupScope:     configuration.DedupScope,
+		mutex:          &sync.RWMutex{},
 	}
+	allocator := newIDAllocator(configuration.KeyGenerator, store.leaseKeySequence)
+	store.generator = NewKeyGenerator(configuration.KeyGenerator, allocator.allocate)
 
 	return store
 }
 
 func (s *MemoryStore) Init() error {
-	entries, err := s.coldStore.LoadAll()
+	// Called only during startup, so no need for mutex locking.
+	now := time.Now()
+	err := s.coldStore.LoadAll(func(entry *domain.ColdStoreEntry) {
+		s.restoreEntry(entry, now)
+	})
 	if err != nil {
 		return fmt.Errorf("InitStorage, coldStorage.LoadAll failed: %w", err)
 	}
 
-	// Called only during startup, so no need for mutex locking.
-	for _, entry := range entries {
-		s.keyToValueMap[entry.Key] = entry.Value
-		s.valueToKeyMap[entry.Value] = entry.Key
+	s.logger.Infof("Loaded %v entries from cold storage", len(s.keyToItemMap))
+
+	// Expired URLs are not loaded, but their keys are still in the collections.
+	for _, collection := range s.idToCollection {
+		collection.keys = slices.DeleteFunc(collection.keys, func(key string) bool {
+			_, exists := s.keyToItemMap[key]
+			return !exists
+		})
 	}
 
-	s.logger.Infof("Loaded %v entries from cold storage", len(entries))
+	// Storage written before leasing has no sequence, values already used as keys are skipped as taken ones.
+	s.keySequence = max(s.keySequence, uint64(len(s.keyToItemMap)))
 
 	return nil
 }
 
+func (s *MemoryStore) Shutdown() {
+	if err := s.coldStore.Close(); err != nil {
+		s.logger.Errorf("MemoryStore.Shutdown, coldStore.Close failed: %v", err)
+	}
+}
+
 func (s *MemoryStore) CheckAvailability(context.Context) error {
 	return nil
 }
 
+func (s *MemoryStore) Compact(context.Context) error {
+	if err := s.coldStore.Compact(); err != nil {
+		return fmt.Errorf("MemoryStore.Compact, coldStore.Compact failed: %w", err)
+	}
+
+	return nil
+}
+
+// Export writes all live items as JSON lines with put entries, the same format the file store journal uses.
+func (s *MemoryStore) Export(_ context.Context, writer io.Writer) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	keys := slices.Sorted(maps.Keys(s.keyToItemMap))
+	encoder := json.NewEncoder(writer)
+	if s.keySequence > 0 {
+		err := encoder.Encode(&domain.ColdStoreEntry{
+			Type:     domain.ColdStoreEntryTypeSequence,
+			Sequence: s.keySequence,
+		})
+		if err != nil {
+			return fmt.Errorf("MemoryStore.Export, encoder.Encode failed: %w", err)
+		}
+	}
+
+	for _, key := range keys {
+		item := s.keyToItemMap[key]
+		err := encoder.Encode(&domain.ColdStoreEntry{
+			Type:           domain.ColdStoreEntryTypePut,
+			Key:            key,
+			Value:          item.value,
+			CanonicalURL:   item.canonicalValue,
+			DedupScope:     item.dedupScope,
+			ExpiresAt:      item.expiresAt,
+			UserID:         item.userID,
+			IsDeleted:      item.isDeleted,
+			DeletedAt:      item.deletedAt,
+			CreatedAt:      &item.createdAt,
+			UpdatedAt:      &item.updatedAt,
+			LastAccessedAt: item.lastAccessedAt,
+			Versions:       item.versions,
+			Title:          item.title,
+			Tags:           item.tags,
+			Notes:          item.notes,
+			RedirectCode:   item.redirectCode,
+			Passthrough:    item.passthrough,
+			CampaignID:     item.campaignID,
+			Rules:          item.rules,
+			Variants:       item.variants,
+		})
+		if err != nil {
+			return fmt.Errorf("MemoryStore.Export, encoder.Encode failed: %w", err)
+		}
+	}
+
+	ids := slices.SortedFunc(maps.Keys(s.idToCollection), func(a, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, id := range ids {
+		if err := encoder.Encode(newCollectionEntry(id, s.idToCollection[id])); err != nil {
+			return fmt.Errorf("MemoryStore.Export, encoder.Encode failed: %w", err)
+		}
+	}
+
+	ids = slices.SortedFunc(maps.Keys(s.idToCampaign), func(a, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	for _, id := range ids {
+		if err := encoder.Encode(newCampaignEntry(id, s.idToCampaign[id])); err != nil {
+			return fmt.Errorf("MemoryStore.Export, encoder.Encode failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (s *MemoryStore) Load(_ context.Context, key string) (*domain.URLItem, error) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	value, ok := s.keyToValueMap[key]
+	item, ok := s.keyToItemMap[key]
 	if !ok {
 		return nil, nil
 	}
 
-	return &domain.URLItem{
-		URL: value,
-	}, nil
+	urlItem := &domain.URLItem{
+		URL:            item.value,
+		UserID:         item.userID,
+		IsDeleted:      item.isDeleted,
+		DeletedAt:      item.deletedAt,
+		ExpiresAt:      item.expiresAt,
+		CreatedAt:      item.createdAt,
+		UpdatedAt:      item.updatedAt,
+		LastAccessedAt: item.lastAccessedAt,
+		RedirectCode:   item.redirectCode,
+		Passthrough:    item.passthrough,
+		Rules:          item.rules,
+		Variants:       item.variants,
+	}
+	if campaign, exists := s.idToCampaign[item.campaignID]; exists {
+		urlItem.CampaignParameters = maps.Clone(campaign.parameters)
+	}
+
+	return urlItem, nil
 }
 
-func (s *MemoryStore) LoadAllByUserID(context.Context, uuid.UUID) ([]*domain.KeyOriginalURLItem, error) {
-	return nil, nil
+func (s *MemoryStore) LoadAllByUserID(
+	_ context.Context,
+	userID uuid.UUID,
+	query *domain.UserURLsQuery,
+) ([]*domain.KeyOriginalURLItem, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var collection *memoryCollection
+	if query.Collection != uuid.Nil {
+		collection = s.idToCollection[query.Collection]
+		if collection == nil || collection.userID != userID {
+			return []*domain.KeyOriginalURLItem{}, nil
+		}
+	}
+
+	now := time.Now()
+	words := searchWords(query.Search)
+	items := []*domain.KeyOriginalURLItem{}
+	for key, item := range s.keyToItemMap {
+		if item.userID != userID || !matchesUserURLsStatus(item, query.Status, now) {
+			continue
+		}
+
+		if collection != nil {
+			if _, found := slices.BinarySearch(collection.keys, key); !found {
+				continue
+			}
+		}
+
+		if len(query.Tag) > 0 && !slices.Contains(item.tags, query.Tag) {
+			continue
+		}
+
+		if !matchesSearchWords(item, words) {
+			continue
+		}
+
+		listItem := &domain.KeyOriginalURLItem{
+			URLKey:         key,
+			OriginalURL:    item.value,
+			CreatedAt:      item.createdAt,
+			UpdatedAt:      item.updatedAt,
+			LastAccessedAt: item.lastAccessedAt,
+			Title:          item.title,
+			Tags:           item.tags,
+			Notes:          item.notes,
+		}
+		if query.After != nil && compareUserURLs(listItem, query.After, query) <= 0 {
+			continue
+		}
+
+		items = append(items, listItem)
+	}
+
+	slices.SortFunc(items, func(a, b *domain.KeyOriginalURLItem) int {
+		return compareUserURLs(a, &domain.UserURLsCursor{CreatedAt: b.CreatedAt, URLKey: b.URLKey}, query)
+	})
+
+	if query.Limit > 0 && len(items) > query.Limit {
+		items = items[:query.Limit]
+	}
+
+	return items, nil
 }
 
-func (s *MemoryStore) Save(ctx context.Context, value string, _ uuid.UUID) (string, error) {
+func (s *MemoryStore) Save(ctx context.Context, request *domain.SaveRequest, userID uuid.UUID) (string, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	return s.saveValue(ctx, value)
+	return s.saveValue(ctx, request, userID)
 }
 
-func (s *MemoryStore) SaveBatch(ctx context.Context, requestItems []*domain.BatchRequestItem, _ uuid.UUID) ([]*domain.BatchResponseItem, error) {
+// SaveBatch saves items one by one, an item that can't be saved doesn't stop the others.
+func (s *MemoryStore) SaveBatch(ctx context.Context, requestItems []*domain.BatchRequestItem, userID uuid.UUID) ([]*domain.BatchResponseItem, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
 	responseItems := make([]*domain.BatchResponseItem, 0, len(requestItems))
 	for _, requestItem := range requestItems {
-		key, err := s.saveValue(ctx, requestItem.OriginalURL)
-		if err != nil {
+		if len(requestItem.Alias) > 0 {
+			if err := s.validateAlias(requestItem.Alias); err != nil {
+				responseItems = append(responseItems, newInvalidBatchItem(requestItem.CorrelationID, err))
+				continue
+			}
+		}
+
+		key, err := s.saveValue(ctx, &requestItem.SaveRequest, userID)
+		var duplicateURLError *DuplicateURLError
+		var aliasConflictError *AliasConflictError
+		switch {
+		case err == nil:
+			responseItems = append(responseItems, &domain.BatchResponseItem{
+				CorrelationID: requestItem.CorrelationID,
+				Key:           key,
+				Status:        domain.BatchItemStatusCreated,
+			})
+		case errors.As(err, &duplicateURLError):
+			responseItems = append(responseItems, &domain.BatchResponseItem{
+				CorrelationID: requestItem.CorrelationID,
+				Key:           duplicateURLError.Key,
+				Status:        domain.BatchItemStatusExisting,
+			})
+		case errors.As(err, &aliasConflictError):
+			responseItems = append(responseItems, newInvalidBatchItem(requestItem.CorrelationID, aliasConflictError))
+		default:
 			return nil, fmt.Errorf("SaveBatch, saveValue failed: %w", err)
 		}
+	}
+
+	return responseItems, nil
+}
+
+// BackfillCanonicalURLs canonicalizes the values loaded without a canonical form. The journal doesn't tell
+// values stored before canonicalization from the ones that are canonical already, so all of them are tried.
+// The canonical forms are written to cold store with the next compaction.
+func (s *MemoryStore) BackfillCanonicalURLs(_ context.Context, canonicalize func(string) string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for _, key := range slices.Sorted(maps.Keys(s.keyToItemMap)) {
+		item := s.keyToItemMap[key]
+		if len(item.canonicalValue) > 0 {
+			continue
+		}
+
+		canonicalURL := canonicalize(item.value)
+		if canonicalURL == item.value {
+			continue
+		}
+
+		updated := *item
+		updated.canonicalValue = canonicalURL
+		if _, taken := s.valueToKeyMap[s.dedupKey(&updated)]; taken {
+			continue
+		}
+
+		s.removeDedupKey(key, item)
+		item.canonicalValue = canonicalURL
+		s.addDedupKey(key, item)
+		count++
+	}
+
+	return count, nil
+}
+
+func (s *MemoryStore) UpdateURL(_ context.Context, request *domain.UpdateRequest, userID uuid.UUID) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	item, exists := s.keyToItemMap[request.Key]
+	if !exists || item.userID != userID || item.isDeleted || (item.expiresAt != nil && !item.expiresAt.After(now)) {
+		return 0, nil
+	}
+
+	if item.value == request.OriginalURL {
+		return len(item.versions) + 1, nil
+	}
+
+	updated := *item
+	updated.value = request.OriginalURL
+	updated.canonicalValue = canonicalValue(&domain.SaveRequest{
+		OriginalURL:  request.OriginalURL,
+		CanonicalURL: request.CanonicalURL,
+	})
+	if existingKey, ok := s.valueToKeyMap[s.dedupKey(&updated)]; ok && existingKey != request.Key {
+		return 0, NewDuplicateURLError(existingKey, request.OriginalURL)
+	}
+
+	err := s.coldStore.Save(&domain.ColdStoreEntry{
+		Type:         domain.ColdStoreEntryTypeUpdate,
+		Key:          request.Key,
+		Value:        updated.value,
+		CanonicalURL: updated.canonicalValue,
+		UpdatedAt:    &now,
+		Version:      len(item.versions) + 1,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("MemoryStore.UpdateURL, coldStore.Save failed: %w", err)
+	}
+
+	s.updateValue(request.Key, item, updated.value, updated.canonicalValue, now)
+	return len(item.versions) + 1, nil
+}
+
+func (s *MemoryStore) LoadVersions(_ context.Context, key string) ([]*domain.URLVersion, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	versions := []*domain.URLVersion{}
+	if item, exists := s.keyToItemMap[key]; exists {
+		versions = append(versions, item.versions...)
+		slices.Reverse(versions)
+	}
+
+	return versions, nil
+}
+
+func (s *MemoryStore) UpdateVariants(
+	_ context.Context,
+	key string,
+	variants []*domain.URLVariant,
+	userID uuid.UUID,
+) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-		responseItems = append(responseItems, &domain.BatchResponseItem{
-			CorrelationID: requestItem.CorrelationID,
-			Key:           key,
+	now := time.Now()
+	item, exists := s.keyToItemMap[key]
+	if !exists || item.userID != userID || item.isDeleted || (item.expiresAt != nil && !item.expiresAt.After(now)) {
+		return false, nil
+	}
+
+	err := s.coldStore.Save(&domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypeUpdateVariants,
+		Key:       key,
+		UpdatedAt: &now,
+		Variants:  variants,
+	})
+	if err != nil {
+		return false, fmt.Errorf("MemoryStore.UpdateVariants, coldStore.Save failed: %w", err)
+	}
+
+	item.variants = variants
+	item.updatedAt = now
+	return true, nil
+}
+
+func (s *MemoryStore) DeleteBatch(_ context.Context, keys []string, userID uuid.UUID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		item, exists := s.keyToItemMap[key]
+		if !exists || item.userID != userID || item.isDeleted {
+			continue
+		}
+
+		err := s.coldStore.Save(&domain.ColdStoreEntry{
+			Type:      domain.ColdStoreEntryTypeDelete,
+			Key:       key,
+			DeletedAt: &now,
 		})
+		if err != nil {
+			return fmt.Errorf("MemoryStore.DeleteBatch, coldStore.Save failed: %w", err)
+		}
+
+		item.isDeleted = true
+		item.deletedAt = &now
+		item.updatedAt = now
 	}
 
-	return responseItems, nil
+	return nil
+}
+
+func (s *MemoryStore) RestoreBatch(_ context.Context, keys []string, userID uuid.UUID, deletedAfter time.Time) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	restored := []string{}
+	for _, key := range keys {
+		item, exists := s.keyToItemMap[key]
+		if !exists || item.userID != userID || !item.isDeleted || !item.deletedAt.After(deletedAfter) {
+			continue
+		}
+
+		err := s.coldStore.Save(&domain.ColdStoreEntry{
+			Type:      domain.ColdStoreEntryTypeRestore,
+			Key:       key,
+			UpdatedAt: &now,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("MemoryStore.RestoreBatch, coldStore.Save failed: %w", err)
+		}
+
+		item.isDeleted = false
+		item.deletedAt = nil
+		item.updatedAt = now
+		restored = append(restored, key)
+	}
+
+	return restored, nil
+}
+
+func (s *MemoryStore) PurgeDeleted(_ context.Context, deletedBefore time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for key, item := range s.keyToItemMap {
+		if !item.isDeleted || item.deletedAt.After(deletedBefore) {
+			continue
+		}
+
+		err := s.coldStore.Save(&domain.ColdStoreEntry{
+			Type: domain.ColdStoreEntryTypePurge,
+			Key:  key,
+		})
+		if err != nil {
+			return count, fmt.Errorf("MemoryStore.PurgeDeleted, coldStore.Save failed: %w", err)
+		}
+
+		delete(s.keyToItemMap, key)
+		s.removeDedupKey(key, item)
+		delete(s.keyToClicks, key)
+		s.removeFromCollections(key)
+		count++
+	}
+
+	return count, nil
+}
+
+func (s *MemoryStore) DeleteExpired(_ context.Context, expiredBefore time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for key, item := range s.keyToItemMap {
+		if item.expiresAt == nil || item.expiresAt.After(expiredBefore) {
+			continue
+		}
+
+		err := s.coldStore.Save(&domain.ColdStoreEntry{
+			Type: domain.ColdStoreEntryTypePurge,
+			Key:  key,
+		})
+		if err != nil {
+			return count, fmt.Errorf("MemoryStore.DeleteExpired, coldStore.Save failed: %w", err)
+		}
+
+		delete(s.keyToItemMap, key)
+		s.removeDedupKey(key, item)
+		delete(s.keyToClicks, key)
+		s.removeFromCollections(key)
+		count++
+	}
+
+	return count, nil
 }
 
-func (s *MemoryStore) DeleteBatch(context.Context, []string, uuid.UUID) error {
+func (s *MemoryStore) SaveCollection(_ context.Context, collection *domain.Collection) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.hasCollectionName(collection.UserID, collection.Name) {
+		return ErrCollectionExists
+	}
+
+	err := s.saveCollection(collection.ID, &memoryCollection{
+		userID:    collection.UserID,
+		name:      collection.Name,
+		createdAt: collection.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("MemoryStore.SaveCollection, saveCollection failed: %w", err)
+	}
+
 	return nil
 }
 
+func (s *MemoryStore) LoadCollections(_ context.Context, userID uuid.UUID) ([]*domain.Collection, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	collections := []*domain.Collection{}
+	for id, collection := range s.idToCollection {
+		if collection.userID == userID {
+			collections = append(collections, s.newCollection(id, collection))
+		}
+	}
+
+	slices.SortFunc(collections, func(a, b *domain.Collection) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return collections, nil
+}
+
+func (s *MemoryStore) LoadCollection(_ context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Collection, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	collection, exists := s.idToCollection[id]
+	if !exists || collection.userID != userID {
+		return nil, nil
+	}
+
+	return s.newCollection(id, collection), nil
+}
+
+func (s *MemoryStore) RenameCollection(_ context.Context, id uuid.UUID, name string, userID uuid.UUID) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	collection, exists := s.idToCollection[id]
+	if !exists || collection.userID != userID {
+		return false, nil
+	}
+
+	if collection.name == name {
+		return true, nil
+	}
+
+	if s.hasCollectionName(userID, name) {
+		return false, ErrCollectionExists
+	}
+
+	renamed := *collection
+	renamed.name = name
+	if err := s.saveCollection(id, &renamed); err != nil {
+		return false, fmt.Errorf("MemoryStore.RenameCollection, saveCollection failed: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *MemoryStore) DeleteCollection(_ context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	collection, exists := s.idToCollection[id]
+	if !exists || collection.userID != userID {
+		return false, nil
+	}
+
+	err := s.coldStore.Save(&domain.ColdStoreEntry{
+		Type: domain.ColdStoreEntryTypeDeleteCollection,
+		Key:  id.String(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("MemoryStore.DeleteCollection, coldStore.Save failed: %w", err)
+	}
+
+	delete(s.idToCollection, id)
+	return true, nil
+}
+
+func (s *MemoryStore) AddCollectionURLs(_ context.Context, id uuid.UUID, keys []string, userID uuid.UUID) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	collection, exists := s.idToCollection[id]
+	if !exists || collection.userID != userID {
+		return false, nil
+	}
+
+	updated := *collection
+	updated.keys = slices.Clone(collection.keys)
+	for _, key := range keys {
+		item, exists := s.keyToItemMap[key]
+		if !exists || item.userID != userID || slices.Contains(updated.keys, key) {
+			continue
+		}
+
+		updated.keys = append(updated.keys, key)
+	}
+
+	if len(updated.keys) == len(collection.keys) {
+		return true, nil
+	}
+
+	slices.Sort(updated.keys)
+	if err := s.saveCollection(id, &updated); err != nil {
+		return false, fmt.Errorf("MemoryStore.AddCollectionURLs, saveCollection failed: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *MemoryStore) RemoveCollectionURLs(_ context.Context, id uuid.UUID, keys []string, userID uuid.UUID) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	collection, exists := s.idToCollection[id]
+	if !exists || collection.userID != userID {
+		return false, nil
+	}
+
+	updated := *collection
+	updated.keys = slices.DeleteFunc(slices.Clone(collection.keys), func(key string) bool {
+		return slices.Contains(keys, key)
+	})
+	if len(updated.keys) == len(collection.keys) {
+		return true, nil
+	}
+
+	if err := s.saveCollection(id, &updated); err != nil {
+		return false, fmt.Errorf("MemoryStore.RemoveCollectionURLs, saveCollection failed: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *MemoryStore) SaveCampaign(_ context.Context, campaign *domain.Campaign) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.hasCampaignName(campaign.UserID, campaign.Name, uuid.Nil) {
+		return ErrCampaignExists
+	}
+
+	err := s.saveCampaign(campaign.ID, &memoryCampaign{
+		userID:     campaign.UserID,
+		name:       campaign.Name,
+		createdAt:  campaign.CreatedAt,
+		parameters: maps.Clone(campaign.Parameters),
+	})
+	if err != nil {
+		return fmt.Errorf("MemoryStore.SaveCampaign, saveCampaign failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) LoadCampaigns(_ context.Context, userID uuid.UUID) ([]*domain.Campaign, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	campaigns := []*domain.Campaign{}
+	for id, campaign := range s.idToCampaign {
+		if campaign.userID == userID {
+			campaigns = append(campaigns, newCampaign(id, campaign))
+		}
+	}
+
+	slices.SortFunc(campaigns, func(a, b *domain.Campaign) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return campaigns, nil
+}
+
+func (s *MemoryStore) LoadCampaign(_ context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Campaign, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	campaign, exists := s.idToCampaign[id]
+	if !exists || campaign.userID != userID {
+		return nil, nil
+	}
+
+	return newCampaign(id, campaign), nil
+}
+
+func (s *MemoryStore) UpdateCampaign(_ context.Context, campaign *domain.Campaign) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, exists := s.idToCampaign[campaign.ID]
+	if !exists || existing.userID != campaign.UserID {
+		return false, nil
+	}
+
+	if s.hasCampaignName(campaign.UserID, campaign.Name, campaign.ID) {
+		return false, ErrCampaignExists
+	}
+
+	updated := *existing
+	updated.name = campaign.Name
+	updated.parameters = maps.Clone(campaign.Parameters)
+	if err := s.saveCampaign(campaign.ID, &updated); err != nil {
+		return false, fmt.Errorf("MemoryStore.UpdateCampaign, saveCampaign failed: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *MemoryStore) DeleteCampaign(_ context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	campaign, exists := s.idToCampaign[id]
+	if !exists || campaign.userID != userID {
+		return false, nil
+	}
+
+	err := s.coldStore.Save(&domain.ColdStoreEntry{
+		Type: domain.ColdStoreEntryTypeDeleteCampaign,
+		Key:  id.String(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("MemoryStore.DeleteCampaign, coldStore.Save failed: %w", err)
+	}
+
+	s.removeCampaign(id)
+	return true, nil
+}
+
+func (s *MemoryStore) SaveClicks(_ context.Context, clicks []*domain.Click) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	accessed := []string{}
+	for _, click := range clicks {
+		item, exists := s.keyToItemMap[click.Key]
+		if !exists {
+			// The key could be removed after the redirect.
+			continue
+		}
+
+		s.keyToClicks[click.Key] = append(s.keyToClicks[click.Key], click)
+		if item.lastAccessedAt == nil || click.Timestamp.After(*item.lastAccessedAt) {
+			if !slices.Contains(accessed, click.Key) {
+				accessed = append(accessed, click.Key)
+			}
+			item.lastAccessedAt = &click.Timestamp
+		}
+	}
+
+	// Only the last access of the key in the batch is written.
+	for _, key := range accessed {
+		err := s.coldStore.Save(&domain.ColdStoreEntry{
+			Type:           domain.ColdStoreEntryTypeAccess,
+			Key:            key,
+			LastAccessedAt: s.keyToItemMap[key].lastAccessedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("MemoryStore.SaveClicks, coldStore.Save failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetClickStatistics(
+	_ context.Context,
+	request *domain.ClickStatisticsRequest,
+) (*domain.ClickStatistics, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	statistics := &domain.ClickStatistics{}
+	visitors := make(map[string]struct{})
+	buckets := make(map[time.Time]int)
+	referrers := make(map[string]int)
+	userAgents := make(map[string]int)
+	variants := make(map[string]int)
+	for _, click := range s.keyToClicks[request.Key] {
+		if click.Timestamp.Before(request.From) || !click.Timestamp.Before(request.To) {
+			continue
+		}
+
+		statistics.TotalClicks++
+		if len(click.IPHash) > 0 {
+			visitors[click.IPHash] = struct{}{}
+		}
+		buckets[request.Bucket.Truncate(click.Timestamp)]++
+		if len(click.Referrer) > 0 {
+			referrers[click.Referrer]++
+		}
+		if len(click.UserAgent) > 0 {
+			userAgents[click.UserAgent]++
+		}
+		if len(click.Variant) > 0 {
+			variants[click.Variant]++
+		}
+	}
+
+	statistics.UniqueVisitors = len(visitors)
+	statistics.Buckets = make([]*domain.ClickBucketCount, 0, len(buckets))
+	for start, count := range buckets {
+		statistics.Buckets = append(statistics.Buckets, &domain.ClickBucketCount{Start: start, Count: count})
+	}
+	slices.SortFunc(statistics.Buckets, func(a, b *domain.ClickBucketCount) int {
+		return a.Start.Compare(b.Start)
+	})
+	statistics.TopReferrers = topClickValues(referrers, request.TopCount)
+	statistics.TopUserAgents = topClickValues(userAgents, request.TopCount)
+	statistics.Variants = topClickValues(variants, len(variants))
+
+	return statistics, nil
+}
+
 func (s *MemoryStore) GetStatistics(context.Context) (int, int, error) {
-	return 0, 0, nil
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	users := make(map[uuid.UUID]struct{})
+	for _, item := range s.keyToItemMap {
+		users[item.userID] = struct{}{}
+	}
+
+	return len(s.keyToItemMap), len(users), nil
 }
 
-func (s *MemoryStore) saveValue(ctx context.Context, value string) (string, error) {
+func (s *MemoryStore) saveValue(ctx context.Context, request *domain.SaveRequest, userID uuid.UUID) (string, error) {
 	// Save to hot store.
-	key, err := s.saveWithUniqueKey(ctx, value, s.saver)
+	createdAt := time.Now()
+	key, err := s.saveWithAlias(ctx, request.Alias, request.OriginalURL, s.newSaver(request, userID, createdAt))
 	if err != nil {
-		return "", fmt.Errorf("MemoryStore.Save, saveWithUniqueKey failed: %w", err)
+		return "", fmt.Errorf("MemoryStore.Save, saveWithAlias failed: %w", err)
 	}
 
 	// Save to cold store.
 	coldStoreEntry := &domain.ColdStoreEntry{
-		Key:   key,
-		Value: value,
+		Type:         domain.ColdStoreEntryTypePut,
+		Key:          key,
+		Value:        request.OriginalURL,
+		CanonicalURL: canonicalValue(request),
+		DedupScope:   s.dedupScope,
+		ExpiresAt:    request.ExpiresAt,
+		UserID:       userID,
+		CreatedAt:    &createdAt,
+		Title:        request.Title,
+		Tags:         request.Tags,
+		Notes:        request.Notes,
+		RedirectCode: request.RedirectCode,
+		Passthrough:  request.Passthrough,
+		CampaignID:   request.CampaignID,
+		Rules:        request.Rules,
+		Variants:     request.Variants,
 	}
 	err = s.coldStore.Save(coldStoreEntry)
 	if err != nil {
@@ -134,16 +972,410 @@ func (s *MemoryStore) saveValue(ctx context.Context, value string) (string, erro
 	return key, nil
 }
 
-func (s *MemoryStore) saver(_ context.Context, key, value string) (bool, error) {
-	if _, exists := s.keyToValueMap[key]; exists {
-		return true, nil
+// leaseKeySequence is called by the key allocator during saving, with mutex locked.
+// The end of the block is saved to cold store, so values are not handed out again after restart.
+func (s *MemoryStore) leaseKeySequence(_ context.Context, size uint64) (uint64, error) {
+	err := s.coldStore.Save(&domain.ColdStoreEntry{
+		Type:     domain.ColdStoreEntryTypeSequence,
+		Sequence: s.keySequence + size,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("MemoryStore.leaseKeySequence, coldStore.Save failed: %w", err)
+	}
+
+	start := s.keySequence + 1
+	s.keySequence += size
+
+	return start, nil
+}
+
+// restoreEntry applies a cold store entry to the maps. Entries come in the order they were written.
+func (s *MemoryStore) restoreEntry(entry *domain.ColdStoreEntry, now time.Time) {
+	item, exists := s.keyToItemMap[entry.Key]
+	switch entry.Type {
+	case domain.ColdStoreEntryTypeSequence:
+		s.keySequence = max(s.keySequence, entry.Sequence)
+	case domain.ColdStoreEntryTypeDelete:
+		if exists {
+			item.isDeleted = true
+			item.deletedAt = deletionTime(entry, now)
+			item.updatedAt = *item.deletedAt
+		}
+	case domain.ColdStoreEntryTypeRestore:
+		if exists {
+			item.isDeleted = false
+			item.deletedAt = nil
+			item.updatedAt = timeOrNow(entry.UpdatedAt, now)
+		}
+	case domain.ColdStoreEntryTypeUpdate:
+		if exists && !isUpdateApplied(entry, len(item.versions)) {
+			s.updateValue(entry.Key, item, entry.Value, entry.CanonicalURL, timeOrNow(entry.UpdatedAt, now))
+		}
+	case domain.ColdStoreEntryTypeAccess:
+		if exists && laterTime(entry.LastAccessedAt, item.lastAccessedAt) {
+			item.lastAccessedAt = entry.LastAccessedAt
+		}
+	case domain.ColdStoreEntryTypePurge:
+		if exists {
+			delete(s.keyToItemMap, entry.Key)
+			s.removeDedupKey(entry.Key, item)
+		}
+		s.removeFromCollections(entry.Key)
+	case domain.ColdStoreEntryTypeCollection:
+		// The store writes valid IDs only.
+		if id, err := uuid.Parse(entry.Key); err == nil {
+			s.idToCollection[id] = &memoryCollection{
+				userID:    entry.UserID,
+				name:      entry.Value,
+				createdAt: timeOrNow(entry.CreatedAt, now),
+				keys:      entry.Keys,
+			}
+		}
+	case domain.ColdStoreEntryTypeDeleteCollection:
+		if id, err := uuid.Parse(entry.Key); err == nil {
+			delete(s.idToCollection, id)
+		}
+	case domain.ColdStoreEntryTypeCampaign:
+		// The store writes valid IDs only.
+		if id, err := uuid.Parse(entry.Key); err == nil {
+			s.idToCampaign[id] = &memoryCampaign{
+				userID:     entry.UserID,
+				name:       entry.Value,
+				createdAt:  timeOrNow(entry.CreatedAt, now),
+				parameters: entry.Parameters,
+			}
+		}
+	case domain.ColdStoreEntryTypeDeleteCampaign:
+		if id, err := uuid.Parse(entry.Key); err == nil {
+			s.removeCampaign(id)
+		}
+	case domain.ColdStoreEntryTypeUpdateVariants:
+		if exists {
+			item.variants = entry.Variants
+			item.updatedAt = timeOrNow(entry.UpdatedAt, now)
+		}
+	case domain.ColdStoreEntryTypeUpdateOwner:
+		if exists {
+			// The owner is a part of the deduplication key in user scope.
+			s.removeDedupKey(entry.Key, item)
+			item.userID = entry.UserID
+			s.addDedupKey(entry.Key, item)
+		}
+	default:
+		if exists {
+			delete(s.keyToItemMap, entry.Key)
+			s.removeDedupKey(entry.Key, item)
+		}
+
+		item = &memoryItem{
+			value:          entry.Value,
+			canonicalValue: entry.CanonicalURL,
+			// Entries written before the scope was recorded keep the scope of the deployment.
+			dedupScope:     cmp.Or(entry.DedupScope, s.dedupScope),
+			expiresAt:      entry.ExpiresAt,
+			userID:         entry.UserID,
+			isDeleted:      entry.IsDeleted,
+			createdAt:      timeOrNow(entry.CreatedAt, now),
+			lastAccessedAt: entry.LastAccessedAt,
+			versions:       entry.Versions,
+			title:          entry.Title,
+			tags:           entry.Tags,
+			notes:          entry.Notes,
+			redirectCode:   entry.RedirectCode,
+			passthrough:    entry.Passthrough,
+			campaignID:     entry.CampaignID,
+			rules:          entry.Rules,
+			variants:       entry.Variants,
+		}
+		item.updatedAt = timeOrNow(entry.UpdatedAt, item.createdAt)
+		if entry.IsDeleted {
+			item.deletedAt = deletionTime(entry, now)
+			// The deletion is the last known change of the item.
+			item.updatedAt = timeOrNow(entry.UpdatedAt, *item.deletedAt)
+		}
+		s.keyToItemMap[entry.Key] = item
+		s.addDedupKey(entry.Key, item)
+	}
+}
+
+func (s *MemoryStore) newSaver(request *domain.SaveRequest, userID uuid.UUID, createdAt time.Time) saverFunc {
+	return func(_ context.Context, key, value string) (bool, error) {
+		if _, exists := s.keyToItemMap[key]; exists {
+			return true, nil
+		}
+
+		item := &memoryItem{
+			value:          value,
+			canonicalValue: canonicalValue(request),
+			dedupScope:     s.dedupScope,
+			expiresAt:      request.ExpiresAt,
+			userID:         userID,
+			createdAt:      createdAt,
+			updatedAt:      createdAt,
+			title:          request.Title,
+			tags:           request.Tags,
+			notes:          request.Notes,
+			redirectCode:   request.RedirectCode,
+			passthrough:    request.Passthrough,
+			campaignID:     request.CampaignID,
+			rules:          request.Rules,
+			variants:       request.Variants,
+		}
+		if existingKey, ok := s.valueToKeyMap[s.dedupKey(item)]; ok {
+			return false, NewDuplicateURLError(existingKey, value)
+		}
+
+		s.keyToItemMap[key] = item
+		s.addDedupKey(key, item)
+		return false, nil
+	}
+}
+
+// updateValue points the item to the new value and keeps the previous one in the item versions.
+func (s *MemoryStore) updateValue(key string, item *memoryItem, value, canonicalValue string, updatedAt time.Time) {
+	s.removeDedupKey(key, item)
+	item.versions = append(item.versions, &domain.URLVersion{
+		Version:     len(item.versions) + 1,
+		OriginalURL: item.value,
+		ReplacedAt:  updatedAt,
+	})
+	item.value = value
+	item.canonicalValue = canonicalValue
+	item.updatedAt = updatedAt
+	s.addDedupKey(key, item)
+}
+
+// saveCollection writes the whole collection to cold store, then replaces the kept one. Called with mutex locked.
+func (s *MemoryStore) saveCollection(id uuid.UUID, collection *memoryCollection) error {
+	if err := s.coldStore.Save(newCollectionEntry(id, collection)); err != nil {
+		return fmt.Errorf("MemoryStore.saveCollection, coldStore.Save failed: %w", err)
 	}
 
-	if existingKey, ok := s.valueToKeyMap[value]; ok {
-		return false, NewDuplicateURLError(existingKey, value)
+	s.idToCollection[id] = collection
+	return nil
+}
+
+// newCollection counts only the URLs of the collection owner, the owner of a URL can change.
+func (s *MemoryStore) newCollection(id uuid.UUID, collection *memoryCollection) *domain.Collection {
+	count := 0
+	for _, key := range collection.keys {
+		if item, exists := s.keyToItemMap[key]; exists && item.userID == collection.userID {
+			count++
+		}
+	}
+
+	return &domain.Collection{
+		ID:        id,
+		UserID:    collection.userID,
+		Name:      collection.name,
+		CreatedAt: collection.createdAt,
+		URLCount:  count,
+	}
+}
+
+func (s *MemoryStore) hasCollectionName(userID uuid.UUID, name string) bool {
+	for _, collection := range s.idToCollection {
+		if collection.userID == userID && collection.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeFromCollections is called when the key becomes free. Collections are not written to cold store:
+// purged keys are removed by replaying the purge, expired ones are removed on loading.
+func (s *MemoryStore) removeFromCollections(key string) {
+	for _, collection := range s.idToCollection {
+		if index, found := slices.BinarySearch(collection.keys, key); found {
+			collection.keys = slices.Delete(slices.Clone(collection.keys), index, index+1)
+		}
+	}
+}
+
+func newCollectionEntry(id uuid.UUID, collection *memoryCollection) *domain.ColdStoreEntry {
+	return &domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypeCollection,
+		Key:       id.String(),
+		Value:     collection.name,
+		UserID:    collection.userID,
+		CreatedAt: &collection.createdAt,
+		Keys:      collection.keys,
+	}
+}
+
+// saveCampaign writes the whole campaign to cold store, then replaces the kept one. Called with mutex locked.
+func (s *MemoryStore) saveCampaign(id uuid.UUID, campaign *memoryCampaign) error {
+	if err := s.coldStore.Save(newCampaignEntry(id, campaign)); err != nil {
+		return fmt.Errorf("MemoryStore.saveCampaign, coldStore.Save failed: %w", err)
 	}
 
-	s.keyToValueMap[key] = value
-	s.valueToKeyMap[value] = key
-	return false, nil
+	s.idToCampaign[id] = campaign
+	return nil
+}
+
+// hasCampaignName reports whether the user has a campaign with the name other than the given one.
+func (s *MemoryStore) hasCampaignName(userID uuid.UUID, name string, exceptID uuid.UUID) bool {
+	for id, campaign := range s.idToCampaign {
+		if id != exceptID && campaign.userID == userID && campaign.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeCampaign removes the campaign and unlinks its URLs, the same way the database store does.
+// URLs are not written to cold store: the unlinking is repeated by replaying the campaign deletion.
+func (s *MemoryStore) removeCampaign(id uuid.UUID) {
+	delete(s.idToCampaign, id)
+	for _, item := range s.keyToItemMap {
+		if item.campaignID == id {
+			item.campaignID = uuid.Nil
+		}
+	}
+}
+
+func newCampaign(id uuid.UUID, campaign *memoryCampaign) *domain.Campaign {
+	return &domain.Campaign{
+		ID:         id,
+		UserID:     campaign.userID,
+		Name:       campaign.name,
+		CreatedAt:  campaign.createdAt,
+		Parameters: maps.Clone(campaign.parameters),
+	}
+}
+
+func newCampaignEntry(id uuid.UUID, campaign *memoryCampaign) *domain.ColdStoreEntry {
+	return &domain.ColdStoreEntry{
+		Type:       domain.ColdStoreEntryTypeCampaign,
+		Key:        id.String(),
+		Value:      campaign.name,
+		UserID:     campaign.userID,
+		CreatedAt:  &campaign.createdAt,
+		Parameters: campaign.parameters,
+	}
+}
+
+// dedupKey returns the key of the item value in valueToKeyMap, empty if values are not deduplicated.
+// The key follows the scope the item was saved with, so a scope change applies to items saved after it.
+func (s *MemoryStore) dedupKey(item *memoryItem) string {
+	switch item.dedupScope {
+	case config.DedupScopeNone:
+		return ""
+	case config.DedupScopeUser:
+		return item.userID.String() + " " + cmp.Or(item.canonicalValue, item.value)
+	default:
+		return cmp.Or(item.canonicalValue, item.value)
+	}
+}
+
+// deletionTime returns the moment the entry was deleted. Entries written before the moment was recorded
+// are considered deleted at loading, so they are purged after the whole retention period.
+func deletionTime(entry *domain.ColdStoreEntry, now time.Time) *time.Time {
+	if entry.DeletedAt == nil {
+		return &now
+	}
+
+	return entry.DeletedAt
+}
+
+func matchesUserURLsStatus(item *memoryItem, status domain.UserURLsStatus, now time.Time) bool {
+	switch status {
+	case domain.UserURLsStatusActive:
+		return !item.isDeleted && (item.expiresAt == nil || item.expiresAt.After(now))
+	case domain.UserURLsStatusDeleted:
+		return item.isDeleted
+	default:
+		return true
+	}
+}
+
+// searchWords splits the text into lower case words of letters and digits, the same way as
+// url_search_vector of the database splits the searched text.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchesSearchWords reports whether every word is a word of the value, the title or the notes of the item,
+// the same way as the database store does.
+func matchesSearchWords(item *memoryItem, words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+
+	itemWords := searchWords(strings.Join([]string{item.value, item.title, item.notes}, " "))
+	for _, word := range words {
+		if !slices.Contains(itemWords, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareUserURLs compares the item with the cursor position in the order of the query,
+// the same way as the database store does.
+func compareUserURLs(item *domain.KeyOriginalURLItem, cursor *domain.UserURLsCursor, query *domain.UserURLsQuery) int {
+	result := cmp.Compare(item.URLKey, cursor.URLKey)
+	if query.Sort != domain.UserURLsSortKey {
+		result = cmp.Or(item.CreatedAt.Compare(cursor.CreatedAt), result)
+	}
+
+	if query.Descending {
+		return -result
+	}
+
+	return result
+}
+
+// timeOrNow returns the recorded moment, entries written before the moment was recorded get the given one.
+func timeOrNow(moment *time.Time, now time.Time) time.Time {
+	if moment == nil {
+		return now
+	}
+
+	return *moment
+}
+
+// laterTime reports whether the moment is after the current one, any moment is later than none.
+func laterTime(moment, current *time.Time) bool {
+	return moment != nil && (current == nil || moment.After(*current))
+}
+
+// canonicalValue returns the canonical URL of the request, empty when it equals the original one.
+func canonicalValue(request *domain.SaveRequest) string {
+	if request.CanonicalURL == request.OriginalURL {
+		return ""
+	}
+
+	return request.CanonicalURL
+}
+
+func (s *MemoryStore) addDedupKey(key string, item *memoryItem) {
+	if dedupKey := s.dedupKey(item); len(dedupKey) > 0 {
+		s.valueToKeyMap[dedupKey] = key
+	}
+}
+
+func (s *MemoryStore) removeDedupKey(key string, item *memoryItem) {
+	if dedupKey := s.dedupKey(item); s.valueToKeyMap[dedupKey] == key {
+		delete(s.valueToKeyMap, dedupKey)
+	}
+}
+
+func topClickValues(counts map[string]int, topCount int) []*domain.ClickValueCount {
+	values := make([]*domain.ClickValueCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, &domain.ClickValueCount{Value: value, Count: count})
+	}
+
+	// The same order as in the database query: most frequent first, then by value.
+	slices.SortFunc(values, func(a, b *domain.ClickValueCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+	})
+
+	return values[:min(len(values), topCount)]
 }
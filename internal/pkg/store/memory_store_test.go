@@ -1,9 +1,14 @@
 package store
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aleffnull/shortener/internal/config"
 	"github.com/aleffnull/shortener/internal/domain"
@@ -27,13 +32,13 @@ func TestMemoryStore_Init(t *testing.T) {
 			name:      "WHEN cold store error THEN error",
 			wantError: true,
 			hookBefore: func(mock *mocks.Mock) {
-				mock.ColdStore.EXPECT().LoadAll().Return(nil, assert.AnError)
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).Return(assert.AnError)
 			},
 		},
 		{
 			name: "WHEN no errors THEN ok",
 			hookBefore: func(mock *mocks.Mock) {
-				mock.ColdStore.EXPECT().LoadAll().Return(nil, nil)
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).Return(nil)
 				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 			},
 		},
@@ -62,6 +67,97 @@ func TestMemoryStore_Init(t *testing.T) {
 	}
 }
 
+func TestMemoryStore_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN cold store error THEN logged",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Close().Return(assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Close().Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+			// Act.
+			store.Shutdown()
+		})
+	}
+}
+
+func TestMemoryStore_Compact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:      "WHEN cold store error THEN error",
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Compact().Return(assert.AnError)
+			},
+		},
+		{
+			name: "WHEN no errors THEN ok",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Compact().Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+			// Act.
+			err := store.Compact(context.Background())
+
+			// Assert.
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestMemoryStore_CheckAvailability(t *testing.T) {
 	t.Parallel()
 
@@ -96,7 +192,30 @@ func TestMemoryStore_Load(t *testing.T) {
 				key: "foo",
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.ColdStore.EXPECT().LoadAll().Return([]*domain.ColdStoreEntry{}, nil)
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{}))
+				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "WHEN key expired before start THEN expired item",
+			args: &args{
+				key: "foo",
+			},
+			want: &domain.URLItem{
+				URL:       "http://foo.bar",
+				ExpiresAt: lo.ToPtr(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+				CreatedAt: time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC),
+				UpdatedAt: time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC),
+			},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+					{
+						Key:       "foo",
+						Value:     "http://foo.bar",
+						ExpiresAt: lo.ToPtr(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+						CreatedAt: lo.ToPtr(time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC)),
+					},
+				}))
 				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 			},
 		},
@@ -106,15 +225,29 @@ func TestMemoryStore_Load(t *testing.T) {
 				key: "foo",
 			},
 			want: &domain.URLItem{
-				URL: "http://foo.bar",
+				URL:            "http://foo.bar",
+				CreatedAt:      time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC),
+				UpdatedAt:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+				LastAccessedAt: lo.ToPtr(time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC)),
+				RedirectCode:   308,
+				Passthrough:    true,
+				Rules:          []*domain.RedirectRule{{Platform: domain.PlatformAndroid, URL: "https://play.google.com/store/apps"}},
+				Variants:       []*domain.URLVariant{{Name: "A", URL: "http://foo.bar/a", Weight: 1}},
 			},
 			hookBefore: func(mock *mocks.Mock) {
-				mock.ColdStore.EXPECT().LoadAll().Return([]*domain.ColdStoreEntry{
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
 					{
-						Key:   "foo",
-						Value: "http://foo.bar",
+						Key:            "foo",
+						Value:          "http://foo.bar",
+						CreatedAt:      lo.ToPtr(time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC)),
+						UpdatedAt:      lo.ToPtr(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+						LastAccessedAt: lo.ToPtr(time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC)),
+						RedirectCode:   308,
+						Passthrough:    true,
+						Rules:          []*domain.RedirectRule{{Platform: domain.PlatformAndroid, URL: "https://play.google.com/store/apps"}},
+						Variants:       []*domain.URLVariant{{Name: "A", URL: "http://foo.bar/a", Weight: 1}},
 					},
-				}, nil)
+				}))
 				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 			},
 		},
@@ -142,28 +275,393 @@ func TestMemoryStore_Load(t *testing.T) {
 	}
 }
 
+func TestMemoryStore_Init_RestoresTypedEntries(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+	createdAt := deletedAt.Add(-time.Hour)
+	restoredAt := deletedAt.Add(time.Minute)
+	accessedAt := deletedAt.Add(-time.Minute)
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz"},
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.new", CreatedAt: &createdAt},
+		{Type: domain.ColdStoreEntryTypeUpdateOwner, Key: "foo", UserID: userID},
+		{Type: domain.ColdStoreEntryTypeAccess, Key: "foo", LastAccessedAt: &accessedAt},
+		{Type: domain.ColdStoreEntryTypeAccess, Key: "foo", LastAccessedAt: lo.ToPtr(createdAt)},
+		{Type: domain.ColdStoreEntryTypeDelete, Key: "foo", DeletedAt: &deletedAt},
+		{Type: domain.ColdStoreEntryTypeDelete, Key: "missing"},
+		{Type: domain.ColdStoreEntryTypeAccess, Key: "missing", LastAccessedAt: &accessedAt},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", ExpiresAt: lo.ToPtr(time.Now().Add(-time.Minute))},
+		{Type: domain.ColdStoreEntryTypePurge, Key: "bar"},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buz", Value: "http://buz.qux", CreatedAt: &createdAt},
+		{Type: domain.ColdStoreEntryTypeDelete, Key: "buz", DeletedAt: &deletedAt},
+		{Type: domain.ColdStoreEntryTypeRestore, Key: "buz", UpdatedAt: &restoredAt},
+		{Type: domain.ColdStoreEntryTypePut, Key: "qux", Value: "http://qux.foo", IsDeleted: true, DeletedAt: &deletedAt},
+		{Type: domain.ColdStoreEntryTypePurge, Key: "qux"},
+		{Type: domain.ColdStoreEntryTypeSequence, Sequence: 2000},
+		{Type: domain.ColdStoreEntryTypeSequence, Sequence: 1000},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), 2)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+	// Act.
+	err := store.Init()
+
+	// Assert.
+	require.NoError(t, err)
+	memoryStore := store.(*MemoryStore)
+	require.Equal(t, map[string]*memoryItem{
+		"foo": {
+			value:          "http://foo.new",
+			userID:         userID,
+			isDeleted:      true,
+			deletedAt:      &deletedAt,
+			createdAt:      createdAt,
+			updatedAt:      deletedAt,
+			lastAccessedAt: &accessedAt,
+		},
+		"buz": {value: "http://buz.qux", createdAt: createdAt, updatedAt: restoredAt},
+	}, memoryStore.keyToItemMap)
+	require.Equal(t, map[string]string{"http://foo.new": "foo", "http://buz.qux": "buz"}, memoryStore.valueToKeyMap)
+	require.Equal(t, uint64(2000), memoryStore.keySequence)
+}
+
+func TestMemoryStore_Save_LeasesKeyBlocks(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypeSequence, Sequence: 10},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	// One lease per block of two values, the rest are item entries.
+	mock.ColdStore.EXPECT().Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeSequence, Sequence: 12})
+	mock.ColdStore.EXPECT().Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeSequence, Sequence: 14})
+	mock.ColdStore.EXPECT().Save(gomock.Cond(func(entry *domain.ColdStoreEntry) bool {
+		return entry.Type == domain.ColdStoreEntryTypePut
+	})).Times(3)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        1,
+				KeyMaxLength:     10,
+				KeyMaxIterations: 10,
+			},
+		},
+		KeyGenerator: &config.KeyGeneratorConfiguration{
+			Type:      config.KeyGeneratorSequence,
+			Alphabet:  "0123456789",
+			BlockSize: 2,
+		},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	keys := []string{}
+	for i := range 3 {
+		key, err := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: fmt.Sprintf("http://foo.bar/%v", i)}, uuid.Nil)
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+
+	// Assert.
+	require.Equal(t, []string{"11", "12", "13"}, keys)
+}
+
+func TestMemoryStore_Save_LeaseError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).Return(nil)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(gomock.Any()).Return(assert.AnError)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        1,
+				KeyMaxLength:     10,
+				KeyMaxIterations: 10,
+			},
+		},
+		KeyGenerator: &config.KeyGeneratorConfiguration{Type: config.KeyGeneratorSequence},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	_, err := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: "http://foo.bar"}, uuid.Nil)
+
+	// Assert.
+	require.ErrorIs(t, err, assert.AnError)
+	require.Zero(t, store.(*MemoryStore).keySequence)
+}
+
+func TestMemoryStore_Export(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.MustParse("2b0c1d4e-8d6c-4a55-9d87-5a8f0c4e7f11")
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Key:            "foo",
+			Value:          "http://foo.bar",
+			UserID:         userID,
+			CreatedAt:      lo.ToPtr(time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC)),
+			LastAccessedAt: lo.ToPtr(time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC)),
+		},
+		{
+			Key:       "bar",
+			Value:     "http://bar.buz",
+			IsDeleted: true,
+			DeletedAt: lo.ToPtr(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+			CreatedAt: lo.ToPtr(time.Date(2026, 1, 1, 3, 4, 5, 0, time.UTC)),
+		},
+		{
+			Type:      domain.ColdStoreEntryTypeCollection,
+			Key:       "8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d",
+			Value:     "Foo",
+			UserID:    userID,
+			CreatedAt: lo.ToPtr(time.Date(2026, 1, 4, 3, 4, 5, 0, time.UTC)),
+			Keys:      []string{"foo"},
+		},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+	buffer := &bytes.Buffer{}
+
+	// Act.
+	err := store.Export(context.Background(), buffer)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"type":"sequence","sequence":2}`+"\n"+
+			`{"type":"put","key":"bar","value":"http://bar.buz","is_deleted":true,"deleted_at":"2026-01-02T03:04:05Z",`+
+			`"created_at":"2026-01-01T03:04:05Z","updated_at":"2026-01-02T03:04:05Z"}`+"\n"+
+			`{"type":"put","key":"foo","value":"http://foo.bar","user_id":"2b0c1d4e-8d6c-4a55-9d87-5a8f0c4e7f11",`+
+			`"created_at":"2026-01-01T03:04:05Z","updated_at":"2026-01-01T03:04:05Z","last_accessed_at":"2026-01-03T03:04:05Z"}`+"\n"+
+			`{"type":"collection","key":"8f1d7c2a-3b4e-4f5a-9c6d-7e8f9a0b1c2d","value":"Foo",`+
+			`"user_id":"2b0c1d4e-8d6c-4a55-9d87-5a8f0c4e7f11","created_at":"2026-01-04T03:04:05Z","keys":["foo"]}`+"\n",
+		buffer.String())
+}
+
 func TestMemoryStore_LoadAllByUserID(t *testing.T) {
 	t.Parallel()
 
+	userID := uuid.New()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []*domain.ColdStoreEntry{
+		{
+			Key:       "foo",
+			Value:     "http://foo.bar",
+			UserID:    userID,
+			CreatedAt: lo.ToPtr(createdAt.Add(time.Minute)),
+		},
+		{
+			Key:       "bar",
+			Value:     "http://bar.buz",
+			UserID:    uuid.New(),
+			CreatedAt: lo.ToPtr(createdAt),
+		},
+		{
+			Key:       "buz",
+			Value:     "http://buz.foo",
+			UserID:    userID,
+			IsDeleted: true,
+			DeletedAt: lo.ToPtr(createdAt),
+			CreatedAt: lo.ToPtr(createdAt),
+		},
+		{
+			Key:       "qux",
+			Value:     "http://QUX.foo/bar",
+			UserID:    userID,
+			CreatedAt: lo.ToPtr(createdAt.Add(time.Minute)),
+			Title:     "Team Handbook",
+			Tags:      []string{"docs", "work"},
+			Notes:     "Updated every quarter",
+		},
+		{
+			Key:       "old",
+			Value:     "http://old.foo",
+			UserID:    userID,
+			ExpiresAt: lo.ToPtr(time.Now().Add(time.Hour)),
+			CreatedAt: lo.ToPtr(createdAt.Add(-time.Minute)),
+		},
+	}
+
+	newItem := func(key, value string, createdAt time.Time) *domain.KeyOriginalURLItem {
+		return &domain.KeyOriginalURLItem{URLKey: key, OriginalURL: value, CreatedAt: createdAt, UpdatedAt: createdAt}
+	}
+	fooItem := newItem("foo", "http://foo.bar", createdAt.Add(time.Minute))
+	buzItem := newItem("buz", "http://buz.foo", createdAt)
+	quxItem := newItem("qux", "http://QUX.foo/bar", createdAt.Add(time.Minute))
+	quxItem.Title = "Team Handbook"
+	quxItem.Tags = []string{"docs", "work"}
+	quxItem.Notes = "Updated every quarter"
+	oldItem := newItem("old", "http://old.foo", createdAt.Add(-time.Minute))
+
+	tests := []struct {
+		name  string
+		query *domain.UserURLsQuery
+		want  []*domain.KeyOriginalURLItem
+	}{
+		{
+			name:  "WHEN no options THEN all user items by creation time",
+			query: &domain.UserURLsQuery{},
+			want:  []*domain.KeyOriginalURLItem{oldItem, buzItem, fooItem, quxItem},
+		},
+		{
+			name:  "WHEN sorted by key descending THEN items in reverse key order",
+			query: &domain.UserURLsQuery{Sort: domain.UserURLsSortKey, Descending: true},
+			want:  []*domain.KeyOriginalURLItem{quxItem, oldItem, fooItem, buzItem},
+		},
+		{
+			name:  "WHEN limit THEN first items only",
+			query: &domain.UserURLsQuery{Limit: 2},
+			want:  []*domain.KeyOriginalURLItem{oldItem, buzItem},
+		},
+		{
+			name: "WHEN cursor THEN items after it",
+			query: &domain.UserURLsQuery{
+				After: &domain.UserURLsCursor{CreatedAt: createdAt.Add(time.Minute), URLKey: "foo"},
+			},
+			want: []*domain.KeyOriginalURLItem{quxItem},
+		},
+		{
+			name: "WHEN cursor and descending THEN items before it",
+			query: &domain.UserURLsQuery{
+				Descending: true,
+				After:      &domain.UserURLsCursor{CreatedAt: createdAt.Add(time.Minute), URLKey: "foo"},
+			},
+			want: []*domain.KeyOriginalURLItem{buzItem, oldItem},
+		},
+		{
+			name:  "WHEN deleted status THEN deleted items only",
+			query: &domain.UserURLsQuery{Status: domain.UserURLsStatusDeleted},
+			want:  []*domain.KeyOriginalURLItem{buzItem},
+		},
+		{
+			name:  "WHEN active status THEN not deleted items only",
+			query: &domain.UserURLsQuery{Status: domain.UserURLsStatusActive, Sort: domain.UserURLsSortKey},
+			want:  []*domain.KeyOriginalURLItem{fooItem, oldItem, quxItem},
+		},
+		{
+			name:  "WHEN search THEN items containing its words in any case",
+			query: &domain.UserURLsQuery{Search: "qux.FOO"},
+			want:  []*domain.KeyOriginalURLItem{quxItem},
+		},
+		{
+			name:  "WHEN search words THEN items containing all of them in URL, title or notes",
+			query: &domain.UserURLsQuery{Search: " handbook  QUARTER foo "},
+			want:  []*domain.KeyOriginalURLItem{quxItem},
+		},
+		{
+			name:  "WHEN search word is part of a word THEN no items",
+			query: &domain.UserURLsQuery{Search: "hand"},
+			want:  []*domain.KeyOriginalURLItem{},
+		},
+		{
+			name:  "WHEN one of search words missing THEN no items",
+			query: &domain.UserURLsQuery{Search: "handbook bar.buz"},
+			want:  []*domain.KeyOriginalURLItem{},
+		},
+		{
+			name:  "WHEN tag THEN items with the tag only",
+			query: &domain.UserURLsQuery{Tag: "work"},
+			want:  []*domain.KeyOriginalURLItem{quxItem},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries(entries))
+			mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+			require.NoError(t, store.Init())
+
+			// Act.
+			items, err := store.LoadAllByUserID(context.Background(), userID, tt.query)
+
+			// Assert.
+			require.NoError(t, err)
+			require.Equal(t, tt.want, items)
+		})
+	}
+}
+
+func TestMemoryStore_LoadAllByUserID_ActiveSkipsExpired(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
 	ctrl := gomock.NewController(t)
 	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Key:       "foo",
+			Value:     "http://foo.bar",
+			UserID:    userID,
+			ExpiresAt: lo.ToPtr(time.Now().Add(time.Hour)),
+		},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 
 	configuration := &config.Configuration{
 		MemoryStore: &config.MemoryStoreConfiguration{},
 	}
 	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+	store.(*MemoryStore).keyToItemMap["foo"].expiresAt = lo.ToPtr(time.Now().Add(-time.Minute))
+
+	// Act.
+	items, err := store.LoadAllByUserID(context.Background(), userID, &domain.UserURLsQuery{Status: domain.UserURLsStatusActive})
 
-	items, err := store.LoadAllByUserID(context.Background(), uuid.New())
-	require.Nil(t, items)
+	// Assert.
 	require.NoError(t, err)
+	require.Empty(t, items)
 }
 
 func TestMemoryStore_Save(t *testing.T) {
 	t.Parallel()
 
+	userID := uuid.New()
+
 	type args struct {
 		key   string
 		value string
+		alias string
 	}
 
 	defaultConfiguration := &config.Configuration{
@@ -189,12 +687,12 @@ func TestMemoryStore_Save(t *testing.T) {
 				value: "http://foo.bar",
 			},
 			hookBefore: func(mock *mocks.Mock, args *args) *config.Configuration {
-				mock.ColdStore.EXPECT().LoadAll().Return([]*domain.ColdStoreEntry{
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
 					{
 						Key:   args.key,
 						Value: args.value,
 					},
-				}, nil)
+				}))
 				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 				return defaultConfiguration
 			},
@@ -206,40 +704,103 @@ func TestMemoryStore_Save(t *testing.T) {
 			},
 		},
 		{
-			name: "WHEN save to cold store error THEN error",
+			name: "GIVEN already existing key WHEN alias THEN alias conflict error",
 			args: &args{
-				value: "http://foo.bar",
+				key:   "foo",
+				value: "http://bar.buz",
+				alias: "foo",
 			},
 			hookBefore: func(mock *mocks.Mock, args *args) *config.Configuration {
-				// Init.
-				mock.ColdStore.EXPECT().LoadAll().Return([]*domain.ColdStoreEntry{}, nil)
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+					{
+						Key:   args.key,
+						Value: "http://foo.bar",
+					},
+				}))
 				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
-				// Save.
-				mock.ColdStore.EXPECT().Save(gomock.Any()).DoAndReturn(func(entry *domain.ColdStoreEntry) error {
-					require.GreaterOrEqual(t, len(entry.Key), 1)
-					require.Equal(t, args.value, entry.Value)
-					return assert.AnError
-				})
 				return defaultConfiguration
 			},
-			checkResult: func(key string, err error, _ *args) {
-				require.Error(t, err)
+			checkResult: func(key string, err error, args *args) {
+				var aliasConflictError *AliasConflictError
+				require.True(t, errors.As(err, &aliasConflictError))
+				require.Equal(t, args.alias, aliasConflictError.Alias)
+				require.Empty(t, key)
 			},
 		},
 		{
-			name: "GIVEN all short keys already exist WHEN no errors THEN saved with doubled key",
+			name: "WHEN invalid alias THEN error",
 			args: &args{
 				value: "http://foo.bar",
+				alias: "api",
 			},
 			hookBefore: func(mock *mocks.Mock, args *args) *config.Configuration {
-				// Init.
-				entries := lo.Map([]rune(alphabet), func(ch rune, _ int) *domain.ColdStoreEntry {
-					return &domain.ColdStoreEntry{
-						Key:   string(ch),
-						Value: string(ch),
-					}
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{}))
+				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+				return defaultConfiguration
+			},
+			checkResult: func(key string, err error, _ *args) {
+				require.ErrorIs(t, err, ErrInvalidAlias)
+				require.Empty(t, key)
+			},
+		},
+		{
+			name: "WHEN valid alias THEN saved with alias",
+			args: &args{
+				value: "http://foo.bar",
+				alias: "spring-sale",
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) *config.Configuration {
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{}))
+				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+				mock.ColdStore.EXPECT().Save(gomock.Cond(func(entry *domain.ColdStoreEntry) bool {
+					return entry.Type == domain.ColdStoreEntryTypePut &&
+						entry.Key == args.alias &&
+						entry.Value == args.value &&
+						entry.UserID == userID &&
+						entry.CreatedAt != nil
+				})).Return(nil)
+				return defaultConfiguration
+			},
+			checkResult: func(key string, err error, args *args) {
+				require.Equal(t, args.alias, key)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "WHEN save to cold store error THEN error",
+			args: &args{
+				value: "http://foo.bar",
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) *config.Configuration {
+				// Init.
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{}))
+				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+				// Save.
+				mock.ColdStore.EXPECT().Save(gomock.Any()).DoAndReturn(func(entry *domain.ColdStoreEntry) error {
+					require.GreaterOrEqual(t, len(entry.Key), 1)
+					require.Equal(t, args.value, entry.Value)
+					return assert.AnError
+				})
+				return defaultConfiguration
+			},
+			checkResult: func(key string, err error, _ *args) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name: "GIVEN all short keys already exist WHEN no errors THEN saved with doubled key",
+			args: &args{
+				value: "http://foo.bar",
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) *config.Configuration {
+				// Init.
+				entries := lo.Map([]rune(defaultRandomKeyAlphabet), func(ch rune, _ int) *domain.ColdStoreEntry {
+					return &domain.ColdStoreEntry{
+						Key:   string(ch),
+						Value: string(ch),
+					}
 				})
-				mock.ColdStore.EXPECT().LoadAll().Return(entries, nil)
+				mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries(entries))
 				mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
 				// Save.
 				mock.ColdStore.EXPECT().Save(gomock.Any()).DoAndReturn(func(entry *domain.ColdStoreEntry) error {
@@ -278,12 +839,240 @@ func TestMemoryStore_Save(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			key, err := store.Save(context.Background(), tt.args.value, uuid.New())
+			key, err := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: tt.args.value, Alias: tt.args.alias}, userID)
 			tt.checkResult(key, err, tt.args)
 		})
 	}
 }
 
+func TestMemoryStore_Save_DedupScopes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		dedupScope      string
+		wantOwnerDup    bool
+		wantStrangerDup bool
+	}{
+		{
+			name:            "WHEN global scope THEN duplicate for everyone",
+			dedupScope:      config.DedupScopeGlobal,
+			wantOwnerDup:    true,
+			wantStrangerDup: true,
+		},
+		{
+			name:         "WHEN user scope THEN duplicate for owner only",
+			dedupScope:   config.DedupScopeUser,
+			wantOwnerDup: true,
+		},
+		{
+			name:       "WHEN no scope THEN no duplicates",
+			dedupScope: config.DedupScopeNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ownerID := uuid.New()
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+				{Key: "foo", Value: "http://foo.bar", UserID: uuid.New()},
+				{Type: domain.ColdStoreEntryTypeUpdateOwner, Key: "foo", UserID: ownerID},
+			}))
+			mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+			mock.ColdStore.EXPECT().Save(gomock.Any()).AnyTimes()
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{
+					KeyStoreConfiguration: config.KeyStoreConfiguration{
+						KeyLength:        8,
+						KeyMaxLength:     100,
+						KeyMaxIterations: 10,
+					},
+				},
+				DedupScope: tt.dedupScope,
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+			require.NoError(t, store.Init())
+			request := &domain.SaveRequest{OriginalURL: "http://foo.bar"}
+
+			// Act.
+			_, ownerErr := store.Save(context.Background(), request, ownerID)
+			_, strangerErr := store.Save(context.Background(), request, uuid.New())
+
+			// Assert.
+			var duplicateURLError *DuplicateURLError
+			require.Equal(t, tt.wantOwnerDup, errors.As(ownerErr, &duplicateURLError))
+			require.Equal(t, tt.wantStrangerDup, errors.As(strangerErr, &duplicateURLError))
+			if tt.wantOwnerDup {
+				require.ErrorAs(t, ownerErr, &duplicateURLError)
+				require.Equal(t, "foo", duplicateURLError.Key)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Save_DedupScopeChange(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ownerID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://foo.bar", UserID: ownerID, DedupScope: config.DedupScopeUser},
+		{Key: "bar", Value: "http://bar.buz", UserID: ownerID},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	savedEntries := []*domain.ColdStoreEntry{}
+	mock.ColdStore.EXPECT().Save(gomock.Any()).DoAndReturn(func(entry *domain.ColdStoreEntry) error {
+		savedEntries = append(savedEntries, entry)
+		return nil
+	}).AnyTimes()
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        8,
+				KeyMaxLength:     100,
+				KeyMaxIterations: 10,
+			},
+		},
+		DedupScope: config.DedupScopeGlobal,
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+	strangerID := uuid.New()
+
+	// Act.
+	key, fooErr := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: "http://foo.bar"}, strangerID)
+	_, barErr := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: "http://bar.buz"}, strangerID)
+
+	// Assert.
+	// The URL saved with the user scope stays deduplicated for its owner only.
+	require.NoError(t, fooErr)
+	require.NotEqual(t, "foo", key)
+	require.Equal(t, config.DedupScopeGlobal, savedEntries[len(savedEntries)-1].DedupScope)
+	// The URL saved before the scope was recorded follows the deployment scope.
+	var duplicateURLError *DuplicateURLError
+	require.ErrorAs(t, barErr, &duplicateURLError)
+	require.Equal(t, "bar", duplicateURLError.Key)
+}
+
+func TestMemoryStore_Save_CanonicalURL(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://Foo.bar:80/", CanonicalURL: "http://foo.bar/"},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	savedEntries := []*domain.ColdStoreEntry{}
+	mock.ColdStore.EXPECT().Save(gomock.Any()).DoAndReturn(func(entry *domain.ColdStoreEntry) error {
+		savedEntries = append(savedEntries, entry)
+		return nil
+	}).AnyTimes()
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        8,
+				KeyMaxLength:     100,
+				KeyMaxIterations: 10,
+			},
+		},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	_, duplicateErr := store.Save(context.Background(), &domain.SaveRequest{
+		OriginalURL:  "http://foo.bar",
+		CanonicalURL: "http://foo.bar/",
+	}, uuid.New())
+	key, err := store.Save(context.Background(), &domain.SaveRequest{
+		OriginalURL:  "http://bar.buz/",
+		CanonicalURL: "http://bar.buz/",
+		Alias:        "bar",
+	}, uuid.New())
+
+	// Assert.
+	var duplicateURLError *DuplicateURLError
+	require.ErrorAs(t, duplicateErr, &duplicateURLError)
+	require.Equal(t, "foo", duplicateURLError.Key)
+	require.NoError(t, err)
+	require.Equal(t, "bar", key)
+
+	item, err := store.Load(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, "http://Foo.bar:80/", item.URL)
+
+	// The canonical URL is journaled only when it differs from the original one.
+	putEntries := lo.Filter(savedEntries, func(entry *domain.ColdStoreEntry, _ int) bool {
+		return entry.Type == domain.ColdStoreEntryTypePut
+	})
+	require.Len(t, putEntries, 1)
+	require.Empty(t, putEntries[0].CanonicalURL)
+}
+
+func TestMemoryStore_BackfillCanonicalURLs(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://Foo.bar/a"},
+		{Key: "bar", Value: "http://Bar.buz/a"},
+		{Key: "buz", Value: "http://bar.buz/a"},
+		{Key: "qux", Value: "http://Qux.foo:80/", CanonicalURL: "http://qux.foo/"},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(gomock.Any()).AnyTimes()
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        8,
+				KeyMaxLength:     100,
+				KeyMaxIterations: 10,
+			},
+		},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	count, err := store.BackfillCanonicalURLs(context.Background(), strings.ToLower)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// The canonical form of the URL stored before canonicalization is deduplicated.
+	_, duplicateErr := store.Save(context.Background(), &domain.SaveRequest{
+		OriginalURL:  "http://foo.bar/a",
+		CanonicalURL: "http://foo.bar/a",
+	}, uuid.New())
+	var duplicateURLError *DuplicateURLError
+	require.ErrorAs(t, duplicateErr, &duplicateURLError)
+	require.Equal(t, "foo", duplicateURLError.Key)
+
+	// The canonical form taken by another URL is not given to the URL.
+	_, duplicateErr = store.Save(context.Background(), &domain.SaveRequest{
+		OriginalURL:  "http://bar.buz/a",
+		CanonicalURL: "http://bar.buz/a",
+	}, uuid.New())
+	require.ErrorAs(t, duplicateErr, &duplicateURLError)
+	require.Equal(t, "buz", duplicateURLError.Key)
+}
+
 func TestMemoryStore_SaveBatch(t *testing.T) {
 	t.Parallel()
 
@@ -313,7 +1102,9 @@ func TestMemoryStore_SaveBatch(t *testing.T) {
 			args: &args{
 				items: []*domain.BatchRequestItem{
 					{
-						OriginalURL: "http://foo.bar",
+						SaveRequest: domain.SaveRequest{
+							OriginalURL: "http://foo.bar",
+						},
 					},
 				},
 			},
@@ -334,8 +1125,10 @@ func TestMemoryStore_SaveBatch(t *testing.T) {
 			args: &args{
 				items: []*domain.BatchRequestItem{
 					{
+						SaveRequest: domain.SaveRequest{
+							OriginalURL: "http://foo.bar",
+						},
 						CorrelationID: correlationID,
-						OriginalURL:   "http://foo.bar",
 					},
 				},
 			},
@@ -349,10 +1142,36 @@ func TestMemoryStore_SaveBatch(t *testing.T) {
 			checkResult: func(items []*domain.BatchResponseItem, err error) {
 				require.Len(t, items, 1)
 				require.Equal(t, correlationID, items[0].CorrelationID)
+				require.Equal(t, domain.BatchItemStatusCreated, items[0].Status)
 				require.GreaterOrEqual(t, len(items[0].Key), defaultConfiguration.MemoryStore.KeyLength)
 				require.NoError(t, err)
 			},
 		},
+		{
+			name: "WHEN some items can't be saved THEN other items saved",
+			args: &args{
+				items: []*domain.BatchRequestItem{
+					{SaveRequest: domain.SaveRequest{OriginalURL: "http://foo.bar", Alias: "foo"}, CorrelationID: "1"},
+					{SaveRequest: domain.SaveRequest{OriginalURL: "http://foo.bar"}, CorrelationID: "2"},
+					{SaveRequest: domain.SaveRequest{OriginalURL: "http://bar.buz", Alias: "foo"}, CorrelationID: "3"},
+					{SaveRequest: domain.SaveRequest{OriginalURL: "http://bar.buz", Alias: "api"}, CorrelationID: "4"},
+					{SaveRequest: domain.SaveRequest{OriginalURL: "http://bar.buz", Alias: "bar"}, CorrelationID: "5"},
+				},
+			},
+			hookBefore: func(mock *mocks.Mock, args *args) {
+				mock.ColdStore.EXPECT().Save(gomock.Any()).Times(2)
+			},
+			checkResult: func(items []*domain.BatchResponseItem, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*domain.BatchResponseItem{
+					{CorrelationID: "1", Key: "foo", Status: domain.BatchItemStatusCreated},
+					{CorrelationID: "2", Key: "foo", Status: domain.BatchItemStatusExisting},
+					{CorrelationID: "3", Status: domain.BatchItemStatusInvalid, Reason: "alias foo is already taken"},
+					{CorrelationID: "4", Status: domain.BatchItemStatusInvalid, Reason: "invalid alias: 'api' is reserved"},
+					{CorrelationID: "5", Key: "bar", Status: domain.BatchItemStatusCreated},
+				}, items)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -373,33 +1192,1100 @@ func TestMemoryStore_SaveBatch(t *testing.T) {
 func TestMemoryStore_DeleteBatch(t *testing.T) {
 	t.Parallel()
 
-	ctrl := gomock.NewController(t)
-	mock := mocks.NewMock(ctrl)
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	entries := []*domain.ColdStoreEntry{
+		{
+			Key:    "foo",
+			Value:  "http://foo.bar",
+			UserID: userID,
+		},
+		{
+			Key:    "bar",
+			Value:  "http://bar.buz",
+			UserID: otherUserID,
+		},
+	}
 
-	configuration := &config.Configuration{
-		MemoryStore: &config.MemoryStoreConfiguration{},
+	tests := []struct {
+		name        string
+		wantError   bool
+		wantDeleted map[string]bool
+		hookBefore  func(mock *mocks.Mock)
+	}{
+		{
+			name:        "WHEN cold store error THEN error and nothing deleted",
+			wantError:   true,
+			wantDeleted: map[string]bool{"foo": false, "bar": false},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:        "WHEN no errors THEN only own keys deleted",
+			wantDeleted: map[string]bool{"foo": true, "bar": false},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().
+					Save(gomock.Cond(func(entry *domain.ColdStoreEntry) bool {
+						return entry.Type == domain.ColdStoreEntryTypeDelete && entry.Key == "foo" && entry.DeletedAt != nil
+					})).
+					Return(nil)
+			},
+		},
 	}
-	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
 
-	err := store.DeleteBatch(context.Background(), []string{}, uuid.New())
-	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries(entries))
+			mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+			tt.hookBefore(mock)
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+			require.NoError(t, store.Init())
+
+			// Act.
+			err := store.DeleteBatch(context.Background(), []string{"foo", "bar", "missing"}, userID)
+
+			// Assert.
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for key, wantDeleted := range tt.wantDeleted {
+				item, err := store.Load(context.Background(), key)
+				require.NoError(t, err)
+				require.Equal(t, wantDeleted, item.IsDeleted)
+			}
+		})
+	}
 }
 
-func TestMemoryStore_GetStatistics(t *testing.T) {
+func TestMemoryStore_RestoreBatch(t *testing.T) {
 	t.Parallel()
 
-	// Arrange.
-	ctrl := gomock.NewController(t)
-	mock := mocks.NewMock(ctrl)
+	now := time.Now()
+	userID := uuid.New()
+	entries := []*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://foo.bar", UserID: userID, IsDeleted: true, DeletedAt: lo.ToPtr(now.Add(-time.Minute))},
+		{Key: "bar", Value: "http://bar.buz", UserID: userID, IsDeleted: true, DeletedAt: lo.ToPtr(now.Add(-time.Hour))},
+		{Key: "buz", Value: "http://buz.qux", UserID: uuid.New(), IsDeleted: true, DeletedAt: lo.ToPtr(now.Add(-time.Minute))},
+		{Key: "qux", Value: "http://qux.foo", UserID: userID},
+	}
 
-	configuration := &config.Configuration{
-		MemoryStore: &config.MemoryStoreConfiguration{},
+	tests := []struct {
+		name         string
+		want         []string
+		wantError    bool
+		wantRestored map[string]bool
+		hookBefore   func(mock *mocks.Mock)
+	}{
+		{
+			name:         "WHEN cold store error THEN error and nothing restored",
+			wantError:    true,
+			wantRestored: map[string]bool{"foo": false, "bar": false, "buz": false},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:         "WHEN no errors THEN only own keys deleted within grace period restored",
+			want:         []string{"foo"},
+			wantRestored: map[string]bool{"foo": true, "bar": false, "buz": false},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Cond(func(entry *domain.ColdStoreEntry) bool {
+					return entry.Type == domain.ColdStoreEntryTypeRestore && entry.Key == "foo" && entry.UpdatedAt != nil
+				})).Return(nil)
+			},
+		},
 	}
-	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
 
-	// Act-assert.
-	urlsCount, usersCount, err := store.GetStatistics(context.Background())
-	require.Zero(t, urlsCount)
-	require.Zero(t, usersCount)
-	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries(entries))
+			mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+			tt.hookBefore(mock)
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+			require.NoError(t, store.Init())
+
+			// Act.
+			restored, err := store.RestoreBatch(
+				context.Background(),
+				[]string{"foo", "bar", "buz", "qux", "missing"},
+				userID,
+				now.Add(-10*time.Minute))
+
+			// Assert.
+			require.Equal(t, tt.want, restored)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for key, wantRestored := range tt.wantRestored {
+				item, err := store.Load(context.Background(), key)
+				require.NoError(t, err)
+				require.Equal(t, !wantRestored, item.IsDeleted)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_UpdateURL(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	entries := []*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://foo.bar", UserID: userID},
+		{Key: "bar", Value: "http://bar.buz", UserID: userID},
+		{Key: "buz", Value: "http://buz.qux", UserID: uuid.New()},
+		{Key: "qux", Value: "http://qux.foo", UserID: userID, IsDeleted: true, DeletedAt: lo.ToPtr(time.Now())},
+		{Key: "old", Value: "http://old.foo", UserID: userID, ExpiresAt: lo.ToPtr(time.Now().Add(-time.Minute))},
+	}
+
+	tests := []struct {
+		name          string
+		key           string
+		value         string
+		want          int
+		wantError     bool
+		wantDuplicate string
+		wantValue     string
+		wantVersions  []string
+		hookBefore    func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN missing key THEN zero",
+			key:        "missing",
+			value:      "http://new.foo",
+			hookBefore: func(*mocks.Mock) {},
+		},
+		{
+			name:       "WHEN key of another user THEN zero",
+			key:        "buz",
+			value:      "http://new.foo",
+			wantValue:  "http://buz.qux",
+			hookBefore: func(*mocks.Mock) {},
+		},
+		{
+			name:       "WHEN deleted key THEN zero",
+			key:        "qux",
+			value:      "http://new.foo",
+			wantValue:  "http://qux.foo",
+			hookBefore: func(*mocks.Mock) {},
+		},
+		{
+			name:       "WHEN expired key THEN zero",
+			key:        "old",
+			value:      "http://new.foo",
+			hookBefore: func(*mocks.Mock) {},
+		},
+		{
+			name:       "WHEN same value THEN current version",
+			key:        "foo",
+			value:      "http://foo.bar",
+			want:       1,
+			wantValue:  "http://foo.bar",
+			hookBefore: func(*mocks.Mock) {},
+		},
+		{
+			name:          "WHEN value of another key THEN duplicate error",
+			key:           "foo",
+			value:         "http://bar.buz",
+			wantError:     true,
+			wantDuplicate: "bar",
+			wantValue:     "http://foo.bar",
+			hookBefore:    func(*mocks.Mock) {},
+		},
+		{
+			name:      "WHEN cold store error THEN error",
+			key:       "foo",
+			value:     "http://new.foo",
+			wantError: true,
+			wantValue: "http://foo.bar",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:         "WHEN no errors THEN value updated and previous one kept as version",
+			key:          "foo",
+			value:        "http://new.foo",
+			want:         2,
+			wantValue:    "http://new.foo",
+			wantVersions: []string{"http://foo.bar"},
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Cond(func(entry *domain.ColdStoreEntry) bool {
+					return entry.Type == domain.ColdStoreEntryTypeUpdate &&
+						entry.Key == "foo" &&
+						entry.Value == "http://new.foo" &&
+						entry.UpdatedAt != nil &&
+						entry.Version == 1
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries(entries))
+			mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+			tt.hookBefore(mock)
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+				DedupScope:  config.DedupScopeGlobal,
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+			require.NoError(t, store.Init())
+
+			// Act.
+			version, err := store.UpdateURL(context.Background(), &domain.UpdateRequest{Key: tt.key, OriginalURL: tt.value}, userID)
+
+			// Assert.
+			require.Equal(t, tt.want, version)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if len(tt.wantDuplicate) > 0 {
+				var duplicateURLError *DuplicateURLError
+				require.ErrorAs(t, err, &duplicateURLError)
+				require.Equal(t, tt.wantDuplicate, duplicateURLError.Key)
+			}
+
+			if len(tt.wantValue) > 0 {
+				item, err := store.Load(context.Background(), tt.key)
+				require.NoError(t, err)
+				require.Equal(t, tt.wantValue, item.URL)
+			}
+
+			versions, err := store.LoadVersions(context.Background(), tt.key)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.wantVersions, lo.Map(versions, func(version *domain.URLVersion, _ int) string {
+				return version.OriginalURL
+			}))
+		})
+	}
+}
+
+func TestMemoryStore_UpdateURL_Deduplication(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://foo.bar", UserID: userID},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(gomock.Any()).Return(nil).Times(2)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        8,
+				KeyMaxLength:     100,
+				KeyMaxIterations: 10,
+			},
+		},
+		DedupScope: config.DedupScopeGlobal,
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	version, updateErr := store.UpdateURL(context.Background(), &domain.UpdateRequest{Key: "foo", OriginalURL: "http://new.foo"}, userID)
+	newKey, oldErr := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: "http://foo.bar"}, userID)
+	_, newErr := store.Save(context.Background(), &domain.SaveRequest{OriginalURL: "http://new.foo"}, userID)
+
+	// Assert.
+	require.NoError(t, updateErr)
+	require.Equal(t, 2, version)
+	require.NoError(t, oldErr)
+	require.NotEqual(t, "foo", newKey)
+	var duplicateURLError *DuplicateURLError
+	require.ErrorAs(t, newErr, &duplicateURLError)
+	require.Equal(t, "foo", duplicateURLError.Key)
+}
+
+func TestMemoryStore_Init_RestoresUpdates(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	firstAt := time.Now().Add(-time.Hour)
+	secondAt := time.Now().Add(-time.Minute)
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", UserID: userID},
+		{Type: domain.ColdStoreEntryTypeUpdate, Key: "foo", Value: "http://bar.buz", UpdatedAt: &firstAt},
+		{Type: domain.ColdStoreEntryTypeUpdate, Key: "foo", Value: "http://buz.qux", UpdatedAt: &secondAt},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+	// Act.
+	err := store.Init()
+
+	// Assert.
+	require.NoError(t, err)
+	item, err := store.Load(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, "http://buz.qux", item.URL)
+	require.Equal(t, secondAt, item.UpdatedAt)
+	versions, err := store.LoadVersions(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, []*domain.URLVersion{
+		{Version: 2, OriginalURL: "http://bar.buz", ReplacedAt: secondAt},
+		{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: firstAt},
+	}, versions)
+}
+
+func TestMemoryStore_Init_SkipsAppliedUpdates(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	updatedAt := time.Now().Add(-time.Minute)
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	// The snapshot already has the update the journal replays.
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Type:      domain.ColdStoreEntryTypePut,
+			Key:       "foo",
+			Value:     "http://bar.buz",
+			UpdatedAt: &updatedAt,
+			Versions:  []*domain.URLVersion{{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: updatedAt}},
+		},
+		{Type: domain.ColdStoreEntryTypeUpdate, Key: "foo", Value: "http://bar.buz", UpdatedAt: &updatedAt, Version: 1},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+	// Act.
+	err := store.Init()
+
+	// Assert.
+	require.NoError(t, err)
+	versions, err := store.LoadVersions(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, []*domain.URLVersion{
+		{Version: 1, OriginalURL: "http://foo.bar", ReplacedAt: updatedAt},
+	}, versions)
+}
+
+func TestMemoryStore_UpdateVariants(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	variants := []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		userID     uuid.UUID
+		want       bool
+		wantError  bool
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN key not found THEN false",
+			key:        "buz",
+			userID:     userID,
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:       "WHEN key of another user THEN false",
+			key:        "foo",
+			userID:     uuid.New(),
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:       "WHEN key deleted THEN false",
+			key:        "bar",
+			userID:     userID,
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:      "WHEN cold store error THEN error",
+			key:       "foo",
+			userID:    userID,
+			wantError: true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:   "WHEN no errors THEN variants replaced",
+			key:    "foo",
+			userID: userID,
+			want:   true,
+			hookBefore: func(mock *mocks.Mock) {
+				mock.ColdStore.EXPECT().Save(gomock.Cond(func(entry *domain.ColdStoreEntry) bool {
+					return entry.Type == domain.ColdStoreEntryTypeUpdateVariants &&
+						entry.Key == "foo" &&
+						entry.UpdatedAt != nil &&
+						slices.Equal(entry.Variants, variants)
+				})).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+				{Key: "foo", Value: "http://foo.bar", UserID: userID},
+				{Key: "bar", Value: "http://bar.buz", UserID: userID, IsDeleted: true},
+			}))
+			mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+			tt.hookBefore(mock)
+
+			configuration := &config.Configuration{
+				MemoryStore: &config.MemoryStoreConfiguration{},
+			}
+			store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+			require.NoError(t, store.Init())
+
+			// Act.
+			updated, err := store.UpdateVariants(context.Background(), tt.key, variants, tt.userID)
+
+			// Assert.
+			require.Equal(t, tt.want, updated)
+			if tt.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			item, err := store.Load(context.Background(), "foo")
+			require.NoError(t, err)
+			if tt.want {
+				require.Equal(t, variants, item.Variants)
+			} else {
+				require.Empty(t, item.Variants)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Init_RestoresVariants(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	updatedAt := time.Now().Add(-time.Minute)
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Type:  domain.ColdStoreEntryTypePut,
+			Key:   "foo",
+			Value: "http://foo.bar",
+			Variants: []*domain.URLVariant{
+				{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+				{Name: "B", URL: "http://foo.bar/b", Weight: 1},
+			},
+		},
+		{
+			Type:      domain.ColdStoreEntryTypeUpdateVariants,
+			Key:       "foo",
+			UpdatedAt: &updatedAt,
+			Variants: []*domain.URLVariant{
+				{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+				{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+			},
+		},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+	// Act.
+	err := store.Init()
+
+	// Assert.
+	require.NoError(t, err)
+	item, err := store.Load(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, "http://foo.bar", item.URL)
+	require.Equal(t, updatedAt, item.UpdatedAt)
+	require.Equal(t, []*domain.URLVariant{
+		{Name: "A", URL: "http://foo.bar/a", Weight: 1},
+		{Name: "B", URL: "http://foo.bar/b", Weight: 0},
+	}, item.Variants)
+}
+
+func TestMemoryStore_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	now := time.Now()
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://foo.bar", IsDeleted: true, DeletedAt: lo.ToPtr(now.Add(-time.Hour))},
+		{Key: "bar", Value: "http://bar.buz", IsDeleted: true, DeletedAt: lo.ToPtr(now.Add(-time.Minute))},
+		{Key: "buz", Value: "http://buz.qux"},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(&domain.ColdStoreEntry{
+		Type: domain.ColdStoreEntryTypePurge,
+		Key:  "foo",
+	}).Return(nil)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	count, err := store.PurgeDeleted(context.Background(), now.Add(-10*time.Minute))
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	memoryStore := store.(*MemoryStore)
+	require.ElementsMatch(t, []string{"bar", "buz"}, lo.Keys(memoryStore.keyToItemMap))
+	// The URL of the purged key can be shortened again.
+	require.NotContains(t, memoryStore.valueToKeyMap, "http://foo.bar")
+}
+
+func TestMemoryStore_DeleteExpired(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	now := time.Now()
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Key:       "foo",
+			Value:     "http://foo.bar",
+			ExpiresAt: lo.ToPtr(now.Add(time.Minute)),
+		},
+		{
+			Key:   "bar",
+			Value: "http://bar.buz",
+		},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePurge, Key: "foo"}).Return(nil)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	notExpiredCount, notExpiredErr := store.DeleteExpired(context.Background(), now)
+	expiredCount, expiredErr := store.DeleteExpired(context.Background(), now.Add(time.Hour))
+
+	// Assert.
+	require.Zero(t, notExpiredCount)
+	require.NoError(t, notExpiredErr)
+	require.Equal(t, 1, expiredCount)
+	require.NoError(t, expiredErr)
+
+	expiredItem, err := store.Load(context.Background(), "foo")
+	require.Nil(t, expiredItem)
+	require.NoError(t, err)
+
+	item, err := store.Load(context.Background(), "bar")
+	require.NotNil(t, item)
+	require.NoError(t, err)
+}
+
+func TestMemoryStore_SaveClicks(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Key:   "foo",
+			Value: "http://foo.bar",
+		},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	now := time.Now()
+	clicks := []*domain.Click{
+		{Key: "foo", Timestamp: now},
+		{Key: "foo", Timestamp: now.Add(-time.Second)},
+		{Key: "bar", Timestamp: now},
+	}
+	mock.ColdStore.EXPECT().Save(&domain.ColdStoreEntry{
+		Type:           domain.ColdStoreEntryTypeAccess,
+		Key:            "foo",
+		LastAccessedAt: &now,
+	}).Return(nil)
+
+	// Act.
+	err := store.SaveClicks(context.Background(), clicks)
+
+	// Assert.
+	require.NoError(t, err)
+	memoryStore := store.(*MemoryStore)
+	require.Len(t, memoryStore.keyToClicks["foo"], 2)
+	require.NotContains(t, memoryStore.keyToClicks, "bar")
+	require.Equal(t, &now, memoryStore.keyToItemMap["foo"].lastAccessedAt)
+}
+
+func TestMemoryStore_GetClickStatistics(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{
+			Key:   "foo",
+			Value: "http://foo.bar",
+		},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	day := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	clicks := []*domain.Click{
+		{Key: "foo", Timestamp: day.Add(-time.Minute), IPHash: "a"},
+		{Key: "foo", Timestamp: day.Add(10 * time.Minute), IPHash: "a", Referrer: "r1", UserAgent: "ua1", Variant: "A"},
+		{Key: "foo", Timestamp: day.Add(20 * time.Minute), IPHash: "b", Referrer: "r2", UserAgent: "ua1", Variant: "B"},
+		{Key: "foo", Timestamp: day.Add(2 * time.Hour), IPHash: "a", Referrer: "r2", Variant: "B"},
+		{Key: "foo", Timestamp: day.Add(3 * time.Hour)},
+	}
+	mock.ColdStore.EXPECT().Save(gomock.Any()).Return(nil)
+	require.NoError(t, store.SaveClicks(context.Background(), clicks))
+
+	request := &domain.ClickStatisticsRequest{
+		Key:      "foo",
+		From:     day,
+		To:       day.Add(3 * time.Hour),
+		Bucket:   domain.ClickBucketHour,
+		TopCount: 1,
+	}
+
+	// Act.
+	statistics, err := store.GetClickStatistics(context.Background(), request)
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, &domain.ClickStatistics{
+		TotalClicks:    3,
+		UniqueVisitors: 2,
+		Buckets: []*domain.ClickBucketCount{
+			{Start: day, Count: 2},
+			{Start: day.Add(2 * time.Hour), Count: 1},
+		},
+		TopReferrers: []*domain.ClickValueCount{
+			{Value: "r2", Count: 2},
+		},
+		TopUserAgents: []*domain.ClickValueCount{
+			{Value: "ua1", Count: 2},
+		},
+		// Variants are not limited by the top count.
+		Variants: []*domain.ClickValueCount{
+			{Value: "B", Count: 2},
+			{Value: "A", Count: 1},
+		},
+	}, statistics)
+}
+
+func TestMemoryStore_GetStatistics(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Key: "foo", Value: "http://foo.bar", UserID: userID},
+		{Key: "bar", Value: "http://bar.buz", UserID: userID},
+		{Key: "buz", Value: "http://buz.foo", UserID: uuid.New()},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+
+	// Act.
+	urlsCount, usersCount, err := store.GetStatistics(context.Background())
+
+	// Assert.
+	require.Equal(t, 3, urlsCount)
+	require.Equal(t, 2, usersCount)
+	require.NoError(t, err)
+}
+
+// restoreEntries mocks ColdStore.LoadAll passing the given entries to restore.
+func TestMemoryStore_Collections(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctx := context.Background()
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", UserID: userID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", UserID: userID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buz", Value: "http://buz.qux", UserID: otherUserID},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(gomock.Any()).Return(nil).AnyTimes()
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+	createdAt := time.Now()
+	work := &domain.Collection{ID: uuid.New(), UserID: userID, Name: "Work", CreatedAt: createdAt}
+	home := &domain.Collection{ID: uuid.New(), UserID: userID, Name: "Home", CreatedAt: createdAt}
+	loadKeys := func(id uuid.UUID) []string {
+		items, err := store.LoadAllByUserID(ctx, userID, &domain.UserURLsQuery{Sort: domain.UserURLsSortKey, Collection: id})
+		require.NoError(t, err)
+		return lo.Map(items, func(item *domain.KeyOriginalURLItem, _ int) string { return item.URLKey })
+	}
+
+	// Act-assert.
+	require.NoError(t, store.SaveCollection(ctx, work))
+	require.NoError(t, store.SaveCollection(ctx, home))
+	require.ErrorIs(t, store.SaveCollection(ctx, &domain.Collection{ID: uuid.New(), UserID: userID, Name: "Work"}), ErrCollectionExists)
+	require.NoError(t, store.SaveCollection(ctx, &domain.Collection{ID: uuid.New(), UserID: otherUserID, Name: "Work"}))
+
+	// Keys of other users and missing keys are skipped.
+	found, err := store.AddCollectionURLs(ctx, work.ID, []string{"foo", "buz", "missing", "bar", "foo"}, userID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []string{"bar", "foo"}, loadKeys(work.ID))
+	found, err = store.AddCollectionURLs(ctx, work.ID, []string{"buz"}, otherUserID)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	loaded, err := store.LoadCollection(ctx, work.ID, userID)
+	require.NoError(t, err)
+	require.Equal(t, &domain.Collection{ID: work.ID, UserID: userID, Name: "Work", CreatedAt: createdAt, URLCount: 2}, loaded)
+	loaded, err = store.LoadCollection(ctx, work.ID, otherUserID)
+	require.NoError(t, err)
+	require.Nil(t, loaded)
+	require.Empty(t, lo.Must(store.LoadAllByUserID(ctx, otherUserID, &domain.UserURLsQuery{Collection: work.ID})))
+
+	_, err = store.RenameCollection(ctx, home.ID, "Work", userID)
+	require.ErrorIs(t, err, ErrCollectionExists)
+	found, err = store.RenameCollection(ctx, home.ID, "Family", userID)
+	require.NoError(t, err)
+	require.True(t, found)
+	collections, err := store.LoadCollections(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Family", "Work"}, lo.Map(collections, func(collection *domain.Collection, _ int) string {
+		return collection.Name
+	}))
+
+	found, err = store.RemoveCollectionURLs(ctx, work.ID, []string{"foo"}, userID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, []string{"bar"}, loadKeys(work.ID))
+
+	found, err = store.DeleteCollection(ctx, work.ID, otherUserID)
+	require.NoError(t, err)
+	require.False(t, found)
+	found, err = store.DeleteCollection(ctx, work.ID, userID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Empty(t, loadKeys(work.ID))
+	// The URLs of the collection are kept.
+	item, err := store.Load(ctx, "bar")
+	require.NoError(t, err)
+	require.NotNil(t, item)
+}
+
+func TestMemoryStore_Collections_ColdStoreError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctx := context.Background()
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", UserID: userID},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	collection := &domain.Collection{ID: uuid.New(), UserID: userID, Name: "Work", CreatedAt: time.Now()}
+	mock.ColdStore.EXPECT().Save(&domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypeCollection,
+		Key:       collection.ID.String(),
+		Value:     "Work",
+		UserID:    userID,
+		CreatedAt: &collection.CreatedAt,
+	}).Return(nil)
+	mock.ColdStore.EXPECT().Save(gomock.Any()).Return(assert.AnError)
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+	require.NoError(t, store.SaveCollection(ctx, collection))
+
+	// Act.
+	found, err := store.AddCollectionURLs(ctx, collection.ID, []string{"foo"}, userID)
+
+	// Assert.
+	require.ErrorIs(t, err, assert.AnError)
+	require.False(t, found)
+	loaded, err := store.LoadCollection(ctx, collection.ID, userID)
+	require.NoError(t, err)
+	require.Zero(t, loaded.URLCount)
+}
+
+func TestMemoryStore_Init_RestoresCollections(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	kept := uuid.New()
+	deleted := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", UserID: userID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", UserID: userID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buz", Value: "http://buz.qux", UserID: userID, ExpiresAt: lo.ToPtr(time.Now().Add(-time.Hour))},
+		{Type: domain.ColdStoreEntryTypeCollection, Key: kept.String(), Value: "Foo", UserID: userID, Keys: []string{"bar", "buz", "foo"}},
+		{Type: domain.ColdStoreEntryTypeCollection, Key: deleted.String(), Value: "Bar", UserID: userID},
+		{Type: domain.ColdStoreEntryTypeDeleteCollection, Key: deleted.String()},
+		{Type: domain.ColdStoreEntryTypePurge, Key: "buz"},
+		{Type: domain.ColdStoreEntryTypePurge, Key: "foo"},
+		// The purged key is taken again, it is not in the collection.
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.qux", UserID: userID},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+	// Act.
+	err := store.Init()
+
+	// Assert.
+	require.NoError(t, err)
+	collections, err := store.LoadCollections(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	require.Equal(t, kept, collections[0].ID)
+	require.Equal(t, "Foo", collections[0].Name)
+	require.Equal(t, 1, collections[0].URLCount)
+	require.Equal(t, []string{"bar"}, store.(*MemoryStore).idToCollection[kept].keys)
+}
+
+func TestMemoryStore_Campaigns(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctx := context.Background()
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).Return(nil)
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+	mock.ColdStore.EXPECT().Save(gomock.Any()).Return(nil).AnyTimes()
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{
+			KeyStoreConfiguration: config.KeyStoreConfiguration{
+				KeyLength:        1,
+				KeyMaxLength:     10,
+				KeyMaxIterations: 10,
+			},
+		},
+		KeyGenerator: &config.KeyGeneratorConfiguration{
+			Type:      config.KeyGeneratorSequence,
+			Alphabet:  "0123456789",
+			BlockSize: 2,
+		},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+	require.NoError(t, store.Init())
+	createdAt := time.Now()
+	spring := &domain.Campaign{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       "Spring",
+		CreatedAt:  createdAt,
+		Parameters: map[string]string{"utm_source": "news", "utm_campaign": "spring"},
+	}
+	autumn := &domain.Campaign{ID: uuid.New(), UserID: userID, Name: "Autumn", CreatedAt: createdAt}
+
+	// Act-assert.
+	require.NoError(t, store.SaveCampaign(ctx, spring))
+	require.NoError(t, store.SaveCampaign(ctx, autumn))
+	require.ErrorIs(t, store.SaveCampaign(ctx, &domain.Campaign{ID: uuid.New(), UserID: userID, Name: "Spring"}), ErrCampaignExists)
+	require.NoError(t, store.SaveCampaign(ctx, &domain.Campaign{ID: uuid.New(), UserID: otherUserID, Name: "Spring"}))
+
+	loaded, err := store.LoadCampaign(ctx, spring.ID, userID)
+	require.NoError(t, err)
+	require.Equal(t, spring, loaded)
+	loaded, err = store.LoadCampaign(ctx, spring.ID, otherUserID)
+	require.NoError(t, err)
+	require.Nil(t, loaded)
+
+	key, err := store.Save(ctx, &domain.SaveRequest{OriginalURL: "http://foo.bar", CampaignID: spring.ID}, userID)
+	require.NoError(t, err)
+	item, err := store.Load(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, spring.Parameters, item.CampaignParameters)
+
+	_, err = store.UpdateCampaign(ctx, &domain.Campaign{ID: autumn.ID, UserID: userID, Name: "Spring"})
+	require.ErrorIs(t, err, ErrCampaignExists)
+	found, err := store.UpdateCampaign(ctx, &domain.Campaign{ID: spring.ID, UserID: otherUserID, Name: "Summer"})
+	require.NoError(t, err)
+	require.False(t, found)
+	found, err = store.UpdateCampaign(ctx, &domain.Campaign{
+		ID:         spring.ID,
+		UserID:     userID,
+		Name:       "Summer",
+		Parameters: map[string]string{"utm_source": "mail"},
+	})
+	require.NoError(t, err)
+	require.True(t, found)
+	campaigns, err := store.LoadCampaigns(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Autumn", "Summer"}, lo.Map(campaigns, func(campaign *domain.Campaign, _ int) string {
+		return campaign.Name
+	}))
+	require.Equal(t, createdAt, campaigns[1].CreatedAt)
+	item, err = store.Load(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"utm_source": "mail"}, item.CampaignParameters)
+
+	found, err = store.DeleteCampaign(ctx, spring.ID, otherUserID)
+	require.NoError(t, err)
+	require.False(t, found)
+	found, err = store.DeleteCampaign(ctx, spring.ID, userID)
+	require.NoError(t, err)
+	require.True(t, found)
+	// The URLs of the campaign are kept without it.
+	item, err = store.Load(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	require.Nil(t, item.CampaignParameters)
+	require.Equal(t, uuid.Nil, store.(*MemoryStore).keyToItemMap[key].campaignID)
+}
+
+func TestMemoryStore_Init_RestoresCampaigns(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	userID := uuid.New()
+	kept := uuid.New()
+	deleted := uuid.New()
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.ColdStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(restoreEntries([]*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypeCampaign, Key: kept.String(), Value: "Foo", UserID: userID, Parameters: map[string]string{"utm_source": "foo"}},
+		{Type: domain.ColdStoreEntryTypeCampaign, Key: deleted.String(), Value: "Bar", UserID: userID},
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", UserID: userID, CampaignID: kept},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", UserID: userID, CampaignID: deleted},
+		{Type: domain.ColdStoreEntryTypeDeleteCampaign, Key: deleted.String()},
+	}))
+	mock.Logger.EXPECT().Infof(gomock.Any(), gomock.Any())
+
+	configuration := &config.Configuration{
+		MemoryStore: &config.MemoryStoreConfiguration{},
+	}
+	store := NewMemoryStore(mock.ColdStore, configuration, mock.Logger)
+
+	// Act.
+	err := store.Init()
+
+	// Assert.
+	require.NoError(t, err)
+	campaigns, err := store.LoadCampaigns(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, campaigns, 1)
+	require.Equal(t, kept, campaigns[0].ID)
+	item, err := store.Load(context.Background(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"utm_source": "foo"}, item.CampaignParameters)
+	require.Equal(t, uuid.Nil, store.(*MemoryStore).keyToItemMap["bar"].campaignID)
+}
+
+func restoreEntries(entries []*domain.ColdStoreEntry) func(func(*domain.ColdStoreEntry)) error {
+	return func(restore func(*domain.ColdStoreEntry)) error {
+		for _, entry := range entries {
+			restore(entry)
+		}
+
+		return nil
+	}
 }
@@ -0,0 +1,933 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/aleffnull/shortener/internal/domain"
+)
+
+// Snapshot file layout, all fixed size integers are little endian:
+//
+//	header: magic "SHRTSNAP", format version uint32, last leased key sequence value uint64
+//	block:  payload length uint32, record count uint32, CRC-32C of the payload uint32, payload
+//	end:    block header with zero payload length and zero record count
+//
+// The payload is a sequence of records, each prefixed with its uvarint encoded length.
+// A record is a flags byte, the key and the value as uvarint length followed by bytes,
+// if flagged, the canonical URL in the same way, the 16 bytes of the user ID and,
+// if flagged, the expiration, deletion, creation, update and last access times as varint Unix nanoseconds and,
+// if flagged, the uvarint number of the previous versions, each as uvarint version, value string and replacement time.
+// The flags byte is full, so the metadata goes last without a flag: records with a title, notes or tags end with
+// the title and notes strings, the uvarint number of tags and the tag strings, other records end before it.
+// Records with a redirect code or options have the metadata, even an empty one, followed by the uvarint
+// redirect code, zero for the deployment default, and, if there are options, the options byte
+// followed by the 16 bytes of the campaign ID, if flagged, the redirect rules, if flagged, the variants, if flagged,
+// and the deduplication scope string, if flagged.
+// The rules are the uvarint number of rules, each as the platform, language, CIDR and URL strings,
+// a byte flagging the start and the end of the time window and the flagged times.
+// The variants are the uvarint number of variants, each as the name and URL strings and the uvarint weight.
+// Version 4 snapshots have the collections and then the campaigns after the URLs, every section in blocks
+// of its own with its own end marker. A collection record is the ID and the name as strings, the 16 bytes
+// of the user ID, the creation time and the uvarint number of URL keys followed by the key strings.
+// A campaign record is the same up to the creation time, followed by the uvarint number of parameters,
+// each as name and value strings, sorted by name.
+// Version 3 snapshots have no campaigns, version 2 ones also have no collections,
+// version 1 snapshots also have no key sequence in the header.
+const (
+	snapshotMagic     = "SHRTSNAP"
+	snapshotVersion   = 4
+	snapshotVersionV3 = 3
+	snapshotVersionV2 = 2
+	snapshotVersionV1 = 1
+
+	snapshotHeaderSize      = len(snapshotMagic) + 4 + 8
+	snapshotHeaderSizeV1    = len(snapshotMagic) + 4
+	snapshotBlockHeaderSize = 12
+	// Blocks are flushed once the payload grows beyond this size.
+	snapshotBlockSize = 64 * 1024
+	// Protects from huge allocations when the block header is damaged.
+	maxSnapshotBlockSize = 64 * 1024 * 1024
+)
+
+const (
+	snapshotRecordFlagExpires byte = 1 << iota
+	snapshotRecordFlagDeleted
+	snapshotRecordFlagCanonical
+	snapshotRecordFlagDeletedAt
+	snapshotRecordFlagCreatedAt
+	snapshotRecordFlagUpdatedAt
+	snapshotRecordFlagLastAccessedAt
+	snapshotRecordFlagVersions
+)
+
+// The flags of the options byte at the end of a record.
+const (
+	snapshotRecordOptionPassthrough byte = 1 << iota
+	snapshotRecordOptionCampaign
+	snapshotRecordOptionRules
+	snapshotRecordOptionVariants
+	snapshotRecordOptionDedupScope
+
+	snapshotRecordOptionsAll = snapshotRecordOptionPassthrough | snapshotRecordOptionCampaign | snapshotRecordOptionRules |
+		snapshotRecordOptionVariants | snapshotRecordOptionDedupScope
+)
+
+// The flags of the time window of a redirect rule.
+const (
+	snapshotRuleFlagFrom byte = 1 << iota
+	snapshotRuleFlagTo
+
+	snapshotRuleFlagsAll = snapshotRuleFlagFrom | snapshotRuleFlagTo
+)
+
+// Snapshot sections in the order they are written.
+const (
+	snapshotSectionURLs = iota
+	snapshotSectionCollections
+	snapshotSectionCampaigns
+)
+
+var (
+	snapshotCRCTable         = crc32.MakeTable(crc32.Castagnoli)
+	errInvalidSnapshotRecord = fmt.Errorf("%w: record is invalid", ErrSnapshotCorrupted)
+)
+
+type snapshotWriter struct {
+	writer       *bufio.Writer
+	block        []byte
+	blockRecords uint32
+	records      int64
+	// The section of the written records, see snapshotSectionURLs.
+	section int
+}
+
+func newSnapshotWriter(writer io.Writer, sequence uint64) (*snapshotWriter, error) {
+	bufferedWriter := bufio.NewWriter(writer)
+
+	header := make([]byte, 0, snapshotHeaderSize)
+	header = append(header, snapshotMagic...)
+	header = binary.LittleEndian.AppendUint32(header, snapshotVersion)
+	header = binary.LittleEndian.AppendUint64(header, sequence)
+	if _, err := bufferedWriter.Write(header); err != nil {
+		return nil, fmt.Errorf("newSnapshotWriter, writer.Write failed: %w", err)
+	}
+
+	return &snapshotWriter{
+		writer: bufferedWriter,
+		block:  make([]byte, 0, snapshotBlockSize),
+	}, nil
+}
+
+// write adds a put, a collection or a campaign entry to the snapshot, entry type is not stored.
+// Collection entries must go after all put entries, campaign entries after all collection ones.
+func (w *snapshotWriter) write(entry *domain.ColdStoreEntry) error {
+	var section int
+	var record []byte
+	switch entry.Type {
+	case domain.ColdStoreEntryTypeCollection:
+		section = snapshotSectionCollections
+		record = encodeSnapshotCollectionRecord(entry)
+	case domain.ColdStoreEntryTypeCampaign:
+		section = snapshotSectionCampaigns
+		record = encodeSnapshotCampaignRecord(entry)
+	default:
+		section = snapshotSectionURLs
+		record = encodeSnapshotRecord(entry)
+	}
+
+	if section < w.section {
+		return fmt.Errorf("snapshotWriter.write, entry %v goes after the next section", entry.Key)
+	}
+
+	for w.section < section {
+		if err := w.closeSection(); err != nil {
+			return err
+		}
+
+		w.section++
+	}
+
+	w.block = binary.AppendUvarint(w.block, uint64(len(record)))
+	w.block = append(w.block, record...)
+	w.blockRecords++
+	w.records++
+
+	if len(w.block) >= snapshotBlockSize {
+		return w.flushBlock()
+	}
+
+	return nil
+}
+
+// close writes the remaining records and the end markers. The underlying writer is not closed.
+func (w *snapshotWriter) close() error {
+	for ; w.section <= snapshotSectionCampaigns; w.section++ {
+		if err := w.closeSection(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.writer.Flush(); err != nil {
+		return fmt.Errorf("snapshotWriter.close, writer.Flush failed: %w", err)
+	}
+
+	return nil
+}
+
+// closeSection writes the remaining records of the section and its end marker.
+func (w *snapshotWriter) closeSection() error {
+	if err := w.flushBlock(); err != nil {
+		return err
+	}
+
+	if _, err := w.writer.Write(make([]byte, snapshotBlockHeaderSize)); err != nil {
+		return fmt.Errorf("snapshotWriter.closeSection, writer.Write failed: %w", err)
+	}
+
+	return nil
+}
+
+func (w *snapshotWriter) flushBlock() error {
+	if w.blockRecords == 0 {
+		return nil
+	}
+
+	header := make([]byte, 0, snapshotBlockHeaderSize)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(w.block)))
+	header = binary.LittleEndian.AppendUint32(header, w.blockRecords)
+	header = binary.LittleEndian.AppendUint32(header, crc32.Checksum(w.block, snapshotCRCTable))
+	if _, err := w.writer.Write(header); err != nil {
+		return fmt.Errorf("snapshotWriter.flushBlock, writer.Write failed: %w", err)
+	}
+
+	if _, err := w.writer.Write(w.block); err != nil {
+		return fmt.Errorf("snapshotWriter.flushBlock, writer.Write failed: %w", err)
+	}
+
+	w.block = w.block[:0]
+	w.blockRecords = 0
+	return nil
+}
+
+// readSnapshot passes every snapshot record to restore as a put, a collection or a campaign entry
+// and returns the number of records.
+// A non-zero key sequence from the header is passed first as a sequence entry, it is not counted as a record.
+func readSnapshot(reader io.Reader, restore func(*domain.ColdStoreEntry)) (int64, error) {
+	bufferedReader := bufio.NewReaderSize(reader, snapshotBlockSize)
+
+	header := make([]byte, snapshotHeaderSizeV1)
+	if _, err := io.ReadFull(bufferedReader, header); err != nil {
+		return 0, fmt.Errorf("%w: header is truncated", ErrSnapshotCorrupted)
+	}
+
+	if !bytes.Equal(header[:len(snapshotMagic)], []byte(snapshotMagic)) {
+		return 0, fmt.Errorf("%w: not a snapshot file", ErrSnapshotCorrupted)
+	}
+
+	version := binary.LittleEndian.Uint32(header[len(snapshotMagic):])
+	switch version {
+	case snapshotVersionV1:
+	case snapshotVersionV2, snapshotVersionV3, snapshotVersion:
+		sequence := make([]byte, snapshotHeaderSize-snapshotHeaderSizeV1)
+		if _, err := io.ReadFull(bufferedReader, sequence); err != nil {
+			return 0, fmt.Errorf("%w: header is truncated", ErrSnapshotCorrupted)
+		}
+
+		if value := binary.LittleEndian.Uint64(sequence); value > 0 {
+			restore(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeSequence, Sequence: value})
+		}
+	default:
+		return 0, fmt.Errorf("unsupported snapshot version %v", version)
+	}
+
+	records, err := readSnapshotSection(bufferedReader, decodeSnapshotRecord, restore)
+	if err != nil || version < snapshotVersionV3 {
+		return records, err
+	}
+
+	collections, err := readSnapshotSection(bufferedReader, decodeSnapshotCollectionRecord, restore)
+	if err != nil || version < snapshotVersion {
+		return records + collections, err
+	}
+
+	campaigns, err := readSnapshotSection(bufferedReader, decodeSnapshotCampaignRecord, restore)
+	if err != nil {
+		return 0, err
+	}
+
+	return records + collections + campaigns, nil
+}
+
+// readSnapshotSection reads the blocks up to the end marker, every record is decoded with decode.
+func readSnapshotSection(
+	bufferedReader *bufio.Reader,
+	decode func([]byte) (*domain.ColdStoreEntry, error),
+	restore func(*domain.ColdStoreEntry),
+) (int64, error) {
+	records := int64(0)
+	blockHeader := make([]byte, snapshotBlockHeaderSize)
+	block := []byte{}
+	for {
+		if _, err := io.ReadFull(bufferedReader, blockHeader); err != nil {
+			// The end marker is written last, without it the snapshot is incomplete.
+			return 0, fmt.Errorf("%w: end marker is missing", ErrSnapshotCorrupted)
+		}
+
+		size := binary.LittleEndian.Uint32(blockHeader)
+		count := binary.LittleEndian.Uint32(blockHeader[4:])
+		checksum := binary.LittleEndian.Uint32(blockHeader[8:])
+		if size == 0 && count == 0 {
+			return records, nil
+		}
+
+		if size > maxSnapshotBlockSize {
+			return 0, fmt.Errorf("%w: block of %v bytes is too large", ErrSnapshotCorrupted, size)
+		}
+
+		if cap(block) < int(size) {
+			block = make([]byte, size)
+		}
+		block = block[:size]
+		if _, err := io.ReadFull(bufferedReader, block); err != nil {
+			return 0, fmt.Errorf("%w: block is truncated", ErrSnapshotCorrupted)
+		}
+
+		if crc32.Checksum(block, snapshotCRCTable) != checksum {
+			return 0, fmt.Errorf("%w: block checksum mismatch", ErrSnapshotCorrupted)
+		}
+
+		if err := decodeSnapshotBlock(block, count, decode, restore); err != nil {
+			return 0, err
+		}
+
+		records += int64(count)
+	}
+}
+
+func decodeSnapshotBlock(
+	block []byte,
+	count uint32,
+	decode func([]byte) (*domain.ColdStoreEntry, error),
+	restore func(*domain.ColdStoreEntry),
+) error {
+	for range count {
+		size, n := binary.Uvarint(block)
+		if n <= 0 || size > uint64(len(block)-n) {
+			return fmt.Errorf("%w: record length is invalid", ErrSnapshotCorrupted)
+		}
+
+		entry, err := decode(block[n : n+int(size)])
+		if err != nil {
+			return err
+		}
+
+		restore(entry)
+		block = block[n+int(size):]
+	}
+
+	if len(block) > 0 {
+		return fmt.Errorf("%w: block has trailing data", ErrSnapshotCorrupted)
+	}
+
+	return nil
+}
+
+func encodeSnapshotRecord(entry *domain.ColdStoreEntry) []byte {
+	flags := byte(0)
+	if entry.ExpiresAt != nil {
+		flags |= snapshotRecordFlagExpires
+	}
+	if entry.IsDeleted {
+		flags |= snapshotRecordFlagDeleted
+	}
+	if len(entry.CanonicalURL) > 0 {
+		flags |= snapshotRecordFlagCanonical
+	}
+	if entry.DeletedAt != nil {
+		flags |= snapshotRecordFlagDeletedAt
+	}
+	if entry.CreatedAt != nil {
+		flags |= snapshotRecordFlagCreatedAt
+	}
+	if entry.UpdatedAt != nil {
+		flags |= snapshotRecordFlagUpdatedAt
+	}
+	if entry.LastAccessedAt != nil {
+		flags |= snapshotRecordFlagLastAccessedAt
+	}
+	if len(entry.Versions) > 0 {
+		flags |= snapshotRecordFlagVersions
+	}
+
+	size := 1 + 3*binary.MaxVarintLen64 + len(entry.Key) + len(entry.Value) + len(entry.CanonicalURL) + len(uuid.UUID{}) + 5*binary.MaxVarintLen64
+	record := make([]byte, 0, size)
+	record = append(record, flags)
+	record = binary.AppendUvarint(record, uint64(len(entry.Key)))
+	record = append(record, entry.Key...)
+	record = binary.AppendUvarint(record, uint64(len(entry.Value)))
+	record = append(record, entry.Value...)
+	if len(entry.CanonicalURL) > 0 {
+		record = binary.AppendUvarint(record, uint64(len(entry.CanonicalURL)))
+		record = append(record, entry.CanonicalURL...)
+	}
+	record = append(record, entry.UserID[:]...)
+	if entry.ExpiresAt != nil {
+		record = binary.AppendVarint(record, entry.ExpiresAt.UnixNano())
+	}
+	if entry.DeletedAt != nil {
+		record = binary.AppendVarint(record, entry.DeletedAt.UnixNano())
+	}
+	if entry.CreatedAt != nil {
+		record = binary.AppendVarint(record, entry.CreatedAt.UnixNano())
+	}
+	if entry.UpdatedAt != nil {
+		record = binary.AppendVarint(record, entry.UpdatedAt.UnixNano())
+	}
+	if entry.LastAccessedAt != nil {
+		record = binary.AppendVarint(record, entry.LastAccessedAt.UnixNano())
+	}
+	if len(entry.Versions) > 0 {
+		record = binary.AppendUvarint(record, uint64(len(entry.Versions)))
+		for _, version := range entry.Versions {
+			record = binary.AppendUvarint(record, uint64(version.Version))
+			record = appendSnapshotString(record, version.OriginalURL)
+			record = binary.AppendVarint(record, version.ReplacedAt.UnixNano())
+		}
+	}
+	var options byte
+	if entry.Passthrough {
+		options |= snapshotRecordOptionPassthrough
+	}
+	if entry.CampaignID != uuid.Nil {
+		options |= snapshotRecordOptionCampaign
+	}
+	if len(entry.Rules) > 0 {
+		options |= snapshotRecordOptionRules
+	}
+	if len(entry.Variants) > 0 {
+		options |= snapshotRecordOptionVariants
+	}
+	if len(entry.DedupScope) > 0 {
+		options |= snapshotRecordOptionDedupScope
+	}
+	if len(entry.Title) > 0 || len(entry.Notes) > 0 || len(entry.Tags) > 0 || entry.RedirectCode > 0 || options != 0 {
+		record = appendSnapshotString(record, entry.Title)
+		record = appendSnapshotString(record, entry.Notes)
+		record = binary.AppendUvarint(record, uint64(len(entry.Tags)))
+		for _, tag := range entry.Tags {
+			record = appendSnapshotString(record, tag)
+		}
+	}
+	if entry.RedirectCode > 0 || options != 0 {
+		record = binary.AppendUvarint(record, uint64(entry.RedirectCode))
+	}
+	if options != 0 {
+		record = append(record, options)
+	}
+	if entry.CampaignID != uuid.Nil {
+		record = append(record, entry.CampaignID[:]...)
+	}
+	if len(entry.Rules) > 0 {
+		record = appendSnapshotRules(record, entry.Rules)
+	}
+	if len(entry.Variants) > 0 {
+		record = appendSnapshotVariants(record, entry.Variants)
+	}
+	if len(entry.DedupScope) > 0 {
+		record = appendSnapshotString(record, entry.DedupScope)
+	}
+
+	return record
+}
+
+func appendSnapshotRules(data []byte, rules []*domain.RedirectRule) []byte {
+	data = binary.AppendUvarint(data, uint64(len(rules)))
+	for _, rule := range rules {
+		data = appendSnapshotString(data, rule.Platform)
+		data = appendSnapshotString(data, rule.Language)
+		data = appendSnapshotString(data, rule.CIDR)
+		data = appendSnapshotString(data, rule.URL)
+
+		var flags byte
+		if rule.From != nil {
+			flags |= snapshotRuleFlagFrom
+		}
+		if rule.To != nil {
+			flags |= snapshotRuleFlagTo
+		}
+		data = append(data, flags)
+		if rule.From != nil {
+			data = binary.AppendVarint(data, rule.From.UnixNano())
+		}
+		if rule.To != nil {
+			data = binary.AppendVarint(data, rule.To.UnixNano())
+		}
+	}
+
+	return data
+}
+
+func appendSnapshotVariants(data []byte, variants []*domain.URLVariant) []byte {
+	data = binary.AppendUvarint(data, uint64(len(variants)))
+	for _, variant := range variants {
+		data = appendSnapshotString(data, variant.Name)
+		data = appendSnapshotString(data, variant.URL)
+		data = binary.AppendUvarint(data, uint64(variant.Weight))
+	}
+
+	return data
+}
+
+func encodeSnapshotCollectionRecord(entry *domain.ColdStoreEntry) []byte {
+	size := 3*binary.MaxVarintLen64 + len(entry.Key) + len(entry.Value) + len(uuid.UUID{}) + binary.MaxVarintLen64
+	record := make([]byte, 0, size)
+	record = appendSnapshotString(record, entry.Key)
+	record = appendSnapshotString(record, entry.Value)
+	record = append(record, entry.UserID[:]...)
+	createdAt := time.Time{}
+	if entry.CreatedAt != nil {
+		createdAt = *entry.CreatedAt
+	}
+	record = binary.AppendVarint(record, createdAt.UnixNano())
+	record = binary.AppendUvarint(record, uint64(len(entry.Keys)))
+	for _, key := range entry.Keys {
+		record = appendSnapshotString(record, key)
+	}
+
+	return record
+}
+
+func encodeSnapshotCampaignRecord(entry *domain.ColdStoreEntry) []byte {
+	record := make([]byte, 0, 3*binary.MaxVarintLen64+len(entry.Key)+len(entry.Value)+len(uuid.UUID{})+binary.MaxVarintLen64)
+	record = appendSnapshotString(record, entry.Key)
+	record = appendSnapshotString(record, entry.Value)
+	record = append(record, entry.UserID[:]...)
+	createdAt := time.Time{}
+	if entry.CreatedAt != nil {
+		createdAt = *entry.CreatedAt
+	}
+	record = binary.AppendVarint(record, createdAt.UnixNano())
+	record = binary.AppendUvarint(record, uint64(len(entry.Parameters)))
+	for _, name := range slices.Sorted(maps.Keys(entry.Parameters)) {
+		record = appendSnapshotString(record, name)
+		record = appendSnapshotString(record, entry.Parameters[name])
+	}
+
+	return record
+}
+
+func appendSnapshotString(data []byte, value string) []byte {
+	data = binary.AppendUvarint(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+func decodeSnapshotRecord(record []byte) (*domain.ColdStoreEntry, error) {
+	if len(record) == 0 {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	flags := record[0]
+	record = record[1:]
+
+	key, record, ok := decodeSnapshotString(record)
+	if !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	value, record, ok := decodeSnapshotString(record)
+	if !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	entry := &domain.ColdStoreEntry{
+		Type:      domain.ColdStoreEntryTypePut,
+		Key:       key,
+		Value:     value,
+		IsDeleted: flags&snapshotRecordFlagDeleted != 0,
+	}
+
+	if flags&snapshotRecordFlagCanonical != 0 {
+		entry.CanonicalURL, record, ok = decodeSnapshotString(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if len(record) < len(entry.UserID) {
+		return nil, errInvalidSnapshotRecord
+	}
+	copy(entry.UserID[:], record)
+	record = record[len(entry.UserID):]
+
+	if flags&snapshotRecordFlagExpires != 0 {
+		entry.ExpiresAt, record, ok = decodeSnapshotTime(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if flags&snapshotRecordFlagDeletedAt != 0 {
+		entry.DeletedAt, record, ok = decodeSnapshotTime(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if flags&snapshotRecordFlagCreatedAt != 0 {
+		entry.CreatedAt, record, ok = decodeSnapshotTime(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if flags&snapshotRecordFlagUpdatedAt != 0 {
+		entry.UpdatedAt, record, ok = decodeSnapshotTime(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if flags&snapshotRecordFlagLastAccessedAt != 0 {
+		entry.LastAccessedAt, record, ok = decodeSnapshotTime(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if flags&snapshotRecordFlagVersions != 0 {
+		entry.Versions, record, ok = decodeSnapshotVersions(record)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if len(record) > 0 {
+		record, ok = decodeSnapshotMetadata(record, entry)
+		if !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	if len(record) > 0 {
+		code, n := binary.Uvarint(record)
+		if n <= 0 || (code != 0 && (code < 100 || code > 999)) {
+			return nil, errInvalidSnapshotRecord
+		}
+
+		entry.RedirectCode = int(code)
+		record = record[n:]
+	}
+
+	if len(record) > 0 {
+		options := record[0]
+		if options == 0 || options&^snapshotRecordOptionsAll != 0 {
+			return nil, errInvalidSnapshotRecord
+		}
+
+		entry.Passthrough = options&snapshotRecordOptionPassthrough != 0
+		record = record[1:]
+		if options&snapshotRecordOptionCampaign != 0 {
+			if len(record) < len(entry.CampaignID) {
+				return nil, errInvalidSnapshotRecord
+			}
+
+			copy(entry.CampaignID[:], record)
+			record = record[len(entry.CampaignID):]
+		}
+
+		if options&snapshotRecordOptionRules != 0 {
+			entry.Rules, record, ok = decodeSnapshotRules(record)
+			if !ok {
+				return nil, errInvalidSnapshotRecord
+			}
+		}
+
+		if options&snapshotRecordOptionVariants != 0 {
+			entry.Variants, record, ok = decodeSnapshotVariants(record)
+			if !ok {
+				return nil, errInvalidSnapshotRecord
+			}
+		}
+
+		if options&snapshotRecordOptionDedupScope != 0 {
+			entry.DedupScope, record, ok = decodeSnapshotString(record)
+			if !ok {
+				return nil, errInvalidSnapshotRecord
+			}
+		}
+
+		if len(record) > 0 {
+			return nil, errInvalidSnapshotRecord
+		}
+	}
+
+	return entry, nil
+}
+
+func decodeSnapshotCollectionRecord(record []byte) (*domain.ColdStoreEntry, error) {
+	entry := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection}
+
+	var ok bool
+	if entry.Key, record, ok = decodeSnapshotString(record); !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	if entry.Value, record, ok = decodeSnapshotString(record); !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	if len(record) < len(entry.UserID) {
+		return nil, errInvalidSnapshotRecord
+	}
+	copy(entry.UserID[:], record)
+	record = record[len(entry.UserID):]
+
+	if entry.CreatedAt, record, ok = decodeSnapshotTime(record); !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	count, n := binary.Uvarint(record)
+	// Every key takes at least one byte, so the count can't exceed the record size.
+	if n <= 0 || count > uint64(len(record)) {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	record = record[n:]
+	for range count {
+		var key string
+		if key, record, ok = decodeSnapshotString(record); !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+
+		entry.Keys = append(entry.Keys, key)
+	}
+
+	if len(record) > 0 {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	return entry, nil
+}
+
+func decodeSnapshotCampaignRecord(record []byte) (*domain.ColdStoreEntry, error) {
+	entry := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCampaign}
+
+	var ok bool
+	if entry.Key, record, ok = decodeSnapshotString(record); !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	if entry.Value, record, ok = decodeSnapshotString(record); !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	if len(record) < len(entry.UserID) {
+		return nil, errInvalidSnapshotRecord
+	}
+	copy(entry.UserID[:], record)
+	record = record[len(entry.UserID):]
+
+	if entry.CreatedAt, record, ok = decodeSnapshotTime(record); !ok {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	count, n := binary.Uvarint(record)
+	// Every parameter takes at least two bytes, so the count can't exceed the record size.
+	if n <= 0 || count > uint64(len(record)) {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	record = record[n:]
+	if count > 0 {
+		entry.Parameters = make(map[string]string, count)
+	}
+	for range count {
+		var name, value string
+		if name, record, ok = decodeSnapshotString(record); !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+
+		if value, record, ok = decodeSnapshotString(record); !ok {
+			return nil, errInvalidSnapshotRecord
+		}
+
+		entry.Parameters[name] = value
+	}
+
+	if len(record) > 0 {
+		return nil, errInvalidSnapshotRecord
+	}
+
+	return entry, nil
+}
+
+func decodeSnapshotMetadata(data []byte, entry *domain.ColdStoreEntry) ([]byte, bool) {
+	var ok bool
+	if entry.Title, data, ok = decodeSnapshotString(data); !ok {
+		return nil, false
+	}
+
+	if entry.Notes, data, ok = decodeSnapshotString(data); !ok {
+		return nil, false
+	}
+
+	count, n := binary.Uvarint(data)
+	// Every tag takes at least one byte, so the count can't exceed the data size.
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, false
+	}
+
+	data = data[n:]
+	for range count {
+		var tag string
+		if tag, data, ok = decodeSnapshotString(data); !ok {
+			return nil, false
+		}
+
+		entry.Tags = append(entry.Tags, tag)
+	}
+
+	return data, true
+}
+
+func decodeSnapshotString(data []byte) (string, []byte, bool) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return "", nil, false
+	}
+
+	end := n + int(size)
+	return string(data[n:end]), data[end:], true
+}
+
+func decodeSnapshotVersions(data []byte) ([]*domain.URLVersion, []byte, bool) {
+	count, n := binary.Uvarint(data)
+	// Every version takes at least three bytes, so the count can't exceed the data size.
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, nil, false
+	}
+
+	data = data[n:]
+	versions := make([]*domain.URLVersion, 0, count)
+	for range count {
+		number, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, nil, false
+		}
+
+		value, rest, ok := decodeSnapshotString(data[n:])
+		if !ok {
+			return nil, nil, false
+		}
+
+		replacedAt, rest, ok := decodeSnapshotTime(rest)
+		if !ok {
+			return nil, nil, false
+		}
+
+		versions = append(versions, &domain.URLVersion{
+			Version:     int(number),
+			OriginalURL: value,
+			ReplacedAt:  *replacedAt,
+		})
+		data = rest
+	}
+
+	return versions, data, true
+}
+
+func decodeSnapshotRules(data []byte) ([]*domain.RedirectRule, []byte, bool) {
+	count, n := binary.Uvarint(data)
+	// Every rule takes at least five bytes, so the count can't exceed the data size.
+	if n <= 0 || count == 0 || count > uint64(len(data)) {
+		return nil, nil, false
+	}
+
+	data = data[n:]
+	rules := make([]*domain.RedirectRule, 0, count)
+	for range count {
+		rule := &domain.RedirectRule{}
+		var ok bool
+		for _, field := range []*string{&rule.Platform, &rule.Language, &rule.CIDR, &rule.URL} {
+			if *field, data, ok = decodeSnapshotString(data); !ok {
+				return nil, nil, false
+			}
+		}
+
+		if len(data) == 0 || data[0]&^snapshotRuleFlagsAll != 0 {
+			return nil, nil, false
+		}
+
+		flags := data[0]
+		data = data[1:]
+		if flags&snapshotRuleFlagFrom != 0 {
+			if rule.From, data, ok = decodeSnapshotTime(data); !ok {
+				return nil, nil, false
+			}
+		}
+
+		if flags&snapshotRuleFlagTo != 0 {
+			if rule.To, data, ok = decodeSnapshotTime(data); !ok {
+				return nil, nil, false
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, data, true
+}
+
+func decodeSnapshotVariants(data []byte) ([]*domain.URLVariant, []byte, bool) {
+	count, n := binary.Uvarint(data)
+	// Every variant takes at least three bytes, so the count can't exceed the data size.
+	if n <= 0 || count == 0 || count > uint64(len(data)) {
+		return nil, nil, false
+	}
+
+	data = data[n:]
+	variants := make([]*domain.URLVariant, 0, count)
+	for range count {
+		variant := &domain.URLVariant{}
+		var ok bool
+		if variant.Name, data, ok = decodeSnapshotString(data); !ok {
+			return nil, nil, false
+		}
+
+		if variant.URL, data, ok = decodeSnapshotString(data); !ok {
+			return nil, nil, false
+		}
+
+		weight, n := binary.Uvarint(data)
+		if n <= 0 || weight > math.MaxInt32 {
+			return nil, nil, false
+		}
+
+		variant.Weight = int(weight)
+		data = data[n:]
+		variants = append(variants, variant)
+	}
+
+	return variants, data, true
+}
+
+func decodeSnapshotTime(data []byte) (*time.Time, []byte, bool) {
+	nanoseconds, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, nil, false
+	}
+
+	moment := time.Unix(0, nanoseconds)
+	return &moment, data[n:], true
+}
@@ -0,0 +1,302 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aleffnull/shortener/internal/config"
+	"github.com/aleffnull/shortener/internal/domain"
+)
+
+func TestSnapshot_WriteRead(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	expiresAt := time.Unix(0, time.Now().Add(time.Hour).UnixNano())
+	deletedAt := time.Unix(0, time.Now().UnixNano())
+	entries := []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar", UserID: uuid.New()},
+		{Type: domain.ColdStoreEntryTypePut, Key: "bar", Value: "http://bar.buz", ExpiresAt: &expiresAt, IsDeleted: true, DeletedAt: &deletedAt},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buz", Value: "http://Buz.qux:80/", CanonicalURL: "http://buz.qux/"},
+		{
+			Type:           domain.ColdStoreEntryTypePut,
+			Key:            "qux",
+			Value:          "http://qux.foo",
+			CreatedAt:      &deletedAt,
+			UpdatedAt:      &deletedAt,
+			LastAccessedAt: &expiresAt,
+			Versions: []*domain.URLVersion{
+				{Version: 1, OriginalURL: "http://foo.qux", ReplacedAt: deletedAt},
+				{Version: 2, OriginalURL: "http://bar.qux", ReplacedAt: expiresAt},
+			},
+			Title:        "Qux",
+			Tags:         []string{"bar", "foo"},
+			Notes:        "Qux notes",
+			RedirectCode: 308,
+		},
+		{Type: domain.ColdStoreEntryTypePut, Key: "foobar", Value: "http://foo.bar/bar", Tags: []string{"foo"}},
+		{Type: domain.ColdStoreEntryTypePut, Key: "barfoo", Value: "http://bar.foo", RedirectCode: 301},
+		{Type: domain.ColdStoreEntryTypePut, Key: "buzfoo", Value: "http://buz.foo", Passthrough: true},
+		{Type: domain.ColdStoreEntryTypePut, Key: "quxfoo", Value: "http://qux.foo", Title: "Qux", RedirectCode: 302, Passthrough: true},
+		{Type: domain.ColdStoreEntryTypePut, Key: "foobuz", Value: "http://foo.buz", CampaignID: uuid.New()},
+		{
+			Type:  domain.ColdStoreEntryTypePut,
+			Key:   "fooqux",
+			Value: "http://foo.qux",
+			Rules: []*domain.RedirectRule{
+				{Platform: domain.PlatformIOS, URL: "https://apps.apple.com/app/qux"},
+				{Language: "de", CIDR: "10.0.0.0/8", From: &deletedAt, URL: "http://foo.qux/de"},
+				{To: &expiresAt, URL: "http://foo.qux/sale"},
+			},
+		},
+		{
+			Type:       domain.ColdStoreEntryTypePut,
+			Key:        "buzqux",
+			Value:      "http://buz.qux",
+			CampaignID: uuid.New(),
+			Variants: []*domain.URLVariant{
+				{Name: "A", URL: "http://buz.qux/a", Weight: 1},
+				{Name: "B", URL: "http://buz.qux/b", Weight: 0},
+			},
+		},
+		{
+			Type:       domain.ColdStoreEntryTypePut,
+			Key:        "quxfoo",
+			Value:      "http://qux.foo",
+			DedupScope: config.DedupScopeUser,
+		},
+	}
+	// Enough records for several blocks.
+	for i := range 10000 {
+		entries = append(entries, &domain.ColdStoreEntry{
+			Type:  domain.ColdStoreEntryTypePut,
+			Key:   fmt.Sprintf("key%v", i),
+			Value: fmt.Sprintf("http://foo.bar/%v", i),
+		})
+	}
+	entries = append(entries,
+		&domain.ColdStoreEntry{
+			Type:      domain.ColdStoreEntryTypeCollection,
+			Key:       uuid.NewString(),
+			Value:     "Foo",
+			UserID:    uuid.New(),
+			CreatedAt: &deletedAt,
+			Keys:      []string{"foo", "qux"},
+		},
+		&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection, Key: uuid.NewString(), Value: "Bar", CreatedAt: &deletedAt},
+		&domain.ColdStoreEntry{
+			Type:       domain.ColdStoreEntryTypeCampaign,
+			Key:        uuid.NewString(),
+			Value:      "Spring",
+			UserID:     uuid.New(),
+			CreatedAt:  &deletedAt,
+			Parameters: map[string]string{"utm_source": "news", "utm_campaign": "spring", "ref": ""},
+		},
+		&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCampaign, Key: uuid.NewString(), Value: "Empty", CreatedAt: &deletedAt},
+	)
+
+	buffer := &bytes.Buffer{}
+	writer, err := newSnapshotWriter(buffer, 42)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, writer.write(entry))
+	}
+	require.NoError(t, writer.close())
+
+	// Act.
+	loadedEntries := []*domain.ColdStoreEntry{}
+	records, err := readSnapshot(buffer, func(entry *domain.ColdStoreEntry) {
+		loadedEntries = append(loadedEntries, entry)
+	})
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, int64(len(entries)), records)
+	require.Equal(t, &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeSequence, Sequence: 42}, loadedEntries[0])
+	require.Equal(t, entries, loadedEntries[1:])
+}
+
+func TestSnapshot_Read_Version1(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	buffer := &bytes.Buffer{}
+	writer, err := newSnapshotWriter(buffer, 42)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, writer.close())
+	// Version 1 has the same blocks, but no key sequence in the header.
+	data := append(append([]byte(snapshotMagic), snapshotVersionV1, 0, 0, 0), buffer.Bytes()[snapshotHeaderSize:]...)
+
+	// Act.
+	loadedEntries := []*domain.ColdStoreEntry{}
+	records, err := readSnapshot(bytes.NewReader(data), func(entry *domain.ColdStoreEntry) {
+		loadedEntries = append(loadedEntries, entry)
+	})
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, int64(1), records)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+	}, loadedEntries)
+}
+
+func TestSnapshot_Read_Version2(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	buffer := &bytes.Buffer{}
+	writer, err := newSnapshotWriter(buffer, 42)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, writer.close())
+	// Version 2 has no collection and campaign sections after the end marker of the URLs.
+	data := buffer.Bytes()
+	data[len(snapshotMagic)] = snapshotVersionV2
+	data = data[:len(data)-2*snapshotBlockHeaderSize]
+
+	// Act.
+	loadedEntries := []*domain.ColdStoreEntry{}
+	records, err := readSnapshot(bytes.NewReader(data), func(entry *domain.ColdStoreEntry) {
+		loadedEntries = append(loadedEntries, entry)
+	})
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, int64(1), records)
+	require.Equal(t, []*domain.ColdStoreEntry{
+		{Type: domain.ColdStoreEntryTypeSequence, Sequence: 42},
+		{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"},
+	}, loadedEntries)
+}
+
+func TestSnapshot_Read_Version3(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	collection := &domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection, Key: uuid.NewString(), Value: "Foo"}
+	buffer := &bytes.Buffer{}
+	writer, err := newSnapshotWriter(buffer, 0)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, writer.write(collection))
+	require.NoError(t, writer.close())
+	// Version 3 has no campaign section after the end marker of the collections.
+	data := buffer.Bytes()
+	data[len(snapshotMagic)] = snapshotVersionV3
+	data = data[:len(data)-snapshotBlockHeaderSize]
+
+	// Act.
+	loadedEntries := []*domain.ColdStoreEntry{}
+	records, err := readSnapshot(bytes.NewReader(data), func(entry *domain.ColdStoreEntry) {
+		loadedEntries = append(loadedEntries, entry)
+	})
+
+	// Assert.
+	require.NoError(t, err)
+	require.Equal(t, int64(2), records)
+	require.Len(t, loadedEntries, 2)
+	require.Equal(t, collection.Key, loadedEntries[1].Key)
+}
+
+func TestSnapshot_Write_CollectionAfterCampaign(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	writer, err := newSnapshotWriter(&bytes.Buffer{}, 0)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCampaign, Key: uuid.NewString()}))
+
+	// Act.
+	err = writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection, Key: uuid.NewString()})
+
+	// Assert.
+	require.Error(t, err)
+}
+
+func TestSnapshot_Write_URLAfterCollection(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	writer, err := newSnapshotWriter(&bytes.Buffer{}, 0)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypeCollection, Key: uuid.NewString()}))
+
+	// Act.
+	err = writer.write(&domain.ColdStoreEntry{Type: domain.ColdStoreEntryTypePut, Key: "foo", Value: "http://foo.bar"})
+
+	// Assert.
+	require.Error(t, err)
+}
+
+func TestSnapshot_Read_Errors(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	writer, err := newSnapshotWriter(buffer, 0)
+	require.NoError(t, err)
+	require.NoError(t, writer.write(&domain.ColdStoreEntry{Key: "foo", Value: "http://foo.bar"}))
+	require.NoError(t, writer.close())
+	snapshot := buffer.Bytes()
+
+	tests := []struct {
+		name          string
+		data          []byte
+		wantCorrupted bool
+	}{
+		{
+			name:          "WHEN empty THEN corrupted",
+			data:          []byte{},
+			wantCorrupted: true,
+		},
+		{
+			name:          "WHEN wrong magic THEN corrupted",
+			data:          append([]byte("NOTASNAP"), snapshot[len(snapshotMagic):]...),
+			wantCorrupted: true,
+		},
+		{
+			name: "WHEN unknown version THEN error",
+			data: append(append([]byte(snapshotMagic), snapshotVersion+1, 0, 0, 0), snapshot[snapshotHeaderSizeV1:]...),
+		},
+		{
+			name:          "WHEN campaign end marker missing THEN corrupted",
+			data:          snapshot[:len(snapshot)-snapshotBlockHeaderSize],
+			wantCorrupted: true,
+		},
+		{
+			name:          "WHEN collection end marker missing THEN corrupted",
+			data:          snapshot[:len(snapshot)-2*snapshotBlockHeaderSize],
+			wantCorrupted: true,
+		},
+		{
+			name:          "WHEN end marker missing THEN corrupted",
+			data:          snapshot[:len(snapshot)-3*snapshotBlockHeaderSize],
+			wantCorrupted: true,
+		},
+		{
+			name:          "WHEN block truncated THEN corrupted",
+			data:          snapshot[:snapshotHeaderSize+snapshotBlockHeaderSize+1],
+			wantCorrupted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act.
+			_, err := readSnapshot(bytes.NewReader(tt.data), func(*domain.ColdStoreEntry) {})
+
+			// Assert.
+			require.Error(t, err)
+			require.Equal(t, tt.wantCorrupted, errors.Is(err, ErrSnapshotCorrupted))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,15 +15,65 @@ import (
 
 type StoreManager interface {
 	Init() error
+	Shutdown()
 	CheckAvailability(context.Context) error
+	Compact(context.Context) error
+	Export(context.Context, io.Writer) error
 }
 
 type DataStore interface {
 	Load(context.Context, string) (*domain.URLItem, error)
-	LoadAllByUserID(context.Context, uuid.UUID) ([]*domain.KeyOriginalURLItem, error)
-	Save(context.Context, string, uuid.UUID) (string, error)
+	LoadAllByUserID(context.Context, uuid.UUID, *domain.UserURLsQuery) ([]*domain.KeyOriginalURLItem, error)
+	Save(context.Context, *domain.SaveRequest, uuid.UUID) (string, error)
 	SaveBatch(context.Context, []*domain.BatchRequestItem, uuid.UUID) ([]*domain.BatchResponseItem, error)
+	// BackfillCanonicalURLs gives the canonical form to URLs stored before canonicalization, returns their number.
+	// A URL whose canonical form is taken by another URL keeps its original form.
+	BackfillCanonicalURLs(context.Context, func(string) string) (int, error)
+	// UpdateURL points the user's live key to another URL, the previous one is kept in the key versions.
+	// Returns the version of the new URL, zero when the user has no such live key.
+	UpdateURL(context.Context, *domain.UpdateRequest, uuid.UUID) (int, error)
+	// LoadVersions returns the previous URLs of the key, the latest first.
+	LoadVersions(context.Context, string) ([]*domain.URLVersion, error)
+	// UpdateVariants replaces the variants of the user's live key, the URL stays the same.
+	// Returns false when the user has no such live key.
+	UpdateVariants(context.Context, string, []*domain.URLVariant, uuid.UUID) (bool, error)
 	DeleteBatch(context.Context, []string, uuid.UUID) error
+	// RestoreBatch reverts the deletion of the user's keys deleted after the given moment, returns the restored keys.
+	RestoreBatch(context.Context, []string, uuid.UUID, time.Time) ([]string, error)
+	// PurgeDeleted removes keys deleted before the given moment for good, returns their number.
+	PurgeDeleted(context.Context, time.Time) (int, error)
+	// DeleteExpired removes keys expired before the given moment for good, returns their number.
+	// Until then expired keys are kept, so following them is told apart from following unknown keys.
+	DeleteExpired(context.Context, time.Time) (int, error)
+	// SaveCollection creates the collection, ErrCollectionExists is returned when the user has one with the same name.
+	SaveCollection(context.Context, *domain.Collection) error
+	// LoadCollections returns the collections of the user ordered by name.
+	LoadCollections(context.Context, uuid.UUID) ([]*domain.Collection, error)
+	// LoadCollection returns nil when the user has no such collection.
+	LoadCollection(context.Context, uuid.UUID, uuid.UUID) (*domain.Collection, error)
+	// RenameCollection returns false when the user has no such collection.
+	RenameCollection(context.Context, uuid.UUID, string, uuid.UUID) (bool, error)
+	// DeleteCollection removes the collection of the user, its URLs are kept. Returns false when there is no such collection.
+	DeleteCollection(context.Context, uuid.UUID, uuid.UUID) (bool, error)
+	// AddCollectionURLs adds the user's keys to the collection, keys of other users are skipped.
+	// Returns false when the user has no such collection.
+	AddCollectionURLs(context.Context, uuid.UUID, []string, uuid.UUID) (bool, error)
+	// RemoveCollectionURLs returns false when the user has no such collection.
+	RemoveCollectionURLs(context.Context, uuid.UUID, []string, uuid.UUID) (bool, error)
+	// SaveCampaign creates the campaign, ErrCampaignExists is returned when the user has one with the same name.
+	SaveCampaign(context.Context, *domain.Campaign) error
+	// LoadCampaigns returns the campaigns of the user ordered by name.
+	LoadCampaigns(context.Context, uuid.UUID) ([]*domain.Campaign, error)
+	// LoadCampaign returns nil when the user has no such campaign.
+	LoadCampaign(context.Context, uuid.UUID, uuid.UUID) (*domain.Campaign, error)
+	// UpdateCampaign replaces the name and the parameters of the user's campaign, the creation time is kept.
+	// Returns false when the user has no such campaign, ErrCampaignExists when the name is taken.
+	UpdateCampaign(context.Context, *domain.Campaign) (bool, error)
+	// DeleteCampaign removes the campaign of the user, its URLs are kept without it.
+	// Returns false when there is no such campaign.
+	DeleteCampaign(context.Context, uuid.UUID, uuid.UUID) (bool, error)
+	SaveClicks(context.Context, []*domain.Click) error
+	GetClickStatistics(context.Context, *domain.ClickStatisticsRequest) (*domain.ClickStatistics, error)
 	GetStatistics(context.Context) (int, int, error)
 }
 
@@ -31,8 +83,11 @@ type Store interface {
 }
 
 type ColdStore interface {
-	LoadAll() ([]*domain.ColdStoreEntry, error)
+	// LoadAll passes the stored entries to restore in the order they must be applied.
+	LoadAll(restore func(*domain.ColdStoreEntry)) error
 	Save(*domain.ColdStoreEntry) error
+	Compact() error
+	Close() error
 }
 
 func NewStore(
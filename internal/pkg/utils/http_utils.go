@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/aleffnull/shortener/internal/pkg/logger"
+	"github.com/go-http-utils/headers"
 )
 
 func HandleServerError(response http.ResponseWriter, err error, logger logger.Logger) {
@@ -16,6 +19,16 @@ func HandleRequestError(response http.ResponseWriter, err error, logger logger.L
 	http.Error(response, err.Error(), http.StatusBadRequest)
 }
 
+func HandleConflict(response http.ResponseWriter, err error, logger logger.Logger) {
+	logger.Warnf("Conflict: %v", err)
+	http.Error(response, err.Error(), http.StatusConflict)
+}
+
+func HandleNotFound(response http.ResponseWriter, err error, logger logger.Logger) {
+	logger.Warnf("Not found: %v", err)
+	http.Error(response, err.Error(), http.StatusNotFound)
+}
+
 func HandleUnauthorized(response http.ResponseWriter, message string, logger logger.Logger) {
 	logger.Warnf("Unauthorized access: %v", message)
 	http.Error(response, "Unauthorized", http.StatusUnauthorized)
@@ -25,3 +38,21 @@ func HandleForbidden(response http.ResponseWriter, message string, logger logger
 	logger.Warnf("Access forbidden: %v", message)
 	http.Error(response, "Forbidden", http.StatusForbidden)
 }
+
+func GetClientIP(request *http.Request) string {
+	if realIP := request.Header.Get(headers.XRealIP); len(realIP) > 0 {
+		return realIP
+	}
+
+	if forwardedFor := request.Header.Get(headers.XForwardedFor); len(forwardedFor) > 0 {
+		clientIP, _, _ := strings.Cut(forwardedFor, ",")
+		return strings.TrimSpace(clientIP)
+	}
+
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+
+	return host
+}
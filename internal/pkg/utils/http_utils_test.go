@@ -43,6 +43,22 @@ func TestHandleRequestError(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, response.Code)
 }
 
+func TestHandleConflict(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Warnf(gomock.Any(), assert.AnError)
+	response := httptest.NewRecorder()
+
+	// Act.
+	HandleConflict(response, assert.AnError, mock.Logger)
+
+	// Assert.
+	require.Equal(t, http.StatusConflict, response.Code)
+}
+
 func TestHandleUnauthorized(t *testing.T) {
 	t.Parallel()
 
@@ -74,3 +90,72 @@ func TestHandleForbidden(t *testing.T) {
 	// Assert.
 	require.Equal(t, http.StatusForbidden, response.Code)
 }
+
+func TestHandleNotFound(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Warnf(gomock.Any(), assert.AnError)
+	response := httptest.NewRecorder()
+
+	// Act.
+	HandleNotFound(response, assert.AnError, mock.Logger)
+
+	// Assert.
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestGetClientIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		remoteAddr string
+		wantIP     string
+	}{
+		{
+			name:       "WHEN X-Real-IP THEN it is used",
+			headers:    map[string]string{"X-Real-IP": "10.0.0.1", "X-Forwarded-For": "10.0.0.2"},
+			remoteAddr: "10.0.0.3:1234",
+			wantIP:     "10.0.0.1",
+		},
+		{
+			name:       "WHEN X-Forwarded-For THEN first address is used",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.2, 10.0.0.4"},
+			remoteAddr: "10.0.0.3:1234",
+			wantIP:     "10.0.0.2",
+		},
+		{
+			name:       "WHEN no headers THEN remote address is used",
+			remoteAddr: "10.0.0.3:1234",
+			wantIP:     "10.0.0.3",
+		},
+		{
+			name:       "WHEN remote address without port THEN it is used as is",
+			remoteAddr: "10.0.0.3",
+			wantIP:     "10.0.0.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				request.Header.Set(name, value)
+			}
+
+			// Act.
+			clientIP := GetClientIP(request)
+
+			// Assert.
+			require.Equal(t, tt.wantIP, clientIP)
+		})
+	}
+}
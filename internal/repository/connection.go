@@ -22,6 +22,7 @@ type Connection interface {
 	QueryRow(ctx context.Context, result any, sql string, args ...any) error
 	QueryRow2(ctx context.Context, result1 any, result2 any, sql string, args ...any) error
 	QueryRows(ctx context.Context, sql string, args ...any) (*sql.Rows, error)
+	QueryRowsTx(ctx context.Context, tx *sql.Tx, sql string, args ...any) (*sql.Rows, error)
 	Exec(ctx context.Context, sql string, args ...any) error
 	ExecTx(ctx context.Context, tx *sql.Tx, sql string, args ...any) error
 	DoInTx(ctx context.Context, action func(*sql.Tx) error) error
@@ -131,6 +132,19 @@ func (c *connectionImpl) QueryRows(ctx context.Context, sql string, args ...any)
 	return rows, nil
 }
 
+func (c *connectionImpl) QueryRowsTx(ctx context.Context, tx *sql.Tx, sql string, args ...any) (*sql.Rows, error) {
+	if c.db == nil {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("connectionImpl.QueryRowsTx, tx.QueryContext failed: %w", err)
+	}
+
+	return rows, nil
+}
+
 func (c *connectionImpl) Exec(ctx context.Context, sql string, args ...any) error {
 	if c.db == nil {
 		return nil
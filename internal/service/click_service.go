@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/logger"
+	"github.com/aleffnull/shortener/internal/pkg/parameters"
+	"github.com/aleffnull/shortener/internal/pkg/store"
+)
+
+const (
+	clicksChannelSize   = 10000
+	clicksBatchSize     = 500
+	clicksFlushInterval = time.Second
+)
+
+type ClickService interface {
+	Init()
+	Shutdown()
+	RegisterClick(click *domain.Click, clientIP string)
+}
+
+type clickServiceImpl struct {
+	storage       store.Store
+	parameters    parameters.AppParameters
+	logger        logger.Logger
+	clicksChannel chan *domain.Click
+	quitChannel   chan struct{}
+	doneChannel   chan struct{}
+}
+
+var _ ClickService = (*clickServiceImpl)(nil)
+
+func NewClickService(storage store.Store, parameters parameters.AppParameters, logger logger.Logger) ClickService {
+	return &clickServiceImpl{
+		storage:       storage,
+		parameters:    parameters,
+		logger:        logger,
+		clicksChannel: make(chan *domain.Click, clicksChannelSize),
+		quitChannel:   make(chan struct{}),
+		doneChannel:   make(chan struct{}),
+	}
+}
+
+func (i *clickServiceImpl) Init() {
+	go func() {
+		defer close(i.doneChannel)
+
+		ticker := time.NewTicker(clicksFlushInterval)
+		defer ticker.Stop()
+
+		clicks := make([]*domain.Click, 0, clicksBatchSize)
+		for {
+			select {
+			case <-i.quitChannel:
+				// Дописываем все, что успели накопить, и завершаем работу.
+				clicks = i.drainClicks(clicks)
+				i.saveClicks(clicks)
+				return
+			case click := <-i.clicksChannel:
+				// Накапливаем клики, при заполнении пачки сразу сохраняем.
+				clicks = append(clicks, click)
+				if len(clicks) >= clicksBatchSize {
+					clicks = i.saveClicks(clicks)
+				}
+			case <-ticker.C:
+				clicks = i.saveClicks(clicks)
+			}
+		}
+	}()
+}
+
+func (i *clickServiceImpl) Shutdown() {
+	close(i.quitChannel)
+	<-i.doneChannel
+}
+
+func (i *clickServiceImpl) RegisterClick(click *domain.Click, clientIP string) {
+	click.IPHash = i.hashIP(clientIP)
+
+	// Редирект не должен ждать записи статистики, поэтому при переполнении буфера клик теряется.
+	select {
+	case i.clicksChannel <- click:
+	default:
+		i.logger.Warnf("Clicks buffer is full, click on key '%v' dropped", click.Key)
+	}
+}
+
+func (i *clickServiceImpl) drainClicks(clicks []*domain.Click) []*domain.Click {
+	for {
+		select {
+		case click := <-i.clicksChannel:
+			clicks = append(clicks, click)
+		default:
+			return clicks
+		}
+	}
+}
+
+func (i *clickServiceImpl) saveClicks(clicks []*domain.Click) []*domain.Click {
+	if len(clicks) == 0 {
+		return clicks
+	}
+
+	if err := i.storage.SaveClicks(context.Background(), clicks); err != nil {
+		// Повторять не пытаемся, чтобы не копить клики в памяти бесконечно.
+		i.logger.Errorf("ClickService.saveClicks, storage.SaveClicks failed: %v", err)
+	}
+
+	return clicks[:0]
+}
+
+func (i *clickServiceImpl) hashIP(clientIP string) string {
+	if len(clientIP) == 0 {
+		return ""
+	}
+
+	// Сам IP-адрес не храним, только его хэш с секретным ключом.
+	mac := hmac.New(sha256.New, []byte(i.parameters.GetJWTSigningKey()))
+	mac.Write([]byte(clientIP))
+	return hex.EncodeToString(mac.Sum(nil))
+}
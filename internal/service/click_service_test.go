@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/domain"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClickService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		noClicks   bool
+		clientIP   string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name:       "WHEN no clicks THEN ok",
+			noClicks:   true,
+			hookBefore: func(_ *mocks.Mock) {},
+		},
+		{
+			name:     "WHEN storage error THEN logged",
+			clientIP: "192.0.2.1",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.AppParameters.EXPECT().GetJWTSigningKey().Return("secret")
+				mock.Store.EXPECT().SaveClicks(gomock.Any(), gomock.Any()).Return(assert.AnError)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:     "WHEN no error THEN saved with hashed IP",
+			clientIP: "192.0.2.1",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.AppParameters.EXPECT().GetJWTSigningKey().Return("secret")
+				mock.Store.EXPECT().SaveClicks(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, clicks []*domain.Click) error {
+						require.Len(t, clicks, 1)
+						require.Equal(t, "foo", clicks[0].Key)
+						require.Len(t, clicks[0].IPHash, 64)
+						require.NotContains(t, clicks[0].IPHash, "192.0.2.1")
+						return nil
+					})
+			},
+		},
+		{
+			name: "WHEN no client IP THEN saved without hash",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().SaveClicks(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, clicks []*domain.Click) error {
+						require.Len(t, clicks, 1)
+						require.Empty(t, clicks[0].IPHash)
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			service := NewClickService(mock.Store, mock.AppParameters, mock.Logger)
+
+			// Act.
+			service.Init()
+			if !tt.noClicks {
+				service.RegisterClick(&domain.Click{Key: "foo", Timestamp: time.Now()}, tt.clientIP)
+			}
+
+			// Накопленные клики сохраняются при завершении работы.
+			service.Shutdown()
+		})
+	}
+}
+
+func TestClickService_RegisterClick_BufferFull(t *testing.T) {
+	t.Parallel()
+
+	// Arrange.
+	ctrl := gomock.NewController(t)
+	mock := mocks.NewMock(ctrl)
+	mock.Logger.EXPECT().Warnf(gomock.Any(), "foo")
+	service := NewClickService(mock.Store, mock.AppParameters, mock.Logger)
+
+	// Act.
+	// Сервис не запущен, поэтому буфер никто не разбирает.
+	for range clicksChannelSize + 1 {
+		service.RegisterClick(&domain.Click{Key: "foo"}, "")
+	}
+}
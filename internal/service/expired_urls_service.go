@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/config"
+	"github.com/aleffnull/shortener/internal/pkg/logger"
+	"github.com/aleffnull/shortener/internal/pkg/store"
+)
+
+type ExpiredURLsService interface {
+	Init()
+	Shutdown()
+}
+
+type expiredURLsServiceImpl struct {
+	storage       store.Store
+	logger        logger.Logger
+	configuration *config.Configuration
+	quitChannel   chan struct{}
+}
+
+var _ ExpiredURLsService = (*expiredURLsServiceImpl)(nil)
+
+func NewExpiredURLsService(storage store.Store, logger logger.Logger, configuration *config.Configuration) ExpiredURLsService {
+	return &expiredURLsServiceImpl{
+		storage:       storage,
+		logger:        logger,
+		configuration: configuration,
+		quitChannel:   make(chan struct{}),
+	}
+}
+
+func (i *expiredURLsServiceImpl) Init() {
+	go func() {
+		ticker := time.NewTicker(i.configuration.ExpiredURLsSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-i.quitChannel:
+				// Завершаем работу.
+				return
+			case <-ticker.C:
+				i.deleteExpiredURLs()
+			}
+		}
+	}()
+}
+
+func (i *expiredURLsServiceImpl) Shutdown() {
+	close(i.quitChannel)
+}
+
+func (i *expiredURLsServiceImpl) deleteExpiredURLs() {
+	// Истекшие ссылки хранятся еще какое-то время, чтобы переход по ним отвечал 410, а не 400.
+	expiredBefore := time.Now().Add(-i.configuration.ExpiredURLsRetention)
+	count, err := i.storage.DeleteExpired(context.Background(), expiredBefore)
+	if err != nil {
+		// Не страшно, попробуем при следующем срабатывании таймера.
+		i.logger.Errorf("ExpiredURLsService.deleteExpiredURLs, storage.DeleteExpired failed: %v", err)
+		return
+	}
+
+	if count > 0 {
+		i.logger.Infof("Deleted %v expired URLs", count)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/config"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExpiredURLsService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN storage error THEN logged",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(0, assert.AnError).MinTimes(1)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any()).MinTimes(1)
+			},
+		},
+		{
+			name: "WHEN nothing expired THEN ok",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(0, nil).MinTimes(1)
+			},
+		},
+		{
+			name: "WHEN expired deleted THEN logged",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(2, nil).MinTimes(1)
+				mock.Logger.EXPECT().Infof(gomock.Any(), 2).MinTimes(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				ExpiredURLsSweepInterval: 100 * time.Millisecond,
+			}
+			service := NewExpiredURLsService(mock.Store, mock.Logger, configuration)
+
+			// Act.
+			service.Init()
+
+			// Ждем, пока отработает удаление по таймеру.
+			time.Sleep(250 * time.Millisecond)
+			service.Shutdown()
+
+			// Ждем завершения горутины удаления.
+			time.Sleep(200 * time.Millisecond)
+		})
+	}
+}
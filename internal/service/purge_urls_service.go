@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/config"
+	"github.com/aleffnull/shortener/internal/pkg/logger"
+	"github.com/aleffnull/shortener/internal/pkg/store"
+)
+
+type PurgeURLsService interface {
+	Init()
+	Shutdown()
+}
+
+type purgeURLsServiceImpl struct {
+	storage       store.Store
+	logger        logger.Logger
+	configuration *config.Configuration
+	quitChannel   chan struct{}
+}
+
+var _ PurgeURLsService = (*purgeURLsServiceImpl)(nil)
+
+func NewPurgeURLsService(storage store.Store, logger logger.Logger, configuration *config.Configuration) PurgeURLsService {
+	return &purgeURLsServiceImpl{
+		storage:       storage,
+		logger:        logger,
+		configuration: configuration,
+		quitChannel:   make(chan struct{}),
+	}
+}
+
+func (i *purgeURLsServiceImpl) Init() {
+	go func() {
+		ticker := time.NewTicker(i.configuration.DeletedURLsPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-i.quitChannel:
+				// Завершаем работу.
+				return
+			case <-ticker.C:
+				i.purgeDeletedURLs()
+			}
+		}
+	}()
+}
+
+func (i *purgeURLsServiceImpl) Shutdown() {
+	close(i.quitChannel)
+}
+
+func (i *purgeURLsServiceImpl) purgeDeletedURLs() {
+	// Удаляем окончательно ссылки, удаленные раньше срока хранения.
+	deletedBefore := time.Now().Add(-i.configuration.DeletedURLsRetention)
+	count, err := i.storage.PurgeDeleted(context.Background(), deletedBefore)
+	if err != nil {
+		// Не страшно, попробуем при следующем срабатывании таймера.
+		i.logger.Errorf("PurgeURLsService.purgeDeletedURLs, storage.PurgeDeleted failed: %v", err)
+		return
+	}
+
+	if count > 0 {
+		i.logger.Infof("Purged %v deleted URLs", count)
+	}
+}
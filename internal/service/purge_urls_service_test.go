@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleffnull/shortener/internal/config"
+	"github.com/aleffnull/shortener/internal/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurgeURLsService(t *testing.T) {
+	t.Parallel()
+
+	const retention = time.Hour
+
+	tests := []struct {
+		name       string
+		hookBefore func(mock *mocks.Mock)
+	}{
+		{
+			name: "WHEN storage error THEN logged",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).Return(0, assert.AnError).MinTimes(1)
+				mock.Logger.EXPECT().Errorf(gomock.Any(), gomock.Any()).MinTimes(1)
+			},
+		},
+		{
+			name: "WHEN nothing to purge THEN ok",
+			hookBefore: func(mock *mocks.Mock) {
+				mock.Store.EXPECT().PurgeDeleted(gomock.Any(), gomock.Any()).Return(0, nil).MinTimes(1)
+			},
+		},
+		{
+			name: "WHEN deleted purged THEN logged",
+			hookBefore: func(mock *mocks.Mock) {
+				// Purged are the URLs deleted before the retention period.
+				deletedBefore := gomock.Cond(func(deletedBefore time.Time) bool {
+					return time.Since(deletedBefore) >= retention
+				})
+				mock.Store.EXPECT().PurgeDeleted(gomock.Any(), deletedBefore).Return(2, nil).MinTimes(1)
+				mock.Logger.EXPECT().Infof(gomock.Any(), 2).MinTimes(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange.
+			ctrl := gomock.NewController(t)
+			mock := mocks.NewMock(ctrl)
+			tt.hookBefore(mock)
+			configuration := &config.Configuration{
+				DeletedURLsRetention:     retention,
+				DeletedURLsPurgeInterval: 100 * time.Millisecond,
+			}
+			service := NewPurgeURLsService(mock.Store, mock.Logger, configuration)
+
+			// Act.
+			service.Init()
+
+			// Ждем, пока отработает очистка по таймеру.
+			time.Sleep(250 * time.Millisecond)
+			service.Shutdown()
+
+			// Ждем завершения горутины очистки.
+			time.Sleep(200 * time.Millisecond)
+		})
+	}
+}
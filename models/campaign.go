@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignRequest запрос создания или изменения кампании.
+// Имя кампании уникально среди кампаний пользователя.
+type CampaignRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	UTMSource   string `json:"utm_source,omitempty" validate:"omitempty,max=200"`
+	UTMMedium   string `json:"utm_medium,omitempty" validate:"omitempty,max=200"`
+	UTMCampaign string `json:"utm_campaign,omitempty" validate:"omitempty,max=200"`
+	// Parameters дополнительные параметры запроса, UTM-параметры задаются полями выше.
+	Parameters map[string]string `json:"parameters,omitempty" validate:"omitempty,max=20,dive,keys,required,max=100,endkeys,max=200"`
+}
+
+// CampaignItem кампания пользователя.
+// При переходе по ссылке кампании ее параметры добавляются к URL, если их там еще нет.
+type CampaignItem struct {
+	ID          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	UTMSource   string            `json:"utm_source,omitempty"`
+	UTMMedium   string            `json:"utm_medium,omitempty"`
+	UTMCampaign string            `json:"utm_campaign,omitempty"`
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
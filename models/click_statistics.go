@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ClickStatisticsRequest параметры запроса статистики переходов по короткой ссылке.
+type ClickStatisticsRequest struct {
+	From   *time.Time
+	To     *time.Time
+	Bucket string `validate:"omitempty,oneof=hour day week"`
+	Top    int    `validate:"omitempty,gt=0,lte=100"`
+}
+
+// ClickBucketItem количество переходов за интервал времени.
+type ClickBucketItem struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// ClickValueItem количество переходов с одним значением реферера, user agent или варианта ссылки.
+type ClickValueItem struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ClickStatisticsResponse статистика переходов по короткой ссылке.
+type ClickStatisticsResponse struct {
+	TotalClicks    int                `json:"total_clicks"`
+	UniqueVisitors int                `json:"unique_visitors"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	Bucket         string             `json:"bucket"`
+	Buckets        []*ClickBucketItem `json:"buckets"`
+	TopReferrers   []*ClickValueItem  `json:"top_referrers"`
+	TopUserAgents  []*ClickValueItem  `json:"top_user_agents"`
+	// Variants переходы по вариантам ссылки, на которые попали посетители.
+	Variants []*ClickValueItem `json:"variants"`
+}
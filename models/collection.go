@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CollectionRequest запрос создания или переименования коллекции ссылок.
+// Имя коллекции уникально среди коллекций пользователя.
+type CollectionRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CollectionItem коллекция ссылок пользователя.
+// URLCount - количество ссылок в коллекции, включая удаленные, но еще не очищенные.
+type CollectionItem struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	URLCount  int       `json:"url_count"`
+}
@@ -1,10 +1,25 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // GetURLResponseItem ответ на запрос URL.
 type GetURLResponseItem struct {
 	URL       string
 	UserID    uuid.UUID
 	IsDeleted bool
+	IsExpired bool
+	// RedirectCode HTTP-статус перенаправления по ссылке.
+	RedirectCode int
+	// CacheMaxAge время, в течение которого перенаправление можно кэшировать, ноль - кэшировать нельзя.
+	CacheMaxAge time.Duration
+	// Passthrough при переходе к URL добавляются остаток пути и параметры запроса.
+	Passthrough bool
+	// Rules правила выбора другого адреса перехода, проверяются по порядку.
+	Rules []*RedirectRule
+	// Variants адреса для A/B-теста, выбираются, если не подошло ни одно правило.
+	Variants []*URLVariant
 }
@@ -1,7 +1,39 @@
 package models
 
+import "time"
+
+// UserURLsRequest параметры запроса URL пользователя.
+// Limit - размер страницы. Без Limit и Cursor возвращается весь список, с Cursor без Limit - страница по умолчанию.
+// Cursor - значение NextCursor предыдущей страницы, пустой для первой страницы.
+// Search - слова из букв и цифр, каждое из которых должно быть словом URL, заголовка или заметок ссылки.
+// Collection - идентификатор коллекции пользователя, в которую входят ссылки.
+type UserURLsRequest struct {
+	Limit      int `validate:"omitempty,gt=0,lte=1000"`
+	Cursor     string
+	Sort       string `validate:"omitempty,oneof=created key"`
+	Order      string `validate:"omitempty,oneof=asc desc"`
+	Status     string `validate:"omitempty,oneof=all active deleted"`
+	Search     string
+	Tag        string `validate:"omitempty,max=50"`
+	Collection string `validate:"omitempty,uuid"`
+}
+
+// UserURLsResponse страница URL пользователя.
+// NextCursor пустой, если страница последняя.
+type UserURLsResponse struct {
+	Items      []*UserURLsResponseItem
+	NextCursor string
+}
+
 // UserURLsResponseItem ответ на запрос получения всех URL пользователя.
+// LastAccessedAt отсутствует, если переходов по ссылке не было.
 type UserURLsResponseItem struct {
-	ShortURL    string `json:"short_url"`
-	OriginalURL string `json:"original_url"`
+	ShortURL       string     `json:"short_url"`
+	OriginalURL    string     `json:"original_url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	Title          string     `json:"title,omitempty"`
+	Tags           []string   `json:"tags,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
 }
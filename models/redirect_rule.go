@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RedirectRule правило выбора другого адреса перехода по ссылке.
+// Должны выполняться все заданные условия правила, хотя бы одно условие обязательно.
+// Правила ссылки проверяются по порядку, срабатывает первое подходящее, без совпадений переход идет на URL ссылки.
+type RedirectRule struct {
+	// Platform платформа клиента по заголовку User-Agent: ios, android или desktop.
+	Platform string `json:"platform,omitempty" validate:"required_without_all=Language CIDR From To,omitempty,oneof=ios android desktop"`
+	// Language язык, принимаемый клиентом по заголовку Accept-Language, например de или pt-BR.
+	// Язык de подходит и для de-AT. Языки клиента перебираются по убыванию веса.
+	Language string `json:"language,omitempty" validate:"omitempty,bcp47_language_tag"`
+	// CIDR сеть IP-адреса клиента.
+	CIDR string `json:"cidr,omitempty" validate:"omitempty,cidr"`
+	// From и To границы интервала времени, конец в интервал не входит.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+	URL  string     `json:"url" validate:"required,url"`
+}
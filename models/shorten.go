@@ -1,8 +1,33 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // ShortenRequest Запрос на сокращение URL.
 type ShortenRequest struct {
-	URL string `json:"url" validate:"required,url"`
+	URL       string     `json:"url" validate:"required,url"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty,gt,excluded_with=TTL"`
+	// TTL время жизни ссылки в секундах.
+	TTL   int64  `json:"ttl,omitempty" validate:"omitempty,gt=0"`
+	Title string `json:"title,omitempty" validate:"omitempty,max=200"`
+	// Tags метки ссылки, регистр не учитывается.
+	Tags  []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=50"`
+	Notes string   `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	// RedirectCode HTTP-статус перенаправления по ссылке, по умолчанию задается конфигурацией.
+	RedirectCode int `json:"redirect_code,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	// Passthrough при переходе добавлять к URL остаток пути и параметры запроса.
+	Passthrough bool `json:"passthrough,omitempty"`
+	// Campaign идентификатор кампании пользователя, ее параметры добавляются к URL при переходе.
+	Campaign uuid.UUID `json:"campaign,omitzero"`
+	// Rules правила выбора другого адреса перехода, URL ссылки используется, если ни одно не подошло.
+	Rules []*RedirectRule `json:"rules,omitempty" validate:"omitempty,max=20,dive,required"`
+	// Variants адреса для A/B-теста, посетитель закрепляется за вариантом по cookie.
+	// Если веса всех вариантов нулевые, переход идет на URL ссылки.
+	Variants []*URLVariant `json:"variants,omitempty" validate:"omitempty,min=2,max=10,dive,required"`
 }
 
 // ShortenResponse сокращенный URL.
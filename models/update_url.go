@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// UpdateURLRequest запрос на изменение адреса, на который ведет короткая ссылка.
+type UpdateURLRequest struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+// UpdateURLResponse короткая ссылка с новым адресом и номером его версии.
+type UpdateURLResponse struct {
+	ShortURL    string `json:"short_url"`
+	OriginalURL string `json:"original_url"`
+	Version     int    `json:"version"`
+}
+
+// URLVersionItem предыдущий адрес короткой ссылки.
+type URLVersionItem struct {
+	Version     int       `json:"version"`
+	OriginalURL string    `json:"original_url"`
+	ReplacedAt  time.Time `json:"replaced_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// URLVariant один из адресов ссылки, между которыми делятся переходы.
+// Посетитель попадает на вариант с вероятностью, пропорциональной весу, вариант с нулевым весом не выбирается.
+type URLVariant struct {
+	// Name имя варианта, уникально в пределах ссылки, записывается в аудит и статистику переходов.
+	Name   string `json:"name" validate:"required,max=50"`
+	URL    string `json:"url" validate:"required,url"`
+	Weight int    `json:"weight" validate:"min=0,max=10000"`
+}
+
+// UpdateURLVariantsRequest запрос на изменение весов вариантов ссылки, короткая ссылка и адреса вариантов не меняются.
+type UpdateURLVariantsRequest struct {
+	// Weights новые веса по именам вариантов, у не указанных вариантов вес не меняется.
+	Weights map[string]int `json:"weights" validate:"required,min=1,max=10,dive,keys,required,endkeys,min=0,max=10000"`
+}